@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"parking-lot-system/internal/api/dto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// snapshot is the state of the lot as read from the API at one point in time
+type snapshot struct {
+	at            time.Time
+	occupancy     dto.OccupancyStatsResponse
+	grids         []grid // per floor
+	discrepancies []dto.Discrepancy
+	exitQueues    []dto.ExitQueue
+}
+
+// grid holds the state of the spots of a floor, indexed by row and column
+type grid [][]cell
+
+// cell states
+type cell int
+
+const (
+	cellNone     cell = iota // no spot, e.g. a pillar or ramp
+	cellInactive             // a closed spot
+	cellFree
+	cellPartial // a spot for several vehicles holding some
+	cellOccupied
+)
+
+// apiClient reads the lot state from a running server
+type apiClient struct {
+	server string
+	token  string // attendant token for the admin endpoints
+	http   *http.Client
+}
+
+// get decodes the JSON response of a GET request into out
+func (c *apiClient) get(path string, query url.Values, out any) error {
+	target := strings.TrimSuffix(c.server, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: HTTP %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// fetch reads the occupancy, the spot grid of every floor and the alert sources
+func (c *apiClient) fetch() (snapshot, error) {
+	snap := snapshot{at: time.Now()}
+
+	if err := c.get("/stats/occupancy", nil, &snap.occupancy); err != nil {
+		return snap, err
+	}
+
+	// The API has no current spot state per floor, a heatmap of the last second comes closest:
+	// the utilization of a spot is the share of its capacity in use throughout that second
+	from := snap.at.Add(-time.Second).UTC().Format(time.RFC3339)
+	for _, floor := range snap.occupancy.Floors {
+		var heatmap dto.HeatmapResponse
+		query := url.Values{"floor": {strconv.Itoa(floor.Floor)}, "from": {from}}
+		if err := c.get("/stats/heatmap", query, &heatmap); err != nil {
+			return snap, err
+		}
+		snap.grids = append(snap.grids, toGrid(heatmap))
+	}
+
+	var discrepancies dto.DiscrepancyReportResponse
+	if err := c.get("/admin/discrepancies", nil, &discrepancies); err != nil {
+		return snap, err
+	}
+	snap.discrepancies = discrepancies.Discrepancies
+
+	var queues dto.ExitQueueResponse
+	if err := c.get("/gates/queues", nil, &queues); err != nil {
+		return snap, err
+	}
+	snap.exitQueues = queues.Queues
+
+	return snap, nil
+}
+
+// toGrid converts the cells of a heatmap into spot states
+func toGrid(heatmap dto.HeatmapResponse) grid {
+	spots := make(grid, len(heatmap.Cells))
+	for row, cells := range heatmap.Cells {
+		spots[row] = make([]cell, len(cells))
+		for column, state := range cells {
+			switch {
+			case state == "none":
+				spots[row][column] = cellNone
+			case state == "inactive":
+				spots[row][column] = cellInactive
+			case heatmap.Utilization[row][column] == nil || *heatmap.Utilization[row][column] == 0:
+				spots[row][column] = cellFree
+			case *heatmap.Utilization[row][column] < 0.999:
+				spots[row][column] = cellPartial
+			default:
+				spots[row][column] = cellOccupied
+			}
+		}
+	}
+	return spots
+}
@@ -0,0 +1,35 @@
+// Command dashboard is a terminal dashboard of a running server for the garage office: live
+// occupancy per floor, the spot grid of a floor, recent spot changes and alerts.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func main() {
+	var server, token string
+	var refresh time.Duration
+	flags := flag.NewFlagSet("dashboard", flag.ExitOnError)
+	flags.StringVar(&server, "server", "http://localhost:8080", "API base URL")
+	flags.StringVar(&token, "token", os.Getenv("DASHBOARD_TOKEN"), "attendant bearer token for the sensor discrepancies (DASHBOARD_TOKEN)")
+	flags.DurationVar(&refresh, "refresh", 2*time.Second, "how often the lot state is read")
+	flags.Parse(os.Args[1:])
+
+	if refresh <= 0 {
+		log.Fatalf("Error: refresh must be positive\n")
+	}
+
+	m := model{
+		client:  &apiClient{server: server, token: token, http: &http.Client{Timeout: 5 * time.Second}},
+		refresh: refresh,
+	}
+	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {
+		log.Fatalf("Error running dashboard: %v\n", err)
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxEvents is the number of recent events shown
+const maxEvents = 10
+
+// snapshotMsg carries the result of a fetch
+type snapshotMsg struct {
+	snapshot snapshot
+	err      error
+}
+
+// tickMsg triggers the next fetch
+type tickMsg struct{}
+
+// model is the dashboard state
+type model struct {
+	client  *apiClient
+	refresh time.Duration
+
+	current *snapshot
+	err     error
+	floor   int      // floor whose grid is shown
+	events  []string // most recent first
+}
+
+func (m model) Init() tea.Cmd {
+	return m.fetch
+}
+
+// fetch reads a snapshot in the background
+func (m model) fetch() tea.Msg {
+	snap, err := m.client.fetch()
+	return snapshotMsg{snapshot: snap, err: err}
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "ctrl+c", "esc":
+			return m, tea.Quit
+		case "right", "l", "tab":
+			if m.current != nil && m.floor < len(m.current.grids)-1 {
+				m.floor++
+			}
+		case "left", "h", "shift+tab":
+			if m.floor > 0 {
+				m.floor--
+			}
+		}
+		return m, nil
+
+	case snapshotMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			if m.current != nil {
+				m.events = append(changes(*m.current, msg.snapshot), m.events...)
+				if len(m.events) > maxEvents {
+					m.events = m.events[:maxEvents]
+				}
+			}
+			m.current = &msg.snapshot
+			if m.floor >= len(m.current.grids) {
+				m.floor = max(0, len(m.current.grids)-1)
+			}
+		}
+		return m, tea.Tick(m.refresh, func(time.Time) tea.Msg { return tickMsg{} })
+
+	case tickMsg:
+		return m, m.fetch
+	}
+
+	return m, nil
+}
+
+// changes lists the spots that were taken or vacated between two snapshots as events,
+// spots that changed and changed back within the refresh interval are missed
+func changes(before, after snapshot) []string {
+	events := []string{}
+	at := after.at.Format("15:04:05")
+	for floor := range min(len(before.grids), len(after.grids)) {
+		for row := range min(len(before.grids[floor]), len(after.grids[floor])) {
+			for column := range min(len(before.grids[floor][row]), len(after.grids[floor][row])) {
+				was, is := before.grids[floor][row][column], after.grids[floor][row][column]
+				switch {
+				case was == is:
+				case is == cellFree && (was == cellOccupied || was == cellPartial):
+					events = append(events, fmt.Sprintf("%s  floor %d row %d column %d vacated", at, floor, row, column))
+				case is == cellOccupied || is == cellPartial:
+					events = append(events, fmt.Sprintf("%s  floor %d row %d column %d taken", at, floor, row, column))
+				default:
+					events = append(events, fmt.Sprintf("%s  floor %d row %d column %d reconfigured", at, floor, row, column))
+				}
+			}
+		}
+	}
+	return events
+}
+
+// alerts lists the conditions an attendant should look at
+func alerts(snap snapshot) []string {
+	alerts := []string{}
+	for _, floor := range snap.occupancy.Floors {
+		if floor.Capacity > 0 && floor.Occupied >= floor.Capacity {
+			alerts = append(alerts, fmt.Sprintf("floor %d is full", floor.Floor))
+		}
+	}
+	for _, discrepancy := range snap.discrepancies {
+		alerts = append(alerts, fmt.Sprintf("sensor disagrees at spot %s: %s", discrepancy.SpotID, discrepancy.Kind))
+	}
+	for _, queue := range snap.exitQueues {
+		if queue.Length > 0 {
+			alerts = append(alerts, fmt.Sprintf("%d vehicles queue at exit gate %d", queue.Length, queue.Gate))
+		}
+	}
+	return alerts
+}
+
+func (m model) View() string {
+	var out strings.Builder
+	out.WriteString("Parking lot dashboard  " + m.client.server + "\n\n")
+
+	if m.current == nil {
+		if m.err != nil {
+			return out.String() + "Error: " + m.err.Error() + "\n\nq quit\n"
+		}
+		return out.String() + "Loading...\n"
+	}
+	snap := m.current
+
+	// Occupancy per floor
+	overall := snap.occupancy.Overall
+	fmt.Fprintf(&out, "Overall  %s %3.0f%%  %d/%d\n", bar(overall.Percentage), overall.Percentage, overall.Occupied, overall.Capacity)
+	for _, floor := range snap.occupancy.Floors {
+		fmt.Fprintf(&out, "Floor %d  %s %3.0f%%  %d/%d\n", floor.Floor, bar(floor.Percentage), floor.Percentage, floor.Occupied, floor.Capacity)
+	}
+
+	// Spot grid of the selected floor
+	if len(snap.grids) > 0 {
+		fmt.Fprintf(&out, "\nFloor %d   . free  # occupied  %% partly occupied  x closed\n", m.floor)
+		for _, row := range snap.grids[m.floor] {
+			out.WriteString("  ")
+			for _, spot := range row {
+				out.WriteString(symbols[spot] + " ")
+			}
+			out.WriteString("\n")
+		}
+	}
+
+	out.WriteString("\nRecent events\n")
+	if len(m.events) == 0 {
+		out.WriteString("  none yet\n")
+	}
+	for _, event := range m.events {
+		out.WriteString("  " + event + "\n")
+	}
+
+	out.WriteString("\nAlerts\n")
+	current := alerts(*snap)
+	if len(current) == 0 {
+		out.WriteString("  none\n")
+	}
+	for _, alert := range current {
+		out.WriteString("  ! " + alert + "\n")
+	}
+
+	fmt.Fprintf(&out, "\nUpdated %s", snap.at.Format("15:04:05"))
+	if m.err != nil {
+		out.WriteString("  (last refresh failed: " + m.err.Error() + ")")
+	}
+	out.WriteString("\n←/→ floor  q quit\n")
+
+	return out.String()
+}
+
+// symbols of the cell states in the grid
+var symbols = map[cell]string{
+	cellNone:     " ",
+	cellInactive: "x",
+	cellFree:     ".",
+	cellPartial:  "%",
+	cellOccupied: "#",
+}
+
+// bar draws a percentage as a 20 character bar
+func bar(percentage float64) string {
+	filled := min(20, max(0, int(percentage/5+0.5)))
+	return "[" + strings.Repeat("█", filled) + strings.Repeat(" ", 20-filled) + "]"
+}
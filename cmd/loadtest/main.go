@@ -0,0 +1,160 @@
+// Command loadtest fires concurrent park, search and unpark requests at a running server
+// and reports the latency percentiles and error rates of each, e.g. for capacity planning.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// operations in the order they are reported
+var operations = []string{"park", "search", "unpark"}
+
+// result is the outcome of a single request
+type result struct {
+	operation string
+	latency   time.Duration
+	status    int // 0 when the request failed before a response
+}
+
+// opStats collects the results of an operation
+type opStats struct {
+	latencies []time.Duration
+	byClass   map[string]int // 2xx, 4xx, 5xx, or error when there was no response
+}
+
+func main() {
+	var baseURL, types string
+	var concurrency int
+	var duration time.Duration
+	flags := flag.NewFlagSet("loadtest", flag.ExitOnError)
+	flags.StringVar(&baseURL, "url", "http://localhost:8080", "server to load")
+	flags.IntVar(&concurrency, "concurrency", 10, "number of concurrent clients")
+	flags.DurationVar(&duration, "duration", 30*time.Second, "how long to fire requests")
+	flags.StringVar(&types, "types", "Automobile,Motorcycle,Bicycle", "comma separated vehicle types to park")
+	flags.Parse(os.Args[1:])
+
+	if concurrency < 1 || duration <= 0 {
+		log.Fatalf("Error: concurrency and duration must be positive\n")
+	}
+	vehicleTypes := strings.Split(types, ",")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	results := make(chan result, concurrency*len(operations))
+	deadline := time.Now().Add(duration)
+
+	// Every client parks a vehicle, searches it and unparks it again until the deadline
+	var clients sync.WaitGroup
+	for c := 0; c < concurrency; c++ {
+		clients.Add(1)
+		go func(c int) {
+			defer clients.Done()
+			random := rand.New(rand.NewSource(int64(c)))
+			for i := 0; time.Now().Before(deadline); i++ {
+				vehicleNumber := fmt.Sprintf("LT%03d%06d", c, i)
+				vehicleType := vehicleTypes[random.Intn(len(vehicleTypes))]
+
+				park := send(client, "park", http.MethodPost, baseURL+"/park",
+					map[string]string{"vehicleType": vehicleType, "vehicleNumber": vehicleNumber})
+				results <- park
+				if park.status != http.StatusOK {
+					continue
+				}
+
+				results <- send(client, "search", http.MethodGet, baseURL+"/search?vehicleNumber="+url.QueryEscape(vehicleNumber), nil)
+				results <- send(client, "unpark", http.MethodPost, baseURL+"/unpark",
+					map[string]string{"vehicleNumber": vehicleNumber})
+			}
+		}(c)
+	}
+	go func() {
+		clients.Wait()
+		close(results)
+	}()
+
+	stats := make(map[string]*opStats, len(operations))
+	for _, operation := range operations {
+		stats[operation] = &opStats{byClass: make(map[string]int)}
+	}
+	for res := range results {
+		opStats := stats[res.operation]
+		opStats.latencies = append(opStats.latencies, res.latency)
+		class := "error"
+		if res.status > 0 {
+			class = fmt.Sprintf("%dxx", res.status/100)
+		}
+		opStats.byClass[class]++
+	}
+
+	report(stats, duration)
+}
+
+// send performs a request with an optional JSON body and measures its latency
+func send(client *http.Client, operation, method, target string, body any) result {
+	var reader io.Reader
+	if body != nil {
+		data, _ := json.Marshal(body)
+		reader = bytes.NewReader(data)
+	}
+
+	start := time.Now()
+	req, err := http.NewRequest(method, target, reader)
+	if err != nil {
+		return result{operation: operation}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return result{operation: operation, latency: time.Since(start)}
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return result{operation: operation, latency: time.Since(start), status: resp.StatusCode}
+}
+
+// report prints the throughput, latency percentiles and error rates of every operation
+func report(stats map[string]*opStats, duration time.Duration) {
+	fmt.Printf("%-8s %8s %8s %10s %10s %10s %10s %7s %7s %7s\n",
+		"", "requests", "req/s", "p50", "p90", "p99", "max", "4xx", "5xx", "errors")
+	for _, operation := range operations {
+		opStats := stats[operation]
+		count := len(opStats.latencies)
+		if count == 0 {
+			fmt.Printf("%-8s %8d\n", operation, 0)
+			continue
+		}
+
+		sort.Slice(opStats.latencies, func(i, j int) bool { return opStats.latencies[i] < opStats.latencies[j] })
+		rate := func(class string) string {
+			return fmt.Sprintf("%.1f%%", float64(opStats.byClass[class])*100/float64(count))
+		}
+		fmt.Printf("%-8s %8d %8.1f %10v %10v %10v %10v %7s %7s %7s\n",
+			operation, count, float64(count)/duration.Seconds(),
+			percentile(opStats.latencies, 0.50), percentile(opStats.latencies, 0.90),
+			percentile(opStats.latencies, 0.99), opStats.latencies[count-1].Round(time.Microsecond),
+			rate("4xx"), rate("5xx"), rate("error"))
+	}
+}
+
+// percentile returns the latency below which the given fraction of the sorted latencies fall
+func percentile(sorted []time.Duration, fraction float64) time.Duration {
+	index := int(fraction*float64(len(sorted))+0.5) - 1
+	index = max(0, min(index, len(sorted)-1))
+	return sorted[index].Round(time.Microsecond)
+}
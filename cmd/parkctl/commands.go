@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"parking-lot-system/internal/api/dto"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+func parkCommand(opts *options) *cobra.Command {
+	req := dto.ParkRequest{}
+	cmd := &cobra.Command{
+		Use:   "park VEHICLE_TYPE VEHICLE_NUMBER",
+		Short: "Park a vehicle at the spot the lot assigns",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.VehicleType, req.VehicleNumber = args[0], args[1]
+
+			var resp dto.ParkResponse
+			if err := opts.call(http.MethodPost, "/park", nil, req, &resp); err != nil {
+				return err
+			}
+
+			// duplicate_pending: the spot is held until an operator confirms the vehicle
+			if resp.HoldID != 0 {
+				return opts.print(resp, []string{"HOLD", "CODE", "MESSAGE"},
+					[][]string{{strconv.Itoa(resp.HoldID), resp.Code, resp.Error}})
+			}
+			return opts.print(resp, []string{"SPOT"}, [][]string{{resp.SpotID}})
+		},
+	}
+	cmd.Flags().IntVar(&req.Gate, "gate", 0, "entry gate")
+	cmd.Flags().StringVar(&req.Zone, "zone", "", "preferred zone")
+	cmd.Flags().StringVar(&req.Size, "size", "", "size class: compact, standard or large")
+	return cmd
+}
+
+func unparkCommand(opts *options) *cobra.Command {
+	req := dto.UnparkRequest{}
+	cmd := &cobra.Command{
+		Use:   "unpark VEHICLE_NUMBER",
+		Short: "Unpark a vehicle",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			req.VehicleNumber = args[0]
+
+			var resp dto.UnparkResponse
+			if err := opts.call(http.MethodPost, "/unpark", nil, req, &resp); err != nil {
+				return err
+			}
+
+			row := []string{resp.SpotID, "-", "-"}
+			if resp.QueuePosition > 0 {
+				row[1] = strconv.Itoa(resp.QueuePosition)
+				row[2] = fmt.Sprintf("%ds", resp.EstimatedWaitSeconds)
+			}
+			return opts.print(resp, []string{"SPOT", "QUEUE POSITION", "WAIT"}, [][]string{row})
+		},
+	}
+	cmd.Flags().StringVar(&req.SpotID, "spot", "", "spot the vehicle is parked at, checked when given")
+	cmd.Flags().IntVar(&req.Gate, "gate", 0, "exit gate")
+	return cmd
+}
+
+func availableCommand(opts *options) *cobra.Command {
+	var zone, size string
+	cmd := &cobra.Command{
+		Use:   "available VEHICLE_TYPE",
+		Short: "List the available spots for a vehicle type",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{"vehicleType": {args[0]}}
+			if zone != "" {
+				query.Set("zone", zone)
+			}
+			if size != "" {
+				query.Set("size", size)
+			}
+
+			var resp dto.AvailableSpotResponse
+			if err := opts.call(http.MethodGet, "/available", query, nil, &resp); err != nil {
+				return err
+			}
+
+			rows := make([][]string, len(resp.Spots))
+			for i, spot := range resp.Spots {
+				rows[i] = []string{spot}
+			}
+			return opts.print(resp, []string{"SPOT"}, rows)
+		},
+	}
+	cmd.Flags().StringVar(&zone, "zone", "", "only spots in this zone")
+	cmd.Flags().StringVar(&size, "size", "", "only spots a vehicle of this size class may use")
+	return cmd
+}
+
+func searchCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "search VEHICLE_NUMBER",
+		Short: "Find where a vehicle is or was last parked",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var resp dto.SearchVehicleResponse
+			if err := opts.call(http.MethodGet, "/search", url.Values{"vehicleNumber": {args[0]}}, nil, &resp); err != nil {
+				return err
+			}
+
+			return opts.print(resp, []string{"SPOT", "PARKED", "OPEN VIOLATIONS"},
+				[][]string{{resp.SpotID, strconv.FormatBool(resp.IsParked), strconv.Itoa(len(resp.Violations))}})
+		},
+	}
+}
+
+func adminCommand(opts *options) *cobra.Command {
+	admin := &cobra.Command{
+		Use:   "admin",
+		Short: "Attendant commands, they need --token",
+	}
+	layout := &cobra.Command{
+		Use:   "layout",
+		Short: "Manage the lot layout",
+	}
+	layout.AddCommand(&cobra.Command{
+		Use:   "reload",
+		Short: "Apply the changed spot configuration of the server's layout file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var resp dto.LayoutReloadResponse
+			err := opts.call(http.MethodPost, "/admin/layout/reload", nil, nil, &resp)
+			if err != nil {
+				return err
+			}
+			return opts.printLayoutReport(resp)
+		},
+	})
+	layout.AddCommand(&cobra.Command{
+		Use:   "export [FILE]",
+		Short: "Save the layout the server runs, to FILE or to stdout",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := opts.send(http.MethodGet, "/admin/layout", nil, "", nil)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				_, err = opts.stdout.Write(data)
+				return err
+			}
+			return os.WriteFile(args[0], data, 0o644)
+		},
+	})
+	layout.AddCommand(&cobra.Command{
+		Use:   "import FILE",
+		Short: "Replace the server's layout file with FILE and apply its spot configuration",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			body, err := opts.send(http.MethodPut, "/admin/layout", nil, "application/yaml", bytes.NewReader(data))
+			if err != nil {
+				return err
+			}
+			var resp dto.LayoutReloadResponse
+			if err := json.Unmarshal(body, &resp); err != nil {
+				return err
+			}
+			return opts.printLayoutReport(resp)
+		},
+	})
+	admin.AddCommand(layout)
+	return admin
+}
+
+// printLayoutReport prints the spots a layout change reconfigured or skipped
+func (o *options) printLayoutReport(resp dto.LayoutReloadResponse) error {
+	rows := [][]string{}
+	for _, spotID := range resp.Changed {
+		rows = append(rows, []string{spotID, "changed", ""})
+	}
+	for _, skipped := range resp.Skipped {
+		rows = append(rows, []string{skipped.SpotID, "skipped", skipped.Error})
+	}
+	return o.print(resp, []string{"SPOT", "RESULT", "REASON"}, rows)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"parking-lot-system/internal/api/dto"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testLayout = "gates: 1\nfloors:\n  - rows: 1\n    columns: 2\n"
+
+// newLayoutServer serves GET and PUT /admin/layout for the token "att", keeping the layout it was last sent
+func newLayoutServer(t *testing.T) (*httptest.Server, *[]byte) {
+	t.Helper()
+
+	stored := []byte(testLayout)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/admin/layout" || r.Header.Get("Authorization") != "Bearer att" {
+			http.Error(w, `{"error":"unauthorized","code":"unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/yaml")
+			w.Write(stored)
+		case http.MethodPut:
+			if r.Header.Get("Content-Type") != "application/yaml" {
+				t.Errorf("import: got Content-Type %q, want application/yaml", r.Header.Get("Content-Type"))
+			}
+			data, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			if strings.Contains(string(data), "columns: 3") {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(w).Encode(dto.LayoutReloadResponse{Error: "floors and gates cannot change while the lot is running"})
+				return
+			}
+			stored = data
+			json.NewEncoder(w).Encode(dto.LayoutReloadResponse{Changed: []string{"F0-R0-C1"}, Skipped: []dto.SkippedSpot{}})
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &stored
+}
+
+// runAdmin runs an admin command and returns what it printed
+func runAdmin(t *testing.T, server string, args ...string) (string, error) {
+	t.Helper()
+
+	var stdout bytes.Buffer
+	cmd := adminCommand(&options{server: server, token: "att", output: "table", stdout: &stdout})
+	cmd.SetArgs(args)
+	cmd.SilenceUsage, cmd.SilenceErrors = true, true
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+func TestLayoutExport(t *testing.T) {
+	server, _ := newLayoutServer(t)
+
+	out, err := runAdmin(t, server.URL, "layout", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != testLayout {
+		t.Errorf("export to stdout: got %q, want the server's layout", out)
+	}
+
+	path := filepath.Join(t.TempDir(), "layout.yaml")
+	if _, err := runAdmin(t, server.URL, "layout", "export", path); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != testLayout {
+		t.Errorf("export to a file: got %q, want the server's layout", data)
+	}
+}
+
+func TestLayoutImport(t *testing.T) {
+	server, stored := newLayoutServer(t)
+	path := filepath.Join(t.TempDir(), "layout.yaml")
+
+	changed := testLayout + "spots:\n  - columns: 1\n    type: M-1\n"
+	if err := os.WriteFile(path, []byte(changed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runAdmin(t, server.URL, "layout", "import", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(*stored) != changed {
+		t.Errorf("import: server got %q, want the file", *stored)
+	}
+	if !strings.Contains(out, "F0-R0-C1") || !strings.Contains(out, "changed") {
+		t.Errorf("import: got output %q, want the changed spot", out)
+	}
+
+	// a layout the server rejects fails with its reason
+	if err := os.WriteFile(path, []byte(strings.Replace(testLayout, "columns: 2", "columns: 3", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = runAdmin(t, server.URL, "layout", "import", path)
+	if err == nil || !strings.Contains(err.Error(), "cannot change") {
+		t.Errorf("import of a rejected layout: got error %v, want the server's reason", err)
+	}
+}
@@ -0,0 +1,88 @@
+// Command parkctl is a command-line client of the parking lot HTTP API for operators and scripts.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// options shared by all commands
+type options struct {
+	server string
+	token  string
+	output string    // table or json
+	stdout io.Writer // where responses are printed
+}
+
+func main() {
+	opts := &options{stdout: os.Stdout}
+	root := &cobra.Command{
+		Use:           "parkctl",
+		Short:         "Command-line client of the parking lot API",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if opts.output != "table" && opts.output != "json" {
+				return fmt.Errorf("invalid output format %q: must be table or json", opts.output)
+			}
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVar(&opts.server, "server", envOr("PARKCTL_SERVER", "http://localhost:8080"), "API base URL (PARKCTL_SERVER)")
+	root.PersistentFlags().StringVar(&opts.token, "token", os.Getenv("PARKCTL_TOKEN"), "attendant bearer token for admin commands (PARKCTL_TOKEN)")
+	root.PersistentFlags().StringVarP(&opts.output, "output", "o", "table", "output format: table or json")
+
+	root.AddCommand(
+		parkCommand(opts),
+		unparkCommand(opts),
+		availableCommand(opts),
+		searchCommand(opts),
+		adminCommand(opts),
+	)
+
+	if err := root.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// envOr returns an environment variable, or the fallback when it is empty
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// print writes a response as indented JSON, or as a table of the given header and rows
+func (o *options) print(resp any, header []string, rows [][]string) error {
+	if o.output == "json" {
+		encoder := json.NewEncoder(o.stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(resp)
+	}
+
+	table := tabwriter.NewWriter(o.stdout, 0, 0, 2, ' ', 0)
+	for i, cell := range header {
+		if i > 0 {
+			fmt.Fprint(table, "\t")
+		}
+		fmt.Fprint(table, cell)
+	}
+	fmt.Fprintln(table)
+	for _, row := range rows {
+		for i, cell := range row {
+			if i > 0 {
+				fmt.Fprint(table, "\t")
+			}
+			fmt.Fprint(table, cell)
+		}
+		fmt.Fprintln(table)
+	}
+	return table.Flush()
+}
@@ -0,0 +1,245 @@
+// Command replay rebuilds the lot state from the event log file the server writes with
+// EVENT_LOG_FILE, up to a moment, e.g. to restore the parked vehicles after a crash or to check
+// that replaying the events gives the state the live server reports.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/config"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/domain/spotid"
+	"parking-lot-system/internal/eventlog"
+	"parking-lot-system/internal/layout"
+	"parking-lot-system/internal/repository"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+func main() {
+	var eventFile, layoutFile, atValue, server, token string
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	flags.StringVar(&eventFile, "events", os.Getenv("EVENT_LOG_FILE"), "event log file written by the server (EVENT_LOG_FILE)")
+	flags.StringVar(&layoutFile, "layout", "configs/layout.yaml", "lot layout file the events happened in")
+	flags.StringVar(&atValue, "at", "", "RFC 3339 moment to rebuild the state at (default now)")
+	flags.StringVar(&server, "compare", "", "base URL of a live server whose /admin/state is compared with the replayed state")
+	flags.StringVar(&token, "token", "", "attendant bearer token for -compare")
+	flags.Parse(os.Args[1:])
+
+	if eventFile == "" {
+		log.Fatalf("Error: -events is required\n")
+	}
+	at := time.Now()
+	if atValue != "" {
+		parsed, err := time.Parse(time.RFC3339, atValue)
+		if err != nil {
+			log.Fatalf("Error parsing -at: %v\n", err)
+		}
+		at = parsed
+	}
+
+	events, err := eventlog.ReadFile(eventFile)
+	if err != nil {
+		log.Fatalf("Error reading events: %v\n", err)
+	}
+	parked := make(map[string]parking.Event)
+	replayed := 0
+	for _, event := range events {
+		if event.Time.After(at) {
+			continue
+		}
+		parking.ReplayEvent(parked, event)
+		replayed++
+	}
+
+	repo, service, err := newLot(layoutFile)
+	if err != nil {
+		log.Fatalf("Error creating parking lot: %v\n", err)
+	}
+	failed := restore(repo, parked)
+
+	fmt.Printf("replayed %d of %d events up to %s\n\n", replayed, len(events), at.Format(time.RFC3339))
+	printOccupancy(service)
+
+	if server != "" {
+		live, err := fetchState(server, token, at)
+		if err != nil {
+			log.Fatalf("Error fetching the live state: %v\n", err)
+		}
+		if differences := compare(parked, live); differences > 0 {
+			fmt.Printf("\n%d differences with %s\n", differences, server)
+			os.Exit(1)
+		}
+		fmt.Printf("\nthe replayed state matches %s\n", server)
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// newLot creates an empty lot described by the layout file, with the vehicle types, spot types
+// and spot ID format of the server's environment variables, so the spot IDs of the events resolve
+func newLot(layoutFile string) (repository.ParkingRepository, *parking.ParkingService, error) {
+	cfg := config.NewAppConfig()
+
+	spotIDs := spotid.Default
+	if cfg.SpotIDFormat != "" {
+		pattern, err := spotid.NewPattern(cfg.SpotIDFormat, cfg.SpotIDBase)
+		if err != nil {
+			return nil, nil, err
+		}
+		spotIDs = pattern
+	}
+	repo := repository.NewParkingRepository(spotIDs)
+	service := parking.NewParkingService(repo)
+
+	vehicleTypes := make([]parking.VehicleType, len(cfg.VehicleTypes))
+	for i, vehicleType := range cfg.VehicleTypes {
+		vehicleTypes[i] = parking.VehicleType{Name: vehicleType.Name, SpotCode: vehicleType.SpotCode, Size: vehicleType.Size, Rate: vehicleType.Rate}
+	}
+	if err := service.RegisterVehicleTypes(vehicleTypes); err != nil {
+		return nil, nil, err
+	}
+
+	spotTypes := make([]parking.SpotType, len(cfg.SpotTypes))
+	for i, spotType := range cfg.SpotTypes {
+		spotTypes[i] = parking.SpotType{
+			Code:        spotType.Code,
+			VehicleType: spotType.VehicleType,
+			Class:       spotType.Class,
+			IsActive:    spotType.VehicleType != "",
+			Exists:      true,
+		}
+	}
+	if err := service.RegisterSpotTypes(spotTypes); err != nil {
+		return nil, nil, err
+	}
+
+	return repo, service, layout.NewManager(layoutFile, service).Load()
+}
+
+// restore parks the replayed vehicles at their spots and returns how many could not be parked,
+// e.g. because the layout changed since
+func restore(repo repository.ParkingRepository, parked map[string]parking.Event) int {
+	vehicles := make([]parking.Event, 0, len(parked))
+	for _, event := range parked {
+		vehicles = append(vehicles, event)
+	}
+	sort.Slice(vehicles, func(i, j int) bool { return vehicles[i].ID < vehicles[j].ID })
+
+	failed := 0
+	for _, event := range vehicles {
+		if err := repo.ParkVehicle(event.SpotID, event.VehicleNumber, event.VehicleType); err != nil {
+			fmt.Printf("cannot restore %s at %s: %v\n", event.VehicleNumber, event.SpotID, err)
+			failed++
+		}
+	}
+	return failed
+}
+
+// printOccupancy prints the occupancy of the rebuilt lot per floor and vehicle type
+func printOccupancy(service *parking.ParkingService) {
+	stats := service.GetOccupancyStats()
+
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "floor\toccupied\tcapacity")
+	for floor, count := range stats.Floors {
+		fmt.Fprintf(table, "%d\t%d\t%d\n", floor, count.Occupied, count.Capacity)
+	}
+	fmt.Fprintf(table, "total\t%d\t%d\n", stats.Total.Occupied, stats.Total.Capacity)
+	table.Flush()
+
+	vehicleTypes := make([]string, 0, len(stats.VehicleTypes))
+	for vehicleType := range stats.VehicleTypes {
+		vehicleTypes = append(vehicleTypes, vehicleType)
+	}
+	sort.Strings(vehicleTypes)
+
+	fmt.Println()
+	table = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "vehicle type\toccupied\tcapacity")
+	for _, vehicleType := range vehicleTypes {
+		count := stats.VehicleTypes[vehicleType]
+		fmt.Fprintf(table, "%s\t%d\t%d\n", vehicleType, count.Occupied, count.Capacity)
+	}
+	table.Flush()
+}
+
+// fetchState returns the vehicles a live server reports parked at a moment, vehicle number -> spot
+func fetchState(server, token string, at time.Time) (map[string]string, error) {
+	target := strings.TrimSuffix(server, "/") + "/admin/state?" + url.Values{"at": {at.Format(time.RFC3339Nano)}}.Encode()
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&failure)
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, failure.Error)
+	}
+
+	var state dto.LotStateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return nil, err
+	}
+	live := make(map[string]string, len(state.Vehicles))
+	for _, vehicle := range state.Vehicles {
+		live[vehicle.VehicleNumber] = vehicle.SpotID
+	}
+	return live, nil
+}
+
+// compare prints the vehicles the replayed and the live state disagree on and returns their number
+func compare(parked map[string]parking.Event, live map[string]string) int {
+	vehicleNumbers := make(map[string]bool)
+	for vehicleNumber := range parked {
+		vehicleNumbers[vehicleNumber] = true
+	}
+	for vehicleNumber := range live {
+		vehicleNumbers[vehicleNumber] = true
+	}
+	sorted := make([]string, 0, len(vehicleNumbers))
+	for vehicleNumber := range vehicleNumbers {
+		sorted = append(sorted, vehicleNumber)
+	}
+	sort.Strings(sorted)
+
+	differences := 0
+	for _, vehicleNumber := range sorted {
+		replayedSpot, liveSpot := parked[vehicleNumber].SpotID, live[vehicleNumber]
+		if replayedSpot == liveSpot {
+			continue
+		}
+		differences++
+		switch {
+		case liveSpot == "":
+			fmt.Printf("%s: replayed at %s, not parked on the server\n", vehicleNumber, replayedSpot)
+		case replayedSpot == "":
+			fmt.Printf("%s: not replayed, parked at %s on the server\n", vehicleNumber, liveSpot)
+		default:
+			fmt.Printf("%s: replayed at %s, parked at %s on the server\n", vehicleNumber, replayedSpot, liveSpot)
+		}
+	}
+	return differences
+}
@@ -1,57 +1,335 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"os"
+	"parking-lot-system/internal/alert"
 	"parking-lot-system/internal/api/handler"
+	"parking-lot-system/internal/auth"
 	"parking-lot-system/internal/config"
+	"parking-lot-system/internal/devicelink"
 	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/domain/plate"
+	"parking-lot-system/internal/domain/spotid"
+	"parking-lot-system/internal/eventlog"
+	"parking-lot-system/internal/federation"
+	"parking-lot-system/internal/gate"
+	"parking-lot-system/internal/i18n"
+	"parking-lot-system/internal/layout"
+	"parking-lot-system/internal/mqtt"
+	"parking-lot-system/internal/notify"
 	"parking-lot-system/internal/repository"
+	"parking-lot-system/internal/templates"
+	"parking-lot-system/internal/wallet"
+	"parking-lot-system/internal/webhook"
+	"syscall"
+	"time"
+	// the lot's time zone is found on hosts without a time zone database, e.g. scratch images
+	_ "time/tzdata"
 )
 
 func main() {
-	// Load configuration
+	// Load configuration from environment variables, overridden by command-line flags
 	cfg := config.NewAppConfig()
+	if err := cfg.ParseFlags(os.Args[1:]); err != nil {
+		log.Fatalf("Error parsing flags: %v\n", err)
+	}
+
+	// Report every configuration problem at once instead of failing on the first one
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v\n", err)
+	}
+
+	logLevel, err := cfg.SlogLevel()
+	if err != nil {
+		log.Fatalf("Error configuring logging: %v\n", err)
+	}
 
-	parkingRepo := repository.NewParkingRepository()
+	// Reports, notifications, API responses and logs use the lot's time zone rather than the host's
+	location, err := cfg.Location()
+	if err != nil {
+		log.Fatalf("Error loading time zone: %v\n", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: logLevel,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if attr.Key == slog.TimeKey && len(groups) == 0 {
+				attr.Value = slog.TimeValue(attr.Value.Time().In(location))
+			}
+			return attr
+		},
+	}))
+	slog.SetDefault(logger)
+
+	// Spot IDs follow the lot's signage when a format is configured
+	spotIDs := spotid.Default
+	if cfg.SpotIDFormat != "" {
+		pattern, err := spotid.NewPattern(cfg.SpotIDFormat, cfg.SpotIDBase)
+		if err != nil {
+			log.Fatalf("Error parsing spot ID format: %v\n", err)
+		}
+		spotIDs = pattern
+	}
+
+	var parkingRepo repository.ParkingRepository
+	switch cfg.StorageDriver {
+	case "memory":
+		parkingRepo = repository.NewParkingRepository(spotIDs)
+	default:
+		log.Fatalf("Error configuring storage: unsupported storage driver %q\n", cfg.StorageDriver)
+	}
 
 	parkingService := parking.NewParkingService(parkingRepo)
+	parkingService.SetLocation(location)
+	parkingService.SetAllowLargerSpots(cfg.AllowLargerSpots)
+	parkingService.SetHoldTTL(cfg.HoldTTL)
+	parkingService.SetReentryCooldown(cfg.ReentryCooldown)
+	parkingService.SetUnusedSpotWindow(cfg.UnusedSpotWindow)
+	if err := parkingService.SetDuplicatePolicy(cfg.DuplicatePolicy); err != nil {
+		log.Fatalf("Error configuring duplicate policy: %v\n", err)
+	}
+
+	// Lots may accept vehicle types beyond the built-in ones, e.g. trucks or EVs
+	vehicleTypes := make([]parking.VehicleType, len(cfg.VehicleTypes))
+	for i, vehicleType := range cfg.VehicleTypes {
+		vehicleTypes[i] = parking.VehicleType{
+			Name:     vehicleType.Name,
+			SpotCode: vehicleType.SpotCode,
+			Size:     vehicleType.Size,
+			Rate:     vehicleType.Rate,
+		}
+	}
+	if err := parkingService.RegisterVehicleTypes(vehicleTypes); err != nil {
+		log.Fatalf("Error configuring vehicle types: %v\n", err)
+	}
+
+	// Spot classes such as EV charging or accessible spots are configured as spot types
+	spotTypes := make([]parking.SpotType, len(cfg.SpotTypes))
+	for i, spotType := range cfg.SpotTypes {
+		spotTypes[i] = parking.SpotType{
+			Code:        spotType.Code,
+			VehicleType: spotType.VehicleType,
+			Class:       spotType.Class,
+			IsActive:    spotType.VehicleType != "",
+			Exists:      true,
+		}
+	}
+	if err := parkingService.RegisterSpotTypes(spotTypes); err != nil {
+		log.Fatalf("Error configuring spot types: %v\n", err)
+	}
+	if err := parkingService.SetFallbacks(cfg.Fallbacks); err != nil {
+		log.Fatalf("Error configuring type fallbacks: %v\n", err)
+	}
 
-	// Create a new parking lot with 3 floors, 5 rows, 10 columns, and 2 gates
-	err := parkingService.InitializeParkingLot(3, 5, 10, 2)
+	// Vehicle numbers follow a built-in regional scheme unless a custom pattern is configured
+	plates, err := plate.Lookup(cfg.PlateScheme)
+	if cfg.PlatePattern != "" {
+		plates, err = plate.NewScheme("custom", cfg.PlatePattern, cfg.PlateMinLength, cfg.PlateMaxLength)
+	}
 	if err != nil {
+		log.Fatalf("Error configuring plate scheme: %v\n", err)
+	}
+	parkingService.SetPlateScheme(plates)
+
+	// Persist the events for cmd/replay, from the lot.initialized of the layout on
+	var eventFile *eventlog.FileWriter
+	if cfg.EventLogFile != "" {
+		if eventFile, err = eventlog.OpenFile(cfg.EventLogFile); err != nil {
+			log.Fatalf("Error configuring event log: %v\n", err)
+		}
+		parkingService.AddEventListener(eventFile)
+	}
+
+	// Build the lot described by the layout file, and apply changes of the file on SIGHUP
+	layoutManager := layout.NewManager(cfg.LayoutFile, parkingService)
+	if err := layoutManager.Load(); err != nil {
 		log.Fatalf("Error creating parking lot: %v\n", err)
 	}
+	layoutManager.ReloadOnSignal(syscall.SIGHUP)
+
+	// Periodically compare sensor readings with the logical spot state
+	parkingService.StartReconciliation(cfg.ReconciliationInterval)
+	if err := parkingService.SetSensorPolicy(parking.SensorPolicy{
+		LowBattery:  cfg.SensorLowBattery,
+		MinFirmware: cfg.SensorMinFirmware,
+	}); err != nil {
+		log.Fatalf("Error configuring sensor maintenance: %v\n", err)
+	}
+
+	// Alert on registered devices that stopped sending heartbeats
+	parkingService.SetDeviceStaleAfter(cfg.DeviceStaleAfter)
+	parkingService.StartDeviceMonitor()
 
-	// Configure some spots
-	configureSpots := []struct {
-		floor    int
-		row      int
-		column   int
-		spotType string
-	}{
-		{0, 0, 0, "B-1"}, // Bicycle spot
-		{0, 0, 1, "B-1"}, // Bicycle spot
-		{0, 1, 0, "M-1"}, // Motorcycle spot
-		{0, 1, 1, "M-1"}, // Motorcycle spot
-		{0, 2, 0, "A-1"}, // Automobile spot
-		{0, 2, 1, "A-1"}, // Automobile spot
-		{0, 2, 2, "X-0"}, // Inactive spot
-		{1, 0, 0, "B-1"}, // Bicycle spot
-		{1, 0, 1, "M-1"}, // Motorcycle spot
-		{1, 1, 0, "A-1"}, // Automobile spot
-	}
-
-	for _, cfg := range configureSpots {
-		err := parkingService.ConfigureSpot(cfg.floor, cfg.row, cfg.column, cfg.spotType)
+	// Record the occupancy over time for the dashboard charts
+	parkingService.StartOccupancySampling(cfg.TimeSeriesInterval, cfg.TimeSeriesRetention)
+	parkingService.SetEventLogLimit(cfg.EventLogLimit)
+
+	// Responses are localized with the built-in texts and the deployment's own translations
+	if cfg.MessageCatalog != "" {
+		catalog, err := i18n.LoadCatalog(cfg.MessageCatalog)
 		if err != nil {
-			log.Printf("Error configuring spot at (%d,%d,%d): %v\n",
-				cfg.floor, cfg.row, cfg.column, err)
+			log.Fatalf("Error loading message catalog: %v\n", err)
 		}
+		i18n.AddCatalog(catalog)
+	}
+
+	// Notifications and webhook bodies use the built-in templates unless the template directory overrides them
+	messageTemplates, err := templates.Load(cfg.TemplateDir)
+	if err != nil {
+		log.Fatalf("Error loading templates: %v\n", err)
 	}
+	parkingService.SetTemplates(messageTemplates)
+
+	// Deliver parking events to webhook subscribers
+	webhookDispatcher := webhook.NewDispatcher()
+	webhookDispatcher.SetTemplates(messageTemplates)
+	webhookNetworks, _ := auth.ParseAllowlist(cfg.WebhookAllowedNetworks) // checked by Validate
+	webhookDispatcher.SetAllowedNetworks(webhookNetworks)
+	parkingService.AddEventListener(webhookDispatcher)
+
+	// Publish availability and spot state changes to MQTT for displays and sensor gateways
+	if cfg.MQTTBrokerURL != "" {
+		mqttPublisher, err := mqtt.NewPublisher(cfg.MQTTBrokerURL, cfg.MQTTClientID, cfg.MQTTTopicPrefix, parkingService)
+		if err != nil {
+			log.Fatalf("Error connecting to MQTT broker: %v\n", err)
+		}
+		parkingService.AddEventListener(mqttPublisher)
+	}
+
+	// Notify drivers who leave a contact by email or SMS, e.g. of their reservation or an overstay
+	var router notify.Router
+	if cfg.NotifySMTPURL != "" || cfg.NotifySMSGatewayURL != "" {
+		if cfg.NotifySMTPURL != "" {
+			email, err := notify.NewSMTPNotifier(cfg.NotifySMTPURL)
+			if err != nil {
+				log.Fatalf("Error configuring email notifications: %v\n", err)
+			}
+			router.Email = email
+		}
+		if cfg.NotifySMSGatewayURL != "" {
+			router.SMS = notify.NewSMSGatewayNotifier(cfg.NotifySMSGatewayURL, cfg.NotifySMSGatewayToken)
+		}
+		parkingService.SetNotifier(router)
+	}
+
+	// Alert operators through the sinks routed by alert type and severity, e.g. critical ones to PagerDuty
+	if len(cfg.AlertRoutes) > 0 {
+		routes := make([]alert.Route, len(cfg.AlertRoutes))
+		for i, route := range cfg.AlertRoutes {
+			sink, err := alert.NewSink(route.Sink, route.Target, router.Email)
+			if err != nil {
+				log.Fatalf("Error configuring alerts: %v\n", err)
+			}
+			routes[i] = alert.Route{Name: route.Sink, Types: route.Types, MinSeverity: route.Severity, Sink: sink}
+		}
+		parkingService.SetAlertDispatcher(alert.NewDispatcher(routes))
+	}
+
+	// Warn operators, and optionally drivers with a reservation, when few spots are left for a vehicle type
+	alerts := make([]parking.AvailabilityAlert, len(cfg.AvailabilityAlerts))
+	for i, alert := range cfg.AvailabilityAlerts {
+		alerts[i] = parking.AvailabilityAlert{Contact: alert.Contact, VehicleType: alert.VehicleType, Threshold: alert.Threshold}
+	}
+	if err := parkingService.SetAvailabilityAlerts(alerts, cfg.AvailabilityAlertReservations); err != nil {
+		log.Fatalf("Error configuring availability alerts: %v\n", err)
+	}
+
+	// Close the business day with a report on occupancy, cash taken and sessions needing a look
+	parkingService.SetStuckSessionAfter(cfg.StuckSessionAfter)
+	if cfg.DailyReportRecipient != "" {
+		closeAt, err := cfg.DailyReportOffset()
+		if err != nil {
+			log.Fatalf("Error configuring daily reports: %v\n", err)
+		}
+		parkingService.StartDailyReports(closeAt, cfg.DailyReportRecipient)
+	}
+
+	// Open the barrier of the gate a vehicle passes through
+	controllers := make(map[int]gate.GateController)
+	for number, address := range cfg.GateControllers {
+		controller, err := gate.NewController(address)
+		if err != nil {
+			log.Fatalf("Error configuring gate %d: %v\n", number, err)
+		}
+		controllers[number] = controller
+	}
+	gateManager := gate.NewManager(controllers, cfg.BarrierHoldTime)
+	gateManager.SetThroughput(cfg.GateThroughput)
+	parkingService.AddEventListener(gateManager)
+
+	// Authenticate attendants for the admin endpoints and enforcement staff for the violation endpoints,
+	// with the configured tokens and the ones admins manage at runtime
+	identities := make(map[string]auth.Identity)
+	for token, name := range cfg.AttendantTokens {
+		identities[token] = auth.Identity{Name: name, Roles: []string{auth.RoleAttendant}}
+	}
+	for token, name := range cfg.EnforcementTokens {
+		identities[token] = auth.Identity{Name: name, Roles: []string{auth.RoleEnforcement}}
+	}
+	for token, name := range cfg.AdminTokens {
+		identities[token] = auth.Identity{Name: name, Roles: []string{auth.RoleAdmin}}
+	}
+	tokenStore := auth.NewTokenStore()
+	deviceSessions := auth.NewDeviceSessions(cfg.DeviceAccessTokenTTL, cfg.DeviceRefreshTokenTTL)
+	authenticator := auth.ChainAuthenticator{auth.NewStaticTokenAuthenticator(identities), tokenStore, deviceSessions}
+
+	// Sign staff in with the OpenID Connect provider, granting the roles of their groups
+	var oidcAuthenticator *auth.OIDCAuthenticator
+	if cfg.OIDCIssuer != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		oidcAuthenticator, err = auth.NewOIDCAuthenticator(ctx, auth.OIDCConfig{
+			Issuer:      cfg.OIDCIssuer,
+			Audience:    cfg.OIDCAudience,
+			GroupsClaim: cfg.OIDCGroupsClaim,
+			RoleGroups:  cfg.OIDCRoleGroups,
+		})
+		cancel()
+		if err != nil {
+			log.Fatalf("Error configuring OIDC: %v\n", err)
+		}
+		authenticator = append(authenticator, oidcAuthenticator)
+	}
+
+	// Aggregate the availability of remote instances in federation mode
+	var aggregator *federation.Aggregator
+	if len(cfg.FederationPeers) > 0 {
+		aggregator = federation.NewAggregator(cfg.FederationPeers)
+	}
+
+	// Push commands and incident broadcasts to devices connected over WebSocket
+	commandHub := devicelink.NewHub(devicelink.DefaultAckTimeout)
+	parkingService.AddEventListener(commandHub)
 
 	// Create a new handler with the parking service
-	parkingHandler := handler.NewParkingHandler(parkingService)
+	parkingHandler := handler.NewParkingHandler(parkingService, webhookDispatcher, authenticator, gateManager, aggregator, layoutManager)
+	parkingHandler.SetLogger(logger)
+	parkingHandler.SetLocation(location)
+	parkingHandler.SetTokenStore(tokenStore)
+	parkingHandler.SetDeviceSessions(deviceSessions, cfg.RequireDeviceTokens)
+	parkingHandler.SetCommandHub(commandHub)
+	parkingHandler.SetPermissionRoles(auth.PermissionRefund, cfg.RefundRoles)
+	parkingHandler.SetWalletIssuer(wallet.Issuer{
+		Organization:    cfg.WalletOrganization,
+		ApplePassTypeID: cfg.WalletApplePassTypeID,
+		AppleTeamID:     cfg.WalletAppleTeamID,
+		GoogleIssuerID:  cfg.WalletGoogleIssuerID,
+		GoogleClass:     cfg.WalletGoogleClass,
+	})
+	if oidcAuthenticator != nil {
+		parkingHandler.SetOIDCEndpoints(oidcAuthenticator.Endpoints())
+	}
+	parkingHandler.SetLatencyBudget(cfg.LatencyBudget)
 
-	// Start the HTTP server on port 8080
-	log.Fatal(parkingHandler.StartServer(cfg.ServerPort))
+	// Start the HTTP(S) server on the configured port. log.Fatal exits without running deferred calls,
+	// so the event log is closed before it.
+	err = parkingHandler.StartServer(cfg.ServerPort, cfg.TLSCertFile, cfg.TLSKeyFile)
+	if eventFile != nil {
+		if closeErr := eventFile.Close(); closeErr != nil {
+			log.Printf("Error closing event log: %v", closeErr)
+		}
+	}
+	log.Fatal(err)
 }
@@ -0,0 +1,301 @@
+// Command simulate drives an in-process parking service with random arrivals and departures
+// and prints the occupancy and rejection statistics, e.g. to validate a layout or allocation
+// settings before rollout. Time is simulated, a day of traffic runs in well under a second.
+package main
+
+import (
+	"container/heap"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"parking-lot-system/internal/config"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/domain/spotid"
+	"parking-lot-system/internal/layout"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// event is an arrival or departure at a simulated time
+type event struct {
+	at            time.Duration
+	arrival       bool
+	vehicleType   string
+	vehicleNumber string
+}
+
+// eventQueue orders events by time, it implements heap.Interface
+type eventQueue []event
+
+func (q eventQueue) Len() int           { return len(q) }
+func (q eventQueue) Less(i, j int) bool { return q[i].at < q[j].at }
+func (q eventQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x any)        { *q = append(*q, x.(event)) }
+func (q *eventQueue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+// typeStats counts what happened to the vehicles of a type
+type typeStats struct {
+	arrivals int
+	parked   int
+	rejected int // no spot available
+	failed   int // any other error
+}
+
+func main() {
+	// Defaults, overridden by the scenario file, overridden by the flags given explicitly
+	sc := scenario{
+		Layout:   "configs/layout.yaml",
+		Duration: 24 * time.Hour,
+		Sample:   time.Hour,
+		Rates:    map[string]float64{"Automobile": 20, "Motorcycle": 10, "Bicycle": 5},
+		Stays:    map[string]time.Duration{"Automobile": 2 * time.Hour, "Motorcycle": time.Hour, "Bicycle": 3 * time.Hour},
+	}
+
+	var scenarioFile, rates, stays string
+	var trace bool
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	flags.StringVar(&scenarioFile, "scenario", "", "YAML scenario file")
+	flags.Int64Var(&sc.Seed, "seed", 0, "random seed, runs with the same seed and scenario are identical (default random)")
+	flags.StringVar(&sc.Layout, "layout", sc.Layout, "lot layout file")
+	flags.DurationVar(&sc.Duration, "duration", sc.Duration, "simulated time")
+	flags.DurationVar(&sc.Sample, "sample", sc.Sample, "how often the occupancy is printed")
+	flags.StringVar(&rates, "rates", "Automobile=20,Motorcycle=10,Bicycle=5", "arrivals per hour, comma separated type=rate pairs")
+	flags.StringVar(&stays, "stays", "Automobile=2h,Motorcycle=1h,Bicycle=3h", "mean stay, comma separated type=duration pairs")
+	flags.BoolVar(&trace, "trace", false, "print every park and unpark, e.g. to compare the allocations of two runs")
+	flags.Parse(os.Args[1:])
+
+	if scenarioFile != "" {
+		// The file is read over the defaults, the flags given explicitly are applied again on top
+		given := make(map[string]bool)
+		flags.Visit(func(f *flag.Flag) { given[f.Name] = true })
+
+		fromFlags := sc
+		if err := loadScenario(scenarioFile, &sc); err != nil {
+			log.Fatalf("Error loading scenario: %v\n", err)
+		}
+		if given["seed"] {
+			sc.Seed = fromFlags.Seed
+		}
+		if given["layout"] {
+			sc.Layout = fromFlags.Layout
+		}
+		if given["duration"] {
+			sc.Duration = fromFlags.Duration
+		}
+		if given["sample"] {
+			sc.Sample = fromFlags.Sample
+		}
+		if !given["rates"] {
+			rates = ""
+		}
+		if !given["stays"] {
+			stays = ""
+		}
+	}
+
+	var err error
+	if rates != "" {
+		if sc.Rates, err = parseRates(rates); err != nil {
+			log.Fatalf("Error parsing rates: %v\n", err)
+		}
+	}
+	if stays != "" {
+		if sc.Stays, err = parseStays(stays); err != nil {
+			log.Fatalf("Error parsing stays: %v\n", err)
+		}
+	}
+	if err := sc.validate(); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+	if sc.Seed == 0 {
+		sc.Seed = time.Now().UnixNano()
+	}
+
+	service, err := newService(sc.Layout)
+	if err != nil {
+		log.Fatalf("Error creating parking lot: %v\n", err)
+	}
+	if err := checkVehicleTypes(service, sc); err != nil {
+		log.Fatalf("Error: %v\n", err)
+	}
+
+	fmt.Printf("seed %d\n\n", sc.Seed)
+	run(os.Stdout, service, sc, rand.New(rand.NewSource(sc.Seed)), trace)
+}
+
+// newService creates a parking service for the lot described by the layout file, with the
+// vehicle types, spot types and allocation settings of the server's environment variables
+func newService(layoutFile string) (*parking.ParkingService, error) {
+	cfg := config.NewAppConfig()
+
+	service := parking.NewParkingService(repository.NewParkingRepository(spotid.Default))
+	service.SetAllowLargerSpots(cfg.AllowLargerSpots)
+
+	vehicleTypes := make([]parking.VehicleType, len(cfg.VehicleTypes))
+	for i, vehicleType := range cfg.VehicleTypes {
+		vehicleTypes[i] = parking.VehicleType{Name: vehicleType.Name, SpotCode: vehicleType.SpotCode, Size: vehicleType.Size, Rate: vehicleType.Rate}
+	}
+	if err := service.RegisterVehicleTypes(vehicleTypes); err != nil {
+		return nil, err
+	}
+
+	spotTypes := make([]parking.SpotType, len(cfg.SpotTypes))
+	for i, spotType := range cfg.SpotTypes {
+		spotTypes[i] = parking.SpotType{
+			Code:        spotType.Code,
+			VehicleType: spotType.VehicleType,
+			Class:       spotType.Class,
+			IsActive:    spotType.VehicleType != "",
+			Exists:      true,
+		}
+	}
+	if err := service.RegisterSpotTypes(spotTypes); err != nil {
+		return nil, err
+	}
+	if err := service.SetFallbacks(cfg.Fallbacks); err != nil {
+		return nil, err
+	}
+
+	return service, layout.NewManager(layoutFile, service).Load()
+}
+
+// checkVehicleTypes checks that the lot accepts every simulated vehicle type
+func checkVehicleTypes(service *parking.ParkingService, sc scenario) error {
+	accepted := make(map[string]bool)
+	for _, vehicleType := range service.GetVehicleTypes() {
+		accepted[vehicleType.Name] = true
+	}
+	for vehicleType := range sc.Rates {
+		if !accepted[vehicleType] {
+			return fmt.Errorf("unknown vehicle type %q, register it with VEHICLE_TYPES", vehicleType)
+		}
+	}
+	return nil
+}
+
+// run simulates the traffic of a scenario and prints the statistics to out
+func run(out io.Writer, service *parking.ParkingService, sc scenario, random *rand.Rand, trace bool) {
+	vehicleTypes := make([]string, 0, len(sc.Rates))
+	for vehicleType := range sc.Rates {
+		vehicleTypes = append(vehicleTypes, vehicleType)
+	}
+	sort.Strings(vehicleTypes)
+
+	// Arrivals of every type form a Poisson process, stays are exponentially distributed
+	queue := &eventQueue{}
+	nextArrival := func(vehicleType string, after time.Duration) {
+		if rate := sc.Rates[vehicleType]; rate > 0 {
+			gap := time.Duration(random.ExpFloat64() / rate * float64(time.Hour))
+			heap.Push(queue, event{at: after + gap, arrival: true, vehicleType: vehicleType})
+		}
+	}
+	for _, vehicleType := range vehicleTypes {
+		nextArrival(vehicleType, 0)
+	}
+
+	stats := make(map[string]*typeStats, len(vehicleTypes))
+	for _, vehicleType := range vehicleTypes {
+		stats[vehicleType] = &typeStats{}
+	}
+
+	fmt.Fprintf(out, "%-10s %10s  %s\n", "time", "occupancy", "per floor")
+	vehicles := 0
+	nextSample := time.Duration(0)
+	for queue.Len() > 0 {
+		ev := heap.Pop(queue).(event)
+		if ev.at > sc.Duration {
+			break
+		}
+		for nextSample <= ev.at {
+			printOccupancy(out, nextSample, service.GetOccupancyStats())
+			nextSample += sc.Sample
+		}
+
+		if !ev.arrival {
+			spotID, err := service.UnparkByPlate(ev.vehicleNumber)
+			if err != nil {
+				log.Printf("Error unparking %s: %v", ev.vehicleNumber, err)
+			} else if trace {
+				fmt.Fprintf(out, "%-10s unpark %s %s\n", formatElapsed(ev.at), ev.vehicleNumber, spotID)
+			}
+			continue
+		}
+
+		nextArrival(ev.vehicleType, ev.at)
+		vehicles++
+		vehicleNumber := fmt.Sprintf("SIM%06d", vehicles)
+		typeStats := stats[ev.vehicleType]
+		typeStats.arrivals++
+
+		spotID, err := service.Park(ev.vehicleType, vehicleNumber)
+		if trace {
+			fmt.Fprintf(out, "%-10s park   %s %s %s\n", formatElapsed(ev.at), vehicleNumber, ev.vehicleType, traceResult(spotID, err))
+		}
+		switch {
+		case err == nil:
+			typeStats.parked++
+			stay := time.Duration(random.ExpFloat64() * float64(sc.Stays[ev.vehicleType]))
+			heap.Push(queue, event{at: ev.at + stay, vehicleType: ev.vehicleType, vehicleNumber: vehicleNumber})
+		case errors.Is(err, pkgerrors.ErrNoAvailableSpot):
+			typeStats.rejected++
+		default:
+			typeStats.failed++
+			log.Printf("Error parking %s: %v", vehicleNumber, err)
+		}
+	}
+	for nextSample <= sc.Duration {
+		printOccupancy(out, nextSample, service.GetOccupancyStats())
+		nextSample += sc.Sample
+	}
+
+	fmt.Fprintf(out, "\n%-12s %8s %8s %8s %8s %9s\n", "vehicle type", "arrivals", "parked", "rejected", "failed", "rejection")
+	for _, vehicleType := range vehicleTypes {
+		typeStats := stats[vehicleType]
+		rejection := 0.0
+		if typeStats.arrivals > 0 {
+			rejection = float64(typeStats.rejected) * 100 / float64(typeStats.arrivals)
+		}
+		fmt.Fprintf(out, "%-12s %8d %8d %8d %8d %8.1f%%\n", vehicleType, typeStats.arrivals, typeStats.parked, typeStats.rejected, typeStats.failed, rejection)
+	}
+}
+
+// traceResult returns the spot a vehicle was parked at, or the code of the error it was refused with
+func traceResult(spotID string, err error) string {
+	if err != nil {
+		return string(pkgerrors.CodeOf(err))
+	}
+	return spotID
+}
+
+// printOccupancy prints the occupancy overall and per floor at a simulated time
+func printOccupancy(out io.Writer, at time.Duration, stats repository.OccupancyStats) {
+	floors := make([]string, len(stats.Floors))
+	for floor, count := range stats.Floors {
+		floors[floor] = fmt.Sprintf("%d/%d", count.Occupied, count.Capacity)
+	}
+	fmt.Fprintf(out, "%-10s %9.1f%%  %s\n", formatElapsed(at), percentage(stats.Total), strings.Join(floors, " "))
+}
+
+func percentage(count repository.OccupancyCount) float64 {
+	if count.Capacity == 0 {
+		return 0
+	}
+	return float64(count.Occupied) * 100 / float64(count.Capacity)
+}
+
+// formatElapsed formats a simulated time as hours, minutes and seconds, e.g. 26:30:00
+func formatElapsed(at time.Duration) string {
+	return fmt.Sprintf("%02d:%02d:%02d", int(at.Hours()), int(at.Minutes())%60, int(at.Seconds())%60)
+}
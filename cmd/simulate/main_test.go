@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden trace with the output of this run")
+
+// simulate runs the repository's simulation scenario with its seed and returns the trace
+func simulate(t *testing.T) []byte {
+	t.Helper()
+
+	sc := scenario{}
+	if err := loadScenario("../../configs/simulation.yaml", &sc); err != nil {
+		t.Fatal(err)
+	}
+	sc.Layout = filepath.Join("../..", sc.Layout)
+
+	service, err := newService(sc.Layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	run(&out, service, sc, rand.New(rand.NewSource(sc.Seed)), true)
+	return out.Bytes()
+}
+
+// The scenario and seed fix every allocation, so a change of the trace is a change of the allocation.
+// Run with -update after an intended change and review the diff of the golden file.
+func TestGoldenTrace(t *testing.T) {
+	const golden = "testdata/simulation.trace"
+
+	trace := simulate(t)
+	if again := simulate(t); !bytes.Equal(trace, again) {
+		t.Fatal("two runs of the same scenario and seed gave different traces")
+	}
+
+	if *update {
+		if err := os.WriteFile(golden, trace, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(trace, want) {
+		t.Errorf("trace differs from %s, run go test ./cmd/simulate -update and review the diff if the change is intended", golden)
+	}
+}
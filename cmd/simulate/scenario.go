@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// scenario describes the simulated traffic. Runs of the same scenario with the same seed
+// park the same vehicles at the same spots, so their traces can be compared across changes.
+type scenario struct {
+	Seed     int64                    `yaml:"seed"` // random when 0
+	Layout   string                   `yaml:"layout"`
+	Duration time.Duration            `yaml:"duration"`
+	Sample   time.Duration            `yaml:"sample"` // how often the occupancy is printed
+	Rates    map[string]float64       `yaml:"rates"`  // vehicle type -> arrivals per hour
+	Stays    map[string]time.Duration `yaml:"stays"`  // vehicle type -> mean stay
+}
+
+// loadScenario reads a YAML scenario file over the given defaults, fields missing from the file keep them
+func loadScenario(path string, sc *scenario) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	// Rates and stays of the file replace the default ones as a whole
+	loaded := scenario{}
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if loaded.Seed != 0 {
+		sc.Seed = loaded.Seed
+	}
+	if loaded.Layout != "" {
+		sc.Layout = loaded.Layout
+	}
+	if loaded.Duration != 0 {
+		sc.Duration = loaded.Duration
+	}
+	if loaded.Sample != 0 {
+		sc.Sample = loaded.Sample
+	}
+	if loaded.Rates != nil {
+		sc.Rates = loaded.Rates
+	}
+	if loaded.Stays != nil {
+		sc.Stays = loaded.Stays
+	}
+	return nil
+}
+
+// validate checks the durations, rates and stays of a scenario
+func (sc scenario) validate() error {
+	if sc.Duration <= 0 || sc.Sample <= 0 {
+		return fmt.Errorf("duration and sample must be positive")
+	}
+	for vehicleType, rate := range sc.Rates {
+		if rate < 0 {
+			return fmt.Errorf("rate of %s must not be negative, got %g", vehicleType, rate)
+		}
+		if sc.Stays[vehicleType] <= 0 {
+			return fmt.Errorf("no mean stay for vehicle type %q", vehicleType)
+		}
+	}
+	return nil
+}
+
+// parses comma separated type=rate pairs
+func parseRates(value string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		vehicleType, rate, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return nil, fmt.Errorf("%q is not a type=rate pair", pair)
+		}
+		parsed, err := strconv.ParseFloat(rate, 64)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("rate of %s must be a non-negative number, got %q", vehicleType, rate)
+		}
+		rates[vehicleType] = parsed
+	}
+	return rates, nil
+}
+
+// parses comma separated type=duration pairs
+func parseStays(value string) (map[string]time.Duration, error) {
+	stays := make(map[string]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		vehicleType, stay, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return nil, fmt.Errorf("%q is not a type=duration pair", pair)
+		}
+		parsed, err := time.ParseDuration(stay)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("stay of %s must be a positive duration, got %q", vehicleType, stay)
+		}
+		stays[vehicleType] = parsed
+	}
+	return stays, nil
+}
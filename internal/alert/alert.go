@@ -0,0 +1,95 @@
+package alert
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+)
+
+// Alert types raised by the parking service
+const (
+	TypeCapacity      = "capacity"
+	TypeOverstay      = "overstay"
+	TypeDeviceOffline = "device_offline"
+	TypeDiscrepancy   = "discrepancy"
+	TypeIncident      = "incident"
+)
+
+// Types lists all alert types
+var Types = []string{TypeCapacity, TypeOverstay, TypeDeviceOffline, TypeDiscrepancy, TypeIncident}
+
+// Alert severities, from the least to the most severe
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Severities lists all severities, from the least to the most severe
+var Severities = []string{SeverityInfo, SeverityWarning, SeverityCritical}
+
+const sendTimeout = 10 * time.Second
+
+// Alert is a condition operators should look into
+type Alert struct {
+	Type     string
+	Severity string
+	Summary  string
+	// identifies the condition, e.g. the device that went offline, so sinks can group repeated alerts
+	Key  string
+	Time time.Time
+}
+
+// Sink delivers alerts through one channel, e.g. Slack or PagerDuty
+type Sink interface {
+	Send(ctx context.Context, alert Alert) error
+}
+
+// Route sends the alerts of some types at or above a severity to a sink
+type Route struct {
+	Name        string   // shown in the logs, e.g. slack
+	Types       []string // all types when empty
+	MinSeverity string
+	Sink        Sink
+}
+
+// matches reports whether an alert is sent through the route
+func (r Route) matches(alert Alert) bool {
+	if len(r.Types) > 0 && !slices.Contains(r.Types, alert.Type) {
+		return false
+	}
+	return slices.Index(Severities, alert.Severity) >= slices.Index(Severities, r.MinSeverity)
+}
+
+// Dispatcher sends alerts to the sinks whose routes match them
+type Dispatcher struct {
+	routes []Route
+}
+
+func NewDispatcher(routes []Route) *Dispatcher {
+	return &Dispatcher{routes: routes}
+}
+
+// Raise sends an alert through every matching route in the background, failures are logged
+func (d *Dispatcher) Raise(alert Alert) {
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	log.Printf("Alert %s (%s): %s", alert.Type, alert.Severity, alert.Summary)
+
+	for _, route := range d.routes {
+		if !route.matches(alert) {
+			continue
+		}
+
+		go func(route Route) {
+			ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+			defer cancel()
+
+			if err := route.Sink.Send(ctx, alert); err != nil {
+				log.Printf("Alert %s to %s failed: %v", alert.Type, route.Name, err)
+			}
+		}(route)
+	}
+}
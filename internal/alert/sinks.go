@@ -0,0 +1,179 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"parking-lot-system/internal/notify"
+	"strings"
+	"time"
+)
+
+// Sink kinds
+const (
+	SinkWebhook   = "webhook"
+	SinkSlack     = "slack"
+	SinkPagerDuty = "pagerduty"
+	SinkEmail     = "email"
+)
+
+// SinkKinds lists all sink kinds
+var SinkKinds = []string{SinkWebhook, SinkSlack, SinkPagerDuty, SinkEmail}
+
+// PagerDutyEventsURL is the PagerDuty Events API v2 endpoint alerts are triggered at
+const PagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+const requestTimeout = 10 * time.Second
+
+// NewSink creates a sink of a kind for its target: the URL of a webhook or Slack incoming webhook,
+// the routing key of a PagerDuty service, or an email address. Email sinks send through the notifier,
+// which may be nil when no email channel is configured.
+func NewSink(kind, target string, email notify.Notifier) (Sink, error) {
+	switch kind {
+	case SinkWebhook, SinkSlack:
+		parsed, err := url.Parse(target)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid %s URL %q: must be an http or https URL", kind, target)
+		}
+		if kind == SinkSlack {
+			return &SlackSink{url: target, client: &http.Client{Timeout: requestTimeout}}, nil
+		}
+		return &WebhookSink{url: target, client: &http.Client{Timeout: requestTimeout}}, nil
+	case SinkPagerDuty:
+		if target == "" {
+			return nil, fmt.Errorf("missing PagerDuty routing key")
+		}
+		return &PagerDutySink{url: PagerDutyEventsURL, routingKey: target, client: &http.Client{Timeout: requestTimeout}}, nil
+	case SinkEmail:
+		if !notify.IsEmail(target) {
+			return nil, fmt.Errorf("invalid email address %q", target)
+		}
+		if email == nil {
+			return nil, fmt.Errorf("email alerts to %s need an email channel", target)
+		}
+		return &EmailSink{to: target, notifier: email}, nil
+	default:
+		return nil, fmt.Errorf("unknown sink %q, must be one of %s", kind, strings.Join(SinkKinds, ", "))
+	}
+}
+
+// WebhookSink posts alerts as JSON to a URL
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// webhookAlert is the body posted to webhooks
+type webhookAlert struct {
+	Type     string    `json:"type"`
+	Severity string    `json:"severity"`
+	Summary  string    `json:"summary"`
+	Key      string    `json:"key,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// Send posts an alert to the webhook
+func (s *WebhookSink) Send(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, s.client, s.url, webhookAlert{
+		Type:     alert.Type,
+		Severity: alert.Severity,
+		Summary:  alert.Summary,
+		Key:      alert.Key,
+		Time:     alert.Time,
+	})
+}
+
+// SlackSink posts alerts to a Slack incoming webhook
+type SlackSink struct {
+	url    string
+	client *http.Client
+}
+
+// Send posts an alert as a Slack message
+func (s *SlackSink) Send(ctx context.Context, alert Alert) error {
+	text := fmt.Sprintf("*[%s] %s*: %s", strings.ToUpper(alert.Severity), alert.Type, alert.Summary)
+	return postJSON(ctx, s.client, s.url, map[string]string{"text": text})
+}
+
+// PagerDutySink triggers PagerDuty incidents through the Events API v2. Alerts with the same key
+// are grouped into one incident.
+type PagerDutySink struct {
+	url        string
+	routingKey string
+	client     *http.Client
+}
+
+// pagerDutyEvent is the body posted to the Events API
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key,omitempty"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+type pagerDutyPayload struct {
+	Summary   string `json:"summary"`
+	Source    string `json:"source"`
+	Severity  string `json:"severity"` // PagerDuty knows info, warning and critical too
+	Component string `json:"component"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Send triggers an incident for an alert
+func (s *PagerDutySink) Send(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, s.client, s.url, pagerDutyEvent{
+		RoutingKey:  s.routingKey,
+		EventAction: "trigger",
+		DedupKey:    alert.Key,
+		Payload: pagerDutyPayload{
+			Summary:   alert.Summary,
+			Source:    "parking-lot-system",
+			Severity:  alert.Severity,
+			Component: alert.Type,
+			Timestamp: alert.Time.Format(time.RFC3339),
+		},
+	})
+}
+
+// EmailSink emails alerts to an address
+type EmailSink struct {
+	to       string
+	notifier notify.Notifier
+}
+
+// Send emails an alert
+func (s *EmailSink) Send(ctx context.Context, alert Alert) error {
+	return s.notifier.Send(ctx, notify.Message{
+		To:      s.to,
+		Subject: fmt.Sprintf("[%s] Parking %s alert", alert.Severity, alert.Type),
+		Body:    fmt.Sprintf("%s\n\nRaised at %s", alert.Summary, alert.Time.Format(time.RFC1123)),
+	})
+}
+
+// postJSON posts a value as JSON and expects a 2xx response
+func postJSON(ctx context.Context, client *http.Client, target string, value any) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("responded with HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+package dto
+
+type OIDCConfigResponse struct {
+	Issuer                string `json:"issuer"`
+	ClientID              string `json:"clientId"`
+	AuthorizationEndpoint string `json:"authorizationEndpoint"`
+	TokenEndpoint         string `json:"tokenEndpoint"`
+}
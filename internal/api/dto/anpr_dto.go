@@ -0,0 +1,16 @@
+package dto
+
+type PlateRecognitionRequest struct {
+	CameraID    string `json:"cameraId"`
+	Gate        int    `json:"gate,omitempty"`
+	Direction   string `json:"direction"`
+	Plate       string `json:"plate"`
+	VehicleType string `json:"vehicleType,omitempty"`
+}
+
+type PlateRecognitionResponse struct {
+	Direction string `json:"direction"`
+	SpotID    string `json:"spotId,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Code      string `json:"code,omitempty"`
+}
@@ -0,0 +1,101 @@
+package dto
+
+type DeviceEnrollmentRequest struct {
+	Device string `json:"device"`
+	Kind   string `json:"kind"` // kiosk or pay_station
+}
+
+type DeviceEnrollmentResponse struct {
+	Code      string `json:"code"` // one-time code entered on the device
+	Device    string `json:"device"`
+	Kind      string `json:"kind"`
+	ExpiresAt string `json:"expiresAt"`
+}
+
+type DeviceEnrollRequest struct {
+	Code string `json:"code"`
+}
+
+type DeviceRefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type DeviceTokenResponse struct {
+	SessionID        int    `json:"sessionId"`
+	Device           string `json:"device"`
+	AccessToken      string `json:"accessToken"`
+	AccessExpiresAt  string `json:"accessExpiresAt"`
+	RefreshToken     string `json:"refreshToken"`
+	RefreshExpiresAt string `json:"refreshExpiresAt"`
+}
+
+type DeviceSession struct {
+	ID               int    `json:"id"`
+	Device           string `json:"device"`
+	Kind             string `json:"kind"`
+	Status           string `json:"status"` // active, expired or revoked
+	EnrolledAt       string `json:"enrolledAt"`
+	RefreshedAt      string `json:"refreshedAt"`
+	AccessExpiresAt  string `json:"accessExpiresAt"`
+	RefreshExpiresAt string `json:"refreshExpiresAt"`
+	RevokedAt        string `json:"revokedAt,omitempty"`
+}
+
+type DeviceSessionResponse struct {
+	Session *DeviceSession `json:"session,omitempty"`
+}
+
+type DeviceSessionsResponse struct {
+	Sessions []DeviceSession `json:"sessions"`
+}
+
+type DeviceRequest struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"` // gate, kiosk, pay_station or sensor
+	Location string `json:"location,omitempty"`
+	Gate     int    `json:"gate,omitempty"`
+}
+
+type Device struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Location     string `json:"location,omitempty"`
+	Gate         int    `json:"gate,omitempty"`
+	Status       string `json:"status"`    // online or stale
+	Connected    bool   `json:"connected"` // connected to the command channel
+	RegisteredAt string `json:"registeredAt"`
+	LastSeen     string `json:"lastSeen,omitempty"`
+}
+
+type DeviceResponse struct {
+	Device *Device `json:"device,omitempty"`
+}
+
+type DevicesResponse struct {
+	Devices []Device `json:"devices"`
+}
+
+type DeviceCommandRequest struct {
+	Device  string `json:"device"`
+	Action  string `json:"action"` // open_barrier, show_message or reboot
+	Message string `json:"message,omitempty"`
+}
+
+type DeviceCommand struct {
+	ID             int    `json:"id"`
+	Device         string `json:"device"`
+	Action         string `json:"action"`
+	Message        string `json:"message,omitempty"`
+	Status         string `json:"status"` // sent, acknowledged, failed or expired
+	Error          string `json:"error,omitempty"`
+	SentAt         string `json:"sentAt"`
+	AcknowledgedAt string `json:"acknowledgedAt,omitempty"`
+}
+
+type DeviceCommandResponse struct {
+	Command *DeviceCommand `json:"command,omitempty"`
+}
+
+type DeviceCommandsResponse struct {
+	Commands []DeviceCommand `json:"commands"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+type DisplayVehicleType struct {
+	Free      int    `json:"free"`
+	Aisle     *int   `json:"aisle"` // null when full
+	AisleFree int    `json:"aisleFree"`
+	Hint      string `json:"hint"`
+}
+
+type DisplayBoardResponse struct {
+	Floor        int                           `json:"floor"`
+	Free         int                           `json:"free"`
+	VehicleTypes map[string]DisplayVehicleType `json:"vehicleTypes"`
+	// shown instead of the free counts while the lot is in emergency mode
+	Emergency string `json:"emergency,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+type Event struct {
+	ID            int    `json:"id"`
+	Type          string `json:"type"`
+	Time          string `json:"time"`
+	SpotID        string `json:"spotId,omitempty"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+	VehicleType   string `json:"vehicleType,omitempty"`
+	Gate          int    `json:"gate,omitempty"`
+	Device        string `json:"device,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+type EventsResponse struct {
+	Events []Event `json:"events"`
+	// value of the after parameter fetching the next page, omitted on the last page
+	NextAfter int `json:"nextAfter,omitempty"`
+}
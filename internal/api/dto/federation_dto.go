@@ -0,0 +1,21 @@
+package dto
+
+type FederationTotals struct {
+	Available int `json:"available"`
+	Capacity  int `json:"capacity"`
+}
+
+type FederationPeer struct {
+	URL          string                        `json:"url"`
+	Reachable    bool                          `json:"reachable"`
+	Error        string                        `json:"error,omitempty"`
+	Overall      *PublicAvailability           `json:"overall,omitempty"`
+	VehicleTypes map[string]PublicAvailability `json:"vehicleTypes,omitempty"`
+}
+
+type FederationAvailabilityResponse struct {
+	FetchedAt    string                      `json:"fetchedAt"`
+	Overall      FederationTotals            `json:"overall"`
+	VehicleTypes map[string]FederationTotals `json:"vehicleTypes"`
+	Peers        []FederationPeer            `json:"peers"`
+}
@@ -0,0 +1,41 @@
+package dto
+
+type GateStatus struct {
+	Gate  int    `json:"gate"`
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+type GateStatusResponse struct {
+	Gates []GateStatus `json:"gates"`
+}
+
+type GateCommandResult struct {
+	Gate   int    `json:"gate"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type GateCommandResponse struct {
+	HeldOpen bool                `json:"heldOpen"`
+	Gates    []GateCommandResult `json:"gates"`
+}
+
+type ExitTicket struct {
+	Position             int    `json:"position"`
+	VehicleNumber        string `json:"vehicleNumber,omitempty"`
+	SpotID               string `json:"spotId"`
+	ReadyAt              string `json:"readyAt"`
+	EstimatedWaitSeconds int    `json:"estimatedWaitSeconds"`
+}
+
+type ExitQueue struct {
+	Gate       int          `json:"gate"`
+	Throughput int          `json:"throughput"`
+	Length     int          `json:"length"`
+	Vehicles   []ExitTicket `json:"vehicles"`
+}
+
+type ExitQueueResponse struct {
+	Queues []ExitQueue `json:"queues"`
+}
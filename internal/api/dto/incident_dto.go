@@ -0,0 +1,12 @@
+package dto
+
+type IncidentRequest struct {
+	Reason string `json:"reason"`
+}
+
+type IncidentResponse struct {
+	Active    bool   `json:"active"`
+	Reason    string `json:"reason,omitempty"`
+	StartedBy string `json:"startedBy,omitempty"`
+	StartedAt string `json:"startedAt,omitempty"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+type SkippedSpot struct {
+	SpotID string `json:"spotId"`
+	Error  string `json:"error"`
+	Code   string `json:"code"`
+}
+
+type LayoutReloadResponse struct {
+	Changed []string      `json:"changed"`
+	Skipped []SkippedSpot `json:"skipped"`
+	Error   string        `json:"error,omitempty"`
+}
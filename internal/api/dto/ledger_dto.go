@@ -0,0 +1,28 @@
+package dto
+
+type LedgerLine struct {
+	Account string  `json:"account"`
+	Debit   float64 `json:"debit,omitempty"`
+	Credit  float64 `json:"credit,omitempty"`
+}
+
+type LedgerTransaction struct {
+	ID        int          `json:"id"`
+	Kind      string       `json:"kind"`
+	TicketID  int          `json:"ticketId"`
+	PaymentID int          `json:"paymentId,omitempty"`
+	Time      string       `json:"time"`
+	Lines     []LedgerLine `json:"lines"`
+}
+
+type AccountBalance struct {
+	Account string  `json:"account"`
+	Debit   float64 `json:"debit"`
+	Credit  float64 `json:"credit"`
+	Balance float64 `json:"balance"` // debits minus credits
+}
+
+type LedgerResponse struct {
+	Transactions []LedgerTransaction `json:"transactions"`
+	Balances     []AccountBalance    `json:"balances"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+type MapCell struct {
+	SpotID      string `json:"spotId,omitempty"`
+	State       string `json:"state"` // occupied, free, inactive or none (no spot exists)
+	VehicleType string `json:"vehicleType,omitempty"`
+	Parked      int    `json:"parked"`
+	Capacity    int    `json:"capacity"`
+}
+
+type FloorMapResponse struct {
+	Floor int         `json:"floor"`
+	Gates []int       `json:"gates"`
+	Cells [][]MapCell `json:"cells"`
+}
@@ -0,0 +1,28 @@
+package dto
+
+type OverrideRequest struct {
+	Action        string `json:"action"`
+	SpotID        string `json:"spotId"`
+	VehicleNumber string `json:"vehicleNumber"`
+	Reason        string `json:"reason"`
+}
+
+type OverrideResponse struct {
+	Success       bool   `json:"success"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`
+}
+
+type AuditEntry struct {
+	Time          string `json:"time"`
+	Actor         string `json:"actor"`
+	Action        string `json:"action"`
+	SpotID        string `json:"spotId,omitempty"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+type AuditLogResponse struct {
+	Entries []AuditEntry `json:"entries"`
+}
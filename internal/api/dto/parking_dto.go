@@ -3,21 +3,41 @@ package dto
 type ParkRequest struct {
 	VehicleType   string `json:"vehicleType"`
 	VehicleNumber string `json:"vehicleNumber"`
+	Gate          int    `json:"gate,omitempty"`
+	Zone          string `json:"zone,omitempty"`
+	Size          string `json:"size,omitempty"`
+	Contact       string `json:"contact,omitempty"`
+	Device        string `json:"device,omitempty"` // must be the device signed in, which the request comes from
 }
 
 type ParkResponse struct {
 	SpotID string `json:"spotId,omitempty"`
-	Error  string `json:"error,omitempty"`
+	// set with the duplicate_pending code, the spot is held until an operator confirms the vehicle
+	HoldID int `json:"holdId,omitempty"`
+	// set with the duplicate_retrying code, the attempt is retried in the background and can be polled
+	RetryID int `json:"retryId,omitempty"`
+	// the ticket of the parked vehicle and the link to its wallet pass, only given to the driver here
+	TicketID      int    `json:"ticketId,omitempty"`
+	WalletPassURL string `json:"walletPassUrl,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`
 }
 
 type UnparkRequest struct {
-	SpotID        string `json:"spotId"`
+	SpotID        string `json:"spotId,omitempty"`
 	VehicleNumber string `json:"vehicleNumber"`
+	Gate          int    `json:"gate,omitempty"`
+	Device        string `json:"device,omitempty"`
 }
 
 type UnparkResponse struct {
 	Success bool   `json:"success"`
-	Error   string `json:"error,omitempty"`
+	SpotID  string `json:"spotId,omitempty"`
+	// set when the exit gate is saturated and the vehicle has to wait for its turn
+	QueuePosition        int    `json:"queuePosition,omitempty"`
+	EstimatedWaitSeconds int    `json:"estimatedWaitSeconds,omitempty"`
+	Error                string `json:"error,omitempty"`
+	Code                 string `json:"code,omitempty"`
 }
 
 type AvailableSpotRequest struct {
@@ -27,6 +47,22 @@ type AvailableSpotRequest struct {
 type AvailableSpotResponse struct {
 	Spots []string `json:"spots,omitempty"`
 	Error string   `json:"error,omitempty"`
+	Code  string   `json:"code,omitempty"`
+}
+
+type RecommendSpotResponse struct {
+	VehicleType string `json:"vehicleType,omitempty"`
+	SpotID      string `json:"spotId,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Code        string `json:"code,omitempty"`
+}
+
+type AvailableCountResponse struct {
+	VehicleType string `json:"vehicleType,omitempty"`
+	Floor       *int   `json:"floor,omitempty"`
+	Available   int    `json:"available"`
+	Error       string `json:"error,omitempty"`
+	Code        string `json:"code,omitempty"`
 }
 
 type SearchVehicleRequest struct {
@@ -34,8 +70,112 @@ type SearchVehicleRequest struct {
 }
 
 type SearchVehicleResponse struct {
+	SpotID     string      `json:"spotId,omitempty"`
+	IsParked   bool        `json:"isParked"`
+	WasParked  bool        `json:"wasParked"`
+	Violations []Violation `json:"violations,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Code       string      `json:"code,omitempty"`
+}
+
+type VehicleCandidate struct {
+	VehicleNumber string `json:"vehicleNumber"`
+	SpotID        string `json:"spotId"`
+}
+
+type SuffixSearchResponse struct {
+	Suffix     string             `json:"suffix"`
+	Candidates []VehicleCandidate `json:"candidates"`
+	Error      string             `json:"error,omitempty"`
+	Code       string             `json:"code,omitempty"`
+}
+
+type HoldRequest struct {
+	VehicleType   string `json:"vehicleType"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+	SpotID        string `json:"spotId,omitempty"`
+	Gate          int    `json:"gate,omitempty"`
+	Zone          string `json:"zone,omitempty"`
+	Size          string `json:"size,omitempty"`
+	Contact       string `json:"contact,omitempty"`
+	Device        string `json:"device,omitempty"`
+}
+
+type HoldResponse struct {
+	HoldID    int    `json:"holdId,omitempty"`
 	SpotID    string `json:"spotId,omitempty"`
-	IsParked  bool   `json:"isParked"`
-	WasParked bool   `json:"wasParked"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
 	Error     string `json:"error,omitempty"`
+	Code      string `json:"code,omitempty"`
+}
+
+type ConfirmHoldRequest struct {
+	HoldID        int    `json:"holdId"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+}
+
+type ConfirmHoldResponse struct {
+	SpotID        string `json:"spotId,omitempty"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+	TicketID      int    `json:"ticketId,omitempty"`
+	WalletPassURL string `json:"walletPassUrl,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`
+}
+
+type PendingDuplicate struct {
+	HoldID        int    `json:"holdId"`
+	VehicleNumber string `json:"vehicleNumber"`
+	VehicleType   string `json:"vehicleType"`
+	SpotID        string `json:"spotId"`
+	Gate          int    `json:"gate,omitempty"`
+	ExpiresAt     string `json:"expiresAt"`
+}
+
+type PendingDuplicatesResponse struct {
+	Duplicates []PendingDuplicate `json:"duplicates"`
+}
+
+type ResolveDuplicateRequest struct {
+	Action        string `json:"action"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+}
+
+type ResolveDuplicateResponse struct {
+	Success       bool   `json:"success"`
+	SpotID        string `json:"spotId,omitempty"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`
+}
+
+type ParkRetryResponse struct {
+	RetryID       int    `json:"retryId,omitempty"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+	Status        string `json:"status,omitempty"`
+	SpotID        string `json:"spotId,omitempty"`
+	TicketID      int    `json:"ticketId,omitempty"`
+	WalletPassURL string `json:"walletPassUrl,omitempty"`
+	Reason        string `json:"reason,omitempty"` // why a failed retry could not park the vehicle
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`
+}
+
+type CheckInRequest struct {
+	VehicleType   string `json:"vehicleType"`
+	VehicleNumber string `json:"vehicleNumber"`
+	Gate          int    `json:"gate,omitempty"`
+	Zone          string `json:"zone,omitempty"`
+	Size          string `json:"size,omitempty"`
+}
+
+type CheckInResponse struct {
+	CheckInID     int    `json:"checkInId,omitempty"`
+	VehicleNumber string `json:"vehicleNumber,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Position      int    `json:"position,omitempty"`
+	SpotID        string `json:"spotId,omitempty"`
+	Reason        string `json:"reason,omitempty"` // why a failed check-in could not be parked
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`
 }
@@ -0,0 +1,49 @@
+package dto
+
+type CashPaymentRequest struct {
+	TicketID  int     `json:"ticketId"`
+	Tendered  float64 `json:"tendered"`
+	Change    float64 `json:"change"`
+	DrawerID  string  `json:"drawerId"`
+	ReceiptTo string  `json:"receiptTo,omitempty"` // emails the receipt, defaults to the email given at park time
+}
+
+type Payment struct {
+	ID       int     `json:"id"`
+	TicketID int     `json:"ticketId"`
+	Method   string  `json:"method"`
+	Amount   float64 `json:"amount"`
+	Tendered float64 `json:"tendered"`
+	Change   float64 `json:"change"`
+	DrawerID string  `json:"drawerId,omitempty"`
+	TakenBy  string  `json:"takenBy"`
+	TakenAt  string  `json:"takenAt"`
+	Refunded float64 `json:"refunded,omitempty"`
+}
+
+type RefundRequest struct {
+	Amount float64 `json:"amount,omitempty"` // all that is left of the payment when omitted
+	Reason string  `json:"reason"`
+}
+
+type Refund struct {
+	ID         int     `json:"id"`
+	PaymentID  int     `json:"paymentId"`
+	Amount     float64 `json:"amount"`
+	Reason     string  `json:"reason"`
+	RefundedBy string  `json:"refundedBy"`
+	RefundedAt string  `json:"refundedAt"`
+}
+
+type RefundResponse struct {
+	Refund *Refund `json:"refund,omitempty"`
+}
+
+type PaymentResponse struct {
+	Payment *Payment `json:"payment,omitempty"`
+}
+
+type PaymentListResponse struct {
+	Payments []Payment `json:"payments"`
+	Total    float64   `json:"total"` // amount kept, less the refunds
+}
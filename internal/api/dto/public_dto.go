@@ -0,0 +1,13 @@
+package dto
+
+type PublicAvailability struct {
+	Available int    `json:"available"`
+	Capacity  int    `json:"capacity"`
+	Status    string `json:"status"` // available, limited or full
+}
+
+type PublicAvailabilityResponse struct {
+	UpdatedAt    string                        `json:"updatedAt"`
+	Overall      PublicAvailability            `json:"overall"`
+	VehicleTypes map[string]PublicAvailability `json:"vehicleTypes"`
+}
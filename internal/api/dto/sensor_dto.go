@@ -0,0 +1,41 @@
+package dto
+
+type SensorReportRequest struct {
+	SpotID   string `json:"spotId"`
+	Occupied *bool  `json:"occupied"`
+	Battery  *int   `json:"battery,omitempty"`  // percent
+	Firmware string `json:"firmware,omitempty"` // e.g. 1.4.2
+}
+
+type SensorReportResponse struct {
+	Success       bool   `json:"success"`
+	MatchesSystem bool   `json:"matchesSystem"`
+	Error         string `json:"error,omitempty"`
+	Code          string `json:"code,omitempty"`
+}
+
+type Discrepancy struct {
+	Kind             string `json:"kind"`
+	SpotID           string `json:"spotId"`
+	VehicleNumber    string `json:"vehicleNumber,omitempty"`
+	SensorReportedAt string `json:"sensorReportedAt"`
+}
+
+type DiscrepancyReportResponse struct {
+	GeneratedAt   string        `json:"generatedAt,omitempty"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+type SensorMaintenance struct {
+	SpotID     string   `json:"spotId"`
+	Battery    *int     `json:"battery,omitempty"`
+	Firmware   string   `json:"firmware,omitempty"`
+	ReportedAt string   `json:"reportedAt"`
+	Reasons    []string `json:"reasons"`
+}
+
+type SensorMaintenanceResponse struct {
+	LowBattery  int                 `json:"lowBattery"`
+	MinFirmware string              `json:"minFirmware,omitempty"`
+	Sensors     []SensorMaintenance `json:"sensors"`
+}
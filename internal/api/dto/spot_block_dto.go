@@ -0,0 +1,28 @@
+package dto
+
+type SpotBlockRequest struct {
+	Name    string   `json:"name"`
+	SpotIDs []string `json:"spotIds"`
+	Zone    string   `json:"zone"`
+	From    string   `json:"from"`  // RFC 3339, now when empty
+	Until   string   `json:"until"` // RFC 3339
+}
+
+type SpotBlock struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	SpotIDs   []string `json:"spotIds,omitempty"`
+	Zone      string   `json:"zone,omitempty"`
+	From      string   `json:"from"`
+	Until     string   `json:"until"`
+	Active    bool     `json:"active"`
+	CreatedBy string   `json:"createdBy"`
+}
+
+type SpotBlockResponse struct {
+	Block *SpotBlock `json:"block,omitempty"`
+}
+
+type SpotBlocksResponse struct {
+	Blocks []SpotBlock `json:"blocks"`
+}
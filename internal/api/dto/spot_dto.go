@@ -0,0 +1,27 @@
+package dto
+
+type SpotDeletionRequest struct {
+	Reason string `json:"reason"`
+}
+
+type SpotDeletionResponse struct {
+	Success bool   `json:"success"`
+	SpotID  string `json:"spotId,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Code    string `json:"code,omitempty"`
+}
+
+type DeletedSpot struct {
+	SpotID      string `json:"spotId"`
+	VehicleType string `json:"vehicleType,omitempty"`
+	Class       string `json:"class,omitempty"`
+	IsActive    bool   `json:"isActive"`
+	Zone        string `json:"zone,omitempty"`
+	Size        string `json:"size"`
+	Capacity    int    `json:"capacity"`
+	TimesUsed   int    `json:"timesUsed"`
+}
+
+type DeletedSpotsResponse struct {
+	Spots []DeletedSpot `json:"spots"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+type SpotType struct {
+	Code        string `json:"code"`
+	VehicleType string `json:"vehicleType,omitempty"`
+	Class       string `json:"class,omitempty"`
+	IsActive    bool   `json:"isActive"`
+	Exists      bool   `json:"exists"`
+}
+
+type SpotTypesResponse struct {
+	SpotTypes []SpotType `json:"spotTypes"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+type StateVehicle struct {
+	SpotID        string `json:"spotId"`
+	Floor         int    `json:"floor"`
+	Row           int    `json:"row"`
+	Column        int    `json:"column"`
+	VehicleNumber string `json:"vehicleNumber"`
+	VehicleType   string `json:"vehicleType"`
+	EventID       int    `json:"eventId"` // vehicle.parked event the vehicle was parked by
+	EntryTime     string `json:"entryTime"`
+	ExitTime      string `json:"exitTime,omitempty"`
+}
+
+type LotStateResponse struct {
+	At       string         `json:"at"`
+	Vehicles []StateVehicle `json:"vehicles"`
+}
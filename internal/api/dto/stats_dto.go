@@ -0,0 +1,139 @@
+package dto
+
+type OccupancyStat struct {
+	Capacity   int     `json:"capacity"`
+	Occupied   int     `json:"occupied"`
+	Percentage float64 `json:"percentage"`
+	// spots blocked for an event, left out of capacity and occupied
+	Blocked         int `json:"blocked,omitempty"`
+	BlockedOccupied int `json:"blockedOccupied,omitempty"`
+}
+
+type FloorOccupancyStat struct {
+	Floor int `json:"floor"`
+	OccupancyStat
+}
+
+type OccupancyStatsResponse struct {
+	Overall      OccupancyStat            `json:"overall"`
+	Floors       []FloorOccupancyStat     `json:"floors"`
+	VehicleTypes map[string]OccupancyStat `json:"vehicleTypes"`
+	Zones        map[string]OccupancyStat `json:"zones"`
+}
+
+type StayStat struct {
+	CompletedSessions  int     `json:"completedSessions"`
+	AverageStayMinutes float64 `json:"averageStayMinutes"`
+}
+
+type UsageStatsResponse struct {
+	From           string              `json:"from"`
+	To             string              `json:"to"`
+	HourlyArrivals []int               `json:"hourlyArrivals"`
+	PeakHour       int                 `json:"peakHour"`
+	StayByType     map[string]StayStat `json:"stayByType"`
+	SpotTurnover   map[string]int      `json:"spotTurnover"`
+}
+
+type HeatmapResponse struct {
+	Floor       int          `json:"floor"`
+	From        string       `json:"from"`
+	To          string       `json:"to"`
+	Cells       [][]string   `json:"cells"`       // active, inactive or none (no spot exists)
+	Utilization [][]*float64 `json:"utilization"` // null for inactive and non-existent spots
+}
+
+type TypeForecast struct {
+	Capacity           int     `json:"capacity"`
+	PredictedOccupied  float64 `json:"predictedOccupied"`
+	PredictedAvailable float64 `json:"predictedAvailable"`
+}
+
+type ForecastResponse struct {
+	At           string                  `json:"at"`
+	Samples      int                     `json:"samples"`
+	VehicleTypes map[string]TypeForecast `json:"vehicleTypes"`
+}
+
+type SpotUsage struct {
+	SpotID        string  `json:"spotId"`
+	Floor         int     `json:"floor"`
+	Row           int     `json:"row"`
+	Column        int     `json:"column"`
+	VehicleType   string  `json:"vehicleType,omitempty"`
+	IsActive      bool    `json:"isActive"`
+	TimesUsed     int     `json:"timesUsed"`
+	OccupiedHours float64 `json:"occupiedHours"`
+}
+
+type SpotUsageResponse struct {
+	Spots []SpotUsage `json:"spots"`
+}
+
+type UnusedSpotsResponse struct {
+	From  string      `json:"from"`
+	To    string      `json:"to"`
+	Spots []SpotUsage `json:"spots"`
+}
+
+type OccupancyLevel struct {
+	Capacity   int     `json:"capacity"`
+	Occupied   float64 `json:"occupied"` // averaged over the step
+	Percentage float64 `json:"percentage"`
+}
+
+type FloorOccupancyLevel struct {
+	Floor int `json:"floor"`
+	OccupancyLevel
+}
+
+type TimeSeriesPoint struct {
+	At      string                `json:"at"`
+	Overall OccupancyLevel        `json:"overall"`
+	Floors  []FloorOccupancyLevel `json:"floors"`
+}
+
+type TimeSeriesResponse struct {
+	From   string            `json:"from"`
+	To     string            `json:"to"`
+	Points []TimeSeriesPoint `json:"points"`
+}
+
+type ReportSession struct {
+	TicketID      int    `json:"ticketId"`
+	VehicleNumber string `json:"vehicleNumber"`
+	SpotID        string `json:"spotId"`
+	EntryTime     string `json:"entryTime"`
+	ExitTime      string `json:"exitTime,omitempty"`
+}
+
+type DailyReportResponse struct {
+	From            string             `json:"from"`
+	To              string             `json:"to"`
+	Occupancy       OccupancyStat      `json:"occupancy"`
+	SessionsStarted int                `json:"sessionsStarted"`
+	Revenue         map[string]float64 `json:"revenue"` // payment method -> amount taken
+	RevenueTotal    float64            `json:"revenueTotal"`
+	Unpaid          []ReportSession    `json:"unpaid"`
+	Stuck           []ReportSession    `json:"stuck"`
+}
+
+type RevenueStat struct {
+	Revenue float64 `json:"revenue"`
+	Refunds float64 `json:"refunds"`
+	Net     float64 `json:"net"`
+}
+
+type DayRevenueStat struct {
+	Date string `json:"date"`
+	RevenueStat
+}
+
+type RevenueReportResponse struct {
+	From           string                 `json:"from"`
+	To             string                 `json:"to"`
+	Total          RevenueStat            `json:"total"`
+	Days           []DayRevenueStat       `json:"days"` // oldest first
+	VehicleTypes   map[string]RevenueStat `json:"vehicleTypes"`
+	PaymentMethods map[string]RevenueStat `json:"paymentMethods"`
+}
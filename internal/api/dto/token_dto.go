@@ -0,0 +1,36 @@
+package dto
+
+type TokenRequest struct {
+	Name       string   `json:"name"`
+	Role       string   `json:"role"`
+	ExpiresAt  string   `json:"expiresAt,omitempty"`
+	AllowedIPs []string `json:"allowedIps,omitempty"`
+}
+
+type TokenRotateRequest struct {
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
+
+type APIToken struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Role       string   `json:"role"`
+	Hint       string   `json:"hint"`
+	Status     string   `json:"status"` // active, expired or revoked
+	AllowedIPs []string `json:"allowedIps,omitempty"`
+	CreatedAt  string   `json:"createdAt"`
+	RotatedAt  string   `json:"rotatedAt,omitempty"`
+	ExpiresAt  string   `json:"expiresAt,omitempty"`
+	RevokedAt  string   `json:"revokedAt,omitempty"`
+	Token      string   `json:"token,omitempty"` // only returned on creation and rotation
+}
+
+type TokenResponse struct {
+	Token *APIToken `json:"token,omitempty"`
+	Error string    `json:"error,omitempty"`
+	Code  string    `json:"code,omitempty"`
+}
+
+type TokensResponse struct {
+	Tokens []APIToken `json:"tokens"`
+}
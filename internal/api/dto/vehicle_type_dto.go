@@ -0,0 +1,12 @@
+package dto
+
+type VehicleType struct {
+	Name     string  `json:"name"`
+	SpotCode string  `json:"spotCode"`
+	Size     string  `json:"size"`
+	Rate     float64 `json:"rate"`
+}
+
+type VehicleTypesResponse struct {
+	VehicleTypes []VehicleType `json:"vehicleTypes"`
+}
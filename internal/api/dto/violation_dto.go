@@ -0,0 +1,35 @@
+package dto
+
+type Violation struct {
+	ID            int     `json:"id"`
+	VehicleNumber string  `json:"vehicleNumber"`
+	SpotID        string  `json:"spotId"`
+	Kind          string  `json:"kind"`
+	Note          string  `json:"note,omitempty"`
+	Status        string  `json:"status"`
+	ReportedBy    string  `json:"reportedBy"`
+	ReportedAt    string  `json:"reportedAt"`
+	ResolvedBy    string  `json:"resolvedBy,omitempty"`
+	ResolvedAt    string  `json:"resolvedAt,omitempty"`
+	Fine          float64 `json:"fine,omitempty"`
+}
+
+type FlagViolationRequest struct {
+	VehicleNumber string `json:"vehicleNumber"`
+	Kind          string `json:"kind"`
+	Note          string `json:"note,omitempty"`
+}
+
+type ResolveViolationRequest struct {
+	Fine float64 `json:"fine,omitempty"`
+}
+
+type ViolationResponse struct {
+	Violation *Violation `json:"violation,omitempty"`
+	Error     string     `json:"error,omitempty"`
+	Code      string     `json:"code,omitempty"`
+}
+
+type ViolationListResponse struct {
+	Violations []Violation `json:"violations"`
+}
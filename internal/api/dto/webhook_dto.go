@@ -0,0 +1,44 @@
+package dto
+
+type WebhookSubscriptionRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+type WebhookSubscription struct {
+	ID        int      `json:"id"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Secret    string   `json:"secret,omitempty"` // only returned on creation
+	CreatedAt string   `json:"createdAt"`
+}
+
+type WebhookSubscriptionResponse struct {
+	Subscription *WebhookSubscription `json:"subscription,omitempty"`
+	Error        string               `json:"error,omitempty"`
+	Code         string               `json:"code,omitempty"`
+}
+
+type WebhookSubscriptionsResponse struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions"`
+}
+
+type WebhookDelivery struct {
+	ID             int    `json:"id"`
+	SubscriptionID int    `json:"subscriptionId"`
+	EventType      string `json:"eventType"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	StatusCode     int    `json:"statusCode,omitempty"`
+	Error          string `json:"error,omitempty"`
+	CreatedAt      string `json:"createdAt"`
+	NextAttemptAt  string `json:"nextAttemptAt,omitempty"`
+	DeliveredAt    string `json:"deliveredAt,omitempty"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Code       string            `json:"code,omitempty"`
+}
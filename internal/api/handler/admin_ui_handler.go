@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"embed"
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+)
+
+// the admin dashboard page, built into the binary so it ships with the server
+//
+//go:embed ui/index.html
+var adminUI embed.FS
+
+// handles the GET /admin/ui endpoint, the page calls the other endpoints itself and asks for the
+// attendant token needed by the admin ones
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/ui"
+**/
+
+func (h *ParkingHandler) handleAdminUI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	page, err := adminUI.ReadFile("ui/index.html")
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(page)
+}
+
+// SetOIDCEndpoints lets the dashboard sign staff in with an OpenID Connect provider
+func (h *ParkingHandler) SetOIDCEndpoints(endpoints auth.OIDCEndpoints) {
+	h.oidc = &endpoints
+}
+
+// handles the GET /admin/ui/oidc endpoint, telling the dashboard where staff sign in
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/ui/oidc"
+**/
+
+func (h *ParkingHandler) handleAdminUIOIDC(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.OIDCConfigResponse{
+		Issuer:                h.oidc.Issuer,
+		ClientID:              h.oidc.ClientID,
+		AuthorizationEndpoint: h.oidc.AuthorizationEndpoint,
+		TokenEndpoint:         h.oidc.TokenEndpoint,
+	})
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	pkgerrors "parking-lot-system/pkg/errors"
+)
+
+// handles the POST /anpr/events endpoint, called by cameras signed in with the device session of a camera.
+// The cameraId defaults to the camera signed in and cannot name another one.
+
+/** cURL example
+curl -X POST http://localhost:8080/anpr/events \
+     -H "Authorization: Bearer <camera access token>" \
+     -H "Content-Type: application/json" \
+     -d '{"cameraId": "gate-1-in", "gate": 1, "direction": "entry", "plate": "B1234XYZ", "vehicleType": "Automobile"}'
+**/
+
+func (h *ParkingHandler) handlePlateRecognition(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.PlateRecognitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+	if req.CameraID == "" {
+		req.CameraID = identity.Name
+	}
+	if req.CameraID != identity.Name {
+		writeServiceError(w, r, fmt.Errorf("%w: signed in as camera %s", pkgerrors.ErrForbidden, identity.Name))
+		return
+	}
+
+	spotID, err := h.service.HandlePlateRecognition(req.Direction, req.VehicleType, req.Plate, req.Gate)
+	resp := dto.PlateRecognitionResponse{Direction: req.Direction}
+
+	if err != nil {
+		log.Printf("ANPR %s event from camera %s for %s failed: %v", req.Direction, req.CameraID, req.Plate, err)
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.SpotID = spotID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
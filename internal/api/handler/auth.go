@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"parking-lot-system/internal/auth"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"slices"
+	"strconv"
+)
+
+// authenticatedHandler is a handler that receives the identity of the caller
+type authenticatedHandler func(w http.ResponseWriter, r *http.Request, identity auth.Identity)
+
+// requireRole only lets callers authenticated with the given role through
+func (h *ParkingHandler) requireRole(role string, next authenticatedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		identity, err := h.auth.Authenticate(r)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		if !identity.HasRole(role) {
+			writeServiceError(w, r, pkgerrors.ErrForbidden)
+			return
+		}
+
+		next(w, r, identity)
+	}
+}
+
+// SetPermissionRoles grants a permission to the given roles only
+func (h *ParkingHandler) SetPermissionRoles(permission string, roles []string) {
+	h.permissions[permission] = roles
+}
+
+// requirePermission only lets callers authenticated with a role granted the permission through
+func (h *ParkingHandler) requirePermission(permission string, next authenticatedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		identity, err := h.auth.Authenticate(r)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		if !slices.ContainsFunc(h.permissions[permission], identity.HasRole) {
+			writeServiceError(w, r, pkgerrors.ErrForbidden)
+			return
+		}
+
+		next(w, r, identity)
+	}
+}
+
+// requireDeviceSession only lets enrolled devices of the given kinds, or of any kind when none are given,
+// through. Unlike requireDevice it ignores REQUIRE_DEVICE_TOKENS and turns staff away: the endpoint acts
+// for the device itself.
+func (h *ParkingHandler) requireDeviceSession(kinds []string, next authenticatedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		identity, err := h.auth.Authenticate(r)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		if !identity.HasRole(auth.RoleDevice) || (len(kinds) > 0 && !slices.Contains(kinds, identity.DeviceKind)) {
+			writeServiceError(w, r, pkgerrors.ErrForbidden)
+			return
+		}
+
+		next(w, r, identity)
+	}
+}
+
+// requireOwnDevice only lets the enrolled device named by the {id} path value through, so a device cannot
+// act as another one
+func (h *ParkingHandler) requireOwnDevice(next authenticatedHandler) http.HandlerFunc {
+	return h.requireDeviceSession(nil, func(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+		if identity.Name != r.PathValue("id") {
+			writeServiceError(w, r, fmt.Errorf("%w: signed in as device %s", pkgerrors.ErrForbidden, identity.Name))
+			return
+		}
+
+		next(w, r, identity)
+	})
+}
+
+// requireTicketHolder only lets the holder of the ticket named by the {id} path value through, with the
+// token of the ticket in ?token=, and staff
+func (h *ParkingHandler) requireTicketHolder(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ticketID, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "ticket ID must be an integer")
+			return
+		}
+
+		if token := r.URL.Query().Get("token"); token != "" {
+			if err := h.service.CheckTicketToken(ticketID, token); err != nil {
+				writeServiceError(w, r, err)
+				return
+			}
+			next(w, r)
+			return
+		}
+
+		identity, err := h.auth.Authenticate(r)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		if !slices.ContainsFunc(auth.Roles, identity.HasRole) {
+			writeServiceError(w, r, fmt.Errorf("%w: only staff fetch tickets without their token", pkgerrors.ErrForbidden))
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// requestDevice returns the enrolled device a request comes from, the one the caller is signed in as,
+// or none for staff and anonymous callers. A device named in the request must be that device.
+func requestDevice(identity auth.Identity, named string) (string, error) {
+	device := ""
+	if identity.HasRole(auth.RoleDevice) {
+		device = identity.Name
+	}
+	if named != "" && named != device {
+		return "", fmt.Errorf("%w: the request names device %s", pkgerrors.ErrForbidden, named)
+	}
+	return device, nil
+}
+
+// requireDevice only lets enrolled kiosks and pay stations and staff through when device tokens are
+// required, and lets everyone through otherwise. The token of a request is checked whenever it carries
+// one, so the handler knows the device the request comes from; anonymous callers get the zero identity.
+func (h *ParkingHandler) requireDevice(next authenticatedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, present := auth.BearerToken(r); !present && !h.requireDevices {
+			next(w, r, auth.Identity{})
+			return
+		}
+
+		identity, err := h.auth.Authenticate(r)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		if h.requireDevices && !identity.HasRole(auth.RoleDevice) && !slices.ContainsFunc(auth.Roles, identity.HasRole) {
+			writeServiceError(w, r, pkgerrors.ErrForbidden)
+			return
+		}
+
+		next(w, r, identity)
+	}
+}
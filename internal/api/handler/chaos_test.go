@@ -0,0 +1,26 @@
+//go:build chaos
+
+package handler
+
+import (
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/domain/spotid"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"testing"
+)
+
+func TestParkReportsStoreTimeoutsAsUnavailable(t *testing.T) {
+	repo := repository.NewFaultInjectingRepository(repository.NewParkingRepository(spotid.Default), repository.FaultConfig{TimeoutRate: 1}, 1)
+	h := newTestHandlerOn(t, repo, 1)
+
+	var resp dto.ParkResponse
+	req := dto.ParkRequest{VehicleType: "Automobile", VehicleNumber: "B1"}
+	if status := serve(t, h.requireDevice(h.handlePark), http.MethodPost, "/park", "", req, &resp); status != http.StatusServiceUnavailable {
+		t.Fatalf("want status %d, got %d", http.StatusServiceUnavailable, status)
+	}
+	if resp.Code != string(pkgerrors.ErrStoreTimeout.Code) || resp.SpotID != "" {
+		t.Fatalf("got %+v, want the %s code and no spot", resp, pkgerrors.ErrStoreTimeout.Code)
+	}
+}
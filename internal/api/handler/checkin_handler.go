@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strconv"
+)
+
+// converts a check-in into its API representation
+func toCheckInResponse(r *http.Request, checkIn parking.CheckIn) dto.CheckInResponse {
+	resp := dto.CheckInResponse{
+		CheckInID:     checkIn.ID,
+		VehicleNumber: checkIn.VehicleNumber,
+		Status:        checkIn.Status,
+		Position:      checkIn.Position,
+		SpotID:        checkIn.SpotID,
+	}
+	if checkIn.Error != nil {
+		resp.Reason = localize(r, checkIn.Error)
+	}
+	return resp
+}
+
+// handles the POST /checkin endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/checkin \
+     -H "Content-Type: application/json" \
+     -d '{"vehicleType": "Automobile", "vehicleNumber": "AB123CD", "gate": 1}'
+**/
+
+func (h *ParkingHandler) handleCheckIn(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.CheckInRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	checkIn, err := h.service.CheckIn(req.VehicleType, req.VehicleNumber, parking.ParkOptions{Gate: req.Gate, Zone: req.Zone, Size: req.Size})
+	resp := dto.CheckInResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp = toCheckInResponse(r, checkIn)
+		w.WriteHeader(http.StatusAccepted)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /checkin/{id} endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/checkin/1"
+**/
+
+func (h *ParkingHandler) handleCheckInStatus(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "id must be an integer")
+		return
+	}
+
+	checkIn, err := h.service.GetCheckIn(id)
+	resp := dto.CheckInResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp = toCheckInResponse(r, checkIn)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/devicelink"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// devices are not browsers, they connect without an Origin header and pass the default origin check
+var upgrader = websocket.Upgrader{}
+
+// SetCommandHub enables pushing commands to devices connected over WebSocket
+func (h *ParkingHandler) SetCommandHub(commands *devicelink.Hub) {
+	h.commands = commands
+}
+
+// handles the GET /devices/{id}/ws endpoint, the WebSocket a registered device receives its commands on.
+// Commands arrive as {"type": "command", "id": 7, "action": "show_message", "message": "Lot full"}
+// and are answered with {"type": "ack", "id": 7, "ok": true} or {"type": "ack", "id": 7, "error": "..."}.
+// Only the device itself connects, with the access token of its device session.
+
+/** cURL example
+curl -i http://localhost:8080/devices/entrance-gate-1/ws \
+     -H "Authorization: Bearer <device access token>" \
+     -H "Connection: Upgrade" -H "Upgrade: websocket" \
+     -H "Sec-WebSocket-Version: 13" -H "Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ=="
+**/
+
+func (h *ParkingHandler) handleDeviceSocket(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	id := r.PathValue("id")
+	if _, err := h.service.Heartbeat(id); err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	socket, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader already answered the request
+		return
+	}
+	h.logger.Info("device connected", "device", id)
+
+	h.commands.Serve(id, socket, func() {
+		h.service.Heartbeat(id)
+	})
+}
+
+// handles the GET and POST /admin/commands endpoint, GET lists the commands of the device given by ?device= or of all devices
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/commands \
+     -H "Authorization: Bearer <admin token>" \
+     -H "Content-Type: application/json" \
+     -d '{"device": "entrance-display-1", "action": "show_message", "message": "Level 2 closed for cleaning"}'
+**/
+
+func (h *ParkingHandler) handleDeviceCommands(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	switch r.Method {
+	case http.MethodGet:
+		resp := dto.DeviceCommandsResponse{Commands: []dto.DeviceCommand{}}
+		for _, command := range h.commands.Commands(r.URL.Query().Get("device")) {
+			resp.Commands = append(resp.Commands, h.toDeviceCommand(command))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	case http.MethodPost:
+		var req dto.DeviceCommandRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+
+		command, err := h.commands.Send(req.Device, req.Action, req.Message)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+		h.logger.Info("device command sent", "actor", identity.Name, "device", command.Device, "commandId", command.ID, "action", command.Action)
+
+		resp := h.toDeviceCommand(command)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(dto.DeviceCommandResponse{Command: &resp})
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET and POST methods are allowed")
+	}
+}
+
+func (h *ParkingHandler) toDeviceCommand(command devicelink.Command) dto.DeviceCommand {
+	resp := dto.DeviceCommand{
+		ID:      command.ID,
+		Device:  command.Device,
+		Action:  command.Action,
+		Message: command.Message,
+		Status:  command.Status,
+		Error:   command.Error,
+		SentAt:  command.SentAt.In(h.location).Format(time.RFC3339),
+	}
+	if !command.AcknowledgedAt.IsZero() {
+		resp.AcknowledgedAt = command.AcknowledgedAt.In(h.location).Format(time.RFC3339)
+	}
+	return resp
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"strconv"
+	"time"
+)
+
+// SetDeviceSessions enables the enrollment of kiosks and pay stations. With required set the kiosk
+// endpoints only serve enrolled devices and staff.
+func (h *ParkingHandler) SetDeviceSessions(devices *auth.DeviceSessions, required bool) {
+	h.devices = devices
+	h.requireDevices = required
+}
+
+// handles the POST /admin/devices/enrollments endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/devices/enrollments \
+     -H "Authorization: Bearer <admin token>" \
+     -H "Content-Type: application/json" \
+     -d '{"device": "entrance-kiosk-2", "kind": "kiosk"}'
+**/
+
+func (h *ParkingHandler) handleDeviceEnrollments(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.DeviceEnrollmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	enrollment, err := h.devices.CreateEnrollment(req.Device, req.Kind)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	h.logger.Info("device enrollment created", "actor", identity.Name, "device", enrollment.Device, "kind", enrollment.Kind)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.DeviceEnrollmentResponse{
+		Code:      enrollment.Code,
+		Device:    enrollment.Device,
+		Kind:      enrollment.Kind,
+		ExpiresAt: enrollment.ExpiresAt.In(h.location).Format(time.RFC3339),
+	})
+}
+
+// handles the GET /admin/devices/sessions endpoint
+
+/** cURL example
+curl http://localhost:8080/admin/devices/sessions \
+     -H "Authorization: Bearer <admin token>"
+**/
+
+func (h *ParkingHandler) handleDeviceSessions(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	resp := dto.DeviceSessionsResponse{Sessions: []dto.DeviceSession{}}
+	for _, session := range h.devices.List() {
+		resp.Sessions = append(resp.Sessions, h.toDeviceSession(session))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the DELETE /admin/devices/sessions/{id} endpoint
+
+/** cURL example
+curl -X DELETE http://localhost:8080/admin/devices/sessions/2 \
+     -H "Authorization: Bearer <admin token>"
+**/
+
+func (h *ParkingHandler) handleRevokeDeviceSession(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodDelete {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only DELETE method is allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "device session ID must be an integer")
+		return
+	}
+
+	session, err := h.devices.Revoke(id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	h.logger.Info("device session revoked", "actor", identity.Name, "sessionId", session.ID, "device", session.Device)
+
+	resp := h.toDeviceSession(session)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.DeviceSessionResponse{Session: &resp})
+}
+
+// handles the POST /devices/enroll endpoint, called by the device with the code an admin handed it
+
+/** cURL example
+curl -X POST http://localhost:8080/devices/enroll \
+     -H "Content-Type: application/json" \
+     -d '{"code": "plt_..."}'
+**/
+
+func (h *ParkingHandler) handleDeviceEnroll(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.DeviceEnrollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	session, tokens, err := h.devices.Enroll(req.Code)
+	if err == nil {
+		h.logger.Info("device enrolled", "sessionId", session.ID, "device", session.Device, "kind", session.Kind)
+	}
+	h.writeDeviceTokens(w, r, session, tokens, err)
+}
+
+// handles the POST /devices/token/refresh endpoint, called by the device before its access token expires
+
+/** cURL example
+curl -X POST http://localhost:8080/devices/token/refresh \
+     -H "Content-Type: application/json" \
+     -d '{"refreshToken": "plt_..."}'
+**/
+
+func (h *ParkingHandler) handleDeviceTokenRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.DeviceRefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	session, tokens, err := h.devices.Refresh(req.RefreshToken)
+	if err != nil {
+		h.logger.Warn("device token refresh rejected", "error", err)
+	}
+	h.writeDeviceTokens(w, r, session, tokens, err)
+}
+
+// writeDeviceTokens writes the tokens issued to a device or the error of the enrollment or refresh
+func (h *ParkingHandler) writeDeviceTokens(w http.ResponseWriter, r *http.Request, session auth.DeviceSession, tokens auth.DeviceTokens, err error) {
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.DeviceTokenResponse{
+		SessionID:        session.ID,
+		Device:           session.Device,
+		AccessToken:      tokens.AccessToken,
+		AccessExpiresAt:  tokens.AccessExpiresAt.In(h.location).Format(time.RFC3339),
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt.In(h.location).Format(time.RFC3339),
+	})
+}
+
+func (h *ParkingHandler) toDeviceSession(session auth.DeviceSession) dto.DeviceSession {
+	resp := dto.DeviceSession{
+		ID:               session.ID,
+		Device:           session.Device,
+		Kind:             session.Kind,
+		Status:           "active",
+		EnrolledAt:       session.EnrolledAt.In(h.location).Format(time.RFC3339),
+		RefreshedAt:      session.RefreshedAt.In(h.location).Format(time.RFC3339),
+		AccessExpiresAt:  session.AccessExpiresAt.In(h.location).Format(time.RFC3339),
+		RefreshExpiresAt: session.RefreshExpiresAt.In(h.location).Format(time.RFC3339),
+	}
+	if !time.Now().Before(session.RefreshExpiresAt) {
+		resp.Status = "expired"
+	}
+	if session.IsRevoked() {
+		resp.RevokedAt = session.RevokedAt.In(h.location).Format(time.RFC3339)
+		resp.Status = "revoked"
+	}
+	return resp
+}
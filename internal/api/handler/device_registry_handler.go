@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/repository"
+	"time"
+)
+
+// handles the GET and POST /admin/devices endpoint, GET lists the devices with a status when one is given
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/devices?status=stale" \
+     -H "Authorization: Bearer <admin token>"
+
+curl -X POST http://localhost:8080/admin/devices \
+     -H "Authorization: Bearer <admin token>" \
+     -H "Content-Type: application/json" \
+     -d '{"id": "exit-kiosk-1", "type": "kiosk", "location": "Level 0, south exit", "gate": 2}'
+**/
+
+func (h *ParkingHandler) handleDevices(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	switch r.Method {
+	case http.MethodGet:
+		devices, err := h.service.GetDevices(r.URL.Query().Get("status"))
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		resp := dto.DevicesResponse{Devices: []dto.Device{}}
+		for _, device := range devices {
+			resp.Devices = append(resp.Devices, h.toDevice(device))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	case http.MethodPost:
+		var req dto.DeviceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+
+		device, err := h.service.RegisterDevice(identity.Name, repository.Device{
+			ID:       req.ID,
+			Type:     req.Type,
+			Location: req.Location,
+			Gate:     req.Gate,
+		})
+		h.writeDeviceResponse(w, r, device, err, http.StatusCreated)
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET and POST methods are allowed")
+	}
+}
+
+// handles the PUT and DELETE /admin/devices/{id} endpoint
+
+/** cURL example
+curl -X PUT http://localhost:8080/admin/devices/exit-kiosk-1 \
+     -H "Authorization: Bearer <admin token>" \
+     -H "Content-Type: application/json" \
+     -d '{"type": "kiosk", "location": "Level 0, north exit", "gate": 3}'
+**/
+
+func (h *ParkingHandler) handleDevice(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	switch r.Method {
+	case http.MethodPut:
+		var req dto.DeviceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+
+		device, err := h.service.UpdateDevice(identity.Name, repository.Device{
+			ID:       r.PathValue("id"),
+			Type:     req.Type,
+			Location: req.Location,
+			Gate:     req.Gate,
+		})
+		h.writeDeviceResponse(w, r, device, err, http.StatusOK)
+	case http.MethodDelete:
+		device, err := h.service.RemoveDevice(identity.Name, r.PathValue("id"))
+		h.writeDeviceResponse(w, r, device, err, http.StatusOK)
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only PUT and DELETE methods are allowed")
+	}
+}
+
+// deviceGate returns the gate a registered device is assigned to, 0 when it has none or is unknown
+func (h *ParkingHandler) deviceGate(id string) int {
+	if id == "" {
+		return 0
+	}
+	devices, _ := h.service.GetDevices("")
+	for _, device := range devices {
+		if device.ID == id {
+			return device.Gate
+		}
+	}
+	return 0
+}
+
+// handles the POST /devices/{id}/heartbeat endpoint, called periodically by gate controllers, kiosks and sensors.
+// Only the device itself sends its heartbeat, with the access token of its device session.
+
+/** cURL example
+curl -X POST http://localhost:8080/devices/exit-kiosk-1/heartbeat -H "Authorization: Bearer <device access token>"
+**/
+
+func (h *ParkingHandler) handleDeviceHeartbeat(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	device, err := h.service.Heartbeat(r.PathValue("id"))
+	h.writeDeviceResponse(w, r, device, err, http.StatusOK)
+}
+
+// writeDeviceResponse writes a device or the error of the operation on it
+func (h *ParkingHandler) writeDeviceResponse(w http.ResponseWriter, r *http.Request, device repository.Device, err error, status int) {
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := h.toDevice(device)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(dto.DeviceResponse{Device: &resp})
+}
+
+func (h *ParkingHandler) toDevice(device repository.Device) dto.Device {
+	resp := dto.Device{
+		ID:           device.ID,
+		Type:         device.Type,
+		Location:     device.Location,
+		Gate:         device.Gate,
+		Status:       h.service.DeviceStatus(device, time.Now()),
+		RegisteredAt: device.RegisteredAt.In(h.location).Format(time.RFC3339),
+	}
+	if !device.LastSeen.IsZero() {
+		resp.LastSeen = device.LastSeen.In(h.location).Format(time.RFC3339)
+	}
+	if h.commands != nil {
+		resp.Connected = h.commands.Connected(device.ID)
+	}
+	return resp
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"strconv"
+)
+
+// how long signs and intermediate caches may reuse a display payload
+const displayMaxAge = 5
+
+// handles the GET /display/{floor} endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/display/0"
+**/
+
+func (h *ParkingHandler) handleDisplayBoard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	floor, err := strconv.Atoi(r.PathValue("floor"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "floor must be an integer")
+		return
+	}
+
+	board, err := h.service.GetDisplayBoard(floor)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	resp := dto.DisplayBoardResponse{
+		Floor:        board.Floor,
+		Free:         board.Free,
+		VehicleTypes: make(map[string]dto.DisplayVehicleType, len(board.VehicleTypes)),
+	}
+	if board.Incident.Active {
+		resp.Emergency = localizeText(r, "display.emergency")
+		resp.Reason = board.Incident.Reason
+	}
+
+	for vehicleType, hint := range board.VehicleTypes {
+		item := dto.DisplayVehicleType{
+			Free:      hint.Free,
+			AisleFree: hint.AisleFree,
+			Hint:      localizeText(r, "display.full"),
+		}
+		if hint.Aisle >= 0 {
+			aisle := hint.Aisle
+			item.Aisle = &aisle
+			item.Hint = localizeText(r, "display.aisle", aisle)
+		}
+		resp.VehicleTypes[vehicleType] = item
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", displayMaxAge))
+	w.Header().Set("Vary", "Accept-Language")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strconv"
+	"time"
+)
+
+// handles the GET /park/retries/{id} endpoint, polled by the gate after /park answered 202 with the
+// duplicate_retrying code
+
+/** cURL example
+curl -X GET "http://localhost:8080/park/retries/1"
+**/
+
+func (h *ParkingHandler) handleParkRetry(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "id must be an integer")
+		return
+	}
+
+	retry, err := h.service.GetParkRetry(id)
+	resp := dto.ParkRetryResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.RetryID = retry.ID
+		resp.VehicleNumber = retry.VehicleNumber
+		resp.Status = retry.Status
+		resp.SpotID = retry.SpotID
+		if retry.Status == parking.RetryParked {
+			resp.TicketID, resp.WalletPassURL = h.walletPassURL(retry.VehicleNumber)
+		}
+		if retry.Error != nil {
+			resp.Reason = localize(r, retry.Error)
+		}
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /admin/duplicates endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/duplicates" -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handlePendingDuplicates(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	resp := dto.PendingDuplicatesResponse{Duplicates: []dto.PendingDuplicate{}}
+	for _, hold := range h.service.PendingDuplicates() {
+		resp.Duplicates = append(resp.Duplicates, dto.PendingDuplicate{
+			HoldID:        hold.ID,
+			VehicleNumber: hold.VehicleNumber,
+			VehicleType:   hold.VehicleType,
+			SpotID:        hold.SpotID,
+			Gate:          hold.Gate,
+			ExpiresAt:     hold.ExpiresAt.In(h.location).Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the POST /admin/duplicates/{id} endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/duplicates/3 \
+     -H "Authorization: Bearer <attendant token>" \
+     -H "Content-Type: application/json" \
+     -d '{"action": "confirm", "vehicleNumber": "AB123CE"}'
+**/
+
+func (h *ParkingHandler) handleResolveDuplicate(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "id must be an integer")
+		return
+	}
+
+	var req dto.ResolveDuplicateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	hold, err := h.service.ResolveDuplicate(identity.Name, id, req.Action, req.VehicleNumber)
+	resp := dto.ResolveDuplicateResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.Success = true
+		resp.SpotID = hold.SpotID
+		resp.VehicleNumber = hold.VehicleNumber
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
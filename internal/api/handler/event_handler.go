@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	"strconv"
+	"time"
+)
+
+// handles the GET /events endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/events?vehicleNumber=B1234XY&from=2025-05-20T00:00:00Z&limit=50" \
+     -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleEvents(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	query, err := h.parseEventQuery(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	page, err := h.service.QueryEvents(query)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := dto.EventsResponse{Events: make([]dto.Event, 0, len(page.Events))}
+	for _, event := range page.Events {
+		resp.Events = append(resp.Events, dto.Event{
+			ID:            event.ID,
+			Type:          event.Type,
+			Time:          event.Time.In(h.location).Format(time.RFC3339Nano),
+			SpotID:        event.SpotID,
+			VehicleNumber: event.VehicleNumber,
+			VehicleType:   event.VehicleType,
+			Gate:          event.Gate,
+			Device:        event.Device,
+			Reason:        event.Reason,
+		})
+	}
+	if page.HasMore {
+		resp.NextAfter = page.Events[len(page.Events)-1].ID
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// parseEventQuery reads the filters and paging parameters of an event log query
+func (h *ParkingHandler) parseEventQuery(r *http.Request) (parking.EventQuery, error) {
+	values := r.URL.Query()
+	query := parking.EventQuery{
+		Type:          values.Get("type"),
+		VehicleNumber: values.Get("vehicleNumber"),
+		SpotID:        values.Get("spotId"),
+		Device:        values.Get("device"),
+	}
+
+	for name, target := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		if value := values.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return parking.EventQuery{}, fmt.Errorf("invalid %s parameter: %v", name, err)
+			}
+			*target = parsed.In(h.location)
+		}
+	}
+
+	for name, target := range map[string]*int{"after": &query.After, "limit": &query.Limit} {
+		if value := values.Get(name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 {
+				return parking.EventQuery{}, fmt.Errorf("%s query parameter must be a non-negative integer", name)
+			}
+			*target = parsed
+		}
+	}
+
+	return query, nil
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"time"
+)
+
+// handles the GET /federation/availability endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/federation/availability"
+**/
+
+func (h *ParkingHandler) handleFederationAvailability(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	combined := h.federation.Availability(r.Context())
+	resp := dto.FederationAvailabilityResponse{
+		FetchedAt: combined.FetchedAt.In(h.location).Format(time.RFC3339),
+		Overall: dto.FederationTotals{
+			Available: combined.Available,
+			Capacity:  combined.Capacity,
+		},
+		VehicleTypes: make(map[string]dto.FederationTotals, len(combined.VehicleTypes)),
+		Peers:        make([]dto.FederationPeer, 0, len(combined.Peers)),
+	}
+
+	for vehicleType, availability := range combined.VehicleTypes {
+		resp.VehicleTypes[vehicleType] = dto.FederationTotals{
+			Available: availability.Available,
+			Capacity:  availability.Capacity,
+		}
+	}
+
+	for _, peer := range combined.Peers {
+		item := dto.FederationPeer{
+			URL:          peer.URL,
+			Reachable:    peer.Reachable,
+			Error:        peer.Error,
+			VehicleTypes: peer.VehicleTypes,
+		}
+		if peer.Reachable {
+			overall := peer.Overall
+			item.Overall = &overall
+		}
+		resp.Peers = append(resp.Peers, item)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/gate"
+	"time"
+)
+
+// handles the GET /gates endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/gates"
+**/
+
+func (h *ParkingHandler) handleGateStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	resp := dto.GateStatusResponse{Gates: []dto.GateStatus{}}
+	for _, status := range h.gates.Statuses(r.Context()) {
+		resp.Gates = append(resp.Gates, dto.GateStatus{
+			Gate:  status.Gate,
+			State: status.State,
+			Error: status.Error,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the POST and DELETE /admin/gates/open endpoint. POST opens every barrier and keeps them open,
+// e.g. to evacuate the lot, DELETE closes them and returns to opening them for each vehicle.
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/gates/open -H "Authorization: Bearer <admin token>"
+
+curl -X DELETE http://localhost:8080/admin/gates/open -H "Authorization: Bearer <admin token>"
+**/
+
+func (h *ParkingHandler) handleOpenAllGates(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	var results []gate.CommandResult
+	switch r.Method {
+	case http.MethodPost:
+		results = h.gates.OpenAll(r.Context())
+	case http.MethodDelete:
+		results = h.gates.ReleaseAll(r.Context())
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST and DELETE methods are allowed")
+		return
+	}
+
+	resp := dto.GateCommandResponse{HeldOpen: h.gates.HeldOpen(), Gates: []dto.GateCommandResult{}}
+	for _, result := range results {
+		resp.Gates = append(resp.Gates, dto.GateCommandResult{
+			Gate:   result.Gate,
+			Status: result.Status,
+			Error:  result.Error,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /gates/queues endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/gates/queues"
+**/
+
+func (h *ParkingHandler) handleExitQueues(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	now := time.Now()
+	resp := dto.ExitQueueResponse{Queues: []dto.ExitQueue{}}
+	for _, queue := range h.gates.ExitQueues() {
+		vehicles := make([]dto.ExitTicket, len(queue.Tickets))
+		for i, ticket := range queue.Tickets {
+			vehicles[i] = dto.ExitTicket{
+				Position:             ticket.Position,
+				VehicleNumber:        ticket.VehicleNumber,
+				SpotID:               ticket.SpotID,
+				ReadyAt:              ticket.ReadyAt.In(h.location).Format(time.RFC3339),
+				EstimatedWaitSeconds: int(math.Ceil(ticket.Wait(now).Seconds())),
+			}
+		}
+
+		resp.Queues = append(resp.Queues, dto.ExitQueue{
+			Gate:       queue.Gate,
+			Throughput: queue.Throughput,
+			Length:     len(vehicles),
+			Vehicles:   vehicles,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
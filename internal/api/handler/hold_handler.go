@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// handles the POST /park/hold endpoint, for enrolled devices. The hold is placed from the device
+// the caller is signed in as.
+
+/** cURL example
+curl -X POST http://localhost:8080/park/hold \
+     -H "Authorization: Bearer <device access token>" \
+     -H "Content-Type: application/json" \
+     -d '{"vehicleType": "Automobile", "spotId": "0-0-2", "gate": 1}'
+**/
+
+func (h *ParkingHandler) handleHoldSpot(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.HoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	device, err := requestDevice(identity, req.Device)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	hold, err := h.service.HoldSpot(req.VehicleType, req.VehicleNumber, req.SpotID, parking.ParkOptions{Gate: req.Gate, Zone: req.Zone, Size: req.Size, Contact: req.Contact, Device: device})
+	resp := dto.HoldResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.HoldID = hold.ID
+		resp.SpotID = hold.SpotID
+		resp.ExpiresAt = hold.ExpiresAt.In(h.location).Format(time.RFC3339)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the POST /park/confirm endpoint, for enrolled devices. A hold is only confirmed by the
+// device the caller is signed in as when it was placed from that device.
+
+/** cURL example
+curl -X POST http://localhost:8080/park/confirm \
+     -H "Authorization: Bearer <device access token>" \
+     -H "Content-Type: application/json" \
+     -d '{"holdId": 1, "vehicleNumber": "AB123CD"}'
+**/
+
+func (h *ParkingHandler) handleConfirmHold(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.ConfirmHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	hold, err := h.service.ConfirmHold(req.HoldID, req.VehicleNumber, identity.Name)
+	resp := dto.ConfirmHoldResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.SpotID = hold.SpotID
+		resp.VehicleNumber = hold.VehicleNumber
+		resp.TicketID, resp.WalletPassURL = h.walletPassURL(hold.VehicleNumber)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
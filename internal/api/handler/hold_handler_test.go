@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/domain/spotid"
+	"parking-lot-system/internal/gate"
+	"parking-lot-system/internal/repository"
+	"testing"
+	"time"
+)
+
+// newTestHandler returns a handler on a lot of one row of spots, with the kiosks kiosk-1 and kiosk-2
+// enrolled and signed in with the tokens "kiosk-1" and "kiosk-2", and the admin token "admin"
+func newTestHandler(t *testing.T, spots int) *ParkingHandler {
+	t.Helper()
+
+	return newTestHandlerOn(t, repository.NewParkingRepository(spotid.Default), spots)
+}
+
+// newTestHandlerOn returns the handler of newTestHandler on a given repository
+func newTestHandlerOn(t *testing.T, repo repository.ParkingRepository, spots int) *ParkingHandler {
+	t.Helper()
+
+	service := parking.NewParkingService(repo)
+	if err := service.InitializeParkingLot(1, 1, spots, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	for col := 0; col < spots; col++ {
+		if err := service.ConfigureSpot(0, 0, col, "A-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tokens := map[string]auth.Identity{"admin": {Name: "admin", Roles: []string{auth.RoleAdmin}}}
+	for _, id := range []string{"kiosk-1", "kiosk-2"} {
+		if _, err := service.RegisterDevice("admin", repository.Device{ID: id, Type: parking.DeviceKiosk}); err != nil {
+			t.Fatal(err)
+		}
+		tokens[id] = auth.Identity{Name: id, Roles: []string{auth.RoleDevice}, DeviceKind: parking.DeviceKiosk}
+	}
+	gates := gate.NewManager(map[int]gate.GateController{}, time.Second)
+	return NewParkingHandler(service, nil, auth.NewStaticTokenAuthenticator(tokens), gates, nil, nil)
+}
+
+// serve sends a request with a JSON body, signed in with token unless it is empty, and decodes the
+// JSON response into resp
+func serve(t *testing.T, handler http.HandlerFunc, method, target, token string, body, resp any) int {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(method, target, bytes.NewReader(payload))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler(recorder, req)
+	if resp != nil {
+		if err := json.NewDecoder(recorder.Body).Decode(resp); err != nil {
+			t.Fatalf("%s %s: decoding the response: %v", method, target, err)
+		}
+	}
+	return recorder.Code
+}
+
+func TestConfirmHoldFromAnotherDeviceIsForbidden(t *testing.T) {
+	h := newTestHandler(t, 1)
+	hold := h.requireDeviceSession(nil, h.handleHoldSpot)
+	confirm := h.requireDeviceSession(nil, h.handleConfirmHold)
+
+	var held dto.HoldResponse
+	if status := serve(t, hold, http.MethodPost, "/park/hold", "kiosk-1",
+		dto.HoldRequest{VehicleType: "Automobile", VehicleNumber: "B1234XY"}, &held); status != http.StatusOK {
+		t.Fatalf("hold: status %d, %s", status, held.Error)
+	}
+
+	// kiosk-2 cannot confirm the hold of kiosk-1, whatever the body says
+	var forged map[string]any
+	body := map[string]any{"holdId": held.HoldID, "device": "kiosk-1"}
+	if status := serve(t, confirm, http.MethodPost, "/park/confirm", "kiosk-2", body, &forged); status != http.StatusForbidden {
+		t.Fatalf("confirm from kiosk-2: want status %d, got %d", http.StatusForbidden, status)
+	}
+	if forged["code"] != "forbidden" {
+		t.Errorf("confirm from kiosk-2: want code forbidden, got %v", forged["code"])
+	}
+
+	// without a device session the request does not reach the hold at all
+	if status := serve(t, confirm, http.MethodPost, "/park/confirm", "", body, nil); status != http.StatusUnauthorized {
+		t.Errorf("confirm without a token: want status %d, got %d", http.StatusUnauthorized, status)
+	}
+	if status := serve(t, confirm, http.MethodPost, "/park/confirm", "admin", body, nil); status != http.StatusForbidden {
+		t.Errorf("confirm with a staff token: want status %d, got %d", http.StatusForbidden, status)
+	}
+
+	var confirmed dto.ConfirmHoldResponse
+	if status := serve(t, confirm, http.MethodPost, "/park/confirm", "kiosk-1", body, &confirmed); status != http.StatusOK {
+		t.Fatalf("confirm from kiosk-1: status %d, %s", status, confirmed.Error)
+	}
+	if confirmed.SpotID != held.SpotID {
+		t.Errorf("confirm from kiosk-1: want spot %s, got %s", held.SpotID, confirmed.SpotID)
+	}
+}
+
+func TestHoldNamingAnotherDeviceIsForbidden(t *testing.T) {
+	h := newTestHandler(t, 1)
+	hold := h.requireDeviceSession(nil, h.handleHoldSpot)
+
+	body := dto.HoldRequest{VehicleType: "Automobile", Device: "kiosk-2"}
+	if status := serve(t, hold, http.MethodPost, "/park/hold", "kiosk-1", body, nil); status != http.StatusForbidden {
+		t.Fatalf("hold naming kiosk-2 from kiosk-1: want status %d, got %d", http.StatusForbidden, status)
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	"strings"
+	"time"
+)
+
+// handles the GET, POST and DELETE /admin/incident endpoint. POST puts the lot into emergency mode:
+// no vehicles are let in and vehicles leave without being held back. DELETE ends it.
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/incident \
+     -H "Authorization: Bearer <admin token>" \
+     -H "Content-Type: application/json" \
+     -d '{"reason": "Fire alarm on level 2, evacuate"}'
+
+curl -X DELETE http://localhost:8080/admin/incident -H "Authorization: Bearer <admin token>"
+**/
+
+func (h *ParkingHandler) handleIncident(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	var incident parking.Incident
+	switch r.Method {
+	case http.MethodGet:
+		incident = h.service.GetIncident()
+	case http.MethodPost:
+		var req dto.IncidentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+		if strings.TrimSpace(req.Reason) == "" {
+			writeErrorResponse(w, http.StatusBadRequest, "reason is required")
+			return
+		}
+		incident = h.service.StartIncident(identity.Name, strings.TrimSpace(req.Reason))
+	case http.MethodDelete:
+		incident = h.service.EndIncident(identity.Name)
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET, POST and DELETE methods are allowed")
+		return
+	}
+
+	resp := dto.IncidentResponse{
+		Active:    incident.Active,
+		Reason:    incident.Reason,
+		StartedBy: incident.StartedBy,
+	}
+	if incident.Active {
+		resp.StartedAt = incident.StartedAt.In(h.location).Format(time.RFC3339)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"parking-lot-system/internal/metrics"
+)
+
+var (
+	// requests served, per method, endpoint and status class
+	requestsTotal = metrics.NewCounter("http_requests_total",
+		"Requests served, by status class such as 2xx or 4xx.", "method", "endpoint", "status_class")
+	// requests being served right now, per method and endpoint
+	requestsInFlight = metrics.NewGauge("http_requests_in_flight",
+		"Requests being served.", "method", "endpoint")
+)
+
+func init() {
+	metrics.Register(requestsTotal, requestsInFlight)
+}
+
+// instrumentRequests counts the requests of every endpoint and the ones in flight. Endpoints are
+// the route patterns, e.g. /checkin/{id}, so /park error rates can be told from /search ones.
+func (h *ParkingHandler) instrumentRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := methodOf(r)
+		endpoint := endpointOf(r)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		requestsInFlight.Inc(method, endpoint)
+		defer requestsInFlight.Dec(method, endpoint)
+
+		next.ServeHTTP(recorder, r)
+
+		requestsTotal.Inc(method, endpoint, fmt.Sprintf("%dxx", recorder.status/100))
+	})
+}
+
+// methodOf returns the request method, other for non-standard ones so clients cannot add label values at will
+func methodOf(r *http.Request) string {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodOptions:
+		return r.Method
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/layout"
+	pkgerrors "parking-lot-system/pkg/errors"
+)
+
+// the largest layout file accepted by PUT /admin/layout
+const maxLayoutSize = 1 << 20
+
+// handles the POST /admin/layout/reload endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/layout/reload \
+     -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleLayoutReload(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	report, err := h.layouts.Reload()
+	writeLayoutReport(w, r, report, err)
+}
+
+// handles the GET and PUT /admin/layout endpoints
+
+/** cURL example
+curl http://localhost:8080/admin/layout \
+     -H "Authorization: Bearer <attendant token>" > layout.yaml
+
+curl -X PUT http://localhost:8080/admin/layout \
+     -H "Authorization: Bearer <attendant token>" \
+     -H "Content-Type: application/yaml" \
+     --data-binary @layout.yaml
+**/
+
+func (h *ParkingHandler) handleLayout(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(h.layouts.Export())
+	case http.MethodPut:
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxLayoutSize))
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid layout: "+err.Error())
+			return
+		}
+
+		report, err := h.layouts.Import(data)
+		writeLayoutReport(w, r, report, err)
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET and PUT methods are allowed")
+	}
+}
+
+// writeLayoutReport writes the outcome of applying a layout
+func writeLayoutReport(w http.ResponseWriter, r *http.Request, report layout.Report, err error) {
+	resp := dto.LayoutReloadResponse{
+		Changed: append([]string{}, report.Changed...),
+		Skipped: make([]dto.SkippedSpot, 0, len(report.Skipped)),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		// the layout is invalid or changes the floors or gates
+		resp.Error = err.Error()
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	for _, skipped := range report.Skipped {
+		resp.Skipped = append(resp.Skipped, dto.SkippedSpot{
+			SpotID: skipped.SpotID,
+			Error:  localize(r, skipped.Err),
+			Code:   string(pkgerrors.CodeOf(skipped.Err)),
+		})
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
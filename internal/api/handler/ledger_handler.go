@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"strconv"
+	"time"
+)
+
+// handles the GET /ledger endpoint, listing the balanced ledger transactions of a ticket or of all
+// tickets, optionally only those posting to an account, with the balances of the accounts
+
+/** cURL example
+curl -X GET "http://localhost:8080/ledger?account=cash:booth-1" -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleLedger(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	ticketID := 0
+	if value := r.URL.Query().Get("ticketId"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			writeErrorResponse(w, http.StatusBadRequest, "ticketId must be a positive integer")
+			return
+		}
+		ticketID = parsed
+	}
+
+	ledger := h.service.GetLedger(ticketID, r.URL.Query().Get("account"))
+	resp := dto.LedgerResponse{
+		Transactions: make([]dto.LedgerTransaction, 0, len(ledger.Transactions)),
+		Balances:     make([]dto.AccountBalance, 0, len(ledger.Balances)),
+	}
+	for _, transaction := range ledger.Transactions {
+		lines := make([]dto.LedgerLine, len(transaction.Lines))
+		for i, line := range transaction.Lines {
+			lines[i] = dto.LedgerLine{Account: line.Account, Debit: line.Debit, Credit: line.Credit}
+		}
+		resp.Transactions = append(resp.Transactions, dto.LedgerTransaction{
+			ID:        transaction.ID,
+			Kind:      transaction.Kind,
+			TicketID:  transaction.SessionID,
+			PaymentID: transaction.PaymentID,
+			Time:      transaction.Time.In(h.location).Format(time.RFC3339),
+			Lines:     lines,
+		})
+	}
+	for _, balance := range ledger.Balances {
+		resp.Balances = append(resp.Balances, dto.AccountBalance{
+			Account: balance.Account,
+			Debit:   balance.Debit,
+			Credit:  balance.Credit,
+			Balance: balance.Balance(),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"parking-lot-system/internal/metrics"
+	"time"
+)
+
+// DefaultLatencyBudget is how long a request may take before it counts as an SLO violation
+const DefaultLatencyBudget = 500 * time.Millisecond
+
+// requests that took longer than the latency budget, per endpoint
+var sloViolations = metrics.NewCounter("http_slo_violations_total",
+	"Requests that took longer than the latency budget.", "endpoint")
+
+func init() {
+	metrics.Register(sloViolations)
+}
+
+// statusRecorder remembers the status code written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Hijack hands the connection over to a WebSocket, which counts as switching protocols
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// SetLatencyBudget sets how long a request may take before it counts as an SLO violation, 0 disables the check
+func (h *ParkingHandler) SetLatencyBudget(budget time.Duration) {
+	h.latencyBudget = budget
+}
+
+// logRequests logs every request at debug level, and failed ones at warn or error level.
+// Requests exceeding the latency budget are logged at least at warn level with their full context,
+// and counted per endpoint.
+func (h *ParkingHandler) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+		duration := time.Since(start)
+
+		level := slog.LevelDebug
+		switch {
+		case recorder.status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case recorder.status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"duration", duration,
+		}
+
+		// WebSocket connections last as long as the device stays connected
+		if h.latencyBudget > 0 && duration > h.latencyBudget && recorder.status != http.StatusSwitchingProtocols {
+			endpoint := endpointOf(r)
+			sloViolations.Inc(endpoint)
+
+			level = max(level, slog.LevelWarn)
+			attrs = append(attrs,
+				"slow", true,
+				"budget", h.latencyBudget,
+				"endpoint", endpoint,
+				"query", r.URL.RawQuery,
+				"contentLength", r.ContentLength,
+				"remoteAddr", r.RemoteAddr,
+				"userAgent", r.UserAgent(),
+			)
+		}
+
+		h.logger.Log(r.Context(), level, "request", attrs...)
+	})
+}
+
+// endpointOf returns the route pattern a request matched, e.g. /checkin/{id}, so that
+// the SLO violation counter has one series per endpoint rather than per path
+func endpointOf(r *http.Request) string {
+	_, pattern := http.DefaultServeMux.Handler(r)
+	if pattern == "" {
+		return "unmatched"
+	}
+	return pattern
+}
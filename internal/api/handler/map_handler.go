@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/i18n"
+	"strconv"
+	"strings"
+)
+
+// states of a floor map cell
+const (
+	mapOccupied = "occupied"
+	mapFree     = "free"
+	mapInactive = "inactive"
+	mapNone     = "none"
+)
+
+// symbols of the floor map cell states in the text format
+var mapSymbols = map[string]string{
+	mapOccupied: "■",
+	mapFree:     "□",
+	mapInactive: "·",
+	mapNone:     " ",
+}
+
+// states explained by the floor map legend
+var mapLegend = []string{mapOccupied, mapFree, mapInactive}
+
+// fill colors of the floor map cell states in the SVG format
+var mapColors = map[string]string{
+	mapOccupied: "#d9534f",
+	mapFree:     "#5cb85c",
+	mapInactive: "#cccccc",
+}
+
+// size of a spot in the SVG format, in pixels
+const (
+	svgSpotWidth  = 64
+	svgSpotHeight = 40
+	svgMargin     = 16
+	svgHeader     = 40 // room for the title and gates above the grid
+	svgLegend     = 32 // room for the legend below the grid
+)
+
+// handles the GET /map/{floor} and GET /map/{floor}.svg endpoints
+
+/** cURL example
+curl -X GET "http://localhost:8080/map/0?format=text"
+curl -X GET "http://localhost:8080/map/0.svg" -o floor0.svg
+**/
+
+func (h *ParkingHandler) handleFloorMap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	// Patterns only match whole path segments, so the .svg extension is part of the floor
+	floorValue, isSVG := strings.CutSuffix(r.PathValue("floor"), ".svg")
+	floor, err := strconv.Atoi(floorValue)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "floor must be an integer")
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if isSVG {
+		format = "svg"
+	}
+	if format != "" && format != "json" && format != "text" && format != "svg" {
+		writeErrorResponse(w, http.StatusBadRequest, "format must be json, text or svg")
+		return
+	}
+
+	floorMap, err := h.service.GetFloorMap(floor)
+	if err != nil {
+		writeErrorResponse(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	language := i18n.Language(r.Header.Get("Accept-Language"))
+	switch format {
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(renderFloorMap(floorMap, language)))
+		return
+	case "svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write([]byte(renderFloorMapSVG(floorMap, language)))
+		return
+	}
+
+	resp := dto.FloorMapResponse{
+		Floor: floorMap.Floor,
+		Gates: floorMap.Gates,
+		Cells: make([][]dto.MapCell, len(floorMap.Cells)),
+	}
+	for row, cells := range floorMap.Cells {
+		resp.Cells[row] = make([]dto.MapCell, len(cells))
+		for col, cell := range cells {
+			resp.Cells[row][col] = dto.MapCell{
+				SpotID:      cell.SpotID,
+				State:       mapStateOf(cell),
+				VehicleType: cell.VehicleType,
+				Parked:      cell.Parked,
+				Capacity:    cell.Capacity,
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// mapStateOf returns the state of a floor map cell, spots holding several vehicles are occupied once full
+func mapStateOf(cell parking.MapCell) string {
+	switch {
+	case !cell.Exists:
+		return mapNone
+	case !cell.IsActive:
+		return mapInactive
+	case cell.IsFull():
+		return mapOccupied
+	default:
+		return mapFree
+	}
+}
+
+// renderFloorMap draws a floor as text with a row number in front of each row and the last digit
+// of the column numbers on top. The layout has no gate positions, so the gates of the ground floor
+// are listed above the grid.
+func renderFloorMap(floorMap parking.FloorMap, language string) string {
+	var out strings.Builder
+	out.WriteString(i18n.Text(language, "map.floor", floorMap.Floor) + "\n")
+	if len(floorMap.Gates) > 0 {
+		out.WriteString(i18n.Text(language, "map.gates"))
+		for _, gate := range floorMap.Gates {
+			fmt.Fprintf(&out, " G%d", gate)
+		}
+		out.WriteString("\n")
+	}
+
+	width := len(strconv.Itoa(len(floorMap.Cells) - 1))
+	if len(floorMap.Cells) > 0 {
+		out.WriteString(strings.Repeat(" ", width+1))
+		for col := range floorMap.Cells[0] {
+			fmt.Fprintf(&out, "%d ", col%10)
+		}
+		out.WriteString("\n")
+	}
+	for row, cells := range floorMap.Cells {
+		fmt.Fprintf(&out, "%*d ", width, row)
+		for _, cell := range cells {
+			out.WriteString(mapSymbols[mapStateOf(cell)] + " ")
+		}
+		out.WriteString("\n")
+	}
+	legend := make([]string, len(mapLegend))
+	for i, state := range mapLegend {
+		legend[i] = mapSymbols[state] + " " + i18n.Text(language, "map."+state)
+	}
+	out.WriteString(strings.Join(legend, "  ") + "\n")
+
+	return out.String()
+}
+
+// renderFloorMapSVG draws a floor as an SVG plan with a colored, labeled rectangle per spot.
+// Like the text format, the gates of the ground floor are listed above the grid.
+func renderFloorMapSVG(floorMap parking.FloorMap, language string) string {
+	columns := 0
+	if len(floorMap.Cells) > 0 {
+		columns = len(floorMap.Cells[0])
+	}
+	width := max(2*svgMargin+columns*svgSpotWidth, 360)
+	height := svgHeader + len(floorMap.Cells)*svgSpotHeight + svgLegend + 2*svgMargin
+
+	var out strings.Builder
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&out, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", width, height)
+
+	title := i18n.Text(language, "map.floor", floorMap.Floor)
+	if len(floorMap.Gates) > 0 {
+		gates := make([]string, len(floorMap.Gates))
+		for i, gate := range floorMap.Gates {
+			gates[i] = fmt.Sprintf("G%d", gate)
+		}
+		title += " — " + i18n.Text(language, "map.gates") + " " + strings.Join(gates, " ")
+	}
+	fmt.Fprintf(&out, `<text x="%d" y="%d" font-size="18" font-weight="bold">%s</text>`+"\n", svgMargin, svgMargin+18, html.EscapeString(title))
+
+	for row, cells := range floorMap.Cells {
+		for col, cell := range cells {
+			state := mapStateOf(cell)
+			if state == mapNone {
+				continue
+			}
+
+			x := svgMargin + col*svgSpotWidth
+			y := svgMargin + svgHeader + row*svgSpotHeight
+			fmt.Fprintf(&out, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="#333333"><title>%s %s</title></rect>`+"\n",
+				x, y, svgSpotWidth, svgSpotHeight, mapColors[state], html.EscapeString(cell.SpotID), html.EscapeString(i18n.Text(language, "map."+state)))
+			fmt.Fprintf(&out, `<text x="%d" y="%d" font-size="11" text-anchor="middle">%s</text>`+"\n",
+				x+svgSpotWidth/2, y+svgSpotHeight/2+4, html.EscapeString(cell.SpotID))
+		}
+	}
+
+	// Legend
+	y := svgMargin + svgHeader + len(floorMap.Cells)*svgSpotHeight + 12
+	for i, state := range mapLegend {
+		x := svgMargin + i*110
+		fmt.Fprintf(&out, `<rect x="%d" y="%d" width="14" height="14" fill="%s" stroke="#333333"/>`+"\n", x, y, mapColors[state])
+		fmt.Fprintf(&out, `<text x="%d" y="%d" font-size="12">%s</text>`+"\n", x+20, y+12, html.EscapeString(i18n.Text(language, "map."+state)))
+	}
+
+	out.WriteString("</svg>\n")
+	return out.String()
+}
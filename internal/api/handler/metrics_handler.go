@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"net/http"
+	"parking-lot-system/internal/metrics"
+)
+
+// handles the GET /metrics endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/metrics"
+**/
+
+func (h *ParkingHandler) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	metrics.Default.WriteTo(w)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// handles the POST /admin/override endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/override \
+     -H "Authorization: Bearer <attendant token>" \
+     -H "Content-Type: application/json" \
+     -d '{"action": "unpark", "spotId": "0-0-1", "reason": "towed away"}'
+**/
+
+func (h *ParkingHandler) handleOverride(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.OverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	var err error
+	resp := dto.OverrideResponse{VehicleNumber: req.VehicleNumber}
+
+	switch req.Action {
+	case parking.OverridePark:
+		err = h.service.ForcePark(identity.Name, req.SpotID, req.VehicleNumber, req.Reason)
+	case parking.OverrideUnpark:
+		resp.VehicleNumber, err = h.service.ForceUnpark(identity.Name, req.SpotID, req.VehicleNumber, req.Reason)
+	default:
+		err = pkgerrors.ErrInvalidOverrideAction
+	}
+
+	if err != nil {
+		resp.VehicleNumber = ""
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.Success = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /admin/audit endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/audit" \
+     -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleAuditLog(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	resp := dto.AuditLogResponse{Entries: []dto.AuditEntry{}}
+	for _, entry := range h.service.GetAuditEntries() {
+		resp.Entries = append(resp.Entries, dto.AuditEntry{
+			Time:          entry.Time.In(h.location).Format(time.RFC3339),
+			Actor:         entry.Actor,
+			Action:        entry.Action,
+			SpotID:        entry.SpotID,
+			VehicleNumber: entry.VehicleNumber,
+			Reason:        entry.Reason,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
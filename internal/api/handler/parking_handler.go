@@ -2,19 +2,75 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"math"
 	"net/http"
 	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/devicelink"
 	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/federation"
+	"parking-lot-system/internal/gate"
+	"parking-lot-system/internal/i18n"
+	"parking-lot-system/internal/layout"
+	"parking-lot-system/internal/wallet"
+	"parking-lot-system/internal/webhook"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strconv"
+	"time"
 )
 
 type ParkingHandler struct {
-	service *parking.ParkingService
+	service    *parking.ParkingService
+	webhooks   *webhook.Dispatcher
+	auth       auth.Authenticator
+	gates      *gate.Manager
+	federation *federation.Aggregator
+	layouts    *layout.Manager
+	tokens     *auth.TokenStore     // nil when API tokens are not managed at runtime
+	oidc       *auth.OIDCEndpoints  // nil when staff do not sign in with OpenID Connect
+	devices    *auth.DeviceSessions // nil when kiosks and pay stations do not enroll
+	commands   *devicelink.Hub      // nil when no commands are pushed to devices
+	// identifies the lot on the wallet passes of tickets
+	walletIssuer wallet.Issuer
+	// whether the kiosk endpoints only serve enrolled devices and staff
+	requireDevices bool
+	// permission -> roles granted it
+	permissions map[string][]string
+	logger      *slog.Logger
+	// how long a request may take before it counts as an SLO violation, 0 when unchecked
+	latencyBudget time.Duration
+	// time zone of the lot, times in responses carry its offset and times without one are read in it
+	location *time.Location
 }
 
-func NewParkingHandler(service *parking.ParkingService) *ParkingHandler {
-	return &ParkingHandler{service: service}
+func NewParkingHandler(service *parking.ParkingService, webhooks *webhook.Dispatcher, authenticator auth.Authenticator,
+	gates *gate.Manager, aggregator *federation.Aggregator, layouts *layout.Manager) *ParkingHandler {
+	return &ParkingHandler{
+		service:       service,
+		webhooks:      webhooks,
+		auth:          authenticator,
+		gates:         gates,
+		federation:    aggregator,
+		layouts:       layouts,
+		logger:        slog.Default(),
+		latencyBudget: DefaultLatencyBudget,
+		location:      time.Local,
+		permissions:   map[string][]string{auth.PermissionRefund: {auth.RoleAdmin}},
+	}
+}
+
+// SetLocation sets the time zone of the lot
+func (h *ParkingHandler) SetLocation(location *time.Location) {
+	h.location = location
+}
+
+// SetLogger sets the logger requests are logged to
+func (h *ParkingHandler) SetLogger(logger *slog.Logger) {
+	h.logger = logger
 }
 
 // Error response helper
@@ -24,15 +80,66 @@ func writeErrorResponse(w http.ResponseWriter, statusCode int, message string) {
 	json.NewEncoder(w).Encode(map[string]string{"error": message})
 }
 
-// handles the POST /park endpoint
+// writeServiceError writes an error returned by the domain layer with its code and matching status
+func writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusOf(err))
+	json.NewEncoder(w).Encode(map[string]string{
+		"error": localize(r, err),
+		"code":  string(pkgerrors.CodeOf(err)),
+	})
+}
+
+// localize returns the message of an error returned by the domain layer in the language the client accepts
+func localize(r *http.Request, err error) string {
+	return i18n.Message(err, i18n.Language(r.Header.Get("Accept-Language")))
+}
+
+// localizeText returns a label shown in a response in the language the client accepts
+func localizeText(r *http.Request, key string, args ...any) string {
+	return i18n.Text(i18n.Language(r.Header.Get("Accept-Language")), key, args...)
+}
+
+// statusOf maps the code of an error returned by the domain layer to an HTTP status
+func statusOf(err error) int {
+	switch pkgerrors.CodeOf(err) {
+	case pkgerrors.CodeInternal:
+		return http.StatusInternalServerError
+	case pkgerrors.ErrDuplicatePending.Code, pkgerrors.ErrDuplicateRetrying.Code:
+		return http.StatusAccepted
+	case pkgerrors.ErrLotEmergency.Code, pkgerrors.ErrStoreTimeout.Code:
+		return http.StatusServiceUnavailable
+	case pkgerrors.ErrUnauthorized.Code, pkgerrors.ErrInvalidEnrollmentCode.Code:
+		return http.StatusUnauthorized
+	case pkgerrors.ErrForbidden.Code:
+		return http.StatusForbidden
+	case pkgerrors.ErrVehicleNotFound.Code, pkgerrors.ErrSubscriptionNotFound.Code, pkgerrors.ErrHoldNotFound.Code,
+		pkgerrors.ErrViolationNotFound.Code, pkgerrors.ErrCheckInNotFound.Code, pkgerrors.ErrTicketNotFound.Code,
+		pkgerrors.ErrTokenNotFound.Code, pkgerrors.ErrDeviceSessionNotFound.Code, pkgerrors.ErrDeviceNotFound.Code,
+		pkgerrors.ErrSpotBlockNotFound.Code, pkgerrors.ErrRetryNotFound.Code, pkgerrors.ErrPaymentNotFound.Code:
+		return http.StatusNotFound
+	case pkgerrors.ErrVehicleAlreadyParked.Code, pkgerrors.ErrSpotOccupied.Code, pkgerrors.ErrSpotFull.Code,
+		pkgerrors.ErrSpotInUse.Code, pkgerrors.ErrLotInUse.Code, pkgerrors.ErrNoAvailableSpot.Code,
+		pkgerrors.ErrViolationResolved.Code, pkgerrors.ErrVehicleFlagged.Code, pkgerrors.ErrReentryCooldown.Code,
+		pkgerrors.ErrVehicleCheckedIn.Code, pkgerrors.ErrSpotDeleted.Code, pkgerrors.ErrTokenRevoked.Code,
+		pkgerrors.ErrDeviceExists.Code, pkgerrors.ErrDeviceNotConnected.Code, pkgerrors.ErrSpotBlocked.Code, pkgerrors.ErrTicketClosed.Code:
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// handles the POST /park endpoint. Vehicles parked with the access token of an enrolled device are
+// parked from that device.
 
 /** cURL example
 curl -X POST http://localhost:8080/park \
+     -H "Authorization: Bearer <device access token>" \
      -H "Content-Type: application/json" \
-     -d '{"vehicleType": "Bicycle", "vehicleNumber": "BC001"}'
+     -d '{"vehicleType": "Bicycle", "vehicleNumber": "BC001", "gate": 1}'
 **/
 
-func (h *ParkingHandler) handlePark(w http.ResponseWriter, r *http.Request) {
+func (h *ParkingHandler) handlePark(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
 	if r.Method != http.MethodPost {
 		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
 		return
@@ -44,29 +151,54 @@ func (h *ParkingHandler) handlePark(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	spotID, err := h.service.Park(req.VehicleType, req.VehicleNumber)
+	device, err := requestDevice(identity, req.Device)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	spotID, err := h.service.ParkWithOptions(req.VehicleType, req.VehicleNumber,
+		parking.ParkOptions{Gate: req.Gate, Zone: req.Zone, Size: req.Size, Contact: req.Contact, Device: device})
 	resp := dto.ParkResponse{}
 
 	if err != nil {
-		resp.Error = err.Error()
-		w.WriteHeader(http.StatusBadRequest)
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		var duplicate *parking.DuplicateParkError
+		if errors.As(err, &duplicate) {
+			resp.SpotID = duplicate.Hold.SpotID
+			resp.HoldID = duplicate.Hold.ID
+		}
+		var retrying *parking.DuplicateRetryError
+		if errors.As(err, &retrying) {
+			resp.RetryID = retrying.Retry.ID
+		}
+		w.WriteHeader(statusOf(err))
 	} else {
 		resp.SpotID = spotID
+		resp.TicketID, resp.WalletPassURL = h.walletPassURL(req.VehicleNumber)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
 
-// handles the POST /unpark endpoint
+// handles the POST /unpark endpoint. Vehicles unparked with the access token of an enrolled device leave
+// from that device.
 
 /** cURL example
 curl -X POST http://localhost:8080/unpark \
+     -H "Authorization: Bearer <device access token>" \
      -H "Content-Type: application/json" \
      -d '{"spotId": "0-0-1", "vehicleNumber": "BC001"}'
+
+or without the spot ID:
+curl -X POST http://localhost:8080/unpark \
+     -H "Content-Type: application/json" \
+     -d '{"vehicleNumber": "BC001"}'
 **/
 
-func (h *ParkingHandler) handleUnpark(w http.ResponseWriter, r *http.Request) {
+func (h *ParkingHandler) handleUnpark(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
 	if r.Method != http.MethodPost {
 		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
 		return
@@ -78,15 +210,34 @@ func (h *ParkingHandler) handleUnpark(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.service.Unpark(req.SpotID, req.VehicleNumber)
+	device, err := requestDevice(identity, req.Device)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	// The spot ID is optional, exit kiosks only know the vehicle number
+	spotID, err := h.service.UnparkWithOptions(req.SpotID, req.VehicleNumber, parking.UnparkOptions{Gate: req.Gate, Device: device})
 	resp := dto.UnparkResponse{}
 
 	if err != nil {
 		resp.Success = false
-		resp.Error = err.Error()
-		w.WriteHeader(http.StatusBadRequest)
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
 	} else {
 		resp.Success = true
+		resp.SpotID = spotID
+
+		// the vehicle leaves through the gate of the device unless the request names one
+		gate := req.Gate
+		if gate == 0 {
+			gate = h.deviceGate(device)
+		}
+		if ticket, queued := h.gates.ExitTicket(gate, spotID); queued {
+			resp.QueuePosition = ticket.Position
+			resp.EstimatedWaitSeconds = int(math.Ceil(ticket.Wait(time.Now()).Seconds()))
+		}
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -96,7 +247,7 @@ func (h *ParkingHandler) handleUnpark(w http.ResponseWriter, r *http.Request) {
 // handles the GET /available endpoint
 
 /** cURL example
-curl -X GET "http://localhost:8080/available?vehicleType=Bicycle"
+curl -X GET "http://localhost:8080/available?vehicleType=Bicycle&zone=A"
 **/
 
 func (h *ParkingHandler) handleAvailableSpots(w http.ResponseWriter, r *http.Request) {
@@ -111,12 +262,13 @@ func (h *ParkingHandler) handleAvailableSpots(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	spots, err := h.service.GetAvailableSpots(vehicleType)
+	spots, err := h.service.GetAvailableSpots(vehicleType, r.URL.Query().Get("zone"), r.URL.Query().Get("size"))
 	resp := dto.AvailableSpotResponse{}
 
 	if err != nil {
-		resp.Error = err.Error()
-		w.WriteHeader(http.StatusBadRequest)
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
 	} else {
 		resp.Spots = spots
 	}
@@ -125,6 +277,89 @@ func (h *ParkingHandler) handleAvailableSpots(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(resp)
 }
 
+// handles the GET /recommend endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/recommend?vehicleType=Automobile&gate=1"
+**/
+
+func (h *ParkingHandler) handleRecommendSpot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	vehicleType := query.Get("vehicleType")
+	if vehicleType == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "vehicleType query parameter is required")
+		return
+	}
+
+	opts := parking.ParkOptions{Zone: query.Get("zone"), Size: query.Get("size")}
+	if value := query.Get("gate"); value != "" {
+		gate, err := strconv.Atoi(value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "gate must be an integer")
+			return
+		}
+		opts.Gate = gate
+	}
+
+	spotID, err := h.service.RecommendSpot(vehicleType, opts)
+	resp := dto.RecommendSpotResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.VehicleType = vehicleType
+		resp.SpotID = spotID
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /available/count endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/available/count?vehicleType=Bicycle&floor=0"
+**/
+
+func (h *ParkingHandler) handleAvailableCount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	resp := dto.AvailableCountResponse{VehicleType: r.URL.Query().Get("vehicleType")}
+
+	floor := -1
+	if value := r.URL.Query().Get("floor"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeErrorResponse(w, http.StatusBadRequest, "floor must be a non-negative integer")
+			return
+		}
+		floor = parsed
+		resp.Floor = &floor
+	}
+
+	available, err := h.service.GetAvailableCount(resp.VehicleType, floor)
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.Available = available
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
 // handles the GET /search endpoint
 
 /** cURL example
@@ -147,31 +382,165 @@ func (h *ParkingHandler) handleSearchVehicle(w http.ResponseWriter, r *http.Requ
 	resp := dto.SearchVehicleResponse{}
 
 	if err != nil {
-		resp.Error = err.Error()
-		w.WriteHeader(http.StatusBadRequest)
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
 	} else {
 		resp.SpotID = spotID
 		resp.IsParked = isParked
 		resp.WasParked = spotID != ""
+		resp.Violations = h.toViolations(h.service.GetViolations(vehicleNumber, true))
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
 }
 
+// handles the GET /admin/search/suffix endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/search/suffix?suffix=XYZ" -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleSearchBySuffix(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	suffix := r.URL.Query().Get("suffix")
+	if suffix == "" {
+		writeErrorResponse(w, http.StatusBadRequest, "suffix query parameter is required")
+		return
+	}
+
+	vehicles, err := h.service.SearchBySuffix(suffix)
+	resp := dto.SuffixSearchResponse{
+		Suffix:     suffix,
+		Candidates: make([]dto.VehicleCandidate, 0, len(vehicles)),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	}
+	for _, vehicle := range vehicles {
+		resp.Candidates = append(resp.Candidates, dto.VehicleCandidate{
+			VehicleNumber: vehicle.VehicleNumber,
+			SpotID:        vehicle.SpotID,
+		})
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
 // registers all the API routes
 func (h *ParkingHandler) registerRoutes() {
-	http.HandleFunc("/park", h.handlePark)
-	http.HandleFunc("/unpark", h.handleUnpark)
+	http.HandleFunc("/park", h.requireDevice(h.handlePark))
+	http.HandleFunc("/park/hold", h.requireDeviceSession(nil, h.handleHoldSpot))
+	http.HandleFunc("/park/confirm", h.requireDeviceSession(nil, h.handleConfirmHold))
+	http.HandleFunc("/checkin", h.requireDevice(h.handleCheckIn))
+	http.HandleFunc("/checkin/{id}", h.requireDevice(h.handleCheckInStatus))
+	http.HandleFunc("/unpark", h.requireDevice(h.handleUnpark))
 	http.HandleFunc("/available", h.handleAvailableSpots)
+	http.HandleFunc("/available/count", h.handleAvailableCount)
+	http.HandleFunc("/recommend", h.handleRecommendSpot)
 	http.HandleFunc("/search", h.handleSearchVehicle)
+	http.HandleFunc("/admin/search/suffix", h.requireRole(auth.RoleAttendant, h.handleSearchBySuffix))
+	http.HandleFunc("/stats/occupancy", h.handleOccupancyStats)
+	http.HandleFunc("/stats/usage", h.handleUsageStats)
+	http.HandleFunc("/stats/heatmap", h.handleHeatmap)
+	http.HandleFunc("/stats/timeseries", h.handleTimeSeries)
+	http.HandleFunc("/stats/spots", h.handleSpotUsage)
+	http.HandleFunc("/stats/spots/unused", h.handleUnusedSpots)
+	http.HandleFunc("/stats/forecast", h.handleForecast)
+	http.HandleFunc("/stats/revenue", h.requireRole(auth.RoleAttendant, h.handleRevenueReport))
+	http.HandleFunc("/events", h.requireRole(auth.RoleAttendant, h.handleEvents))
+	http.HandleFunc("/webhooks", h.requireRole(auth.RoleAdmin, h.handleWebhooks))
+	http.HandleFunc("/webhooks/deliveries", h.requireRole(auth.RoleAdmin, h.handleWebhookDeliveries))
+	http.HandleFunc("/admin/webhooks/dead-letters", h.requireRole(auth.RoleAdmin, h.handleWebhookDeadLetters))
+	http.HandleFunc("/sensors/report", h.requireDeviceSession([]string{auth.DeviceSensor}, h.handleSensorReport))
+	http.HandleFunc("/admin/discrepancies", h.requireRole(auth.RoleAttendant, h.handleDiscrepancies))
+	http.HandleFunc("/admin/sensors/maintenance", h.requireRole(auth.RoleAttendant, h.handleSensorMaintenance))
+	http.HandleFunc("/admin/override", h.requireRole(auth.RoleAttendant, h.handleOverride))
+	http.HandleFunc("/admin/audit", h.requireRole(auth.RoleAttendant, h.handleAuditLog))
+	http.HandleFunc("/payments", h.requireRole(auth.RoleAttendant, h.handlePayments))
+	http.HandleFunc("/payments/cash", h.requireRole(auth.RoleAttendant, h.handleCashPayment))
+	http.HandleFunc("/payments/{id}/refund", h.requirePermission(auth.PermissionRefund, h.handleRefund))
+	http.HandleFunc("/ledger", h.requireRole(auth.RoleAttendant, h.handleLedger))
+	http.HandleFunc("/admin/reports/daily", h.requireRole(auth.RoleAttendant, h.handleDailyReport))
+	http.HandleFunc("/violations", h.requireRole(auth.RoleEnforcement, h.handleViolations))
+	http.HandleFunc("/violations/{id}/resolve", h.requireRole(auth.RoleEnforcement, h.handleResolveViolation))
+	http.HandleFunc("/park/retries/{id}", h.requireDevice(h.handleParkRetry))
+	http.HandleFunc("/admin/duplicates", h.requireRole(auth.RoleAttendant, h.handlePendingDuplicates))
+	http.HandleFunc("/admin/duplicates/{id}", h.requireRole(auth.RoleAttendant, h.handleResolveDuplicate))
+	http.HandleFunc("/admin/layout", h.requireRole(auth.RoleAttendant, h.handleLayout))
+	http.HandleFunc("/admin/layout/reload", h.requireRole(auth.RoleAttendant, h.handleLayoutReload))
+	http.HandleFunc("/admin/state", h.requireRole(auth.RoleAttendant, h.handleLotState))
+	http.HandleFunc("/admin/spots/deleted", h.requireRole(auth.RoleAttendant, h.handleDeletedSpots))
+	http.HandleFunc("/admin/spots/{spotId}", h.requireRole(auth.RoleAttendant, h.handleDeleteSpot))
+	http.HandleFunc("/admin/spots/{spotId}/restore", h.requireRole(auth.RoleAttendant, h.handleRestoreSpot))
+	http.HandleFunc("/admin/blocks", h.requireRole(auth.RoleAttendant, h.handleSpotBlocks))
+	http.HandleFunc("/admin/blocks/{id}", h.requireRole(auth.RoleAttendant, h.handleCancelSpotBlock))
+	http.HandleFunc("/admin/devices", h.requireRole(auth.RoleAdmin, h.handleDevices))
+	http.HandleFunc("/admin/devices/{id}", h.requireRole(auth.RoleAdmin, h.handleDevice))
+	http.HandleFunc("/devices/{id}/heartbeat", h.requireOwnDevice(h.handleDeviceHeartbeat))
+	http.HandleFunc("/admin/incident", h.requireRole(auth.RoleAdmin, h.handleIncident))
+	http.HandleFunc("/admin/gates/open", h.requireRole(auth.RoleAdmin, h.handleOpenAllGates))
+	http.HandleFunc("/admin/ui", h.handleAdminUI)
+	http.HandleFunc("/anpr/events", h.requireDeviceSession([]string{auth.DeviceCamera}, h.handlePlateRecognition))
+	http.HandleFunc("/gates", h.handleGateStatus)
+	http.HandleFunc("/gates/queues", h.handleExitQueues)
+	http.HandleFunc("/vehicle-types", h.handleVehicleTypes)
+	http.HandleFunc("/spot-types", h.handleSpotTypes)
+	http.HandleFunc("/display/{floor}", h.handleDisplayBoard)
+	http.HandleFunc("/map/{floor}", h.handleFloorMap)
+	http.HandleFunc("/qr/tickets/{id}", h.requireTicketHolder(h.handleTicketQR))
+	http.HandleFunc("/wallet/tickets/{id}", h.requireTicketHolder(h.handleTicketWalletPass))
+	http.HandleFunc("/qr/spots/{spotId}", h.handleSpotQR)
+	http.HandleFunc("/public/availability", h.handlePublicAvailability)
+	http.HandleFunc("/metrics", h.handleMetrics)
+
+	// Only instances aggregating a campus of garages expose the combined view
+	if h.federation != nil {
+		http.HandleFunc("/federation/availability", h.handleFederationAvailability)
+	}
+	if h.oidc != nil {
+		http.HandleFunc("/admin/ui/oidc", h.handleAdminUIOIDC)
+	}
+	if h.tokens != nil {
+		http.HandleFunc("/admin/tokens", h.requireRole(auth.RoleAdmin, h.handleTokens))
+		http.HandleFunc("/admin/tokens/{id}", h.requireRole(auth.RoleAdmin, h.handleRevokeToken))
+		http.HandleFunc("/admin/tokens/{id}/rotate", h.requireRole(auth.RoleAdmin, h.handleRotateToken))
+	}
+	if h.devices != nil {
+		http.HandleFunc("/admin/devices/enrollments", h.requireRole(auth.RoleAdmin, h.handleDeviceEnrollments))
+		http.HandleFunc("/admin/devices/sessions", h.requireRole(auth.RoleAdmin, h.handleDeviceSessions))
+		http.HandleFunc("/admin/devices/sessions/{id}", h.requireRole(auth.RoleAdmin, h.handleRevokeDeviceSession))
+		http.HandleFunc("/devices/enroll", h.handleDeviceEnroll)
+		http.HandleFunc("/devices/token/refresh", h.handleDeviceTokenRefresh)
+	}
+	if h.commands != nil {
+		http.HandleFunc("/devices/{id}/ws", h.requireOwnDevice(h.handleDeviceSocket))
+		http.HandleFunc("/admin/commands", h.requireRole(auth.RoleAdmin, h.handleDeviceCommands))
+	}
+	http.HandleFunc("/admin/sessions/export", h.requireRole(auth.RoleAttendant, h.handleExportSessions))
 }
 
-// starts the HTTP server on the specified port
-func (h *ParkingHandler) StartServer(port int) error {
+// starts the HTTP server on the specified port, HTTPS when a TLS certificate and key file are given
+func (h *ParkingHandler) StartServer(port int, tlsCertFile, tlsKeyFile string) error {
 	h.registerRoutes()
 
 	addr := fmt.Sprintf(":%d", port)
+	server := &http.Server{Addr: addr, Handler: h.logRequests(h.instrumentRequests(http.DefaultServeMux))}
+
+	if tlsCertFile != "" && tlsKeyFile != "" {
+		log.Printf("Starting parking lot API server on %s (HTTPS)", addr)
+		return server.ListenAndServeTLS(tlsCertFile, tlsKeyFile)
+	}
+
 	log.Printf("Starting parking lot API server on %s", addr)
-	return http.ListenAndServe(addr, nil)
+	return server.ListenAndServe()
 }
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/domain/parking"
+	"testing"
+)
+
+func TestParkTakesTheDeviceSignedIn(t *testing.T) {
+	h := newTestHandler(t, 2)
+	park := h.requireDevice(h.handlePark)
+
+	// a body naming another device, or naming one without a device token, is refused
+	forged := dto.ParkRequest{VehicleType: "Automobile", VehicleNumber: "B1", Device: "kiosk-2"}
+	if status := serve(t, park, http.MethodPost, "/park", "kiosk-1", forged, nil); status != http.StatusForbidden {
+		t.Fatalf("park naming kiosk-2 from kiosk-1: want status %d, got %d", http.StatusForbidden, status)
+	}
+	if status := serve(t, park, http.MethodPost, "/park", "", forged, nil); status != http.StatusForbidden {
+		t.Fatalf("park naming kiosk-2 anonymously: want status %d, got %d", http.StatusForbidden, status)
+	}
+
+	var parked dto.ParkResponse
+	req := dto.ParkRequest{VehicleType: "Automobile", VehicleNumber: "B1"}
+	if status := serve(t, park, http.MethodPost, "/park", "kiosk-1", req, &parked); status != http.StatusOK {
+		t.Fatalf("park from kiosk-1: status %d, %s", status, parked.Error)
+	}
+
+	page, err := h.service.QueryEvents(parking.EventQuery{Device: "kiosk-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Events) != 1 || page.Events[0].VehicleNumber != "B1" {
+		t.Fatalf("got events %+v from kiosk-1, want B1 parking", page.Events)
+	}
+}
+
+func TestUnparkNamingAnotherDeviceIsForbidden(t *testing.T) {
+	h := newTestHandler(t, 1)
+	park := h.requireDevice(h.handlePark)
+	unpark := h.requireDevice(h.handleUnpark)
+
+	if status := serve(t, park, http.MethodPost, "/park", "", dto.ParkRequest{VehicleType: "Automobile", VehicleNumber: "B1"}, nil); status != http.StatusOK {
+		t.Fatalf("park: status %d", status)
+	}
+
+	req := dto.UnparkRequest{VehicleNumber: "B1", Device: "kiosk-1"}
+	if status := serve(t, unpark, http.MethodPost, "/unpark", "kiosk-2", req, nil); status != http.StatusForbidden {
+		t.Fatalf("unpark naming kiosk-1 from kiosk-2: want status %d, got %d", http.StatusForbidden, status)
+	}
+	if status := serve(t, unpark, http.MethodPost, "/unpark", "kiosk-1", req, nil); status != http.StatusOK {
+		t.Fatalf("unpark from kiosk-1: want status %d, got %d", http.StatusOK, status)
+	}
+}
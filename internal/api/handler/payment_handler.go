@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/repository"
+	"strconv"
+	"time"
+)
+
+// handles the POST /payments/cash endpoint, recording cash an attendant took for a ticket
+// together with the change given and the drawer the money went into. The receipt is emailed to receiptTo,
+// or to the email address given when the vehicle parked.
+
+/** cURL example
+curl -X POST http://localhost:8080/payments/cash \
+     -H "Authorization: Bearer <attendant token>" \
+     -H "Content-Type: application/json" \
+     -d '{"ticketId": 12, "tendered": 50000, "change": 5000, "drawerId": "booth-1", "receiptTo": "driver@example.com"}'
+**/
+
+func (h *ParkingHandler) handleCashPayment(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.CashPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	payment, err := h.service.RecordCashPayment(identity.Name, req.TicketID, req.Tendered, req.Change, req.DrawerID, req.ReceiptTo)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := h.toPayment(payment)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.PaymentResponse{Payment: &resp})
+}
+
+// handles the GET /payments endpoint, listing the payments of a ticket or of all tickets with their total
+
+/** cURL example
+curl -X GET "http://localhost:8080/payments?ticketId=12" -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handlePayments(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	ticketID := 0
+	if value := r.URL.Query().Get("ticketId"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			writeErrorResponse(w, http.StatusBadRequest, "ticketId must be a positive integer")
+			return
+		}
+		ticketID = parsed
+	}
+
+	resp := dto.PaymentListResponse{Payments: []dto.Payment{}}
+	for _, payment := range h.service.GetPayments(ticketID) {
+		resp.Payments = append(resp.Payments, h.toPayment(payment))
+		resp.Total += payment.Amount - payment.Refunded
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ParkingHandler) toPayment(payment repository.Payment) dto.Payment {
+	return dto.Payment{
+		ID:       payment.ID,
+		TicketID: payment.SessionID,
+		Method:   payment.Method,
+		Amount:   payment.Amount,
+		Tendered: payment.Tendered,
+		Change:   payment.Change,
+		DrawerID: payment.DrawerID,
+		TakenBy:  payment.TakenBy,
+		TakenAt:  payment.TakenAt.In(h.location).Format(time.RFC3339),
+		Refunded: payment.Refunded,
+	}
+}
+
+// handles the POST /payments/{id}/refund endpoint, giving back an amount of a payment, or all that
+// is left of it when the amount is omitted, through the provider of its payment method. Only roles
+// granted the refund permission (REFUND_ROLES) may refund.
+
+/** cURL example
+curl -X POST http://localhost:8080/payments/3/refund \
+     -H "Authorization: Bearer <admin token>" \
+     -H "Content-Type: application/json" \
+     -d '{"amount": 5000, "reason": "charged for an hour the barrier was stuck"}'
+**/
+
+func (h *ParkingHandler) handleRefund(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	paymentID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || paymentID < 1 {
+		writeErrorResponse(w, http.StatusBadRequest, "payment ID must be a positive integer")
+		return
+	}
+
+	var req dto.RefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	refund, err := h.service.RefundPayment(identity.Name, paymentID, req.Amount, req.Reason)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.RefundResponse{Refund: &dto.Refund{
+		ID:         refund.ID,
+		PaymentID:  refund.PaymentID,
+		Amount:     refund.Amount,
+		Reason:     refund.Reason,
+		RefundedBy: refund.RefundedBy,
+		RefundedAt: refund.RefundedAt.In(h.location).Format(time.RFC3339),
+	}})
+}
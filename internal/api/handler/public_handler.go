@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/repository"
+	"time"
+)
+
+const (
+	// how long browsers and CDNs may reuse the public availability
+	publicMaxAge = 60
+
+	// below this share of free spots availability is reported as limited
+	limitedAvailabilityRatio = 0.1
+)
+
+// converts an occupancy count into coarse public availability
+func toPublicAvailability(count repository.OccupancyCount) dto.PublicAvailability {
+	available := count.Capacity - count.Occupied
+	status := "available"
+	switch {
+	case available <= 0:
+		status = "full"
+	case float64(available) < float64(count.Capacity)*limitedAvailabilityRatio:
+		status = "limited"
+	}
+
+	return dto.PublicAvailability{
+		Available: available,
+		Capacity:  count.Capacity,
+		Status:    status,
+	}
+}
+
+// handles the GET /public/availability endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/public/availability"
+**/
+
+func (h *ParkingHandler) handlePublicAvailability(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	stats := h.service.GetOccupancyStats()
+	resp := dto.PublicAvailabilityResponse{
+		Overall:      toPublicAvailability(stats.Total),
+		VehicleTypes: make(map[string]dto.PublicAvailability, len(stats.VehicleTypes)),
+	}
+	for vehicleType, count := range stats.VehicleTypes {
+		resp.VehicleTypes[vehicleType] = toPublicAvailability(count)
+	}
+
+	// The ETag only covers the counts, so unchanged availability revalidates cheaply
+	body, err := json.Marshal(resp)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, stale-while-revalidate=%d", publicMaxAge, publicMaxAge))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	resp.UpdatedAt = time.Now().In(h.location).Format(time.RFC3339)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// size of the QR code images in pixels
+const (
+	defaultQRSize = 256
+	minQRSize     = 64
+	maxQRSize     = 1024
+)
+
+// handles the GET /qr/tickets/{id} endpoint, for the holder of the ticket, with the token of its
+// walletPassUrl, and for staff
+
+/** cURL example
+curl -X GET "http://localhost:8080/qr/tickets/1?size=512&token=<token from the /park response>" -o ticket.png
+**/
+
+func (h *ParkingHandler) handleTicketQR(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	ticketID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "ticket ID must be an integer")
+		return
+	}
+	size, ok := qrSizeOf(w, r)
+	if !ok {
+		return
+	}
+
+	code, err := h.service.TicketCode(ticketID)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeQRCode(w, code, size)
+}
+
+// handles the GET /qr/spots/{spotId} endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/qr/spots/0-2-0" -o spot.png
+**/
+
+func (h *ParkingHandler) handleSpotQR(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	size, ok := qrSizeOf(w, r)
+	if !ok {
+		return
+	}
+
+	code, err := h.service.SpotCode(r.PathValue("spotId"))
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	writeQRCode(w, code, size)
+}
+
+// qrSizeOf reads the optional size query parameter, writing an error response when it is invalid
+func qrSizeOf(w http.ResponseWriter, r *http.Request) (int, bool) {
+	value := r.URL.Query().Get("size")
+	if value == "" {
+		return defaultQRSize, true
+	}
+
+	size, err := strconv.Atoi(value)
+	if err != nil || size < minQRSize || size > maxQRSize {
+		writeErrorResponse(w, http.StatusBadRequest, "size must be an integer between 64 and 1024")
+		return 0, false
+	}
+	return size, true
+}
+
+// writeQRCode writes a code as a PNG QR code image
+func writeQRCode(w http.ResponseWriter, code string, size int) {
+	png, err := qrcode.Encode(code, qrcode.Medium, size)
+	if err != nil {
+		writeErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(png)
+}
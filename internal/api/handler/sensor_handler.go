@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strconv"
+	"time"
+)
+
+// handles the POST /sensors/report endpoint, called by sensors or their gateways signed in with the device
+// session of a sensor
+
+/** cURL example
+curl -X POST http://localhost:8080/sensors/report \
+     -H "Authorization: Bearer <sensor access token>" \
+     -H "Content-Type: application/json" \
+     -d '{"spotId": "0-0-1", "occupied": true, "battery": 64, "firmware": "1.4.2"}'
+**/
+
+func (h *ParkingHandler) handleSensorReport(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	var req dto.SensorReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	if req.Occupied == nil {
+		writeErrorResponse(w, http.StatusBadRequest, "occupied is required")
+		return
+	}
+
+	// Sensors that do not report their health keep the last reported values
+	health := repository.SensorHealth{Battery: -1, Firmware: req.Firmware}
+	if req.Battery != nil {
+		if *req.Battery < 0 {
+			writeErrorResponse(w, http.StatusBadRequest, "battery must be between 0 and 100")
+			return
+		}
+		health.Battery = *req.Battery
+	}
+
+	matches, err := h.service.ReportSensor(req.SpotID, *req.Occupied, health)
+	resp := dto.SensorReportResponse{}
+
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.Success = true
+		resp.MatchesSystem = matches
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /admin/discrepancies endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/discrepancies?refresh=true" -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleDiscrepancies(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	// Serve the latest periodic report unless a fresh run is requested
+	report := h.service.GetDiscrepancyReport()
+	if r.URL.Query().Get("refresh") == "true" {
+		report = h.service.Reconcile()
+	}
+
+	resp := dto.DiscrepancyReportResponse{Discrepancies: []dto.Discrepancy{}}
+	if !report.GeneratedAt.IsZero() {
+		resp.GeneratedAt = report.GeneratedAt.In(h.location).Format(time.RFC3339)
+	}
+
+	for _, discrepancy := range report.Discrepancies {
+		resp.Discrepancies = append(resp.Discrepancies, dto.Discrepancy{
+			Kind:             discrepancy.Kind,
+			SpotID:           discrepancy.SpotID,
+			VehicleNumber:    discrepancy.VehicleNumber,
+			SensorReportedAt: discrepancy.SensorReportedAt.In(h.location).Format(time.RFC3339),
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /admin/sensors/maintenance endpoint, the configured battery threshold and
+// minimum firmware version apply unless given
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/sensors/maintenance?battery=30&firmware=1.5.0" \
+     -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleSensorMaintenance(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	policy := parking.SensorPolicy{MinFirmware: r.URL.Query().Get("firmware")}
+	if value := r.URL.Query().Get("battery"); value != "" {
+		battery, err := strconv.Atoi(value)
+		if err != nil || battery <= 0 || battery > 100 {
+			writeErrorResponse(w, http.StatusBadRequest, "battery query parameter must be an integer between 1 and 100")
+			return
+		}
+		policy.LowBattery = battery
+	}
+
+	report, err := h.service.GetSensorMaintenance(policy)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := dto.SensorMaintenanceResponse{
+		LowBattery:  report.Policy.LowBattery,
+		MinFirmware: report.Policy.MinFirmware,
+		Sensors:     []dto.SensorMaintenance{},
+	}
+	for _, sensor := range report.Sensors {
+		maintenance := dto.SensorMaintenance{
+			SpotID:     sensor.SpotID,
+			Firmware:   sensor.Firmware,
+			ReportedAt: sensor.ReportedAt.In(h.location).Format(time.RFC3339),
+			Reasons:    sensor.Reasons,
+		}
+		if sensor.Battery >= 0 {
+			battery := sensor.Battery
+			maintenance.Battery = &battery
+		}
+		resp.Sensors = append(resp.Sensors, maintenance)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
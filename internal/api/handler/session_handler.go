@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"strconv"
+	"time"
+)
+
+// handles the GET /admin/sessions/export endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/sessions/export?from=2025-05-20T00:00:00Z&to=2025-05-21T00:00:00Z" \
+     -H "Authorization: Bearer <attendant token>" -o sessions.csv
+**/
+
+func (h *ParkingHandler) handleExportSessions(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	from, to, err := h.parseTimeWindow(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sessions, err := h.service.GetSessions(from, to)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="sessions.csv"`)
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{
+		"sessionId", "vehicleNumber", "vehicleType", "spotId",
+		"entryTime", "exitTime", "durationMinutes", "spotType", "fallback",
+	})
+
+	now := time.Now()
+	for _, session := range sessions {
+		exitTime := ""
+		if !session.IsActive() {
+			exitTime = session.ExitTime.In(h.location).Format(time.RFC3339)
+		}
+
+		writer.Write([]string{
+			strconv.Itoa(session.ID),
+			session.VehicleNumber,
+			session.VehicleType,
+			session.SpotID,
+			session.EntryTime.In(h.location).Format(time.RFC3339),
+			exitTime,
+			strconv.FormatFloat(session.Duration(now).Minutes(), 'f', 2, 64),
+			session.SpotType,
+			strconv.FormatBool(session.Fallback),
+		})
+	}
+
+	writer.Flush()
+}
+
+// handles the GET /admin/state endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/state?at=2025-05-20T14:30:00Z&floor=0" \
+     -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleLotState(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	at := time.Now()
+	if value := r.URL.Query().Get("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "invalid at parameter: "+err.Error())
+			return
+		}
+		at = parsed.In(h.location)
+	}
+
+	floor := -1
+	if value := r.URL.Query().Get("floor"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			writeErrorResponse(w, http.StatusBadRequest, "floor query parameter must be a non-negative integer")
+			return
+		}
+		floor = parsed
+	}
+
+	state, err := h.service.GetLotState(at, floor)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+	resp := dto.LotStateResponse{
+		At:       state.At.In(h.location).Format(time.RFC3339),
+		Vehicles: make([]dto.StateVehicle, 0, len(state.Vehicles)),
+	}
+	for _, vehicle := range state.Vehicles {
+		exitTime := ""
+		if !vehicle.ExitTime.IsZero() {
+			exitTime = vehicle.ExitTime.In(h.location).Format(time.RFC3339)
+		}
+		resp.Vehicles = append(resp.Vehicles, dto.StateVehicle{
+			SpotID:        vehicle.Parked.SpotID,
+			Floor:         vehicle.Floor,
+			Row:           vehicle.Row,
+			Column:        vehicle.Column,
+			VehicleNumber: vehicle.Parked.VehicleNumber,
+			VehicleType:   vehicle.Parked.VehicleType,
+			EventID:       vehicle.Parked.ID,
+			EntryTime:     vehicle.Parked.Time.In(h.location).Format(time.RFC3339),
+			ExitTime:      exitTime,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
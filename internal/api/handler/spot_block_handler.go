@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	"strconv"
+	"time"
+)
+
+// handles the GET and POST /admin/blocks endpoint. POST reserves spots or a whole zone for a scheduled
+// event, taking them out of general allocation between from and until. GET lists the blocks that have not ended.
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/blocks \
+     -H "Authorization: Bearer <attendant token>" \
+     -H "Content-Type: application/json" \
+     -d '{"name": "Stadium VIPs", "zone": "A", "from": "2026-11-02T17:00:00+07:00", "until": "2026-11-02T23:30:00+07:00"}'
+
+curl -X GET http://localhost:8080/admin/blocks -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleSpotBlocks(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	switch r.Method {
+	case http.MethodGet:
+		resp := dto.SpotBlocksResponse{Blocks: []dto.SpotBlock{}}
+		for _, block := range h.service.GetSpotBlocks() {
+			resp.Blocks = append(resp.Blocks, h.toSpotBlock(block))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	case http.MethodPost:
+		var req dto.SpotBlockRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+
+		block := parking.SpotBlock{Name: req.Name, SpotIDs: req.SpotIDs, Zone: req.Zone}
+		for name, field := range map[string]struct {
+			value  string
+			target *time.Time
+		}{"from": {req.From, &block.From}, "until": {req.Until, &block.Until}} {
+			if field.value == "" {
+				continue
+			}
+			parsed, err := time.Parse(time.RFC3339, field.value)
+			if err != nil {
+				writeErrorResponse(w, http.StatusBadRequest, "invalid "+name+": "+err.Error())
+				return
+			}
+			*field.target = parsed.In(h.location)
+		}
+
+		block, err := h.service.BlockSpots(identity.Name, block)
+		if err != nil {
+			writeServiceError(w, r, err)
+			return
+		}
+
+		resp := h.toSpotBlock(block)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(dto.SpotBlockResponse{Block: &resp})
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET and POST methods are allowed")
+	}
+}
+
+// handles the DELETE /admin/blocks/{id} endpoint, giving the spots of a block back before it ends
+
+/** cURL example
+curl -X DELETE http://localhost:8080/admin/blocks/1 -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleCancelSpotBlock(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodDelete {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only DELETE method is allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "id must be an integer")
+		return
+	}
+
+	block, err := h.service.CancelSpotBlock(identity.Name, id)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := h.toSpotBlock(block)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.SpotBlockResponse{Block: &resp})
+}
+
+func (h *ParkingHandler) toSpotBlock(block parking.SpotBlock) dto.SpotBlock {
+	return dto.SpotBlock{
+		ID:        block.ID,
+		Name:      block.Name,
+		SpotIDs:   block.SpotIDs,
+		Zone:      block.Zone,
+		From:      block.From.In(h.location).Format(time.RFC3339),
+		Until:     block.Until.In(h.location).Format(time.RFC3339),
+		Active:    block.Active(time.Now()),
+		CreatedBy: block.CreatedBy,
+	}
+}
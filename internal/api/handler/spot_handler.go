@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	pkgerrors "parking-lot-system/pkg/errors"
+)
+
+// handles the DELETE /admin/spots/{spotId} endpoint
+
+/** cURL example
+curl -X DELETE http://localhost:8080/admin/spots/0-0-1 \
+     -H "Authorization: Bearer <attendant token>" \
+     -H "Content-Type: application/json" \
+     -d '{"reason": "resurfacing"}'
+**/
+
+func (h *ParkingHandler) handleDeleteSpot(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodDelete {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only DELETE method is allowed")
+		return
+	}
+
+	h.handleSpotDeletion(w, r, identity, h.service.DeleteSpot)
+}
+
+// handles the POST /admin/spots/{spotId}/restore endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/spots/0-0-1/restore \
+     -H "Authorization: Bearer <attendant token>" \
+     -H "Content-Type: application/json" \
+     -d '{"reason": "resurfacing done"}'
+**/
+
+func (h *ParkingHandler) handleRestoreSpot(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	h.handleSpotDeletion(w, r, identity, h.service.RestoreSpot)
+}
+
+// handleSpotDeletion decodes the reason of a deletion or restore and applies it to the spot of the path
+func (h *ParkingHandler) handleSpotDeletion(w http.ResponseWriter, r *http.Request, identity auth.Identity,
+	apply func(attendant, spotID, reason string) error) {
+	var req dto.SpotDeletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	spotID := r.PathValue("spotId")
+	resp := dto.SpotDeletionResponse{SpotID: spotID}
+
+	if err := apply(identity.Name, spotID, req.Reason); err != nil {
+		resp.SpotID = ""
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.Success = true
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /admin/spots/deleted endpoint
+
+/** cURL example
+curl -X GET http://localhost:8080/admin/spots/deleted \
+     -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleDeletedSpots(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	resp := dto.DeletedSpotsResponse{Spots: []dto.DeletedSpot{}}
+	for _, spot := range h.service.GetDeletedSpots() {
+		resp.Spots = append(resp.Spots, dto.DeletedSpot{
+			SpotID:      spot.SpotID,
+			VehicleType: spot.VehicleType,
+			Class:       spot.Class,
+			IsActive:    spot.IsActive,
+			Zone:        spot.Zone,
+			Size:        spot.Size,
+			Capacity:    spot.Capacity,
+			TimesUsed:   spot.TimesUsed,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
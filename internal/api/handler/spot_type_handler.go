@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+)
+
+// handles the GET /spot-types endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/spot-types"
+**/
+
+func (h *ParkingHandler) handleSpotTypes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	resp := dto.SpotTypesResponse{SpotTypes: []dto.SpotType{}}
+	for _, spotType := range h.service.GetSpotTypes() {
+		resp.SpotTypes = append(resp.SpotTypes, dto.SpotType{
+			Code:        spotType.Code,
+			VehicleType: spotType.VehicleType,
+			Class:       spotType.Class,
+			IsActive:    spotType.IsActive,
+			Exists:      spotType.Exists,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,498 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/repository"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// converts a repository occupancy count into its API representation
+func toOccupancyStat(count repository.OccupancyCount) dto.OccupancyStat {
+	stat := dto.OccupancyStat{
+		Capacity:        count.Capacity,
+		Occupied:        count.Occupied,
+		Blocked:         count.Blocked,
+		BlockedOccupied: count.BlockedOccupied,
+	}
+	if count.Capacity > 0 {
+		stat.Percentage = float64(count.Occupied) * 100 / float64(count.Capacity)
+	}
+	return stat
+}
+
+// handles the GET /stats/occupancy endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/stats/occupancy"
+**/
+
+func (h *ParkingHandler) handleOccupancyStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	stats := h.service.GetOccupancyStats()
+	resp := dto.OccupancyStatsResponse{
+		Overall:      toOccupancyStat(stats.Total),
+		Floors:       make([]dto.FloorOccupancyStat, 0, len(stats.Floors)),
+		VehicleTypes: make(map[string]dto.OccupancyStat, len(stats.VehicleTypes)),
+		Zones:        make(map[string]dto.OccupancyStat, len(stats.Zones)),
+	}
+
+	for floor, count := range stats.Floors {
+		resp.Floors = append(resp.Floors, dto.FloorOccupancyStat{
+			Floor:         floor,
+			OccupancyStat: toOccupancyStat(count),
+		})
+	}
+
+	for vehicleType, count := range stats.VehicleTypes {
+		resp.VehicleTypes[vehicleType] = toOccupancyStat(count)
+	}
+
+	for zone, count := range stats.Zones {
+		resp.Zones[zone] = toOccupancyStat(count)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// parses the optional from/to RFC3339 query parameters, defaulting to the last 24 hours. Times are
+// converted to the lot's time zone, so reports use it whatever offset the client sent.
+func (h *ParkingHandler) parseTimeWindow(r *http.Request) (time.Time, time.Time, error) {
+	to := time.Now()
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to parameter: %v", err)
+		}
+		to = parsed.In(h.location)
+	}
+
+	from := to.Add(-24 * time.Hour)
+	if value := r.URL.Query().Get("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from parameter: %v", err)
+		}
+		from = parsed.In(h.location)
+	}
+
+	return from, to, nil
+}
+
+// handles the GET /stats/usage endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/stats/usage?from=2025-05-20T00:00:00Z&to=2025-05-21T00:00:00Z"
+**/
+
+func (h *ParkingHandler) handleUsageStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	from, to, err := h.parseTimeWindow(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	stats, err := h.service.GetUsageStats(from, to)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := dto.UsageStatsResponse{
+		From:           stats.From.In(h.location).Format(time.RFC3339),
+		To:             stats.To.In(h.location).Format(time.RFC3339),
+		HourlyArrivals: stats.HourlyArrivals[:],
+		StayByType:     make(map[string]dto.StayStat, len(stats.StayByType)),
+		SpotTurnover:   stats.SpotTurnover,
+	}
+
+	for hour, arrivals := range stats.HourlyArrivals {
+		if arrivals > stats.HourlyArrivals[resp.PeakHour] {
+			resp.PeakHour = hour
+		}
+	}
+
+	for vehicleType, stay := range stats.StayByType {
+		resp.StayByType[vehicleType] = dto.StayStat{
+			CompletedSessions:  stay.CompletedSessions,
+			AverageStayMinutes: stay.AverageStay.Minutes(),
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /stats/heatmap endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/stats/heatmap?floor=0"
+**/
+
+func (h *ParkingHandler) handleHeatmap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	floor, err := strconv.Atoi(r.URL.Query().Get("floor"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "floor query parameter must be an integer")
+		return
+	}
+
+	from, to, err := h.parseTimeWindow(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	heatmap, err := h.service.GetHeatmap(floor, from, to)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := dto.HeatmapResponse{
+		Floor:       heatmap.Floor,
+		From:        heatmap.From.In(h.location).Format(time.RFC3339),
+		To:          heatmap.To.In(h.location).Format(time.RFC3339),
+		Cells:       make([][]string, len(heatmap.Cells)),
+		Utilization: make([][]*float64, len(heatmap.Cells)),
+	}
+
+	for row, cells := range heatmap.Cells {
+		resp.Cells[row] = make([]string, len(cells))
+		resp.Utilization[row] = make([]*float64, len(cells))
+		for col, cell := range cells {
+			switch {
+			case !cell.Exists:
+				resp.Cells[row][col] = "none"
+			case !cell.IsActive:
+				resp.Cells[row][col] = "inactive"
+			default:
+				resp.Cells[row][col] = "active"
+				utilization := cell.Utilization
+				resp.Utilization[row][col] = &utilization
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /stats/timeseries endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/stats/timeseries?from=2025-05-20T00:00:00Z&to=2025-05-21T00:00:00Z&step=15m"
+**/
+
+func (h *ParkingHandler) handleTimeSeries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	from, to, err := h.parseTimeWindow(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// Every sample is its own point without step
+	var step time.Duration
+	if value := r.URL.Query().Get("step"); value != "" {
+		step, err = time.ParseDuration(value)
+		if err != nil || step <= 0 {
+			writeErrorResponse(w, http.StatusBadRequest, "step query parameter must be a positive duration, e.g. 15m")
+			return
+		}
+	}
+
+	points, err := h.service.GetOccupancyTimeSeries(from, to, step)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := dto.TimeSeriesResponse{
+		From:   from.In(h.location).Format(time.RFC3339),
+		To:     to.In(h.location).Format(time.RFC3339),
+		Points: make([]dto.TimeSeriesPoint, 0, len(points)),
+	}
+	for _, point := range points {
+		respPoint := dto.TimeSeriesPoint{
+			At:      point.At.In(h.location).Format(time.RFC3339),
+			Overall: toOccupancyLevel(point.Total),
+			Floors:  make([]dto.FloorOccupancyLevel, 0, len(point.Floors)),
+		}
+		for floor, level := range point.Floors {
+			respPoint.Floors = append(respPoint.Floors, dto.FloorOccupancyLevel{
+				Floor:          floor,
+				OccupancyLevel: toOccupancyLevel(level),
+			})
+		}
+		resp.Points = append(resp.Points, respPoint)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// converts an averaged occupancy level into its API representation
+func toOccupancyLevel(level parking.OccupancyLevel) dto.OccupancyLevel {
+	stat := dto.OccupancyLevel{
+		Capacity: level.Capacity,
+		Occupied: level.Occupied,
+	}
+	if level.Capacity > 0 {
+		stat.Percentage = level.Occupied * 100 / float64(level.Capacity)
+	}
+	return stat
+}
+
+// handles the GET /stats/forecast endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/stats/forecast?at=2025-05-21T18:00:00Z"
+**/
+
+func (h *ParkingHandler) handleForecast(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	at := time.Now()
+	if value := r.URL.Query().Get("at"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, fmt.Sprintf("invalid at parameter: %v", err))
+			return
+		}
+		at = parsed.In(h.location)
+	}
+
+	forecast := h.service.GetForecast(at)
+	resp := dto.ForecastResponse{
+		At:           forecast.At.In(h.location).Format(time.RFC3339),
+		Samples:      forecast.Samples,
+		VehicleTypes: make(map[string]dto.TypeForecast, len(forecast.VehicleTypes)),
+	}
+
+	for vehicleType, typeForecast := range forecast.VehicleTypes {
+		resp.VehicleTypes[vehicleType] = dto.TypeForecast{
+			Capacity:           typeForecast.Capacity,
+			PredictedOccupied:  typeForecast.PredictedOccupied,
+			PredictedAvailable: typeForecast.PredictedAvailable,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /stats/spots endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/stats/spots?sort=least-used"
+**/
+
+func (h *ParkingHandler) handleSpotUsage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	order := r.URL.Query().Get("sort")
+	if !slices.Contains(parking.SpotUsageOrders, order) {
+		writeErrorResponse(w, http.StatusBadRequest, "sort must be least-used or most-used")
+		return
+	}
+
+	resp := dto.SpotUsageResponse{Spots: []dto.SpotUsage{}}
+	for _, usage := range h.service.GetSpotUsage(order) {
+		resp.Spots = append(resp.Spots, toSpotUsageDTO(usage))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /stats/spots/unused endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/stats/spots/unused?days=14"
+**/
+
+func (h *ParkingHandler) handleUnusedSpots(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	// The configured window applies without days
+	var window time.Duration
+	if value := r.URL.Query().Get("days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days <= 0 {
+			writeErrorResponse(w, http.StatusBadRequest, "days query parameter must be a positive integer")
+			return
+		}
+		window = time.Duration(days) * 24 * time.Hour
+	}
+
+	unused := h.service.GetUnusedSpots(window)
+	resp := dto.UnusedSpotsResponse{
+		From:  unused.From.In(h.location).Format(time.RFC3339),
+		To:    unused.To.In(h.location).Format(time.RFC3339),
+		Spots: []dto.SpotUsage{},
+	}
+	for _, usage := range unused.Spots {
+		resp.Spots = append(resp.Spots, toSpotUsageDTO(usage))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func toSpotUsageDTO(usage parking.SpotUsage) dto.SpotUsage {
+	return dto.SpotUsage{
+		SpotID:        usage.SpotID,
+		Floor:         usage.Floor,
+		Row:           usage.Row,
+		Column:        usage.Column,
+		VehicleType:   usage.VehicleType,
+		IsActive:      usage.IsActive,
+		TimesUsed:     usage.TimesUsed,
+		OccupiedHours: usage.OccupiedTime.Hours(),
+	}
+}
+
+// handles the GET /admin/reports/daily endpoint, reporting on the business day ending at the optional
+// RFC3339 to parameter, or on the last 24 hours
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/reports/daily?to=2026-10-17T00:00:00+07:00" \
+     -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleDailyReport(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	to := time.Now()
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "invalid to parameter: "+err.Error())
+			return
+		}
+		to = parsed.In(h.location)
+	}
+
+	report := h.service.GetDailyReport(to)
+	resp := dto.DailyReportResponse{
+		From:            report.From.In(h.location).Format(time.RFC3339),
+		To:              report.To.In(h.location).Format(time.RFC3339),
+		Occupancy:       toOccupancyStat(report.Occupancy.Total),
+		SessionsStarted: report.SessionsStarted,
+		Revenue:         report.Revenue,
+		RevenueTotal:    report.RevenueTotal,
+		Unpaid:          h.toReportSessions(report.Unpaid),
+		Stuck:           h.toReportSessions(report.Stuck),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ParkingHandler) toReportSessions(sessions []repository.Session) []dto.ReportSession {
+	resp := make([]dto.ReportSession, 0, len(sessions))
+	for _, session := range sessions {
+		reportSession := dto.ReportSession{
+			TicketID:      session.ID,
+			VehicleNumber: session.VehicleNumber,
+			SpotID:        session.SpotID,
+			EntryTime:     session.EntryTime.In(h.location).Format(time.RFC3339),
+		}
+		if !session.IsActive() {
+			reportSession.ExitTime = session.ExitTime.In(h.location).Format(time.RFC3339)
+		}
+		resp = append(resp, reportSession)
+	}
+	return resp
+}
+
+// handles the GET /stats/revenue endpoint, adding up the ledger over the optional from/to RFC3339
+// parameters, or the last 24 hours, by day, vehicle type and payment method
+
+/** cURL example
+curl -X GET "http://localhost:8080/stats/revenue?from=2026-10-01T00:00:00+07:00&to=2026-11-01T00:00:00+07:00" \
+     -H "Authorization: Bearer <attendant token>"
+**/
+
+func (h *ParkingHandler) handleRevenueReport(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	from, to, err := h.parseTimeWindow(r)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report, err := h.service.GetRevenueReport(from, to)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := dto.RevenueReportResponse{
+		From:           report.From.In(h.location).Format(time.RFC3339),
+		To:             report.To.In(h.location).Format(time.RFC3339),
+		Total:          toRevenueStat(report.Total),
+		Days:           make([]dto.DayRevenueStat, 0, len(report.Days)),
+		VehicleTypes:   make(map[string]dto.RevenueStat, len(report.VehicleTypes)),
+		PaymentMethods: make(map[string]dto.RevenueStat, len(report.Methods)),
+	}
+	for date, totals := range report.Days {
+		resp.Days = append(resp.Days, dto.DayRevenueStat{Date: date, RevenueStat: toRevenueStat(totals)})
+	}
+	slices.SortFunc(resp.Days, func(a, b dto.DayRevenueStat) int { return strings.Compare(a.Date, b.Date) })
+	for vehicleType, totals := range report.VehicleTypes {
+		resp.VehicleTypes[vehicleType] = toRevenueStat(totals)
+	}
+	for method, totals := range report.Methods {
+		resp.PaymentMethods[method] = toRevenueStat(totals)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func toRevenueStat(totals parking.RevenueTotals) dto.RevenueStat {
+	return dto.RevenueStat{Revenue: totals.Revenue, Refunds: totals.Refunds, Net: totals.Net()}
+}
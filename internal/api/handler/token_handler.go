@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"strconv"
+	"time"
+)
+
+// SetTokenStore enables the management of API tokens at runtime through the admin token endpoints
+func (h *ParkingHandler) SetTokenStore(tokens *auth.TokenStore) {
+	h.tokens = tokens
+}
+
+// handles the GET and POST /admin/tokens endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/tokens \
+     -H "Authorization: Bearer <admin token>" \
+     -H "Content-Type: application/json" \
+     -d '{"name": "kiosk-1", "role": "attendant", "expiresAt": "2026-01-01T00:00:00Z", "allowedIps": ["10.0.0.0/24"]}'
+**/
+
+func (h *ParkingHandler) handleTokens(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	switch r.Method {
+	case http.MethodGet:
+		resp := dto.TokensResponse{Tokens: []dto.APIToken{}}
+		for _, token := range h.tokens.List() {
+			resp.Tokens = append(resp.Tokens, h.toAPIToken(token, ""))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	case http.MethodPost:
+		var req dto.TokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+		if req.Name == "" {
+			writeErrorResponse(w, http.StatusBadRequest, "name is required")
+			return
+		}
+		expiresAt, err := parseExpiry(req.ExpiresAt)
+		if err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		allowedIPs, err := auth.ParseAllowlist(req.AllowedIPs)
+		var token auth.APIToken
+		var secret string
+		if err == nil {
+			token, secret, err = h.tokens.Create(req.Name, req.Role, expiresAt, allowedIPs)
+		}
+		if err == nil {
+			h.logger.Info("api token created", "actor", identity.Name, "tokenId", token.ID, "name", token.Name, "role", token.Role)
+		}
+		h.writeTokenResponse(w, r, token, secret, err, http.StatusCreated)
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET and POST methods are allowed")
+	}
+}
+
+// handles the DELETE /admin/tokens/{id} endpoint
+
+/** cURL example
+curl -X DELETE http://localhost:8080/admin/tokens/3 \
+     -H "Authorization: Bearer <admin token>"
+**/
+
+func (h *ParkingHandler) handleRevokeToken(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodDelete {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only DELETE method is allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "token ID must be an integer")
+		return
+	}
+
+	token, err := h.tokens.Revoke(id)
+	if err == nil {
+		h.logger.Info("api token revoked", "actor", identity.Name, "tokenId", token.ID, "name", token.Name)
+	}
+	h.writeTokenResponse(w, r, token, "", err, http.StatusOK)
+}
+
+// handles the POST /admin/tokens/{id}/rotate endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/admin/tokens/3/rotate \
+     -H "Authorization: Bearer <admin token>" \
+     -H "Content-Type: application/json" \
+     -d '{"expiresAt": "2026-07-01T00:00:00Z"}'
+**/
+
+func (h *ParkingHandler) handleRotateToken(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "token ID must be an integer")
+		return
+	}
+
+	// the body is optional, the expiry is kept without one
+	var req dto.TokenRotateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+	expiresAt, err := parseExpiry(req.ExpiresAt)
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	token, secret, err := h.tokens.Rotate(id, expiresAt)
+	if err == nil {
+		h.logger.Info("api token rotated", "actor", identity.Name, "tokenId", token.ID, "name", token.Name)
+	}
+	h.writeTokenResponse(w, r, token, secret, err, http.StatusOK)
+}
+
+// writeTokenResponse writes a token, with the token itself when it was just issued, or the error of the operation
+func (h *ParkingHandler) writeTokenResponse(w http.ResponseWriter, r *http.Request, token auth.APIToken, secret string, err error, status int) {
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	resp := h.toAPIToken(token, secret)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(dto.TokenResponse{Token: &resp})
+}
+
+// parseExpiry parses an optional RFC 3339 expiry, the zero time when it is empty
+func parseExpiry(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid expiresAt: " + err.Error())
+	}
+	return expiresAt, nil
+}
+
+func (h *ParkingHandler) toAPIToken(token auth.APIToken, secret string) dto.APIToken {
+	resp := dto.APIToken{
+		ID:        token.ID,
+		Name:      token.Name,
+		Role:      token.Role,
+		Hint:      token.Hint,
+		Status:    "active",
+		CreatedAt: token.CreatedAt.In(h.location).Format(time.RFC3339),
+		Token:     secret,
+	}
+	for _, prefix := range token.AllowedIPs {
+		resp.AllowedIPs = append(resp.AllowedIPs, prefix.String())
+	}
+	if !token.RotatedAt.IsZero() {
+		resp.RotatedAt = token.RotatedAt.In(h.location).Format(time.RFC3339)
+	}
+	if !token.ExpiresAt.IsZero() {
+		resp.ExpiresAt = token.ExpiresAt.In(h.location).Format(time.RFC3339)
+		if token.IsExpired(time.Now()) {
+			resp.Status = "expired"
+		}
+	}
+	if token.IsRevoked() {
+		resp.RevokedAt = token.RevokedAt.In(h.location).Format(time.RFC3339)
+		resp.Status = "revoked"
+	}
+	return resp
+}
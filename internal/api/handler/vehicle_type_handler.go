@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+)
+
+// handles the GET /vehicle-types endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/vehicle-types"
+**/
+
+func (h *ParkingHandler) handleVehicleTypes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	resp := dto.VehicleTypesResponse{VehicleTypes: []dto.VehicleType{}}
+	for _, vehicleType := range h.service.GetVehicleTypes() {
+		resp.VehicleTypes = append(resp.VehicleTypes, dto.VehicleType{
+			Name:     vehicleType.Name,
+			SpotCode: vehicleType.SpotCode,
+			Size:     vehicleType.Size,
+			Rate:     vehicleType.Rate,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strconv"
+	"time"
+)
+
+// Violation statuses
+const (
+	violationOpen     = "open"
+	violationResolved = "resolved"
+	violationFined    = "fined"
+)
+
+// converts violations into their API representation
+func (h *ParkingHandler) toViolations(violations []repository.Violation) []dto.Violation {
+	items := make([]dto.Violation, 0, len(violations))
+	for _, violation := range violations {
+		item := dto.Violation{
+			ID:            violation.ID,
+			VehicleNumber: violation.VehicleNumber,
+			SpotID:        violation.SpotID,
+			Kind:          violation.Kind,
+			Note:          violation.Note,
+			Status:        violationOpen,
+			ReportedBy:    violation.ReportedBy,
+			ReportedAt:    violation.ReportedAt.In(h.location).Format(time.RFC3339),
+			Fine:          violation.Fine,
+		}
+		if !violation.IsOpen() {
+			item.Status = violationResolved
+			if violation.Fine > 0 {
+				item.Status = violationFined
+			}
+			item.ResolvedBy = violation.ResolvedBy
+			item.ResolvedAt = violation.ResolvedAt.In(h.location).Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// handles the POST and GET /violations endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/violations \
+     -H "Authorization: Bearer <enforcement token>" \
+     -H "Content-Type: application/json" \
+     -d '{"vehicleNumber": "AB123CD", "kind": "blocking", "note": "blocks the ramp"}'
+
+curl -X GET "http://localhost:8080/violations?vehicleNumber=AB123CD&status=open" \
+     -H "Authorization: Bearer <enforcement token>"
+**/
+
+func (h *ParkingHandler) handleViolations(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handleFlagViolation(w, r, identity)
+	case http.MethodGet:
+		h.handleListViolations(w, r)
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET and POST methods are allowed")
+	}
+}
+
+func (h *ParkingHandler) handleFlagViolation(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	var req dto.FlagViolationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	violation, err := h.service.FlagViolation(identity.Name, req.VehicleNumber, req.Kind, req.Note)
+	resp := dto.ViolationResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.Violation = &h.toViolations([]repository.Violation{violation})[0]
+		w.WriteHeader(http.StatusCreated)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ParkingHandler) handleListViolations(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	status := query.Get("status")
+	if status != "" && status != violationOpen {
+		writeErrorResponse(w, http.StatusBadRequest, "status must be open when given")
+		return
+	}
+
+	violations := h.service.GetViolations(query.Get("vehicleNumber"), status == violationOpen)
+	resp := dto.ViolationListResponse{Violations: h.toViolations(violations)}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the POST /violations/{id}/resolve endpoint
+
+/** cURL example
+curl -X POST http://localhost:8080/violations/1/resolve \
+     -H "Authorization: Bearer <enforcement token>" \
+     -H "Content-Type: application/json" \
+     -d '{"fine": 50}'
+**/
+
+func (h *ParkingHandler) handleResolveViolation(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodPost {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only POST method is allowed")
+		return
+	}
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "id must be an integer")
+		return
+	}
+
+	// The body is optional, a violation is dismissed without a fine
+	var req dto.ResolveViolationRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+			return
+		}
+	}
+
+	violation, err := h.service.ResolveViolation(identity.Name, id, req.Fine)
+	resp := dto.ViolationResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.Violation = &h.toViolations([]repository.Violation{violation})[0]
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
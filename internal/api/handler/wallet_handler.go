@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"parking-lot-system/internal/wallet"
+	"strconv"
+)
+
+// SetWalletIssuer sets how the lot is identified on the wallet passes of tickets
+func (h *ParkingHandler) SetWalletIssuer(issuer wallet.Issuer) {
+	h.walletIssuer = issuer
+}
+
+// walletPassURL returns the link to the wallet pass of the ticket of a parked vehicle, with the token only
+// the driver gets when parking; empty when the vehicle is not parked
+func (h *ParkingHandler) walletPassURL(vehicleNumber string) (int, string) {
+	ticketID, parked := h.service.ActiveTicket(vehicleNumber)
+	if !parked {
+		return 0, ""
+	}
+	return ticketID, fmt.Sprintf("/wallet/tickets/%d?token=%s", ticketID, h.service.TicketToken(ticketID))
+}
+
+// handles the GET /wallet/tickets/{id} endpoint, returning the Apple Wallet and Google Wallet passes of the
+// ticket of a parked vehicle, with its spot, expiry and QR code. The token comes with the walletPassUrl of
+// the /park response; without it the pass is only given to staff. The passes are not signed: the driver app or a
+// signing service holding the lot's certificates turns them into a .pkpass file or a save link.
+
+/** cURL example
+curl -X GET "http://localhost:8080/wallet/tickets/1?token=<token from the /park response>"
+**/
+
+func (h *ParkingHandler) handleTicketWalletPass(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	ticketID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "ticket ID must be an integer")
+		return
+	}
+
+	ticket, err := h.service.WalletTicket(ticketID)
+	if err != nil {
+		writeServiceError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(wallet.New(h.walletIssuer, ticket))
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"strconv"
+	"testing"
+)
+
+func TestTicketsOnlyServedToTheirHolderAndStaff(t *testing.T) {
+	h := newTestHandler(t, 2)
+	park := h.requireDevice(h.handlePark)
+
+	var first, second dto.ParkResponse
+	for plate, parked := range map[string]*dto.ParkResponse{"B1": &first, "B2": &second} {
+		req := dto.ParkRequest{VehicleType: "Automobile", VehicleNumber: plate}
+		if status := serve(t, park, http.MethodPost, "/park", "", req, parked); status != http.StatusOK {
+			t.Fatalf("park %s: status %d, %s", plate, status, parked.Error)
+		}
+	}
+	token := h.service.TicketToken(first.TicketID)
+	otherToken := h.service.TicketToken(second.TicketID)
+
+	endpoints := map[string]http.HandlerFunc{
+		"/wallet/tickets/": h.requireTicketHolder(h.handleTicketWalletPass),
+		"/qr/tickets/":     h.requireTicketHolder(h.handleTicketQR),
+	}
+	for prefix, handler := range endpoints {
+		cases := []struct {
+			name   string
+			query  string
+			bearer string
+			want   int
+		}{
+			{"without a token", "", "", http.StatusUnauthorized},
+			{"with the token of another ticket", "?token=" + otherToken, "", http.StatusForbidden},
+			{"with a device token", "", "kiosk-1", http.StatusForbidden},
+			{"with the ticket token", "?token=" + token, "", http.StatusOK},
+			{"with a staff token", "", "admin", http.StatusOK},
+		}
+		for _, c := range cases {
+			target := prefix + strconv.Itoa(first.TicketID) + c.query
+			mux := http.NewServeMux()
+			mux.HandleFunc(prefix+"{id}", handler)
+
+			status := serve(t, mux.ServeHTTP, http.MethodGet, target, c.bearer, nil, nil)
+			if status != c.want {
+				t.Errorf("GET %s %s: want status %d, got %d", prefix+"{id}", c.name, c.want, status)
+			}
+		}
+	}
+}
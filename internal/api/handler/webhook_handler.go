@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/webhook"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strconv"
+	"time"
+)
+
+// converts a webhook subscription into its API representation
+func (h *ParkingHandler) toWebhookSubscription(subscription webhook.Subscription) dto.WebhookSubscription {
+	return dto.WebhookSubscription{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    subscription.EventTypes,
+		CreatedAt: subscription.CreatedAt.In(h.location).Format(time.RFC3339),
+	}
+}
+
+// converts webhook deliveries into their API representation
+func (h *ParkingHandler) toWebhookDeliveries(deliveries []webhook.Delivery) []dto.WebhookDelivery {
+	items := make([]dto.WebhookDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		item := dto.WebhookDelivery{
+			ID:             delivery.ID,
+			SubscriptionID: delivery.SubscriptionID,
+			EventType:      delivery.EventType,
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			StatusCode:     delivery.StatusCode,
+			Error:          delivery.Error,
+			CreatedAt:      delivery.CreatedAt.In(h.location).Format(time.RFC3339),
+		}
+		if !delivery.NextAttemptAt.IsZero() {
+			item.NextAttemptAt = delivery.NextAttemptAt.In(h.location).Format(time.RFC3339)
+		}
+		if !delivery.DeliveredAt.IsZero() {
+			item.DeliveredAt = delivery.DeliveredAt.In(h.location).Format(time.RFC3339)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// handles the POST and GET /webhooks endpoint. Subscriptions receive plates, so only admins manage them.
+
+/** cURL example
+curl -X POST http://localhost:8080/webhooks \
+     -H "Authorization: Bearer <admin token>" \
+     -H "Content-Type: application/json" \
+     -d '{"url": "https://example.com/hooks/parking", "events": ["vehicle.parked", "lot.full"]}'
+**/
+
+func (h *ParkingHandler) handleWebhooks(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handleCreateWebhook(w, r, identity)
+	case http.MethodGet:
+		h.handleListWebhooks(w, r)
+	default:
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET and POST methods are allowed")
+	}
+}
+
+func (h *ParkingHandler) handleCreateWebhook(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	var req dto.WebhookSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "Invalid JSON: "+err.Error())
+		return
+	}
+
+	subscription, err := h.webhooks.Subscribe(r.Context(), req.URL, req.Events, req.Secret)
+	resp := dto.WebhookSubscriptionResponse{}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		h.logger.Info("webhook subscribed", "actor", identity.Name, "subscriptionId", subscription.ID, "url", subscription.URL)
+
+		// The secret is only revealed once, when the subscription is created
+		created := h.toWebhookSubscription(subscription)
+		created.Secret = subscription.Secret
+		resp.Subscription = &created
+		w.WriteHeader(http.StatusCreated)
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (h *ParkingHandler) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	resp := dto.WebhookSubscriptionsResponse{Subscriptions: []dto.WebhookSubscription{}}
+	for _, subscription := range h.webhooks.Subscriptions() {
+		resp.Subscriptions = append(resp.Subscriptions, h.toWebhookSubscription(subscription))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /webhooks/deliveries endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/webhooks/deliveries?subscriptionId=1" -H "Authorization: Bearer <admin token>"
+**/
+
+func (h *ParkingHandler) handleWebhookDeliveries(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	subscriptionID, err := strconv.Atoi(r.URL.Query().Get("subscriptionId"))
+	if err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "subscriptionId query parameter must be an integer")
+		return
+	}
+
+	deliveries, err := h.webhooks.Deliveries(subscriptionID)
+	resp := dto.WebhookDeliveriesResponse{}
+
+	if err != nil {
+		resp.Error = localize(r, err)
+		resp.Code = string(pkgerrors.CodeOf(err))
+		w.WriteHeader(statusOf(err))
+	} else {
+		resp.Deliveries = h.toWebhookDeliveries(deliveries)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// handles the GET /admin/webhooks/dead-letters endpoint
+
+/** cURL example
+curl -X GET "http://localhost:8080/admin/webhooks/dead-letters" -H "Authorization: Bearer <admin token>"
+**/
+
+func (h *ParkingHandler) handleWebhookDeadLetters(w http.ResponseWriter, r *http.Request, identity auth.Identity) {
+	if r.Method != http.MethodGet {
+		writeErrorResponse(w, http.StatusMethodNotAllowed, "Only GET method is allowed")
+		return
+	}
+
+	resp := dto.WebhookDeliveriesResponse{
+		Deliveries: h.toWebhookDeliveries(h.webhooks.DeadLetters()),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
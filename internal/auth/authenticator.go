@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"net/http"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"slices"
+	"strings"
+)
+
+// Roles
+const (
+	RoleAttendant   = "attendant"
+	RoleEnforcement = "enforcement"
+	RoleAdmin       = "admin" // manages the API tokens
+)
+
+// Permissions granted to roles on top of the endpoints of their own
+const (
+	PermissionRefund = "refund" // refunds payments
+)
+
+// Roles lists all roles a caller can have
+var Roles = []string{RoleAttendant, RoleEnforcement, RoleAdmin}
+
+// Identity represents an authenticated caller
+type Identity struct {
+	Name  string
+	Roles []string // a user signed in with OIDC has the roles of all their groups
+	// kind of an enrolled device, e.g. "gate", empty for staff
+	DeviceKind string
+}
+
+// HasRole reports whether the caller has a role
+func (i Identity) HasRole(role string) bool {
+	return slices.Contains(i.Roles, role)
+}
+
+// Authenticator resolves the identity of the caller of a request
+type Authenticator interface {
+	Authenticate(r *http.Request) (Identity, error)
+}
+
+// StaticTokenAuthenticator authenticates bearer tokens against a fixed set of tokens
+type StaticTokenAuthenticator struct {
+	tokens map[string]Identity // token -> identity
+}
+
+func NewStaticTokenAuthenticator(tokens map[string]Identity) *StaticTokenAuthenticator {
+	return &StaticTokenAuthenticator{tokens: tokens}
+}
+
+// Authenticate looks up the bearer token of the Authorization header
+func (a *StaticTokenAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	token, ok := BearerToken(r)
+	if !ok {
+		return Identity{}, pkgerrors.ErrUnauthorized
+	}
+
+	identity, exists := a.tokens[token]
+	if !exists {
+		return Identity{}, pkgerrors.ErrUnauthorized
+	}
+
+	return identity, nil
+}
+
+// ChainAuthenticator tries several authenticators in order, e.g. the configured tokens and then
+// the managed ones
+type ChainAuthenticator []Authenticator
+
+// Authenticate returns the identity of the first authenticator recognizing the caller. An error
+// other than ErrUnauthorized, e.g. a known token used from a disallowed address, ends the search.
+func (c ChainAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	for _, authenticator := range c {
+		identity, err := authenticator.Authenticate(r)
+		if err == nil {
+			return identity, nil
+		}
+		if pkgerrors.CodeOf(err) != pkgerrors.ErrUnauthorized.Code {
+			return Identity{}, err
+		}
+	}
+	return Identity{}, pkgerrors.ErrUnauthorized
+}
+
+// BearerToken extracts the token of a "Authorization: Bearer <token>" header
+func BearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	token, found := strings.CutPrefix(header, "Bearer ")
+	if !found || token == "" {
+		return "", false
+	}
+	return token, true
+}
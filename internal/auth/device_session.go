@@ -0,0 +1,286 @@
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// RoleDevice is the role of kiosks, pay stations and other lot devices signed in with a device session.
+// It is not one of the staff Roles and only lets devices call the endpoints a device needs.
+const RoleDevice = "device"
+
+// Device kinds that can enroll, the types of the device registry
+const (
+	DeviceGate       = "gate"
+	DeviceKiosk      = "kiosk"
+	DevicePayStation = "pay_station"
+	DeviceSensor     = "sensor"
+	DeviceCamera     = "camera"
+)
+
+// DeviceKinds lists the kinds of devices that can enroll
+var DeviceKinds = []string{DeviceGate, DeviceKiosk, DevicePayStation, DeviceSensor, DeviceCamera}
+
+// Lifetimes of the codes and tokens of device sessions unless configured otherwise
+const (
+	DefaultEnrollmentTTL = 15 * time.Minute
+	DefaultAccessTTL     = 15 * time.Minute
+	DefaultRefreshTTL    = 30 * 24 * time.Hour
+)
+
+// DeviceEnrollment is a one-time code an admin hands to a device to enroll it
+type DeviceEnrollment struct {
+	Code      string
+	Device    string // name of the device, e.g. "entrance-kiosk-2"
+	Kind      string
+	ExpiresAt time.Time
+}
+
+// DeviceSession is an enrolled device. It authenticates with a short-lived access token and
+// trades its refresh token for a new pair before the access token expires.
+type DeviceSession struct {
+	ID               int
+	Device           string
+	Kind             string
+	EnrolledAt       time.Time
+	RefreshedAt      time.Time // last time the tokens were refreshed, the enrollment time until then
+	AccessExpiresAt  time.Time
+	RefreshExpiresAt time.Time
+	RevokedAt        time.Time // zero until the session is revoked
+}
+
+// IsRevoked reports whether the session was revoked
+func (s DeviceSession) IsRevoked() bool {
+	return !s.RevokedAt.IsZero()
+}
+
+// DeviceTokens are the tokens issued to a device on enrollment and refresh
+type DeviceTokens struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// one token of a session, the current access or refresh token or a refresh token already traded in
+type deviceToken struct {
+	session *DeviceSession
+	refresh bool
+	used    bool // a refresh token that was traded in, presenting it again revokes the session
+}
+
+// DeviceSessions enrolls kiosks and pay stations and authenticates them with short-lived tokens,
+// so no long-lived shared key has to be installed on devices standing in public places. Sessions
+// are kept in memory; devices enroll again after a restart.
+type DeviceSessions struct {
+	enrollmentTTL time.Duration
+	accessTTL     time.Duration
+	refreshTTL    time.Duration
+
+	mutex       sync.RWMutex
+	enrollments map[string]*DeviceEnrollment // hash of the code -> enrollment
+	sessions    []*DeviceSession             // in the order the devices enrolled, session ID - 1 is the index
+	tokens      map[string]*deviceToken      // hash of the token -> token
+}
+
+func NewDeviceSessions(accessTTL, refreshTTL time.Duration) *DeviceSessions {
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTTL
+	}
+	return &DeviceSessions{
+		enrollmentTTL: DefaultEnrollmentTTL,
+		accessTTL:     accessTTL,
+		refreshTTL:    refreshTTL,
+		enrollments:   make(map[string]*DeviceEnrollment),
+		tokens:        make(map[string]*deviceToken),
+	}
+}
+
+// CreateEnrollment issues a one-time code enrolling a device of a kind
+func (d *DeviceSessions) CreateEnrollment(device, kind string) (DeviceEnrollment, error) {
+	if device == "" {
+		return DeviceEnrollment{}, fmt.Errorf("%w: device name is required", pkgerrors.ErrInvalidDevice)
+	}
+	if !slices.Contains(DeviceKinds, kind) {
+		return DeviceEnrollment{}, fmt.Errorf("%w: kind %q", pkgerrors.ErrInvalidDevice, kind)
+	}
+
+	code, err := newSecret()
+	if err != nil {
+		return DeviceEnrollment{}, err
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	enrollment := &DeviceEnrollment{
+		Code:      code,
+		Device:    device,
+		Kind:      kind,
+		ExpiresAt: time.Now().Add(d.enrollmentTTL),
+	}
+	d.enrollments[hashSecret(code)] = enrollment
+
+	return *enrollment, nil
+}
+
+// Enroll trades an enrollment code for a device session and its first tokens. Codes work once.
+func (d *DeviceSessions) Enroll(code string) (DeviceSession, DeviceTokens, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	hash := hashSecret(code)
+	enrollment, exists := d.enrollments[hash]
+	if !exists || !time.Now().Before(enrollment.ExpiresAt) {
+		return DeviceSession{}, DeviceTokens{}, pkgerrors.ErrInvalidEnrollmentCode
+	}
+	delete(d.enrollments, hash)
+
+	now := time.Now()
+	session := &DeviceSession{
+		ID:          len(d.sessions) + 1,
+		Device:      enrollment.Device,
+		Kind:        enrollment.Kind,
+		EnrolledAt:  now,
+		RefreshedAt: now,
+	}
+	d.sessions = append(d.sessions, session)
+
+	tokens, err := d.issue(session)
+	if err != nil {
+		return DeviceSession{}, DeviceTokens{}, err
+	}
+	return *session, tokens, nil
+}
+
+// Refresh trades a refresh token for a new access and refresh token; the old ones stop working.
+// A refresh token that was already traded in has leaked or been replayed, so presenting it again
+// revokes the session and the device has to enroll again.
+func (d *DeviceSessions) Refresh(refreshToken string) (DeviceSession, DeviceTokens, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	token, exists := d.tokens[hashSecret(refreshToken)]
+	if !exists || !token.refresh || token.session.IsRevoked() {
+		return DeviceSession{}, DeviceTokens{}, pkgerrors.ErrUnauthorized
+	}
+	session := token.session
+	if token.used {
+		d.revoke(session)
+		return DeviceSession{}, DeviceTokens{}, fmt.Errorf("%w: refresh token reused, device session %d revoked",
+			pkgerrors.ErrUnauthorized, session.ID)
+	}
+	if !time.Now().Before(session.RefreshExpiresAt) {
+		return DeviceSession{}, DeviceTokens{}, fmt.Errorf("%w: refresh token expired", pkgerrors.ErrUnauthorized)
+	}
+
+	session.RefreshedAt = time.Now()
+	tokens, err := d.issue(session)
+	if err != nil {
+		return DeviceSession{}, DeviceTokens{}, err
+	}
+	return *session, tokens, nil
+}
+
+// List returns all device sessions, revoked ones included, in the order the devices enrolled
+func (d *DeviceSessions) List() []DeviceSession {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	sessions := make([]DeviceSession, 0, len(d.sessions))
+	for _, session := range d.sessions {
+		sessions = append(sessions, *session)
+	}
+	return sessions
+}
+
+// Revoke ends a device session right away, e.g. for a stolen kiosk
+func (d *DeviceSessions) Revoke(id int) (DeviceSession, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if id < 1 || id > len(d.sessions) {
+		return DeviceSession{}, fmt.Errorf("%w: %d", pkgerrors.ErrDeviceSessionNotFound, id)
+	}
+	session := d.sessions[id-1]
+	d.revoke(session)
+
+	return *session, nil
+}
+
+// Authenticate looks up the bearer token of the Authorization header among the device access tokens
+func (d *DeviceSessions) Authenticate(r *http.Request) (Identity, error) {
+	accessToken, ok := BearerToken(r)
+	if !ok {
+		return Identity{}, pkgerrors.ErrUnauthorized
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	token, exists := d.tokens[hashSecret(accessToken)]
+	if !exists || token.refresh || token.session.IsRevoked() {
+		return Identity{}, pkgerrors.ErrUnauthorized
+	}
+	if !time.Now().Before(token.session.AccessExpiresAt) {
+		return Identity{}, fmt.Errorf("%w: access token expired, refresh it", pkgerrors.ErrUnauthorized)
+	}
+
+	return Identity{Name: token.session.Device, Roles: []string{RoleDevice}, DeviceKind: token.session.Kind}, nil
+}
+
+// issue replaces the tokens of a session by new ones. The refresh tokens it traded in are kept to
+// detect their reuse. Must be called with the mutex held.
+func (d *DeviceSessions) issue(session *DeviceSession) (DeviceTokens, error) {
+	accessToken, err := newSecret()
+	if err != nil {
+		return DeviceTokens{}, err
+	}
+	refreshToken, err := newSecret()
+	if err != nil {
+		return DeviceTokens{}, err
+	}
+
+	for hash, token := range d.tokens {
+		if token.session != session {
+			continue
+		}
+		if token.refresh {
+			token.used = true
+		} else {
+			delete(d.tokens, hash)
+		}
+	}
+
+	now := time.Now()
+	session.AccessExpiresAt = now.Add(d.accessTTL)
+	session.RefreshExpiresAt = now.Add(d.refreshTTL)
+	d.tokens[hashSecret(accessToken)] = &deviceToken{session: session}
+	d.tokens[hashSecret(refreshToken)] = &deviceToken{session: session, refresh: true}
+
+	return DeviceTokens{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  session.AccessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.RefreshExpiresAt,
+	}, nil
+}
+
+// revoke ends a session and forgets its tokens. Must be called with the mutex held.
+func (d *DeviceSessions) revoke(session *DeviceSession) {
+	if !session.IsRevoked() {
+		session.RevokedAt = time.Now()
+	}
+	for hash, token := range d.tokens {
+		if token.session == session {
+			delete(d.tokens, hash)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+)
+
+// DefaultGroupsClaim is the ID token claim listing the groups of a user unless configured otherwise
+const DefaultGroupsClaim = "groups"
+
+// OIDCConfig describes the OpenID Connect provider human users sign in with
+type OIDCConfig struct {
+	Issuer      string            // e.g. "https://login.example.com/realms/garage"
+	Audience    string            // client ID the tokens are issued for
+	GroupsClaim string            // claim listing the groups of the user, DefaultGroupsClaim when empty
+	RoleGroups  map[string]string // group -> role it grants
+}
+
+// OIDCEndpoints are the provider endpoints a browser signs in with
+type OIDCEndpoints struct {
+	Issuer                string
+	ClientID              string
+	AuthorizationEndpoint string
+	TokenEndpoint         string
+}
+
+// OIDCAuthenticator authenticates users by the ID tokens of an OpenID Connect provider,
+// granting the roles mapped to their groups
+type OIDCAuthenticator struct {
+	config    OIDCConfig
+	verifier  *oidc.IDTokenVerifier
+	endpoints OIDCEndpoints
+}
+
+// NewOIDCAuthenticator discovers the provider of the issuer, whose signing keys are fetched
+// when tokens are verified and refreshed when the provider rotates them
+func NewOIDCAuthenticator(ctx context.Context, config OIDCConfig) (*OIDCAuthenticator, error) {
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = DefaultGroupsClaim
+	}
+
+	provider, err := oidc.NewProvider(ctx, config.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("discovering OIDC provider %s: %w", config.Issuer, err)
+	}
+
+	endpoint := provider.Endpoint()
+	return &OIDCAuthenticator{
+		config:   config,
+		verifier: provider.Verifier(&oidc.Config{ClientID: config.Audience}),
+		endpoints: OIDCEndpoints{
+			Issuer:                config.Issuer,
+			ClientID:              config.Audience,
+			AuthorizationEndpoint: endpoint.AuthURL,
+			TokenEndpoint:         endpoint.TokenURL,
+		},
+	}, nil
+}
+
+// Endpoints returns the provider endpoints, e.g. for the dashboard to sign users in
+func (a *OIDCAuthenticator) Endpoints() OIDCEndpoints {
+	return a.endpoints
+}
+
+// Authenticate verifies the bearer token of the Authorization header as an ID token of the provider.
+// Tokens that are not JWTs, such as API tokens, are left to the other authenticators. A user without
+// a group mapped to a role is signed in without roles and so forbidden on every protected endpoint.
+func (a *OIDCAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	token, ok := BearerToken(r)
+	if !ok || strings.Count(token, ".") != 2 {
+		return Identity{}, pkgerrors.ErrUnauthorized
+	}
+
+	idToken, err := a.verifier.Verify(r.Context(), token)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", pkgerrors.ErrUnauthorized, err)
+	}
+
+	claims := map[string]any{}
+	if err := idToken.Claims(&claims); err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", pkgerrors.ErrUnauthorized, err)
+	}
+
+	identity := Identity{Name: idToken.Subject}
+	for _, claim := range []string{"preferred_username", "email"} {
+		if name, ok := claims[claim].(string); ok && name != "" {
+			identity.Name = name
+			break
+		}
+	}
+
+	for _, group := range stringList(claims[a.config.GroupsClaim]) {
+		if role, exists := a.config.RoleGroups[group]; exists && !identity.HasRole(role) {
+			identity.Roles = append(identity.Roles, role)
+		}
+	}
+
+	return identity, nil
+}
+
+// stringList returns the strings of a claim holding a list of strings or a single string
+func stringList(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,254 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenPrefix starts every managed token, telling them apart from configured tokens in logs and leak scanners
+const TokenPrefix = "plt_"
+
+// length of the token beginning kept to recognize a token, e.g. one found in a leak
+const tokenHintLength = len(TokenPrefix) + 6
+
+// APIToken describes a managed token. The token itself is only known to its holder,
+// the store keeps a hash of it.
+type APIToken struct {
+	ID         int
+	Name       string
+	Role       string
+	Hint       string         // beginning of the token, e.g. "plt_3fZq9a"
+	AllowedIPs []netip.Prefix // addresses the token may be used from, any when empty
+	CreatedAt  time.Time
+	RotatedAt  time.Time // zero until the token is rotated
+	ExpiresAt  time.Time // zero when the token does not expire
+	RevokedAt  time.Time // zero until the token is revoked
+}
+
+// IsExpired reports whether the token expired at a moment
+func (t APIToken) IsExpired(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && !now.Before(t.ExpiresAt)
+}
+
+// IsRevoked reports whether the token was revoked
+func (t APIToken) IsRevoked() bool {
+	return !t.RevokedAt.IsZero()
+}
+
+// allows reports whether the token may be used from an address
+func (t APIToken) allows(address netip.Addr) bool {
+	if len(t.AllowedIPs) == 0 {
+		return true
+	}
+	for _, prefix := range t.AllowedIPs {
+		if prefix.Contains(address.Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenStore manages API tokens that are created, rotated and revoked at runtime, so a leaked token
+// can be replaced without a redeploy. Tokens are kept in memory and are lost on restart; the tokens
+// of the configuration stay valid to bootstrap.
+type TokenStore struct {
+	mutex  sync.RWMutex
+	tokens []*APIToken          // in the order they were created, token ID - 1 is the index
+	hashes map[string]*APIToken // hash of the token -> token
+}
+
+func NewTokenStore() *TokenStore {
+	return &TokenStore{hashes: make(map[string]*APIToken)}
+}
+
+// ParseAllowlist parses IP addresses and CIDR ranges, e.g. "10.0.0.0/8" or "192.168.1.20"
+func ParseAllowlist(entries []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if address, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(address.Unmap(), address.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", pkgerrors.ErrInvalidAllowlist, entry)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// Create issues a token for a role, expiring at expiresAt unless it is zero and usable from the
+// allowed addresses only unless there are none. It returns the token, which cannot be retrieved later.
+func (s *TokenStore) Create(name, role string, expiresAt time.Time, allowedIPs []netip.Prefix) (APIToken, string, error) {
+	if !slices.Contains(Roles, role) {
+		return APIToken{}, "", fmt.Errorf("%w: %q", pkgerrors.ErrInvalidRole, role)
+	}
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return APIToken{}, "", pkgerrors.ErrInvalidExpiry
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return APIToken{}, "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	token := &APIToken{
+		ID:         len(s.tokens) + 1,
+		Name:       name,
+		Role:       role,
+		Hint:       secret[:tokenHintLength],
+		AllowedIPs: append([]netip.Prefix(nil), allowedIPs...),
+		CreatedAt:  time.Now(),
+		ExpiresAt:  expiresAt,
+	}
+	s.tokens = append(s.tokens, token)
+	s.hashes[hashSecret(secret)] = token
+
+	return *token, secret, nil
+}
+
+// List returns all tokens, revoked and expired ones included, in the order they were created
+func (s *TokenStore) List() []APIToken {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tokens := make([]APIToken, 0, len(s.tokens))
+	for _, token := range s.tokens {
+		tokens = append(tokens, *token)
+	}
+	return tokens
+}
+
+// Revoke makes a token unusable right away. Revoking a revoked token has no effect.
+func (s *TokenStore) Revoke(id int) (APIToken, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	token, err := s.token(id)
+	if err != nil {
+		return APIToken{}, err
+	}
+
+	if !token.IsRevoked() {
+		token.RevokedAt = time.Now()
+		s.forget(token)
+	}
+	return *token, nil
+}
+
+// Rotate replaces a token by a new one with the same name, role and allowlist; the old token stops
+// working right away. The expiry is moved to expiresAt unless it is zero. Revoked tokens cannot be
+// rotated, expired ones can.
+func (s *TokenStore) Rotate(id int, expiresAt time.Time) (APIToken, string, error) {
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return APIToken{}, "", pkgerrors.ErrInvalidExpiry
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return APIToken{}, "", err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	token, err := s.token(id)
+	if err != nil {
+		return APIToken{}, "", err
+	}
+	if token.IsRevoked() {
+		return APIToken{}, "", fmt.Errorf("%w: %d", pkgerrors.ErrTokenRevoked, id)
+	}
+
+	s.forget(token)
+	token.Hint = secret[:tokenHintLength]
+	token.RotatedAt = time.Now()
+	if !expiresAt.IsZero() {
+		token.ExpiresAt = expiresAt
+	}
+	s.hashes[hashSecret(secret)] = token
+
+	return *token, secret, nil
+}
+
+// Authenticate looks up the bearer token of the Authorization header among the managed tokens.
+// Expired and revoked tokens are unauthorized, tokens used from an address outside their allowlist forbidden.
+func (s *TokenStore) Authenticate(r *http.Request) (Identity, error) {
+	secret, ok := BearerToken(r)
+	if !ok {
+		return Identity{}, pkgerrors.ErrUnauthorized
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	token, exists := s.hashes[hashSecret(secret)]
+	if !exists {
+		return Identity{}, pkgerrors.ErrUnauthorized
+	}
+	if token.IsExpired(time.Now()) {
+		return Identity{}, fmt.Errorf("%w: token %d expired", pkgerrors.ErrUnauthorized, token.ID)
+	}
+	if !token.allows(remoteAddr(r)) {
+		return Identity{}, fmt.Errorf("%w: token %d is not allowed from this address", pkgerrors.ErrForbidden, token.ID)
+	}
+
+	return Identity{Name: token.Name, Roles: []string{token.Role}}, nil
+}
+
+// token returns the token with an ID. Must be called with the mutex held.
+func (s *TokenStore) token(id int) (*APIToken, error) {
+	if id < 1 || id > len(s.tokens) {
+		return nil, fmt.Errorf("%w: %d", pkgerrors.ErrTokenNotFound, id)
+	}
+	return s.tokens[id-1], nil
+}
+
+// forget removes the hash of a token so it no longer authenticates. Must be called with the mutex held.
+func (s *TokenStore) forget(token *APIToken) {
+	for hash, known := range s.hashes {
+		if known == token {
+			delete(s.hashes, hash)
+		}
+	}
+}
+
+// newSecret generates a random token
+func newSecret() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("generating token: %w", err)
+	}
+	return TokenPrefix + base64.RawURLEncoding.EncodeToString(random), nil
+}
+
+// hashSecret returns the hash a token is stored under
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return string(sum[:])
+}
+
+// remoteAddr returns the address of the caller, the zero address when it is unknown
+func remoteAddr(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	address, _ := netip.ParseAddr(host)
+	return address.Unmap()
+}
@@ -1,14 +1,594 @@
 package config
 
+import (
+	"errors"
+	"fmt"
+	"os"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // holds application configuration
 type AppConfig struct {
 	ServerPort int
+	// the server speaks HTTPS when both a certificate and a key file are given
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// where the lot state is kept, only "memory" is supported
+	StorageDriver string
+	// minimum level of logged messages: debug, info, warn or error
+	LogLevel string
+	// how long a request may take before it is logged as slow and counted as an SLO violation, 0 disables the check
+	LatencyBudget time.Duration
+	// IANA time zone the lot's times are kept and reported in, e.g. "Asia/Jakarta", the server's when empty
+	Timezone string
+
+	// YAML file describing the floors, gates and spots of the lot
+	LayoutFile string
+
+	// spot ID template such as "F{floor}R{row:2}C{column:2}", the "floor-row-column" default is used when empty
+	SpotIDFormat string
+	// number the first floor, row and column of spot IDs start from
+	SpotIDBase int
+
+	// whether vehicles may use spots larger than their size class when their own size is full
+	AllowLargerSpots bool
+
+	// how long an unparked vehicle must stay away before it may park again, 0 when it may return right away
+	ReentryCooldown time.Duration
+	// how park attempts for a vehicle number that is already parked are handled: reject, retry or confirm
+	DuplicatePolicy string
+	// how far back the unused spot report looks by default
+	UnusedSpotWindow time.Duration
+
+	// name of the built-in vehicle number scheme, e.g. "generic", "id" or "uk"
+	PlateScheme string
+	// custom vehicle number regular expression and length limits, replacing the scheme when set
+	PlatePattern   string
+	PlateMinLength int
+	PlateMaxLength int
+
+	// vehicle types accepted in addition to, or replacing, the built-in Bicycle, Motorcycle and Automobile
+	VehicleTypes []VehicleTypeConfig
+
+	// spot types in addition to the built-in ones and the regular spot of each vehicle type
+	SpotTypes []SpotTypeConfig
+
+	// spot types a vehicle type may fall back to when its own spots are full, vehicleType -> spot types
+	Fallbacks map[string][]string
+
+	// how often sensor readings are reconciled with the logical spot state
+	ReconciliationInterval time.Duration
+	// battery percentage below which a spot sensor needs a battery swap
+	SensorLowBattery int
+	// oldest firmware version spot sensors may run, any version is fine when empty
+	SensorMinFirmware string
+
+	// how often the occupancy is sampled for the time series, and how long samples are kept
+	TimeSeriesInterval  time.Duration
+	TimeSeriesRetention time.Duration
+
+	// how many events the event log keeps before dropping the oldest ones, 0 to keep every event
+	EventLogLimit int
+	// file every event is appended to for cmd/replay, events are not persisted when empty
+	EventLogFile string
+
+	// how long a spot held at the entry gate stays reserved when the park is not confirmed
+	HoldTTL time.Duration
+
+	// attendant bearer tokens, token -> attendant name
+	AttendantTokens map[string]string
+	// enforcement staff bearer tokens, token -> name
+	EnforcementTokens map[string]string
+	// admin bearer tokens managing the API tokens at runtime, token -> name
+	AdminTokens map[string]string
+
+	// OpenID Connect provider staff sign in with, disabled when the issuer is empty
+	OIDCIssuer   string
+	OIDCAudience string // client ID the ID tokens are issued for
+	// ID token claim listing the groups of a user
+	OIDCGroupsClaim string
+	// OIDC group -> role granted to its members
+	OIDCRoleGroups map[string]string
+	// roles allowed to refund payments
+	RefundRoles []string
+
+	// lifetimes of the access and refresh tokens of enrolled kiosks and pay stations
+	DeviceAccessTokenTTL  time.Duration
+	DeviceRefreshTokenTTL time.Duration
+	// whether the kiosk endpoints only serve enrolled devices and staff
+	RequireDeviceTokens bool
+	// how long a registered device may stay silent before it is stale
+	DeviceStaleAfter time.Duration
+
+	// barrier controller addresses, gate -> http(s):// or tcp:// address
+	GateControllers map[int]string
+	// how long a barrier stays open for a vehicle to pass
+	BarrierHoldTime time.Duration
+	// vehicles per minute that can leave through each gate, gate -> throughput, unlimited when missing
+	GateThroughput map[int]int
+
+	// remote instances whose availability is aggregated, federation is disabled when empty
+	FederationPeers []string
+
+	// private networks webhook subscriptions may call, IP addresses or CIDR ranges; only public addresses when empty
+	WebhookAllowedNetworks []string
+
+	// MQTT publishing is disabled when the broker URL is empty
+	MQTTBrokerURL   string
+	MQTTClientID    string
+	MQTTTopicPrefix string
+
+	// notifications to drivers are sent by email through the SMTP server and by SMS through the
+	// gateway, each channel is disabled when its URL is empty
+	NotifySMTPURL         string
+	NotifySMSGatewayURL   string
+	NotifySMSGatewayToken string
+	// YAML file with translations added to the built-in English and Indonesian texts, none when empty
+	MessageCatalog string
+	// directory of *.tmpl files overriding the built-in notification and webhook body templates
+	TemplateDir string
+
+	// operators notified when the free spots of a vehicle type drop below a threshold
+	AvailabilityAlerts []AvailabilityAlertConfig
+	// whether drivers holding a spot of that vehicle type are notified too
+	AvailabilityAlertReservations bool
+	// sinks operator alerts are sent to, by alert type and severity
+	AlertRoutes []AlertRouteConfig
+
+	// where the end-of-day report is sent, an email address or a phone number, none is sent when empty
+	DailyReportRecipient string
+	// time of day the business day closes, HH:MM in the lot's time zone
+	DailyReportTime string
+	// how long a vehicle may stay before the daily report lists its session as stuck
+	StuckSessionAfter time.Duration
+
+	// identify the lot on the Apple Wallet and Google Wallet passes of tickets
+	WalletOrganization    string
+	WalletApplePassTypeID string
+	WalletAppleTeamID     string
+	WalletGoogleIssuerID  string
+	WalletGoogleClass     string
+
+	// environment variables that could not be parsed, reported by Validate
+	envProblems []FieldError
+}
+
+// describes where alerts of some types at or above a severity are sent
+type AlertRouteConfig struct {
+	Types    []string // all types when empty
+	Severity string
+	Sink     string // webhook, slack, pagerduty or email
+	Target   string // URL, PagerDuty routing key or email address
+}
+
+// describes a configured availability alert
+type AvailabilityAlertConfig struct {
+	VehicleType string
+	Threshold   int
+	Contact     string
+}
+
+// describes a configured vehicle type
+type VehicleTypeConfig struct {
+	Name     string
+	SpotCode string
+	Size     string // standard when empty
+	Rate     float64
+}
+
+// describes a configured spot type
+type SpotTypeConfig struct {
+	Code        string
+	VehicleType string // empty for inactive spots
+	Class       string
 }
 
 func NewAppConfig() *AppConfig {
+	env := &envReader{}
 	cfg := &AppConfig{
-		ServerPort: 8080,
+		ServerPort:             8080,
+		TLSCertFile:            os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:             os.Getenv("TLS_KEY_FILE"),
+		StorageDriver:          "memory",
+		LogLevel:               "info",
+		LatencyBudget:          500 * time.Millisecond,
+		LayoutFile:             "configs/layout.yaml",
+		SpotIDFormat:           os.Getenv("SPOT_ID_FORMAT"),
+		SpotIDBase:             env.int("SPOT_ID_BASE"),
+		AllowLargerSpots:       env.bool("ALLOW_LARGER_SPOTS", true),
+		ReentryCooldown:        time.Duration(env.int("REENTRY_COOLDOWN_MINUTES")) * time.Minute,
+		DuplicatePolicy:        "reject",
+		UnusedSpotWindow:       7 * 24 * time.Hour,
+		PlateScheme:            "generic",
+		PlatePattern:           os.Getenv("PLATE_PATTERN"),
+		PlateMinLength:         1,
+		PlateMaxLength:         20,
+		VehicleTypes:           parseEnv(env, "VEHICLE_TYPES", parseVehicleTypes),
+		SpotTypes:              parseSpotTypes(os.Getenv("SPOT_TYPES")),
+		Fallbacks:              parseEnv(env, "TYPE_FALLBACKS", parseFallbacks),
+		ReconciliationInterval: time.Minute,
+		SensorLowBattery:       20,
+		SensorMinFirmware:      os.Getenv("SENSOR_MIN_FIRMWARE"),
+		Timezone:               os.Getenv("LOT_TIMEZONE"),
+		TimeSeriesInterval:     time.Minute,
+		TimeSeriesRetention:    7 * 24 * time.Hour,
+		EventLogLimit:          parking.DefaultEventLogLimit,
+		EventLogFile:           os.Getenv("EVENT_LOG_FILE"),
+		HoldTTL:                2 * time.Minute,
+		AttendantTokens:        parseEnv(env, "ATTENDANT_TOKENS", parseTokens),
+		EnforcementTokens:      parseEnv(env, "ENFORCEMENT_TOKENS", parseTokens),
+		AdminTokens:            parseEnv(env, "ADMIN_TOKENS", parseTokens),
+		OIDCIssuer:             os.Getenv("OIDC_ISSUER"),
+		OIDCAudience:           os.Getenv("OIDC_AUDIENCE"),
+		OIDCGroupsClaim:        auth.DefaultGroupsClaim,
+		OIDCRoleGroups:         parseRoleGroups(os.Getenv("OIDC_ROLE_GROUPS")),
+		RefundRoles:            []string{auth.RoleAdmin},
+		DeviceAccessTokenTTL:   auth.DefaultAccessTTL,
+		DeviceRefreshTokenTTL:  auth.DefaultRefreshTTL,
+		RequireDeviceTokens:    env.bool("REQUIRE_DEVICE_TOKENS", false),
+		DeviceStaleAfter:       2 * time.Minute,
+		GateControllers:        parseEnv(env, "GATE_CONTROLLERS", parseGateControllers),
+		BarrierHoldTime:        10 * time.Second,
+		GateThroughput:         parseEnv(env, "GATE_THROUGHPUT", parseGateThroughput),
+		FederationPeers:        parseList(os.Getenv("FEDERATION_PEERS")),
+		WebhookAllowedNetworks: parseList(os.Getenv("WEBHOOK_ALLOWED_NETWORKS")),
+		MQTTBrokerURL:          os.Getenv("MQTT_BROKER_URL"),
+		MQTTClientID:           "parking-lot-system",
+		MQTTTopicPrefix:        "lot",
+		NotifySMTPURL:          os.Getenv("NOTIFY_SMTP_URL"),
+		NotifySMSGatewayURL:    os.Getenv("NOTIFY_SMS_GATEWAY_URL"),
+		NotifySMSGatewayToken:  os.Getenv("NOTIFY_SMS_GATEWAY_TOKEN"),
+		MessageCatalog:         os.Getenv("MESSAGE_CATALOG"),
+		TemplateDir:            "configs/templates",
+
+		AvailabilityAlerts:            parseEnv(env, "AVAILABILITY_ALERTS", parseAvailabilityAlerts),
+		AvailabilityAlertReservations: env.bool("AVAILABILITY_ALERT_RESERVATIONS", false),
+		AlertRoutes:                   parseAlertRoutes(os.Getenv("ALERT_ROUTES")),
+		DailyReportRecipient:          os.Getenv("DAILY_REPORT_TO"),
+		DailyReportTime:               "00:00",
+		StuckSessionAfter:             24 * time.Hour,
+		WalletOrganization:            "Parking Lot",
+		WalletApplePassTypeID:         os.Getenv("WALLET_APPLE_PASS_TYPE_ID"),
+		WalletAppleTeamID:             os.Getenv("WALLET_APPLE_TEAM_ID"),
+		WalletGoogleIssuerID:          os.Getenv("WALLET_GOOGLE_ISSUER_ID"),
+		WalletGoogleClass:             "parking_ticket",
 	}
 
+	if port := env.int("PORT"); port > 0 {
+		cfg.ServerPort = port
+	}
+	if days := env.int("UNUSED_SPOT_WINDOW_DAYS"); days > 0 {
+		cfg.UnusedSpotWindow = time.Duration(days) * 24 * time.Hour
+	}
+	if seconds := env.int("TIMESERIES_INTERVAL_SECONDS"); seconds > 0 {
+		cfg.TimeSeriesInterval = time.Duration(seconds) * time.Second
+	}
+	if hours := env.int("TIMESERIES_RETENTION_HOURS"); hours > 0 {
+		cfg.TimeSeriesRetention = time.Duration(hours) * time.Hour
+	}
+	if os.Getenv("EVENT_LOG_LIMIT") != "" {
+		cfg.EventLogLimit = env.int("EVENT_LOG_LIMIT")
+	}
+	if driver := os.Getenv("STORAGE_DRIVER"); driver != "" {
+		cfg.StorageDriver = driver
+	}
+	if level := os.Getenv("LOG_LEVEL"); level != "" {
+		cfg.LogLevel = level
+	}
+	if os.Getenv("LATENCY_BUDGET_MS") != "" {
+		cfg.LatencyBudget = time.Duration(env.int("LATENCY_BUDGET_MS")) * time.Millisecond
+	}
+	if minutes := env.int("DEVICE_ACCESS_TOKEN_MINUTES"); minutes > 0 {
+		cfg.DeviceAccessTokenTTL = time.Duration(minutes) * time.Minute
+	}
+	if days := env.int("DEVICE_REFRESH_TOKEN_DAYS"); days > 0 {
+		cfg.DeviceRefreshTokenTTL = time.Duration(days) * 24 * time.Hour
+	}
+	if percent := env.int("SENSOR_LOW_BATTERY_PERCENT"); percent > 0 {
+		cfg.SensorLowBattery = percent
+	}
+	if seconds := env.int("DEVICE_STALE_SECONDS"); seconds > 0 {
+		cfg.DeviceStaleAfter = time.Duration(seconds) * time.Second
+	}
+	if roles := parseList(os.Getenv("REFUND_ROLES")); len(roles) > 0 {
+		cfg.RefundRoles = roles
+	}
+	if claim := os.Getenv("OIDC_GROUPS_CLAIM"); claim != "" {
+		cfg.OIDCGroupsClaim = claim
+	}
+	if dir := os.Getenv("TEMPLATE_DIR"); dir != "" {
+		cfg.TemplateDir = dir
+	}
+	if layoutFile := os.Getenv("LAYOUT_FILE"); layoutFile != "" {
+		cfg.LayoutFile = layoutFile
+	}
+	if policy := os.Getenv("DUPLICATE_PLATE_POLICY"); policy != "" {
+		cfg.DuplicatePolicy = policy
+	}
+	if scheme := os.Getenv("PLATE_SCHEME"); scheme != "" {
+		cfg.PlateScheme = scheme
+	}
+	if length := env.int("PLATE_MIN_LENGTH"); length > 0 {
+		cfg.PlateMinLength = length
+	}
+	if length := env.int("PLATE_MAX_LENGTH"); length > 0 {
+		cfg.PlateMaxLength = length
+	}
+	if closeAt := os.Getenv("DAILY_REPORT_TIME"); closeAt != "" {
+		cfg.DailyReportTime = closeAt
+	}
+	if organization := os.Getenv("WALLET_ORGANIZATION"); organization != "" {
+		cfg.WalletOrganization = organization
+	}
+	if class := os.Getenv("WALLET_GOOGLE_CLASS"); class != "" {
+		cfg.WalletGoogleClass = class
+	}
+	if hours := env.int("STUCK_SESSION_HOURS"); hours > 0 {
+		cfg.StuckSessionAfter = time.Duration(hours) * time.Hour
+	}
+
+	cfg.envProblems = env.problems
 	return cfg
 }
+
+// DailyReportOffset returns the time of day the business day closes as the time since midnight
+func (cfg *AppConfig) DailyReportOffset() (time.Duration, error) {
+	closeAt, err := time.Parse("15:04", cfg.DailyReportTime)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: must be HH:MM, e.g. 23:30", cfg.DailyReportTime)
+	}
+	return time.Duration(closeAt.Hour())*time.Hour + time.Duration(closeAt.Minute())*time.Minute, nil
+}
+
+// parses a comma separated list of name:token pairs into a token -> name map.
+// Malformed entries are reported by position only, so that the error does not reveal a token.
+func parseTokens(value string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	var problems []error
+	for i, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		name, token, found := strings.Cut(pair, ":")
+		if !found || name == "" || token == "" {
+			problems = append(problems, fmt.Errorf("entry %d must be name:token", i+1))
+			continue
+		}
+		tokens[token] = name
+	}
+	return tokens, errors.Join(problems...)
+}
+
+// parses a comma separated list of group:role pairs into a group -> role map
+func parseRoleGroups(value string) map[string]string {
+	roles := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		group, role, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if found && group != "" {
+			roles[group] = role
+		}
+	}
+	return roles
+}
+
+// parses a comma separated list of gate=address pairs into a gate -> address map
+func parseGateControllers(value string) (map[int]string, error) {
+	controllers := make(map[int]string)
+	var problems []error
+	for i, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		gate, address, found := strings.Cut(pair, "=")
+		number, err := strconv.Atoi(gate)
+		if !found || address == "" || err != nil {
+			problems = append(problems, fmt.Errorf("entry %d %q must be gate=address with a gate number", i+1, pair))
+			continue
+		}
+		controllers[number] = address
+	}
+	return controllers, errors.Join(problems...)
+}
+
+// parses a comma separated list of gate=vehiclesPerMinute pairs into a gate -> throughput map
+func parseGateThroughput(value string) (map[int]int, error) {
+	throughput := make(map[int]int)
+	var problems []error
+	for i, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		gate, vehicles, found := strings.Cut(pair, "=")
+		number, gateErr := strconv.Atoi(gate)
+		perMinute, vehiclesErr := strconv.Atoi(vehicles)
+		if !found || gateErr != nil || vehiclesErr != nil {
+			problems = append(problems, fmt.Errorf("entry %d %q must be gate=vehiclesPerMinute with whole numbers", i+1, pair))
+			continue
+		}
+		throughput[number] = perMinute
+	}
+	return throughput, errors.Join(problems...)
+}
+
+// parses a comma separated list of name:spotCode[:size[:rate]] entries
+func parseVehicleTypes(value string) ([]VehicleTypeConfig, error) {
+	types := []VehicleTypeConfig{}
+	var problems []error
+	for i, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		fields := strings.Split(entry, ":")
+		if len(fields) < 2 || fields[0] == "" || fields[1] == "" {
+			problems = append(problems, fmt.Errorf("entry %d %q must be name:spotCode[:size[:rate]]", i+1, entry))
+			continue
+		}
+
+		vehicleType := VehicleTypeConfig{Name: fields[0], SpotCode: fields[1]}
+		if len(fields) > 2 {
+			vehicleType.Size = fields[2]
+		}
+		if len(fields) > 3 {
+			rate, err := strconv.ParseFloat(fields[3], 64)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("entry %d %q: rate must be a number, got %q", i+1, entry, fields[3]))
+				continue
+			}
+			vehicleType.Rate = rate
+		}
+		types = append(types, vehicleType)
+	}
+	return types, errors.Join(problems...)
+}
+
+// parses a comma separated list of code:vehicleType[:class] entries, skipping entries without a code
+func parseSpotTypes(value string) []SpotTypeConfig {
+	types := []SpotTypeConfig{}
+	for _, entry := range strings.Split(value, ",") {
+		fields := strings.Split(strings.TrimSpace(entry), ":")
+		if len(fields) < 2 || fields[0] == "" {
+			continue
+		}
+
+		spotType := SpotTypeConfig{Code: fields[0], VehicleType: fields[1]}
+		if len(fields) > 2 {
+			spotType.Class = fields[2]
+		}
+		types = append(types, spotType)
+	}
+	return types
+}
+
+// parses a comma separated list of vehicleType:threshold:contact entries
+func parseAvailabilityAlerts(value string) ([]AvailabilityAlertConfig, error) {
+	alerts := []AvailabilityAlertConfig{}
+	var problems []error
+	for i, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		fields := strings.SplitN(entry, ":", 3)
+		if len(fields) < 3 || fields[0] == "" || fields[2] == "" {
+			problems = append(problems, fmt.Errorf("entry %d %q must be vehicleType:threshold:contact", i+1, entry))
+			continue
+		}
+
+		threshold, err := strconv.Atoi(fields[1])
+		if err != nil {
+			problems = append(problems, fmt.Errorf("entry %d %q: threshold must be a whole number, got %q", i+1, entry, fields[1]))
+			continue
+		}
+		alerts = append(alerts, AvailabilityAlertConfig{VehicleType: fields[0], Threshold: threshold, Contact: fields[2]})
+	}
+	return alerts, errors.Join(problems...)
+}
+
+// parses a comma separated list of types:severity=sink:target entries, where types is a |-separated
+// list of alert types or * for all of them
+func parseAlertRoutes(value string) []AlertRouteConfig {
+	routes := []AlertRouteConfig{}
+	for _, entry := range strings.Split(value, ",") {
+		selector, destination, found := strings.Cut(strings.TrimSpace(entry), "=")
+		if !found {
+			continue
+		}
+		types, severity, _ := strings.Cut(selector, ":")
+		sink, target, _ := strings.Cut(destination, ":")
+
+		route := AlertRouteConfig{Severity: severity, Sink: sink, Target: target}
+		if types != "*" {
+			route.Types = strings.Split(types, "|")
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// parses a comma separated list of vehicleType=spotType|spotType pairs into a vehicleType -> spot types map
+func parseFallbacks(value string) (map[string][]string, error) {
+	fallbacks := make(map[string][]string)
+	var problems []error
+	for i, pair := range strings.Split(value, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		vehicleType, spotTypes, found := strings.Cut(pair, "=")
+		if !found || vehicleType == "" {
+			problems = append(problems, fmt.Errorf("entry %d %q must be vehicleType=spotType|spotType", i+1, pair))
+			continue
+		}
+		for _, spotType := range strings.Split(spotTypes, "|") {
+			if spotType = strings.TrimSpace(spotType); spotType != "" {
+				fallbacks[vehicleType] = append(fallbacks[vehicleType], spotType)
+			}
+		}
+	}
+	return fallbacks, errors.Join(problems...)
+}
+
+// parses a comma separated list, skipping empty entries
+func parseList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// reads numbers and booleans from environment variables, remembering the values that are not
+// for Validate to report rather than silently using a default
+type envReader struct {
+	problems []FieldError
+}
+
+// int parses an integer variable, 0 when it is empty or invalid
+func (e *envReader) int(name string) int {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return 0
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		e.problems = append(e.problems, FieldError{Field: name, Message: fmt.Sprintf("must be an integer, got %q", value)})
+		return 0
+	}
+	return number
+}
+
+// parseEnv parses a list variable, remembering each malformed entry the parser reports
+func parseEnv[T any](e *envReader, name string, parse func(string) (T, error)) T {
+	value, err := parse(os.Getenv(name))
+	if err == nil {
+		return value
+	}
+
+	problems := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		problems = joined.Unwrap()
+	}
+	for _, problem := range problems {
+		e.problems = append(e.problems, FieldError{Field: name, Message: problem.Error()})
+	}
+	return value
+}
+
+// bool parses a boolean variable, the fallback when it is empty or invalid
+func (e *envReader) bool(name string, fallback bool) bool {
+	value := strings.TrimSpace(os.Getenv(name))
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		e.problems = append(e.problems, FieldError{Field: name, Message: fmt.Sprintf("must be true or false, got %q", value)})
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMalformedListEntriesAreReported(t *testing.T) {
+	t.Setenv("ATTENDANT_TOKENS", "alice:s3cret,bob,")
+	t.Setenv("GATE_CONTROLLERS", "1=tcp://10.0.0.1:9000,east=tcp://10.0.0.2:9000")
+	t.Setenv("GATE_THROUGHPUT", "1=12,2=fast")
+	t.Setenv("VEHICLE_TYPES", "Van:V-1:large:abc,:X-1")
+	t.Setenv("AVAILABILITY_ALERTS", "Automobile:ten:ops@example.com,Bicycle:5")
+	t.Setenv("TYPE_FALLBACKS", "Automobile=B-1,M-1")
+
+	cfg := NewAppConfig()
+	if len(cfg.AttendantTokens) != 1 || len(cfg.GateControllers) != 1 || len(cfg.GateThroughput) != 1 {
+		t.Errorf("got tokens %v, controllers %v and throughput %v, want the well-formed entries kept",
+			cfg.AttendantTokens, cfg.GateControllers, cfg.GateThroughput)
+	}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("want the malformed entries reported")
+	}
+	report := err.Error()
+	for _, want := range []string{
+		"ATTENDANT_TOKENS: entry 2 must be name:token",
+		`GATE_CONTROLLERS: entry 2 "east=tcp://10.0.0.2:9000"`,
+		`GATE_THROUGHPUT: entry 2 "2=fast"`,
+		`VEHICLE_TYPES: entry 1 "Van:V-1:large:abc": rate must be a number`,
+		`VEHICLE_TYPES: entry 2 ":X-1"`,
+		`AVAILABILITY_ALERTS: entry 1 "Automobile:ten:ops@example.com": threshold must be a whole number`,
+		`AVAILABILITY_ALERTS: entry 2 "Bicycle:5"`,
+		`TYPE_FALLBACKS: entry 2 "M-1"`,
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report misses %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "s3cret") || strings.Contains(report, "bob") {
+		t.Errorf("report reveals a token:\n%s", report)
+	}
+}
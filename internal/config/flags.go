@@ -0,0 +1,52 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ParseFlags overrides the configuration with command-line flags, which take precedence over
+// environment variables, e.g. -port 9090 -layout garage.yaml. Only the settings of the server process
+// have flags; lot policies and tokens are read from the environment only.
+func (cfg *AppConfig) ParseFlags(args []string) error {
+	flags := flag.NewFlagSet("server", flag.ContinueOnError)
+	flags.IntVar(&cfg.ServerPort, "port", cfg.ServerPort, "port to listen on (PORT)")
+	flags.StringVar(&cfg.TLSCertFile, "tls-cert", cfg.TLSCertFile, "TLS certificate file, enables HTTPS with -tls-key (TLS_CERT_FILE)")
+	flags.StringVar(&cfg.TLSKeyFile, "tls-key", cfg.TLSKeyFile, "TLS private key file (TLS_KEY_FILE)")
+	flags.StringVar(&cfg.StorageDriver, "storage", cfg.StorageDriver, "storage driver (STORAGE_DRIVER)")
+	flags.StringVar(&cfg.LogLevel, "log-level", cfg.LogLevel, "minimum log level: debug, info, warn or error (LOG_LEVEL)")
+	flags.StringVar(&cfg.LayoutFile, "layout", cfg.LayoutFile, "lot layout file (LAYOUT_FILE)")
+	flags.StringVar(&cfg.Timezone, "timezone", cfg.Timezone, "IANA time zone of the lot, e.g. Asia/Jakarta (LOT_TIMEZONE)")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", flags.Args())
+	}
+
+	return nil
+}
+
+// SlogLevel returns the configured log level
+func (cfg *AppConfig) SlogLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: must be debug, info, warn or error", cfg.LogLevel)
+	}
+	return level, nil
+}
+
+// Location returns the configured time zone of the lot, the server's local time zone when none is set
+func (cfg *AppConfig) Location() (*time.Location, error) {
+	if cfg.Timezone == "" {
+		return time.Local, nil
+	}
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: must be an IANA time zone such as Asia/Jakarta", cfg.Timezone)
+	}
+	return location, nil
+}
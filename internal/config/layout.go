@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// limits of the lot dimensions
+const (
+	maxFloors   = 8
+	maxGridSize = 1000
+)
+
+// describes the garage: its floors, gates and spots
+type Layout struct {
+	Gates  int           `yaml:"gates"`
+	Floors []FloorLayout `yaml:"floors"`
+	Spots  []SpotRule    `yaml:"spots"`
+}
+
+// describes the grid of one or more identical floors
+type FloorLayout struct {
+	Rows    int `yaml:"rows"`
+	Columns int `yaml:"columns"`
+	Count   int `yaml:"count"` // number of consecutive floors with this grid, 1 when omitted
+}
+
+// configures every spot within the given floors, rows and columns, all of them when omitted.
+// Rules apply in order, so later rules override earlier ones for the settings they set.
+type SpotRule struct {
+	Floors   *Range `yaml:"floors"`
+	Rows     *Range `yaml:"rows"`
+	Columns  *Range `yaml:"columns"`
+	Type     string `yaml:"type"` // spot code, e.g. "B-1"
+	Zone     string `yaml:"zone"`
+	Size     string `yaml:"size"`
+	Capacity int    `yaml:"capacity"`
+}
+
+// an inclusive range of indices, written as a single index like 3 or a range like "0-4"
+type Range struct {
+	From int
+	To   int
+}
+
+// UnmarshalYAML parses a single index or a "from-to" range
+func (r *Range) UnmarshalYAML(node *yaml.Node) error {
+	from, to, isRange := strings.Cut(strings.TrimSpace(node.Value), "-")
+	if !isRange {
+		to = from
+	}
+
+	var err error
+	if r.From, err = strconv.Atoi(strings.TrimSpace(from)); err != nil {
+		return fmt.Errorf("line %d: invalid range %q", node.Line, node.Value)
+	}
+	if r.To, err = strconv.Atoi(strings.TrimSpace(to)); err != nil {
+		return fmt.Errorf("line %d: invalid range %q", node.Line, node.Value)
+	}
+	if r.From < 0 || r.To < r.From {
+		return fmt.Errorf("line %d: invalid range %q", node.Line, node.Value)
+	}
+	return nil
+}
+
+// reports whether a range contains an index, a nil range contains all of them
+func (r *Range) contains(index int) bool {
+	return r == nil || (index >= r.From && index <= r.To)
+}
+
+// configures a single spot, empty fields are left as they are
+type SpotConfig struct {
+	Floor    int
+	Row      int
+	Column   int
+	Type     string
+	Zone     string
+	Size     string
+	Capacity int
+}
+
+// ParseLayout parses a YAML layout and checks its dimensions and rules. Problems are reported under source,
+// e.g. the name of the file the layout comes from.
+func ParseLayout(source string, data []byte) (*Layout, error) {
+	layout, err := parseLayout(source, data)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := &ValidationError{}
+	layout.validate(problems, source)
+	if err := problems.err(); err != nil {
+		return nil, err
+	}
+
+	return layout, nil
+}
+
+// reads a YAML layout file without checking it
+func readLayout(path string) (*Layout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseLayout(path, data)
+}
+
+// parses a YAML layout without checking it
+func parseLayout(source string, data []byte) (*Layout, error) {
+	layout := &Layout{}
+	if err := yaml.Unmarshal(data, layout); err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+
+	return layout, nil
+}
+
+// FloorGrids returns the rows and columns of every floor
+func (l *Layout) FloorGrids() []FloorLayout {
+	grids := []FloorLayout{}
+	for _, floor := range l.Floors {
+		count := floor.Count
+		if count == 0 {
+			count = 1
+		}
+		for i := 0; i < count; i++ {
+			grids = append(grids, FloorLayout{Rows: floor.Rows, Columns: floor.Columns, Count: 1})
+		}
+	}
+	return grids
+}
+
+// SpotConfigs applies the spot rules to every spot and returns the spots configured by at least one
+// rule, ordered by floor, row and column
+func (l *Layout) SpotConfigs() []SpotConfig {
+	spots := []SpotConfig{}
+	for f, grid := range l.FloorGrids() {
+		for row := 0; row < grid.Rows; row++ {
+			for column := 0; column < grid.Columns; column++ {
+				spot := SpotConfig{Floor: f, Row: row, Column: column}
+				matched := false
+				for _, rule := range l.Spots {
+					if !rule.Floors.contains(f) || !rule.Rows.contains(row) || !rule.Columns.contains(column) {
+						continue
+					}
+					matched = true
+					if rule.Type != "" {
+						spot.Type = rule.Type
+					}
+					if rule.Zone != "" {
+						spot.Zone = rule.Zone
+					}
+					if rule.Size != "" {
+						spot.Size = rule.Size
+					}
+					if rule.Capacity != 0 {
+						spot.Capacity = rule.Capacity
+					}
+				}
+				if matched {
+					spots = append(spots, spot)
+				}
+			}
+		}
+	}
+	return spots
+}
+
+// checks the dimensions and that every rule addresses existing spots
+func (l *Layout) validate(problems *ValidationError, prefix string) {
+	if l.Gates < 1 {
+		problems.add(prefix+".gates", "must be at least 1")
+	}
+
+	grids := l.FloorGrids()
+	if len(grids) < 1 || len(grids) > maxFloors {
+		problems.add(prefix+".floors", "must describe 1 to %d floors, got %d", maxFloors, len(grids))
+	}
+	for i, floor := range l.Floors {
+		field := fmt.Sprintf("%s.floors[%d]", prefix, i)
+		if floor.Rows < 1 || floor.Rows > maxGridSize {
+			problems.add(field+".rows", "must be between 1 and %d", maxGridSize)
+		}
+		if floor.Columns < 1 || floor.Columns > maxGridSize {
+			problems.add(field+".columns", "must be between 1 and %d", maxGridSize)
+		}
+		if floor.Count < 0 {
+			problems.add(field+".count", "must not be negative")
+		}
+	}
+
+	for i, rule := range l.Spots {
+		field := fmt.Sprintf("%s.spots[%d]", prefix, i)
+		if rule.Floors != nil && rule.Floors.To >= len(grids) {
+			problems.add(field+".floors", "%d-%d outside the %d floors", rule.Floors.From, rule.Floors.To, len(grids))
+		}
+		for f, grid := range grids {
+			if !rule.Floors.contains(f) {
+				continue
+			}
+			if rule.Rows != nil && rule.Rows.To >= grid.Rows {
+				problems.add(field+".rows", "%d-%d outside floor %d with %d rows", rule.Rows.From, rule.Rows.To, f, grid.Rows)
+				break
+			}
+			if rule.Columns != nil && rule.Columns.To >= grid.Columns {
+				problems.add(field+".columns", "%d-%d outside floor %d with %d columns", rule.Columns.From, rule.Columns.To, f, grid.Columns)
+				break
+			}
+		}
+		if rule.Capacity < 0 {
+			problems.add(field+".capacity", "must be at least 1")
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"parking-lot-system/internal/alert"
+	"parking-lot-system/internal/auth"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/domain/plate"
+	"parking-lot-system/internal/domain/spotid"
+	"parking-lot-system/internal/i18n"
+	"parking-lot-system/internal/notify"
+	"parking-lot-system/internal/templates"
+	"sort"
+	"strings"
+)
+
+// storage drivers the server supports
+var storageDrivers = []string{"memory"}
+
+// a problem with a single configuration field, e.g. "layout.spots[2].rows"
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// lists every problem found in the configuration
+type ValidationError struct {
+	Problems []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		lines[i] = fmt.Sprintf("%s: %s", problem.Field, problem.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ValidationError) add(field, format string, args ...any) {
+	e.Problems = append(e.Problems, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// returns the error when problems were found, nil otherwise
+func (e *ValidationError) err() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the whole configuration, including the layout file, and reports all problems at once
+func (cfg *AppConfig) Validate() error {
+	problems := &ValidationError{Problems: append([]FieldError(nil), cfg.envProblems...)}
+
+	// Server
+	if cfg.ServerPort < 1 || cfg.ServerPort > 65535 {
+		problems.add("port", "must be between 1 and 65535, got %d", cfg.ServerPort)
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		problems.add("tls", "needs both a certificate and a key file")
+	}
+	if !contains(storageDrivers, cfg.StorageDriver) {
+		problems.add("storageDriver", "unsupported driver %q, must be one of %s", cfg.StorageDriver, strings.Join(storageDrivers, ", "))
+	}
+	if _, err := cfg.SlogLevel(); err != nil {
+		problems.add("logLevel", "%v", err)
+	}
+	if cfg.LatencyBudget < 0 {
+		problems.add("latencyBudget", "must not be negative, got %v", cfg.LatencyBudget)
+	}
+	if _, err := cfg.Location(); err != nil {
+		problems.add("timezone", "%v", err)
+	}
+
+	// Spot IDs and vehicle numbers
+	if cfg.SpotIDFormat != "" {
+		if _, err := spotid.NewPattern(cfg.SpotIDFormat, cfg.SpotIDBase); err != nil {
+			problems.add("spotIdFormat", "%v", err)
+		}
+	}
+	if cfg.PlatePattern != "" {
+		if _, err := plate.NewScheme("custom", cfg.PlatePattern, cfg.PlateMinLength, cfg.PlateMaxLength); err != nil {
+			problems.add("platePattern", "%v", err)
+		}
+	} else if _, err := plate.Lookup(cfg.PlateScheme); err != nil {
+		problems.add("plateScheme", "%v", err)
+	}
+	if !contains(parking.DuplicatePolicies, cfg.DuplicatePolicy) {
+		problems.add("duplicatePolicy", "unknown policy %q, must be one of %s", cfg.DuplicatePolicy, strings.Join(parking.DuplicatePolicies, ", "))
+	}
+	if cfg.ReentryCooldown < 0 {
+		problems.add("reentryCooldown", "must not be negative, got %v", cfg.ReentryCooldown)
+	}
+	if cfg.UnusedSpotWindow <= 0 {
+		problems.add("unusedSpotWindow", "must be positive, got %v", cfg.UnusedSpotWindow)
+	}
+	if cfg.SensorLowBattery > 100 {
+		problems.add("sensorLowBattery", "must be a percentage up to 100, got %d", cfg.SensorLowBattery)
+	}
+	if cfg.TimeSeriesInterval <= 0 {
+		problems.add("timeSeriesInterval", "must be positive, got %v", cfg.TimeSeriesInterval)
+	} else if cfg.TimeSeriesRetention < cfg.TimeSeriesInterval {
+		problems.add("timeSeriesRetention", "must be at least the interval %v, got %v", cfg.TimeSeriesInterval, cfg.TimeSeriesRetention)
+	}
+	if cfg.EventLogLimit < 0 {
+		problems.add("eventLogLimit", "must not be negative, got %d", cfg.EventLogLimit)
+	}
+
+	// Vehicle and spot types
+	vehicleTypes := make(map[string]bool)
+	spotCodes := map[string]bool{parking.InactiveSpotCode: true, parking.NoSpotCode: true}
+	for _, vehicleType := range parking.DefaultVehicleTypes {
+		vehicleTypes[vehicleType.Name] = true
+		spotCodes[vehicleType.SpotCode] = true
+	}
+	for i, vehicleType := range cfg.VehicleTypes {
+		field := fmt.Sprintf("vehicleTypes[%d]", i)
+		if vehicleType.Size != "" && !contains(parking.SpotSizes, vehicleType.Size) {
+			problems.add(field+".size", "unknown size %q, must be one of %s", vehicleType.Size, strings.Join(parking.SpotSizes, ", "))
+		}
+		if vehicleType.Rate < 0 {
+			problems.add(field+".rate", "must not be negative, got %g", vehicleType.Rate)
+		}
+		vehicleTypes[vehicleType.Name] = true
+		spotCodes[vehicleType.SpotCode] = true
+	}
+	for i, spotType := range cfg.SpotTypes {
+		field := fmt.Sprintf("spotTypes[%d]", i)
+		if spotCodes[spotType.Code] {
+			problems.add(field+".code", "%s is already used", spotType.Code)
+		}
+		if spotType.VehicleType != "" && !vehicleTypes[spotType.VehicleType] {
+			problems.add(field+".vehicleType", "unknown vehicle type %q", spotType.VehicleType)
+		}
+		spotCodes[spotType.Code] = true
+	}
+	for _, vehicleType := range sortedKeys(cfg.Fallbacks) {
+		if !vehicleTypes[vehicleType] {
+			problems.add("fallbacks."+vehicleType, "unknown vehicle type %q", vehicleType)
+		}
+		for i, spotType := range cfg.Fallbacks[vehicleType] {
+			if !vehicleTypes[spotType] {
+				problems.add(fmt.Sprintf("fallbacks.%s[%d]", vehicleType, i), "unknown vehicle type %q", spotType)
+			}
+		}
+	}
+	for i, alert := range cfg.AvailabilityAlerts {
+		field := fmt.Sprintf("availabilityAlerts[%d]", i)
+		if !vehicleTypes[alert.VehicleType] {
+			problems.add(field+".vehicleType", "unknown vehicle type %q", alert.VehicleType)
+		}
+		if alert.Threshold < 1 {
+			problems.add(field+".threshold", "must be at least 1 spot")
+		}
+		if !notify.IsEmail(alert.Contact) && !notify.IsPhoneNumber(alert.Contact) {
+			problems.add(field+".contact", "invalid contact %q, must be an email address or a phone number like +6281234567890", alert.Contact)
+		}
+	}
+
+	if _, err := cfg.DailyReportOffset(); err != nil {
+		problems.add("dailyReportTime", "%v", err)
+	}
+	switch recipient := cfg.DailyReportRecipient; {
+	case recipient == "":
+	case notify.IsEmail(recipient):
+		if cfg.NotifySMTPURL == "" {
+			problems.add("dailyReportRecipient", "emailing the report to %s needs NOTIFY_SMTP_URL", recipient)
+		}
+	case notify.IsPhoneNumber(recipient):
+		if cfg.NotifySMSGatewayURL == "" {
+			problems.add("dailyReportRecipient", "texting the report to %s needs NOTIFY_SMS_GATEWAY_URL", recipient)
+		}
+	default:
+		problems.add("dailyReportRecipient", "invalid contact %q, must be an email address or a phone number like +6281234567890", recipient)
+	}
+
+	// Layout
+	layout, err := readLayout(cfg.LayoutFile)
+	if err != nil {
+		problems.add("layout", "%v", err)
+	} else {
+		layout.validate(problems, "layout")
+		for i, rule := range layout.Spots {
+			field := fmt.Sprintf("layout.spots[%d]", i)
+			if rule.Type != "" && !spotCodes[rule.Type] {
+				problems.add(field+".type", "unknown spot type %q", rule.Type)
+			}
+			if rule.Size != "" && !contains(parking.SpotSizes, rule.Size) {
+				problems.add(field+".size", "unknown size %q, must be one of %s", rule.Size, strings.Join(parking.SpotSizes, ", "))
+			}
+		}
+	}
+
+	// Staff
+	for _, token := range sortedKeys(cfg.EnforcementTokens) {
+		if name, exists := cfg.AttendantTokens[token]; exists {
+			problems.add("enforcementTokens."+cfg.EnforcementTokens[token], "token is also the attendant token of %s", name)
+		}
+	}
+	for _, token := range sortedKeys(cfg.AdminTokens) {
+		if name, exists := cfg.AttendantTokens[token]; exists {
+			problems.add("adminTokens."+cfg.AdminTokens[token], "token is also the attendant token of %s", name)
+		}
+		if name, exists := cfg.EnforcementTokens[token]; exists {
+			problems.add("adminTokens."+cfg.AdminTokens[token], "token is also the enforcement token of %s", name)
+		}
+	}
+	if cfg.OIDCIssuer != "" {
+		if parsed, err := url.Parse(cfg.OIDCIssuer); err != nil || !contains([]string{"http", "https"}, parsed.Scheme) || parsed.Host == "" {
+			problems.add("oidcIssuer", "invalid URL %q, must be an http or https URL", cfg.OIDCIssuer)
+		}
+		if cfg.OIDCAudience == "" {
+			problems.add("oidcAudience", "required with an OIDC issuer")
+		}
+		if len(cfg.OIDCRoleGroups) == 0 {
+			problems.add("oidcRoleGroups", "required with an OIDC issuer, no user would have a role")
+		}
+	}
+	for _, group := range sortedKeys(cfg.OIDCRoleGroups) {
+		if role := cfg.OIDCRoleGroups[group]; !contains(auth.Roles, role) {
+			problems.add("oidcRoleGroups."+group, "unknown role %q, must be one of %s", role, strings.Join(auth.Roles, ", "))
+		}
+	}
+	for _, role := range cfg.RefundRoles {
+		if !contains(auth.Roles, role) {
+			problems.add("refundRoles", "unknown role %q, must be one of %s", role, strings.Join(auth.Roles, ", "))
+		}
+	}
+	if cfg.DeviceAccessTokenTTL >= cfg.DeviceRefreshTokenTTL {
+		problems.add("deviceAccessTokenTTL", "must be shorter than the device refresh token lifetime of %v, got %v",
+			cfg.DeviceRefreshTokenTTL, cfg.DeviceAccessTokenTTL)
+	}
+
+	// Integrations
+	for _, gate := range sortedKeys(cfg.GateControllers) {
+		field := fmt.Sprintf("gateControllers.%d", gate)
+		if layout != nil && (gate < 1 || gate > layout.Gates) {
+			problems.add(field, "gate %d does not exist, the layout has %d gates", gate, layout.Gates)
+		}
+		if parsed, err := url.Parse(cfg.GateControllers[gate]); err != nil || !contains([]string{"http", "https", "tcp"}, parsed.Scheme) {
+			problems.add(field, "invalid address %q, must be an http, https or tcp URL", cfg.GateControllers[gate])
+		}
+	}
+	for _, gate := range sortedKeys(cfg.GateThroughput) {
+		field := fmt.Sprintf("gateThroughput.%d", gate)
+		if layout != nil && (gate < 1 || gate > layout.Gates) {
+			problems.add(field, "gate %d does not exist, the layout has %d gates", gate, layout.Gates)
+		}
+		if cfg.GateThroughput[gate] < 1 {
+			problems.add(field, "throughput must be at least 1 vehicle per minute")
+		}
+	}
+	for i, peer := range cfg.FederationPeers {
+		if parsed, err := url.Parse(peer); err != nil || parsed.Host == "" {
+			problems.add(fmt.Sprintf("federationPeers[%d]", i), "invalid URL %q", peer)
+		}
+	}
+	if _, err := auth.ParseAllowlist(cfg.WebhookAllowedNetworks); err != nil {
+		problems.add("webhookAllowedNetworks", "%v", err)
+	}
+	if cfg.MQTTBrokerURL != "" {
+		if parsed, err := url.Parse(cfg.MQTTBrokerURL); err != nil || parsed.Host == "" {
+			problems.add("mqttBrokerUrl", "invalid URL %q", cfg.MQTTBrokerURL)
+		}
+	}
+	if cfg.NotifySMTPURL != "" {
+		if _, err := notify.NewSMTPNotifier(cfg.NotifySMTPURL); err != nil {
+			problems.add("notifySmtpUrl", "%v", err)
+		}
+	}
+	if cfg.MessageCatalog != "" {
+		if _, err := i18n.LoadCatalog(cfg.MessageCatalog); err != nil {
+			problems.add("messageCatalog", "%v", err)
+		}
+	}
+	if _, err := templates.Load(cfg.TemplateDir); err != nil {
+		problems.add("templateDir", "%v", err)
+	}
+	if cfg.NotifySMSGatewayURL != "" {
+		if parsed, err := url.Parse(cfg.NotifySMSGatewayURL); err != nil || !contains([]string{"http", "https"}, parsed.Scheme) || parsed.Host == "" {
+			problems.add("notifySmsGatewayUrl", "invalid URL %q, must be an http or https URL", cfg.NotifySMSGatewayURL)
+		}
+	}
+	for i, route := range cfg.AlertRoutes {
+		field := fmt.Sprintf("alertRoutes[%d]", i)
+		for _, alertType := range route.Types {
+			if !contains(alert.Types, alertType) {
+				problems.add(field+".types", "unknown alert type %q, must be one of %s", alertType, strings.Join(alert.Types, ", "))
+			}
+		}
+		if !contains(alert.Severities, route.Severity) {
+			problems.add(field+".severity", "unknown severity %q, must be one of %s", route.Severity, strings.Join(alert.Severities, ", "))
+		}
+		switch {
+		case !contains(alert.SinkKinds, route.Sink):
+			problems.add(field+".sink", "unknown sink %q, must be one of %s", route.Sink, strings.Join(alert.SinkKinds, ", "))
+		case route.Sink == alert.SinkEmail && cfg.NotifySMTPURL == "":
+			problems.add(field+".sink", "email alerts need NOTIFY_SMTP_URL")
+		default:
+			// The email channel was checked, any notifier will do to check the target
+			if _, err := alert.NewSink(route.Sink, route.Target, notify.Router{}); err != nil {
+				problems.add(field+".target", "%v", err)
+			}
+		}
+	}
+
+	return problems.err()
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// returns the keys of a map in order, so problems are reported in a stable order
+func sortedKeys[K int | string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
@@ -0,0 +1,301 @@
+package devicelink
+
+import (
+	"fmt"
+	"log"
+	"parking-lot-system/internal/domain/parking"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Actions devices can be commanded to perform
+const (
+	ActionOpenBarrier = "open_barrier"
+	ActionShowMessage = "show_message"
+	ActionReboot      = "reboot"
+)
+
+// Actions lists the actions devices can be commanded to perform
+var Actions = []string{ActionOpenBarrier, ActionShowMessage, ActionReboot}
+
+// Command statuses
+const (
+	StatusSent         = "sent"         // written to the device, awaiting its acknowledgment
+	StatusAcknowledged = "acknowledged" // the device performed the command
+	StatusFailed       = "failed"       // the device could not perform the command, or it could not be sent
+	StatusExpired      = "expired"      // the device did not acknowledge the command in time
+)
+
+// DefaultAckTimeout is how long a device has to acknowledge a command unless configured otherwise
+const DefaultAckTimeout = 30 * time.Second
+
+const (
+	writeTimeout = 5 * time.Second
+	// a device that answers no ping for this long is disconnected
+	pongTimeout  = 60 * time.Second
+	pingInterval = pongTimeout / 2
+)
+
+// Command is an action pushed to a connected device
+type Command struct {
+	ID             int
+	Device         string
+	Action         string
+	Message        string // text to show for show_message
+	Status         string
+	Error          string // reason the command failed, as reported by the device
+	SentAt         time.Time
+	AcknowledgedAt time.Time // zero until the device acknowledged or failed the command
+}
+
+// message is a frame exchanged with a device: commands and incident broadcasts from the server,
+// acknowledgments from the device
+type message struct {
+	Type    string `json:"type"` // command, ack, incident.started or incident.ended
+	ID      int    `json:"id,omitempty"`
+	Action  string `json:"action,omitempty"`
+	Message string `json:"message,omitempty"`
+	OK      bool   `json:"ok,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// connection is the WebSocket of a device, writes are serialized
+type connection struct {
+	socket     *websocket.Conn
+	writeMutex sync.Mutex
+}
+
+func (c *connection) write(frame message) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	c.socket.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return c.socket.WriteJSON(frame)
+}
+
+func (c *connection) ping() error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	return c.socket.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout))
+}
+
+// Hub keeps the WebSocket connections of the devices and pushes commands to them, tracking whether
+// each command was acknowledged. Commands are kept in memory.
+type Hub struct {
+	ackTimeout time.Duration
+
+	mutex       sync.Mutex
+	connections map[string]*connection // device ID -> its connection
+	commands    []*Command             // in the order they were sent, command ID - 1 is the index
+	incident    *message               // broadcast of the active incident, nil when there is none
+}
+
+func NewHub(ackTimeout time.Duration) *Hub {
+	if ackTimeout <= 0 {
+		ackTimeout = DefaultAckTimeout
+	}
+	return &Hub{
+		ackTimeout:  ackTimeout,
+		connections: make(map[string]*connection),
+	}
+}
+
+// Serve reads the acknowledgments of a device from its WebSocket until it disconnects. A new connection
+// of the device replaces the previous one. seen is called whenever the device shows it is alive.
+func (h *Hub) Serve(device string, socket *websocket.Conn, seen func()) {
+	conn := &connection{socket: socket}
+
+	h.mutex.Lock()
+	previous := h.connections[device]
+	h.connections[device] = conn
+	incident := h.incident
+	h.mutex.Unlock()
+
+	if previous != nil {
+		previous.socket.Close()
+	}
+	// Devices connecting during an incident learn about it right away
+	if incident != nil {
+		conn.write(*incident)
+	}
+	defer func() {
+		h.mutex.Lock()
+		if h.connections[device] == conn {
+			delete(h.connections, device)
+		}
+		h.mutex.Unlock()
+		socket.Close()
+	}()
+
+	socket.SetReadDeadline(time.Now().Add(pongTimeout))
+	socket.SetPongHandler(func(string) error {
+		seen()
+		return socket.SetReadDeadline(time.Now().Add(pongTimeout))
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+	go h.keepAlive(conn, done)
+
+	for {
+		var frame message
+		if err := socket.ReadJSON(&frame); err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				log.Printf("Device %s disconnected: %v", device, err)
+			}
+			return
+		}
+		socket.SetReadDeadline(time.Now().Add(pongTimeout))
+		seen()
+
+		if frame.Type == "ack" {
+			h.acknowledge(device, frame)
+		}
+	}
+}
+
+// keepAlive pings a connection until done is closed, so silent devices are noticed
+func (h *Hub) keepAlive(conn *connection, done <-chan struct{}) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := conn.ping(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Send pushes a command to a connected device. The command is sent once the device received it;
+// its status turns acknowledged or failed when the device answers, or expired when it does not.
+func (h *Hub) Send(device, action, text string) (Command, error) {
+	if !slices.Contains(Actions, action) {
+		return Command{}, fmt.Errorf("%w: action %q", pkgerrors.ErrInvalidCommand, action)
+	}
+	if action == ActionShowMessage && text == "" {
+		return Command{}, fmt.Errorf("%w: show_message needs a message", pkgerrors.ErrInvalidCommand)
+	}
+
+	h.mutex.Lock()
+	conn, connected := h.connections[device]
+	if !connected {
+		h.mutex.Unlock()
+		return Command{}, fmt.Errorf("%w: %s", pkgerrors.ErrDeviceNotConnected, device)
+	}
+	command := &Command{
+		ID:      len(h.commands) + 1,
+		Device:  device,
+		Action:  action,
+		Message: text,
+		Status:  StatusSent,
+		SentAt:  time.Now(),
+	}
+	h.commands = append(h.commands, command)
+	h.mutex.Unlock()
+
+	if err := conn.write(message{Type: "command", ID: command.ID, Action: action, Message: text}); err != nil {
+		h.mutex.Lock()
+		command.Status = StatusFailed
+		command.Error = err.Error()
+		h.mutex.Unlock()
+		return Command{}, fmt.Errorf("%w: %s: %v", pkgerrors.ErrDeviceNotConnected, device, err)
+	}
+
+	time.AfterFunc(h.ackTimeout, func() { h.expire(command) })
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return *command, nil
+}
+
+// HandleEvent broadcasts the start and end of an incident to every connected device, e.g. for
+// displays to show evacuation instructions
+func (h *Hub) HandleEvent(event parking.Event) {
+	if event.Type != parking.EventIncidentStarted && event.Type != parking.EventIncidentEnded {
+		return
+	}
+	frame := message{Type: event.Type, Message: event.Reason}
+
+	h.mutex.Lock()
+	h.incident = nil
+	if event.Type == parking.EventIncidentStarted {
+		h.incident = &frame
+	}
+	connections := make([]*connection, 0, len(h.connections))
+	for _, conn := range h.connections {
+		connections = append(connections, conn)
+	}
+	h.mutex.Unlock()
+
+	// Writes may block on slow devices, listeners must not
+	go func() {
+		for _, conn := range connections {
+			conn.write(frame)
+		}
+	}()
+}
+
+// Commands returns the commands sent to a device, or to all devices when device is empty, in the order they were sent
+func (h *Hub) Commands(device string) []Command {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	commands := []Command{}
+	for _, command := range h.commands {
+		if device == "" || command.Device == device {
+			commands = append(commands, *command)
+		}
+	}
+	return commands
+}
+
+// Connected reports whether a device is connected
+func (h *Hub) Connected(device string) bool {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	_, connected := h.connections[device]
+	return connected
+}
+
+// acknowledge records the answer of a device to one of its commands
+func (h *Hub) acknowledge(device string, frame message) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if frame.ID < 1 || frame.ID > len(h.commands) {
+		return
+	}
+	command := h.commands[frame.ID-1]
+	// devices cannot answer the commands of other devices, nor commands that expired
+	if command.Device != device || command.Status != StatusSent {
+		return
+	}
+
+	command.AcknowledgedAt = time.Now()
+	command.Status = StatusAcknowledged
+	if !frame.OK {
+		command.Status = StatusFailed
+		command.Error = frame.Error
+	}
+}
+
+// expire gives up on a command the device did not answer
+func (h *Hub) expire(command *Command) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if command.Status == StatusSent {
+		command.Status = StatusExpired
+	}
+}
@@ -0,0 +1,21 @@
+package parking
+
+import (
+	"parking-lot-system/internal/alert"
+	"time"
+)
+
+// SetAlertDispatcher sets where alerts for operators are sent, e.g. when the lot is full or a device
+// went offline. No alerts are sent when it is nil.
+func (s *ParkingService) SetAlertDispatcher(dispatcher *alert.Dispatcher) {
+	s.alerts = dispatcher
+}
+
+// raiseAlert sends an alert to the operators, when alerting is configured
+func (s *ParkingService) raiseAlert(alertType, severity, key, summary string) {
+	if s.alerts == nil {
+		return
+	}
+
+	s.alerts.Raise(alert.Alert{Type: alertType, Severity: severity, Key: key, Summary: summary, Time: time.Now().In(s.location)})
+}
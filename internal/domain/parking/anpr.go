@@ -0,0 +1,29 @@
+package parking
+
+import (
+	pkgerrors "parking-lot-system/pkg/errors"
+)
+
+// Plate recognition event directions
+const (
+	DirectionEntry = "entry"
+	DirectionExit  = "exit"
+)
+
+// HandlePlateRecognition parks a vehicle recognized by an entry camera with automatic spot
+// allocation, or unparks a vehicle recognized by an exit camera. It returns the spot ID.
+// The gate is the one the camera is installed at, 0 when unknown.
+func (s *ParkingService) HandlePlateRecognition(direction, vehicleType, vehicleNumber string, gate int) (string, error) {
+	switch direction {
+	case DirectionEntry:
+		if vehicleType == "" {
+			// Cameras that do not classify vehicles are installed at car lanes
+			vehicleType = Automobile
+		}
+		return s.ParkWithOptions(vehicleType, vehicleNumber, ParkOptions{Gate: gate})
+	case DirectionExit:
+		return s.UnparkWithOptions("", vehicleNumber, UnparkOptions{Gate: gate})
+	default:
+		return "", pkgerrors.ErrInvalidDirection
+	}
+}
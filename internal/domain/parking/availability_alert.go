@@ -0,0 +1,113 @@
+package parking
+
+import (
+	"fmt"
+	"parking-lot-system/internal/templates"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sync"
+)
+
+// AvailabilityAlert subscribes an operator to a notification when the free spots of a vehicle type drop below a threshold
+type AvailabilityAlert struct {
+	Contact     string
+	VehicleType string
+	Threshold   int
+}
+
+// availabilityAlerts keeps the subscriptions and which vehicle types are below their threshold
+type availabilityAlerts struct {
+	mutex  sync.Mutex
+	alerts []AvailabilityAlert
+	// also notify the drivers holding a spot of the vehicle type
+	notifyReservations bool
+	// whether the availability is below the threshold of each alert, so an alert is sent once per drop
+	low []bool
+}
+
+// availabilityListener checks the availability of the subscribed vehicle types whenever it may have changed
+type availabilityListener struct {
+	service *ParkingService
+}
+
+func (l availabilityListener) HandleEvent(event Event) {
+	if event.Type == EventVehicleParked || event.Type == EventVehicleUnparked || event.Type == EventSpotReconfigured {
+		l.service.checkAvailability()
+	}
+}
+
+// SetAvailabilityAlerts sets who is notified when the free spots of a vehicle type drop below a threshold, and
+// whether drivers holding a spot of that vehicle type are notified too. Each alert is sent once per drop and
+// again only after the availability recovered to the threshold.
+func (s *ParkingService) SetAvailabilityAlerts(alerts []AvailabilityAlert, notifyReservations bool) error {
+	for _, alert := range alerts {
+		if err := s.validateVehicleType(alert.VehicleType); err != nil {
+			return err
+		}
+		if alert.Contact == "" {
+			return fmt.Errorf("%w: missing for %s alert", pkgerrors.ErrInvalidContact, alert.VehicleType)
+		}
+		if err := s.validateContact(alert.Contact); err != nil {
+			return err
+		}
+		if alert.Threshold < 1 {
+			return fmt.Errorf("%s alert threshold must be at least 1, got %d", alert.VehicleType, alert.Threshold)
+		}
+	}
+
+	s.availability.mutex.Lock()
+	defer s.availability.mutex.Unlock()
+
+	s.availability.alerts = append([]AvailabilityAlert(nil), alerts...)
+	s.availability.notifyReservations = notifyReservations
+	s.availability.low = make([]bool, len(alerts))
+	return nil
+}
+
+// checkAvailability notifies the subscribers of the vehicle types whose availability dropped below their threshold
+func (s *ParkingService) checkAvailability() {
+	s.availability.mutex.Lock()
+	defer s.availability.mutex.Unlock()
+
+	available := make(map[string]int)
+	for i, alert := range s.availability.alerts {
+		free, checked := available[alert.VehicleType]
+		if !checked {
+			var err error
+			if free, err = s.repo.GetAvailableCount(alert.VehicleType, -1); err != nil {
+				continue
+			}
+			available[alert.VehicleType] = free
+		}
+
+		wasLow := s.availability.low[i]
+		s.availability.low[i] = free < alert.Threshold
+		if wasLow || free >= alert.Threshold {
+			continue
+		}
+
+		data := struct {
+			VehicleType string
+			Free        int
+		}{alert.VehicleType, free}
+		s.sendNotification(alert.Contact, templates.AvailabilityAlert, data)
+		if s.availability.notifyReservations {
+			for _, contact := range s.reservationContacts(alert.VehicleType) {
+				s.sendNotification(contact, templates.AvailabilityAlert, data)
+			}
+		}
+	}
+}
+
+// reservationContacts returns the contacts of the drivers holding a spot of a vehicle type
+func (s *ParkingService) reservationContacts(vehicleType string) []string {
+	s.holds.mutex.Lock()
+	defer s.holds.mutex.Unlock()
+
+	contacts := []string{}
+	for _, hold := range s.holds.holds {
+		if hold.VehicleType == vehicleType && hold.Contact != "" {
+			contacts = append(contacts, hold.Contact)
+		}
+	}
+	return contacts
+}
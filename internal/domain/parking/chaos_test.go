@@ -0,0 +1,70 @@
+//go:build chaos
+
+package parking
+
+import (
+	"errors"
+	"fmt"
+	"parking-lot-system/internal/domain/spotid"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"testing"
+)
+
+// newFaultyService returns a service on a lot of one row of spots whose storage calls fail as configured
+func newFaultyService(t *testing.T, spots int, faults repository.FaultConfig) *ParkingService {
+	t.Helper()
+
+	service := NewParkingService(repository.NewFaultInjectingRepository(repository.NewParkingRepository(spotid.Default), faults, 1))
+	if err := service.InitializeParkingLot(1, 1, spots, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	for col := 0; col < spots; col++ {
+		if err := service.ConfigureSpot(0, 0, col, "A-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return service
+}
+
+func TestParkFailsCleanlyOnStoreTimeouts(t *testing.T) {
+	service := newFaultyService(t, 2, repository.FaultConfig{TimeoutRate: 1})
+
+	if _, err := service.Park(Automobile, "AB1"); !errors.Is(err, pkgerrors.ErrStoreTimeout) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrStoreTimeout)
+	}
+	if stats := service.GetOccupancyStats(); stats.Total.Occupied != 0 {
+		t.Fatalf("got %d occupied after a failed park, want none", stats.Total.Occupied)
+	}
+	page, err := service.QueryEvents(EventQuery{Type: EventVehicleParked})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Events) != 0 {
+		t.Fatalf("got %+v, want no vehicle.parked event for a failed park", page.Events)
+	}
+}
+
+func TestParkUnderConflictsKeepsCountersConsistent(t *testing.T) {
+	const spots = 20
+	service := newFaultyService(t, spots, repository.FaultConfig{ConflictRate: 0.4})
+
+	parked := 0
+	for i := 0; i < spots; i++ {
+		_, err := service.Park(Automobile, fmt.Sprintf("AB%d", i))
+		switch {
+		case err == nil:
+			parked++
+		case !errors.Is(err, pkgerrors.ErrSpotFull):
+			t.Fatalf("park %d: got %v, want success or %v", i, err, pkgerrors.ErrSpotFull)
+		}
+	}
+	if parked == 0 {
+		t.Fatal("no vehicle parked, the service does not retry conflicts")
+	}
+
+	stats := service.GetOccupancyStats()
+	if stats.Total.Occupied != parked || stats.Total.Capacity != spots {
+		t.Fatalf("got %+v, want %d of %d occupied", stats.Total, parked, spots)
+	}
+}
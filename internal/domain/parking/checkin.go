@@ -0,0 +1,196 @@
+package parking
+
+import (
+	"errors"
+	"fmt"
+	"parking-lot-system/internal/domain/plate"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sync"
+	"time"
+)
+
+// Check-in statuses
+const (
+	CheckInWaiting  = "waiting"  // the vehicle waits in the buffer lane for a spot
+	CheckInAssigned = "assigned" // the vehicle was parked at the assigned spot
+	CheckInFailed   = "failed"   // the vehicle cannot be parked, e.g. it is already parked elsewhere
+)
+
+// CheckIn is a vehicle that entered the buffer lane at the gate before it was assigned a spot
+type CheckIn struct {
+	ID            int
+	VehicleType   string
+	VehicleNumber string
+	Options       ParkOptions
+	Status        string
+	Position      int    // place among the waiting vehicles, 1 for the next one, 0 once assigned or failed
+	SpotID        string // set once assigned
+	Error         error  // set when failed
+	CheckedInAt   time.Time
+	AssignedAt    time.Time
+	FinishedAt    time.Time // set once assigned or failed
+}
+
+// checkInQueue keeps the check-ins and assigns spots to the waiting ones in check-in order
+type checkInQueue struct {
+	mutex    sync.Mutex
+	nextID   int
+	checkIns map[int]*CheckIn
+	waiting  []*CheckIn
+
+	start sync.Once
+	wake  chan struct{}
+}
+
+// checkInListener looks for spots for the waiting check-ins when spots are vacated or reconfigured
+type checkInListener struct {
+	service *ParkingService
+}
+
+func (l checkInListener) HandleEvent(event Event) {
+	if event.Type == EventVehicleUnparked || event.Type == EventSpotReconfigured || event.Type == EventIncidentEnded {
+		l.service.wakeCheckIns()
+	}
+}
+
+// CheckIn lets a vehicle into the buffer lane at the gate. A spot is assigned asynchronously,
+// the vehicle is parked there and announced with a vehicle.parked event as soon as a spot is free.
+func (s *ParkingService) CheckIn(vehicleType, vehicleNumber string, opts ParkOptions) (CheckIn, error) {
+	vehicleNumber = plate.Normalize(vehicleNumber)
+
+	if err := s.checkIncident(); err != nil {
+		return CheckIn{}, err
+	}
+
+	// Validate inputs
+	registered, err := s.vehicleType(vehicleType)
+	if err != nil {
+		return CheckIn{}, err
+	}
+	if err := s.validateVehicleNumber(vehicleNumber); err != nil {
+		return CheckIn{}, err
+	}
+	if err := s.validateGate(opts.Gate); err != nil {
+		return CheckIn{}, err
+	}
+	if opts.Size == "" {
+		opts.Size = registered.Size
+	}
+	if err := s.validateSize(opts.Size); err != nil {
+		return CheckIn{}, err
+	}
+
+	isParked, currentSpotID, _ := s.repo.IsVehicleParked(vehicleNumber)
+	if isParked {
+		return CheckIn{}, fmt.Errorf("%w: %s at spot %s", pkgerrors.ErrVehicleAlreadyParked, vehicleNumber, currentSpotID)
+	}
+
+	s.checkIns.mutex.Lock()
+	for _, waiting := range s.checkIns.waiting {
+		if waiting.VehicleNumber == vehicleNumber {
+			s.checkIns.mutex.Unlock()
+			return CheckIn{}, fmt.Errorf("%w: %s", pkgerrors.ErrVehicleCheckedIn, vehicleNumber)
+		}
+	}
+
+	// Forget the check-ins finished long enough ago, nobody polls them anymore
+	for id, finished := range s.checkIns.checkIns {
+		if finished.Status != CheckInWaiting && time.Since(finished.FinishedAt) > finishedRetention {
+			delete(s.checkIns.checkIns, id)
+		}
+	}
+
+	s.checkIns.nextID++
+	checkIn := &CheckIn{
+		ID:            s.checkIns.nextID,
+		VehicleType:   vehicleType,
+		VehicleNumber: vehicleNumber,
+		Options:       opts,
+		Status:        CheckInWaiting,
+		Position:      len(s.checkIns.waiting) + 1,
+		CheckedInAt:   time.Now(),
+	}
+	s.checkIns.checkIns[checkIn.ID] = checkIn
+	s.checkIns.waiting = append(s.checkIns.waiting, checkIn)
+	s.checkIns.mutex.Unlock()
+
+	s.checkIns.start.Do(func() { go s.runCheckIns() })
+	s.wakeCheckIns()
+
+	return *checkIn, nil
+}
+
+// GetCheckIn returns the current state of a check-in
+func (s *ParkingService) GetCheckIn(id int) (CheckIn, error) {
+	s.checkIns.mutex.Lock()
+	defer s.checkIns.mutex.Unlock()
+
+	checkIn, exists := s.checkIns.checkIns[id]
+	if !exists {
+		return CheckIn{}, fmt.Errorf("%w: %d", pkgerrors.ErrCheckInNotFound, id)
+	}
+	return *checkIn, nil
+}
+
+// wakeCheckIns asks the assigner to look for spots for the waiting check-ins
+func (s *ParkingService) wakeCheckIns() {
+	select {
+	case s.checkIns.wake <- struct{}{}:
+	default:
+	}
+}
+
+// runCheckIns assigns spots to the waiting check-ins every time it is woken up
+func (s *ParkingService) runCheckIns() {
+	for range s.checkIns.wake {
+		s.assignCheckIns()
+	}
+}
+
+// assignCheckIns parks the waiting vehicles in check-in order. A vehicle that finds no spot keeps
+// waiting without holding up the vehicles behind it, which may be of a type with free spots.
+// The duplicate policy is not applied: check-ins of parked vehicles are refused, so a vehicle parked
+// while it waited fails rather than holding a spot or retrying in the assigner.
+func (s *ParkingService) assignCheckIns() {
+	s.checkIns.mutex.Lock()
+	waiting := append([]*CheckIn(nil), s.checkIns.waiting...)
+	s.checkIns.mutex.Unlock()
+
+	for _, checkIn := range waiting {
+		// Vehicles keep waiting while no spot is free or the lot is in emergency mode
+		spotID, err := s.parkWithOptions(checkIn.VehicleType, checkIn.VehicleNumber, checkIn.Options, DuplicateReject)
+		if errors.Is(err, pkgerrors.ErrNoAvailableSpot) || errors.Is(err, pkgerrors.ErrLotEmergency) {
+			continue
+		}
+
+		s.checkIns.mutex.Lock()
+		if err != nil {
+			checkIn.Status = CheckInFailed
+			checkIn.Error = err
+		} else {
+			checkIn.Status = CheckInAssigned
+			checkIn.SpotID = spotID
+			checkIn.AssignedAt = time.Now()
+		}
+		checkIn.FinishedAt = time.Now()
+		s.checkIns.removeWaiting(checkIn)
+		s.checkIns.mutex.Unlock()
+	}
+}
+
+// removeWaiting drops a check-in from the waiting ones and renumbers the others.
+// Must be called with the check-in lock held.
+func (q *checkInQueue) removeWaiting(checkIn *CheckIn) {
+	waiting := q.waiting[:0]
+	for _, other := range q.waiting {
+		if other != checkIn {
+			waiting = append(waiting, other)
+		}
+	}
+	q.waiting = waiting
+
+	checkIn.Position = 0
+	for i, other := range q.waiting {
+		other.Position = i + 1
+	}
+}
@@ -0,0 +1,115 @@
+package parking
+
+import (
+	"errors"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"testing"
+	"time"
+)
+
+// waitForCheckIn polls a check-in until it was assigned or failed
+func waitForCheckIn(t *testing.T, service *ParkingService, id int) CheckIn {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		checkIn, err := service.GetCheckIn(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if checkIn.Status != CheckInWaiting {
+			return checkIn
+		}
+	}
+	t.Fatalf("check-in %d is still waiting", id)
+	return CheckIn{}
+}
+
+func TestCheckInAssignsFreedSpot(t *testing.T) {
+	service := newTestService(t, 1)
+
+	spotID, err := service.Park(Automobile, "AB1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkIn, err := service.CheckIn(Automobile, "AB2", ParkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkIn.Status != CheckInWaiting || checkIn.Position != 1 {
+		t.Fatalf("got %+v, want the first waiting check-in", checkIn)
+	}
+
+	if err := service.Unpark(spotID, "AB1"); err != nil {
+		t.Fatal(err)
+	}
+	if checkIn = waitForCheckIn(t, service, checkIn.ID); checkIn.Status != CheckInAssigned || checkIn.SpotID != spotID {
+		t.Fatalf("got %+v, want assigned to %s", checkIn, spotID)
+	}
+}
+
+// A vehicle parked while its check-in waited fails the check-in, whatever the duplicate policy: the
+// assigner neither holds a spot for an operator nor retries
+func TestCheckInOfParkedVehicleFails(t *testing.T) {
+	for _, policy := range DuplicatePolicies {
+		t.Run(policy, func(t *testing.T) {
+			service := newTestService(t, 2)
+			service.SetDuplicatePolicy(policy)
+			service.ConfigureZone(0, 0, 0, "B")
+
+			if _, err := service.ParkWithOptions(Automobile, "AB1", ParkOptions{Zone: "B"}); err != nil {
+				t.Fatal(err)
+			}
+			checkIn, err := service.CheckIn(Automobile, "AB2", ParkOptions{Zone: "B"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The vehicle parks outside the zone while it waits, then the zone frees up
+			if _, err := service.Park(Automobile, "AB2"); err != nil {
+				t.Fatal(err)
+			}
+			if err := service.Unpark("0-0-0", "AB1"); err != nil {
+				t.Fatal(err)
+			}
+
+			checkIn = waitForCheckIn(t, service, checkIn.ID)
+			if checkIn.Status != CheckInFailed || !errors.Is(checkIn.Error, pkgerrors.ErrVehicleAlreadyParked) {
+				t.Fatalf("got %+v, want failed with %v", checkIn, pkgerrors.ErrVehicleAlreadyParked)
+			}
+			if pending := service.PendingDuplicates(); len(pending) != 0 {
+				t.Fatalf("assigner held spots for duplicates %+v", pending)
+			}
+			if occupied, _ := service.repo.IsSpotOccupied(0, 0, 0); occupied {
+				t.Fatal("assigner took the freed spot")
+			}
+		})
+	}
+}
+
+func TestFinishedCheckInsExpire(t *testing.T) {
+	service := newTestService(t, 2)
+
+	first, err := service.CheckIn(Automobile, "AB1", ParkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForCheckIn(t, service, first.ID)
+
+	// Check-ins finished within the retention stay, older ones go with the next check-in
+	if _, err := service.CheckIn(Automobile, "AB2", ParkOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.GetCheckIn(first.ID); err != nil {
+		t.Fatalf("recent check-in expired: %v", err)
+	}
+
+	service.checkIns.mutex.Lock()
+	service.checkIns.checkIns[first.ID].FinishedAt = time.Now().Add(-finishedRetention - time.Minute)
+	service.checkIns.mutex.Unlock()
+
+	if _, err := service.CheckIn(Automobile, "AB3", ParkOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.GetCheckIn(first.ID); !errors.Is(err, pkgerrors.ErrCheckInNotFound) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrCheckInNotFound)
+	}
+}
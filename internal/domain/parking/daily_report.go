@@ -0,0 +1,102 @@
+package parking
+
+import (
+	"log"
+	"parking-lot-system/internal/repository"
+	"parking-lot-system/internal/templates"
+	"time"
+)
+
+// DefaultStuckAfter is how long a vehicle may stay before its session is reported as stuck
+const DefaultStuckAfter = 24 * time.Hour
+
+// DailyReport closes a business day: the occupancy when it closed, the money taken during the day,
+// and the sessions that need a look
+type DailyReport struct {
+	From            time.Time
+	To              time.Time
+	Occupancy       repository.OccupancyStats // when the report was made
+	SessionsStarted int
+	Revenue         map[string]float64 // payment method -> amount taken, less the refunds given
+	RevenueTotal    float64
+	Unpaid          []repository.Session // sessions that ended during the day without a payment
+	Stuck           []repository.Session // active sessions that started longer than the stuck time ago
+}
+
+// SetStuckSessionAfter sets how long a vehicle may stay before its session is reported as stuck
+func (s *ParkingService) SetStuckSessionAfter(stuckAfter time.Duration) {
+	s.stuckAfter = stuckAfter
+}
+
+// GetDailyReport reports on the business day ending at a time, i.e. the day before it. Times are in
+// the lot's time zone.
+func (s *ParkingService) GetDailyReport(to time.Time) DailyReport {
+	to = to.In(s.location)
+	report := DailyReport{
+		From:      to.AddDate(0, 0, -1),
+		To:        to,
+		Occupancy: s.repo.GetOccupancyStats(),
+		Revenue:   make(map[string]float64),
+		Unpaid:    []repository.Session{},
+		Stuck:     []repository.Session{},
+	}
+
+	paid := make(map[int]bool)
+	methods := make(map[int]string) // payment ID -> method
+	for _, payment := range s.repo.GetPayments(0) {
+		paid[payment.SessionID] = true
+		methods[payment.ID] = payment.Method
+		if !payment.TakenAt.Before(report.From) && payment.TakenAt.Before(to) {
+			report.Revenue[payment.Method] += payment.Amount
+			report.RevenueTotal += payment.Amount
+		}
+	}
+	for _, refund := range s.repo.GetRefunds(0) {
+		if !refund.RefundedAt.Before(report.From) && refund.RefundedAt.Before(to) {
+			report.Revenue[methods[refund.PaymentID]] -= refund.Amount
+			report.RevenueTotal -= refund.Amount
+		}
+	}
+
+	for _, session := range s.repo.GetSessions(report.From, to) {
+		if !session.EntryTime.Before(report.From) {
+			report.SessionsStarted++
+		}
+		switch {
+		case session.IsActive() && to.Sub(session.EntryTime) > s.stuckAfter:
+			report.Stuck = append(report.Stuck, s.sessionInLot(session))
+		case !session.IsActive() && session.ExitTime.Before(to) && !paid[session.ID]:
+			report.Unpaid = append(report.Unpaid, s.sessionInLot(session))
+		}
+	}
+
+	return report
+}
+
+// sessionInLot returns a session with its times in the lot's time zone
+func (s *ParkingService) sessionInLot(session repository.Session) repository.Session {
+	session.EntryTime = session.EntryTime.In(s.location)
+	session.ExitTime = session.ExitTime.In(s.location)
+	return session
+}
+
+// StartDailyReports closes the business day every day at a time after midnight in the background,
+// sending the report to the recipient. The report is only logged when no notifier is set.
+func (s *ParkingService) StartDailyReports(closeAt time.Duration, recipient string) {
+	go func() {
+		for {
+			now := time.Now().In(s.location)
+			year, month, day := now.Date()
+			next := time.Date(year, month, day, 0, 0, 0, 0, s.location).Add(closeAt)
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 1)
+			}
+			time.Sleep(time.Until(next))
+
+			report := s.GetDailyReport(next)
+			log.Printf("Closed business day %s: %.2f taken, %d unpaid and %d stuck sessions",
+				report.To.Format("2006-01-02"), report.RevenueTotal, len(report.Unpaid), len(report.Stuck))
+			s.sendNotification(recipient, templates.DailyReport, report)
+		}
+	}()
+}
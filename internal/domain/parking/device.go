@@ -0,0 +1,230 @@
+package parking
+
+import (
+	"fmt"
+	"log"
+	"parking-lot-system/internal/alert"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Device types that can be registered
+const (
+	DeviceGate       = "gate"
+	DeviceKiosk      = "kiosk"
+	DevicePayStation = "pay_station"
+	DeviceSensor     = "sensor"
+	DeviceCamera     = "camera"
+)
+
+// DeviceTypes lists the types of devices that can be registered
+var DeviceTypes = []string{DeviceGate, DeviceKiosk, DevicePayStation, DeviceSensor, DeviceCamera}
+
+// Device statuses, a device is stale when it has not been seen for the stale window
+const (
+	DeviceOnline = "online"
+	DeviceStale  = "stale"
+)
+
+// DeviceStatuses lists the statuses devices can be filtered by
+var DeviceStatuses = []string{DeviceOnline, DeviceStale}
+
+// DefaultDeviceStaleAfter is how long a device may stay silent before it is stale unless configured otherwise
+const DefaultDeviceStaleAfter = 2 * time.Minute
+
+var deviceIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// deviceHealth keeps which registered devices are stale, so an alert is published once per silence
+type deviceHealth struct {
+	mutex      sync.Mutex
+	staleAfter time.Duration
+	stale      map[string]bool // device ID -> whether the device was stale when last checked
+}
+
+// SetDeviceStaleAfter sets how long a device may stay silent before it is stale
+func (s *ParkingService) SetDeviceStaleAfter(staleAfter time.Duration) {
+	s.deviceHealth.mutex.Lock()
+	defer s.deviceHealth.mutex.Unlock()
+
+	s.deviceHealth.staleAfter = staleAfter
+}
+
+// RegisterDevice adds a device to the registry on behalf of an admin
+func (s *ParkingService) RegisterDevice(admin string, device repository.Device) (repository.Device, error) {
+	if err := s.validateDevice(device); err != nil {
+		return repository.Device{}, err
+	}
+
+	registered, err := s.repo.AddDevice(device)
+	if err != nil {
+		return repository.Device{}, err
+	}
+
+	s.auditDevice(admin, "device.register", registered)
+	return registered, nil
+}
+
+// UpdateDevice changes the type, location and gate of a registered device on behalf of an admin
+func (s *ParkingService) UpdateDevice(admin string, device repository.Device) (repository.Device, error) {
+	if err := s.validateDevice(device); err != nil {
+		return repository.Device{}, err
+	}
+
+	updated, err := s.repo.UpdateDevice(device)
+	if err != nil {
+		return repository.Device{}, err
+	}
+
+	s.auditDevice(admin, "device.update", updated)
+	return updated, nil
+}
+
+// RemoveDevice removes a device from the registry on behalf of an admin. Events it originated keep its ID.
+func (s *ParkingService) RemoveDevice(admin, id string) (repository.Device, error) {
+	removed, err := s.repo.RemoveDevice(id)
+	if err != nil {
+		return repository.Device{}, err
+	}
+
+	s.deviceHealth.mutex.Lock()
+	delete(s.deviceHealth.stale, id)
+	s.deviceHealth.mutex.Unlock()
+
+	s.auditDevice(admin, "device.remove", removed)
+	return removed, nil
+}
+
+// GetDevices returns the registered devices with a status, or all of them when status is empty, ordered by ID
+func (s *ParkingService) GetDevices(status string) ([]repository.Device, error) {
+	if status != "" && !slices.Contains(DeviceStatuses, status) {
+		return nil, fmt.Errorf("%w: %q", pkgerrors.ErrInvalidDeviceStatus, status)
+	}
+
+	devices := []repository.Device{}
+	now := time.Now()
+	for _, device := range s.repo.GetDevices() {
+		if status == "" || s.DeviceStatus(device, now) == status {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+// DeviceStatus returns whether a device was online or stale at a moment. A device that was never
+// seen counts as seen when it was registered.
+func (s *ParkingService) DeviceStatus(device repository.Device, at time.Time) string {
+	s.deviceHealth.mutex.Lock()
+	staleAfter := s.deviceHealth.staleAfter
+	s.deviceHealth.mutex.Unlock()
+
+	if at.Sub(lastSeen(device)) > staleAfter {
+		return DeviceStale
+	}
+	return DeviceOnline
+}
+
+// Heartbeat records that a device is alive, e.g. from the periodic heartbeat of a gate controller,
+// publishing a device.recovered event when it was stale. Only the device itself should call it:
+// requests naming a device do not keep it alive.
+func (s *ParkingService) Heartbeat(id string) (repository.Device, error) {
+	device, err := s.repo.TouchDevice(id, time.Now())
+	if err != nil {
+		return repository.Device{}, err
+	}
+
+	s.deviceHealth.mutex.Lock()
+	wasStale := s.deviceHealth.stale[id]
+	delete(s.deviceHealth.stale, id)
+	s.deviceHealth.mutex.Unlock()
+
+	if wasStale {
+		s.publish(Event{Type: EventDeviceRecovered, Device: device.ID, Gate: device.Gate})
+	}
+	return device, nil
+}
+
+// CheckDevices publishes a device.stale event for every device that went silent since the last check
+// and returns the stale devices
+func (s *ParkingService) CheckDevices() []repository.Device {
+	now := time.Now()
+	stale := []repository.Device{}
+	for _, device := range s.repo.GetDevices() {
+		if s.DeviceStatus(device, now) != DeviceStale {
+			continue
+		}
+		stale = append(stale, device)
+
+		s.deviceHealth.mutex.Lock()
+		alerted := s.deviceHealth.stale[device.ID]
+		s.deviceHealth.stale[device.ID] = true
+		s.deviceHealth.mutex.Unlock()
+
+		if !alerted {
+			log.Printf("Device %s is stale, last seen %s", device.ID, lastSeen(device).Format(time.RFC3339))
+			s.publish(Event{Type: EventDeviceStale, Device: device.ID, Gate: device.Gate})
+			name := device.Type + " " + device.ID
+			if device.Location != "" {
+				name += " at " + device.Location
+			}
+			s.raiseAlert(alert.TypeDeviceOffline, alert.SeverityWarning, "device_offline:"+device.ID,
+				fmt.Sprintf("%s is offline, last seen %s", name, lastSeen(device).Format(time.RFC3339)))
+		}
+	}
+	return stale
+}
+
+// StartDeviceMonitor runs CheckDevices periodically in the background, often enough to notice
+// a silent device within a quarter of the stale window
+func (s *ParkingService) StartDeviceMonitor() {
+	s.deviceHealth.mutex.Lock()
+	interval := max(s.deviceHealth.staleAfter/4, time.Second)
+	s.deviceHealth.mutex.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			s.CheckDevices()
+		}
+	}()
+}
+
+// resolveDevice returns the registered device originating a request. Requests without a device
+// resolve to the zero device.
+func (s *ParkingService) resolveDevice(id string) (repository.Device, error) {
+	if id == "" {
+		return repository.Device{}, nil
+	}
+	return s.repo.GetDevice(id)
+}
+
+// lastSeen returns when a device was last seen, when it was registered if it was never seen
+func lastSeen(device repository.Device) time.Time {
+	if device.LastSeen.IsZero() {
+		return device.RegisteredAt
+	}
+	return device.LastSeen
+}
+
+func (s *ParkingService) validateDevice(device repository.Device) error {
+	if !deviceIDPattern.MatchString(device.ID) {
+		return fmt.Errorf("%w: %q", pkgerrors.ErrInvalidDeviceID, device.ID)
+	}
+	if !slices.Contains(DeviceTypes, device.Type) {
+		return fmt.Errorf("%w: %q", pkgerrors.ErrInvalidDeviceType, device.Type)
+	}
+	return s.validateGate(device.Gate)
+}
+
+func (s *ParkingService) auditDevice(admin, action string, device repository.Device) {
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:  admin,
+		Action: action,
+		Reason: device.ID,
+	})
+}
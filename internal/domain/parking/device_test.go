@@ -0,0 +1,47 @@
+package parking
+
+import (
+	"parking-lot-system/internal/repository"
+	"testing"
+	"time"
+)
+
+func TestOnlyHeartbeatsKeepDevicesAlive(t *testing.T) {
+	service := newTestService(t, 2)
+	service.SetDeviceStaleAfter(10 * time.Millisecond)
+	if _, err := service.RegisterDevice("admin", repository.Device{ID: "kiosk-1", Type: DeviceKiosk}); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if stale := service.CheckDevices(); len(stale) != 1 {
+		t.Fatalf("got %d stale devices, want kiosk-1", len(stale))
+	}
+
+	// Requests naming the device do not count as a sign of life
+	spotID, err := service.ParkWithOptions(Automobile, "AB1", ParkOptions{Device: "kiosk-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.UnparkWithOptions(spotID, "AB1", UnparkOptions{Device: "kiosk-1"}); err != nil {
+		t.Fatal(err)
+	}
+	devices, err := service.GetDevices(DeviceStale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(devices) != 1 || !devices[0].LastSeen.IsZero() {
+		t.Fatalf("got stale devices %+v, want kiosk-1 never seen", devices)
+	}
+
+	device, err := service.Heartbeat("kiosk-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := service.DeviceStatus(device, time.Now()); status != DeviceOnline {
+		t.Fatalf("got kiosk-1 %s after its heartbeat, want %s", status, DeviceOnline)
+	}
+	if recovered, _ := service.repo.GetEvents(repository.EventFilter{Type: EventDeviceRecovered}); len(recovered) != 1 {
+		t.Fatalf("got %d device.recovered events, want 1", len(recovered))
+	}
+}
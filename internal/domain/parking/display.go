@@ -0,0 +1,56 @@
+package parking
+
+// AisleHint points drivers of a vehicle type towards the aisle with the most free spots
+type AisleHint struct {
+	Free      int // free spots of the vehicle type on the floor
+	Aisle     int // row with the most free spots, -1 when the floor is full
+	AisleFree int // free spots in that aisle
+}
+
+// DisplayBoard holds what a floor entrance sign shows
+type DisplayBoard struct {
+	Floor        int
+	Free         int
+	VehicleTypes map[string]AisleHint // vehicleType -> hint
+	Incident     Incident             // shown instead of the hints while active
+}
+
+// GetDisplayBoard returns the free counts per vehicle type of a floor and the emptiest aisle for each
+func (s *ParkingService) GetDisplayBoard(floor int) (DisplayBoard, error) {
+	spots, err := s.repo.GetFloorSpots(floor)
+	if err != nil {
+		return DisplayBoard{}, err
+	}
+
+	board := DisplayBoard{
+		Floor:        floor,
+		VehicleTypes: make(map[string]AisleHint),
+		Incident:     s.GetIncident(),
+	}
+
+	for row, rowSpots := range spots {
+		freeByType := make(map[string]int)
+		for _, spot := range rowSpots {
+			if !spot.Allocatable() {
+				continue
+			}
+			if _, exists := board.VehicleTypes[spot.VehicleType]; !exists {
+				board.VehicleTypes[spot.VehicleType] = AisleHint{Aisle: -1}
+			}
+			freeByType[spot.VehicleType] += spot.Free()
+		}
+
+		for vehicleType, free := range freeByType {
+			hint := board.VehicleTypes[vehicleType]
+			hint.Free += free
+			if free > hint.AisleFree {
+				hint.Aisle = row
+				hint.AisleFree = free
+			}
+			board.VehicleTypes[vehicleType] = hint
+			board.Free += free
+		}
+	}
+
+	return board, nil
+}
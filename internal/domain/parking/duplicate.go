@@ -0,0 +1,269 @@
+package parking
+
+import (
+	"errors"
+	"fmt"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Duplicate park policies, applied when a vehicle number that is already parked is parked again,
+// e.g. when two gates read the same plate at once
+const (
+	DuplicateReject  = "reject"  // the attempt fails
+	DuplicateRetry   = "retry"   // the attempt is retried in the background for a while, in case the other record is corrected
+	DuplicateConfirm = "confirm" // a spot is held until an operator confirms the vehicle under its corrected number
+)
+
+// DuplicatePolicies lists the supported duplicate park policies
+var DuplicatePolicies = []string{
+	DuplicateReject,
+	DuplicateRetry,
+	DuplicateConfirm,
+}
+
+// Operator decisions on a duplicate park attempt
+const (
+	DuplicateActionConfirm = "confirm"
+	DuplicateActionReject  = "reject"
+)
+
+// how long and how often a duplicate park attempt is retried under the retry policy
+const (
+	duplicateRetryTimeout  = 10 * time.Second
+	duplicateRetryInterval = 500 * time.Millisecond
+)
+
+// how long finished duplicate park retries and check-ins can still be polled
+const finishedRetention = time.Hour
+
+// Duplicate park retry statuses
+const (
+	RetryPending = "retrying" // the attempt is retried until the other record is corrected or the retries time out
+	RetryParked  = "parked"   // a retry parked the vehicle
+	RetryFailed  = "failed"   // the retries timed out or failed for another reason
+)
+
+// ParkRetry is a duplicate park attempt retried in the background under the retry policy
+type ParkRetry struct {
+	ID            int
+	VehicleType   string
+	VehicleNumber string
+	Options       ParkOptions
+	Status        string
+	SpotID        string // set once parked
+	Error         error  // set when failed
+	StartedAt     time.Time
+	FinishedAt    time.Time
+}
+
+// parkRetries keeps the duplicate park attempts retried in the background
+type parkRetries struct {
+	mutex   sync.Mutex
+	nextID  int
+	retries map[int]*ParkRetry
+	// how long and how often attempts are retried
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// DuplicateRetryError is returned for a duplicate park attempt that is retried in the background
+type DuplicateRetryError struct {
+	Retry ParkRetry
+	err   error
+}
+
+func (e *DuplicateRetryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *DuplicateRetryError) Unwrap() error {
+	return e.err
+}
+
+// DuplicateParkError is returned for a duplicate park attempt held for operator confirmation
+type DuplicateParkError struct {
+	Hold Hold
+	err  error
+}
+
+func (e *DuplicateParkError) Error() string {
+	return e.err.Error()
+}
+
+func (e *DuplicateParkError) Unwrap() error {
+	return e.err
+}
+
+// SetDuplicatePolicy sets how park attempts for a vehicle number that is already parked are handled
+func (s *ParkingService) SetDuplicatePolicy(policy string) error {
+	for _, known := range DuplicatePolicies {
+		if known == policy {
+			s.duplicatePolicy = policy
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", pkgerrors.ErrInvalidDuplicatePolicy, policy)
+}
+
+// resolveDuplicate applies a duplicate policy to a park attempt that failed with conflict
+func (s *ParkingService) resolveDuplicate(policy, vehicleType, vehicleNumber string, opts ParkOptions, conflict error) (string, error) {
+	switch policy {
+	case DuplicateRetry:
+		retry := s.startRetry(vehicleType, vehicleNumber, opts, conflict)
+		return "", &DuplicateRetryError{Retry: retry, err: fmt.Errorf("%w: %w", pkgerrors.ErrDuplicateRetrying, conflict)}
+
+	case DuplicateConfirm:
+		spotID, err := s.reserveSpot(vehicleType, "", opts)
+		if err != nil {
+			return "", err
+		}
+		hold := s.addHold(Hold{
+			SpotID:        spotID,
+			VehicleType:   vehicleType,
+			VehicleNumber: vehicleNumber,
+			Gate:          opts.Gate,
+			Device:        opts.Device,
+			Duplicate:     true,
+		})
+		return "", &DuplicateParkError{Hold: hold, err: fmt.Errorf("%w: %w", pkgerrors.ErrDuplicatePending, conflict)}
+
+	default:
+		return "", conflict
+	}
+}
+
+// startRetry retries a duplicate park attempt in the background, so the request and the check-in
+// assigner do not wait for the other record to be corrected
+func (s *ParkingService) startRetry(vehicleType, vehicleNumber string, opts ParkOptions, conflict error) ParkRetry {
+	s.retries.mutex.Lock()
+	defer s.retries.mutex.Unlock()
+
+	// Forget the retries finished long enough ago, nobody polls them anymore
+	for id, retry := range s.retries.retries {
+		if retry.Status != RetryPending && time.Since(retry.FinishedAt) > finishedRetention {
+			delete(s.retries.retries, id)
+		}
+	}
+
+	s.retries.nextID++
+	retry := &ParkRetry{
+		ID:            s.retries.nextID,
+		VehicleType:   vehicleType,
+		VehicleNumber: vehicleNumber,
+		Options:       opts,
+		Status:        RetryPending,
+		StartedAt:     time.Now(),
+	}
+	s.retries.retries[retry.ID] = retry
+
+	go s.retryDuplicate(retry, conflict)
+
+	return *retry
+}
+
+// retryDuplicate parks the vehicle of a duplicate park attempt once the other record is corrected,
+// e.g. unparked by an attendant, or gives up when the retries time out
+func (s *ParkingService) retryDuplicate(retry *ParkRetry, conflict error) {
+	spotID, err := "", conflict
+	for deadline := time.Now().Add(s.retries.timeout); time.Now().Before(deadline); {
+		time.Sleep(s.retries.interval)
+
+		spotID, err = s.park(retry.VehicleType, retry.VehicleNumber, retry.Options)
+		if !errors.Is(err, pkgerrors.ErrVehicleAlreadyParked) {
+			break
+		}
+	}
+
+	if err == nil {
+		s.rememberContact(retry.VehicleNumber, retry.Options.Contact)
+		s.publishParked(spotID, retry.VehicleNumber, retry.VehicleType, retry.Options.Gate, retry.Options.Device)
+	}
+
+	s.retries.mutex.Lock()
+	defer s.retries.mutex.Unlock()
+
+	if err != nil {
+		retry.Status = RetryFailed
+		retry.Error = err
+	} else {
+		retry.Status = RetryParked
+		retry.SpotID = spotID
+	}
+	retry.FinishedAt = time.Now()
+}
+
+// GetParkRetry returns the current state of a duplicate park attempt retried in the background
+func (s *ParkingService) GetParkRetry(id int) (ParkRetry, error) {
+	s.retries.mutex.Lock()
+	defer s.retries.mutex.Unlock()
+
+	retry, exists := s.retries.retries[id]
+	if !exists {
+		return ParkRetry{}, fmt.Errorf("%w: %d", pkgerrors.ErrRetryNotFound, id)
+	}
+	return *retry, nil
+}
+
+// ResolveDuplicate applies an operator's decision on a duplicate park attempt: confirm parks the
+// vehicle under its corrected number, or its original one if the other record was corrected meanwhile,
+// reject releases the held spot.
+func (s *ParkingService) ResolveDuplicate(attendant string, holdID int, action, vehicleNumber string) (Hold, error) {
+	hold, err := s.getHold(holdID)
+	if err != nil {
+		return Hold{}, err
+	}
+	if !hold.Duplicate {
+		return Hold{}, fmt.Errorf("%w: %d is not a duplicate park attempt", pkgerrors.ErrHoldNotFound, holdID)
+	}
+
+	var resolved Hold
+	switch action {
+	case DuplicateActionConfirm:
+		resolved, err = s.confirmHold(hold, vehicleNumber)
+	case DuplicateActionReject:
+		resolved = *hold
+		if !s.takeHold(holdID) {
+			err = fmt.Errorf("%w: %d", pkgerrors.ErrHoldNotFound, holdID)
+		} else {
+			err = s.repo.ReleaseHold(hold.SpotID)
+		}
+	default:
+		err = fmt.Errorf("%w: %q", pkgerrors.ErrInvalidDuplicateAction, action)
+	}
+	if err != nil {
+		return Hold{}, err
+	}
+
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:         attendant,
+		Action:        "duplicate." + action,
+		SpotID:        resolved.SpotID,
+		VehicleNumber: resolved.VehicleNumber,
+		Reason:        "read as " + hold.VehicleNumber,
+	})
+
+	return resolved, nil
+}
+
+// PendingDuplicates returns the duplicate park attempts awaiting operator confirmation, oldest first
+func (s *ParkingService) PendingDuplicates() []Hold {
+	s.holds.mutex.Lock()
+	defer s.holds.mutex.Unlock()
+
+	pending := []Hold{}
+	for _, hold := range s.holds.holds {
+		if hold.Duplicate {
+			pending = append(pending, *hold)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].ID < pending[j].ID
+	})
+
+	return pending
+}
@@ -0,0 +1,159 @@
+package parking
+
+import (
+	"errors"
+	"parking-lot-system/internal/domain/spotid"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"testing"
+	"time"
+)
+
+// newTestService returns a service for a lot of one floor with a row of automobile spots,
+// retrying duplicate park attempts quickly
+func newTestService(t *testing.T, spots int) *ParkingService {
+	t.Helper()
+
+	service := NewParkingService(repository.NewParkingRepository(spotid.Default))
+	if err := service.InitializeParkingLot(1, 1, spots, 1, false); err != nil {
+		t.Fatal(err)
+	}
+	for col := 0; col < spots; col++ {
+		if err := service.ConfigureSpot(0, 0, col, "A-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	service.retries.timeout = 500 * time.Millisecond
+	service.retries.interval = 10 * time.Millisecond
+	return service
+}
+
+// waitForRetry polls a duplicate park retry until it finished
+func waitForRetry(t *testing.T, service *ParkingService, id int) ParkRetry {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		retry, err := service.GetParkRetry(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if retry.Status != RetryPending {
+			return retry
+		}
+	}
+	t.Fatalf("retry %d did not finish", id)
+	return ParkRetry{}
+}
+
+func TestDuplicateReject(t *testing.T) {
+	service := newTestService(t, 2)
+
+	if _, err := service.Park(Automobile, "AB1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Park(Automobile, "AB1"); !errors.Is(err, pkgerrors.ErrVehicleAlreadyParked) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrVehicleAlreadyParked)
+	}
+}
+
+func TestDuplicateRetryParksOnceCorrected(t *testing.T) {
+	service := newTestService(t, 2)
+	service.SetDuplicatePolicy(DuplicateRetry)
+
+	first, err := service.Park(Automobile, "AB1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The attempt answers right away and is retried in the background
+	start := time.Now()
+	_, err = service.Park(Automobile, "AB1")
+	var retrying *DuplicateRetryError
+	if !errors.As(err, &retrying) || !errors.Is(err, pkgerrors.ErrDuplicateRetrying) {
+		t.Fatalf("got %v, want a duplicate retry", err)
+	}
+	if elapsed := time.Since(start); elapsed >= service.retries.interval {
+		t.Fatalf("park waited %v for the retry", elapsed)
+	}
+	if retrying.Retry.Status != RetryPending {
+		t.Fatalf("got status %q, want %q", retrying.Retry.Status, RetryPending)
+	}
+
+	// The attendant corrects the other record
+	if err := service.Unpark(first, "AB1"); err != nil {
+		t.Fatal(err)
+	}
+
+	retry := waitForRetry(t, service, retrying.Retry.ID)
+	if retry.Status != RetryParked || retry.SpotID == "" {
+		t.Fatalf("got %+v, want the vehicle parked", retry)
+	}
+	if parked, spotID, _ := service.repo.IsVehicleParked("AB1"); !parked || spotID != retry.SpotID {
+		t.Fatalf("vehicle parked %v at %q, want at %s", parked, spotID, retry.SpotID)
+	}
+}
+
+func TestDuplicateRetryFailsAfterTimeout(t *testing.T) {
+	service := newTestService(t, 2)
+	service.SetDuplicatePolicy(DuplicateRetry)
+
+	if _, err := service.Park(Automobile, "AB1"); err != nil {
+		t.Fatal(err)
+	}
+	_, err := service.Park(Automobile, "AB1")
+	var retrying *DuplicateRetryError
+	if !errors.As(err, &retrying) {
+		t.Fatalf("got %v, want a duplicate retry", err)
+	}
+
+	retry := waitForRetry(t, service, retrying.Retry.ID)
+	if retry.Status != RetryFailed || !errors.Is(retry.Error, pkgerrors.ErrVehicleAlreadyParked) {
+		t.Fatalf("got %+v, want the retry failed with %v", retry, pkgerrors.ErrVehicleAlreadyParked)
+	}
+
+	if _, err := service.GetParkRetry(retry.ID + 1); !errors.Is(err, pkgerrors.ErrRetryNotFound) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrRetryNotFound)
+	}
+}
+
+func TestDuplicateConfirm(t *testing.T) {
+	service := newTestService(t, 3)
+	service.SetDuplicatePolicy(DuplicateConfirm)
+
+	if _, err := service.Park(Automobile, "AB1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A spot is held until an operator confirms the vehicle under its corrected number
+	_, err := service.Park(Automobile, "AB1")
+	var duplicate *DuplicateParkError
+	if !errors.As(err, &duplicate) || !errors.Is(err, pkgerrors.ErrDuplicatePending) {
+		t.Fatalf("got %v, want a pending duplicate", err)
+	}
+	if pending := service.PendingDuplicates(); len(pending) != 1 || pending[0].ID != duplicate.Hold.ID {
+		t.Fatalf("got pending duplicates %+v, want hold %d", pending, duplicate.Hold.ID)
+	}
+
+	hold, err := service.ResolveDuplicate("att", duplicate.Hold.ID, DuplicateActionConfirm, "AB2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parked, spotID, _ := service.repo.IsVehicleParked("AB2"); !parked || spotID != hold.SpotID {
+		t.Fatalf("corrected vehicle parked %v at %q, want at %s", parked, spotID, hold.SpotID)
+	}
+
+	// Rejecting an attempt releases its spot
+	_, err = service.Park(Automobile, "AB1")
+	if !errors.As(err, &duplicate) {
+		t.Fatalf("got %v, want a pending duplicate", err)
+	}
+	if _, err := service.ResolveDuplicate("att", duplicate.Hold.ID, DuplicateActionReject, ""); err != nil {
+		t.Fatal(err)
+	}
+	if occupied, _ := service.repo.IsSpotOccupied(0, 0, 2); occupied {
+		t.Fatal("rejected attempt still holds its spot")
+	}
+	if pending := service.PendingDuplicates(); len(pending) != 0 {
+		t.Fatalf("got pending duplicates %+v, want none", pending)
+	}
+}
@@ -0,0 +1,160 @@
+package parking
+
+import (
+	"fmt"
+	"parking-lot-system/internal/domain/plate"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sync"
+	"time"
+)
+
+// Event types published by the parking service
+const (
+	EventVehicleParked    = "vehicle.parked"
+	EventVehicleUnparked  = "vehicle.unparked"
+	EventLotFull          = "lot.full"
+	EventSpotReconfigured = "spot.reconfigured"
+	EventDeviceStale      = "device.stale"
+	EventDeviceRecovered  = "device.recovered"
+	EventIncidentStarted  = "incident.started"
+	EventIncidentEnded    = "incident.ended"
+	EventLotInitialized   = "lot.initialized"
+)
+
+// EventTypes lists all event types published by the parking service
+var EventTypes = []string{
+	EventVehicleParked,
+	EventVehicleUnparked,
+	EventLotFull,
+	EventSpotReconfigured,
+	EventDeviceStale,
+	EventDeviceRecovered,
+	EventIncidentStarted,
+	EventIncidentEnded,
+	EventLotInitialized,
+}
+
+// Event represents a state change in the parking lot
+type Event struct {
+	ID            int // position in the event log, starting at 1
+	Type          string
+	Time          time.Time
+	SpotID        string
+	VehicleNumber string
+	VehicleType   string
+	Gate          int    // gate the vehicle passed through, 0 when unknown
+	Device        string // registered device the change was requested from, empty when unknown
+	Reason        string // why an incident was declared, for incident events
+}
+
+// EventListener receives the events published by the parking service.
+// HandleEvent is called synchronously and must not block.
+type EventListener interface {
+	HandleEvent(event Event)
+}
+
+// eventBus fans out events to the registered listeners
+type eventBus struct {
+	mutex     sync.RWMutex
+	listeners []EventListener
+}
+
+// IsValidEventType checks if the event type is published by the parking service
+func IsValidEventType(eventType string) bool {
+	for _, known := range EventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// AddEventListener registers a listener for all events published by the service
+func (s *ParkingService) AddEventListener(listener EventListener) {
+	s.events.mutex.Lock()
+	defer s.events.mutex.Unlock()
+
+	s.events.listeners = append(s.events.listeners, listener)
+}
+
+// publish records an event in the event log and sends it to all registered listeners
+func (s *ParkingService) publish(event Event) {
+	event.Time = time.Now().In(s.location)
+	event.ID = s.repo.AppendEvent(repository.EventRecord{
+		Type:          event.Type,
+		Time:          event.Time,
+		SpotID:        event.SpotID,
+		VehicleNumber: event.VehicleNumber,
+		VehicleType:   event.VehicleType,
+		Gate:          event.Gate,
+		Device:        event.Device,
+		Reason:        event.Reason,
+	}).ID
+	s.trimEventLog()
+
+	s.events.mutex.RLock()
+	defer s.events.mutex.RUnlock()
+
+	for _, listener := range s.events.listeners {
+		listener.HandleEvent(event)
+	}
+}
+
+// Limits of the number of events returned per page of the event log
+const (
+	DefaultEventPageSize = 100
+	MaxEventPageSize     = 1000
+)
+
+// EventQuery selects events from the event log, empty fields match all events
+type EventQuery struct {
+	Type          string
+	VehicleNumber string
+	SpotID        string
+	Device        string
+	From          time.Time // inclusive, zero for no lower bound
+	To            time.Time // exclusive, zero for no upper bound
+	After         int       // ID of the last event of the previous page, 0 for the first page
+	Limit         int       // page size, DefaultEventPageSize when 0
+}
+
+// EventPage is a page of the events selected by a query
+type EventPage struct {
+	Events  []Event
+	HasMore bool // more events match after the last one of the page
+}
+
+// QueryEvents returns a page of the events in the event log selected by a query, oldest first
+func (s *ParkingService) QueryEvents(query EventQuery) (EventPage, error) {
+	if query.Type != "" && !IsValidEventType(query.Type) {
+		return EventPage{}, fmt.Errorf("%w: %s", pkgerrors.ErrInvalidEventType, query.Type)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.From.Before(query.To) {
+		return EventPage{}, pkgerrors.ErrInvalidTimeWindow
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultEventPageSize
+	}
+	if query.Limit < 0 || query.Limit > MaxEventPageSize {
+		return EventPage{}, fmt.Errorf("%w: limit must be between 1 and %d", pkgerrors.ErrInvalidPageSize, MaxEventPageSize)
+	}
+
+	records, more := s.repo.GetEvents(repository.EventFilter{
+		Type:          query.Type,
+		VehicleNumber: plate.Normalize(query.VehicleNumber),
+		SpotID:        query.SpotID,
+		Device:        query.Device,
+		From:          query.From,
+		To:            query.To,
+		After:         query.After,
+		Limit:         query.Limit,
+	})
+
+	page := EventPage{Events: make([]Event, 0, len(records)), HasMore: more}
+	for _, record := range records {
+		page.Events = append(page.Events, eventFromRecord(record))
+	}
+
+	return page, nil
+}
@@ -0,0 +1,79 @@
+package parking
+
+import (
+	"parking-lot-system/internal/repository"
+	"sync"
+	"time"
+)
+
+// DefaultEventLogLimit is how many events the event log keeps before dropping the oldest ones
+const DefaultEventLogLimit = 1_000_000
+
+// eventLog bounds the event log. The vehicles parked as of the last dropped event are kept, so the
+// lot state at any moment since can still be rebuilt from the events kept.
+type eventLog struct {
+	mutex       sync.Mutex
+	limit       int // 0 keeps every event
+	droppedAt   time.Time
+	droppedLast int              // ID of the last dropped event, 0 while none were dropped
+	parked      map[string]Event // vehicle number -> vehicle.parked event, as of the last dropped event
+}
+
+// SetEventLogLimit sets how many events the event log keeps, 0 to keep every event
+func (s *ParkingService) SetEventLogLimit(limit int) {
+	s.eventLog.mutex.Lock()
+	s.eventLog.limit = limit
+	s.eventLog.mutex.Unlock()
+
+	s.trimEventLog()
+}
+
+// trimEventLog drops the oldest events beyond the limit of the event log, remembering the vehicles
+// that were parked after them
+func (s *ParkingService) trimEventLog() {
+	s.eventLog.mutex.Lock()
+	defer s.eventLog.mutex.Unlock()
+
+	if s.eventLog.limit == 0 {
+		return
+	}
+
+	dropped := s.repo.DropEvents(s.eventLog.limit)
+	if len(dropped) == 0 {
+		return
+	}
+
+	for _, record := range dropped {
+		ReplayEvent(s.eventLog.parked, eventFromRecord(record))
+	}
+	last := dropped[len(dropped)-1]
+	s.eventLog.droppedAt = last.Time
+	s.eventLog.droppedLast = last.ID
+}
+
+// ReplayEvent applies an event to the vehicles parked before it, vehicle number -> vehicle.parked event
+func ReplayEvent(parked map[string]Event, event Event) {
+	switch event.Type {
+	case EventVehicleParked:
+		parked[event.VehicleNumber] = event
+	case EventVehicleUnparked:
+		delete(parked, event.VehicleNumber)
+	case EventLotInitialized:
+		clear(parked)
+	}
+}
+
+// eventFromRecord returns the event stored in an event log record
+func eventFromRecord(record repository.EventRecord) Event {
+	return Event{
+		ID:            record.ID,
+		Type:          record.Type,
+		Time:          record.Time,
+		SpotID:        record.SpotID,
+		VehicleNumber: record.VehicleNumber,
+		VehicleType:   record.VehicleType,
+		Gate:          record.Gate,
+		Device:        record.Device,
+		Reason:        record.Reason,
+	}
+}
@@ -0,0 +1,87 @@
+package parking
+
+import (
+	"errors"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"testing"
+	"time"
+)
+
+// lotStateVehicles returns the vehicles parked at a moment, vehicle number -> spot
+func lotStateVehicles(t *testing.T, service *ParkingService, at time.Time) map[string]string {
+	t.Helper()
+
+	state, err := service.GetLotState(at, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vehicles := make(map[string]string)
+	for _, vehicle := range state.Vehicles {
+		vehicles[vehicle.Parked.VehicleNumber] = vehicle.Parked.SpotID
+	}
+	return vehicles
+}
+
+func TestLotStateFromEventLog(t *testing.T) {
+	service := newTestService(t, 2)
+
+	first, err := service.Park(Automobile, "AB1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.Park(Automobile, "AB2"); err != nil {
+		t.Fatal(err)
+	}
+	bothParked := time.Now()
+	if err := service.Unpark(first, "AB1"); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := service.GetLotState(bothParked, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Vehicles) != 2 || state.Vehicles[0].Parked.VehicleNumber != "AB1" || state.Vehicles[0].ExitTime.IsZero() {
+		t.Fatalf("got %+v, want AB1, which left since, and AB2", state.Vehicles)
+	}
+	if !state.Vehicles[1].ExitTime.IsZero() {
+		t.Fatalf("AB2 is still parked but has exit time %v", state.Vehicles[1].ExitTime)
+	}
+
+	// Laying out the lot again unparks every vehicle
+	if err := service.InitializeParkingLot(1, 1, 2, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if vehicles := lotStateVehicles(t, service, time.Now()); len(vehicles) != 0 {
+		t.Fatalf("got %v parked after the lot was initialized, want none", vehicles)
+	}
+}
+
+func TestEventLogLimit(t *testing.T) {
+	start := time.Now()
+	service := newTestService(t, 3)
+	service.SetEventLogLimit(2)
+
+	for _, vehicleNumber := range []string{"AB1", "AB2", "AB3"} {
+		if _, err := service.Park(Automobile, vehicleNumber); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the last events are kept, with their IDs
+	page, err := service.QueryEvents(EventQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Events) != 2 || page.Events[0].ID == 1 {
+		t.Fatalf("got events %+v, want the last two", page.Events)
+	}
+
+	// The vehicles parked by the dropped events are still found
+	if vehicles := lotStateVehicles(t, service, time.Now()); len(vehicles) != 3 {
+		t.Fatalf("got %v parked, want AB1, AB2 and AB3", vehicles)
+	}
+	if _, err := service.GetLotState(start, -1); !errors.Is(err, pkgerrors.ErrEventsExpired) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrEventsExpired)
+	}
+}
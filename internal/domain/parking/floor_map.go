@@ -0,0 +1,62 @@
+package parking
+
+// MapCell is a grid cell of a floor map
+type MapCell struct {
+	SpotID      string // empty when no spot exists
+	Exists      bool
+	IsActive    bool
+	VehicleType string
+	Parked      int // vehicles parked at the spot
+	Capacity    int
+}
+
+// IsFull returns whether no more vehicles fit at the spot
+func (c MapCell) IsFull() bool {
+	return c.Parked >= c.Capacity
+}
+
+// FloorMap holds the current state of the spots of a floor
+type FloorMap struct {
+	Floor int
+	Gates []int       // gates on the floor, the lot's gates are on the ground floor
+	Cells [][]MapCell // indexed by row and column
+}
+
+// GetFloorMap returns the current spot grid of a floor
+func (s *ParkingService) GetFloorMap(floor int) (FloorMap, error) {
+	spots, err := s.repo.GetFloorSpots(floor)
+	if err != nil {
+		return FloorMap{}, err
+	}
+
+	floorMap := FloorMap{
+		Floor: floor,
+		Gates: []int{},
+		Cells: make([][]MapCell, len(spots)),
+	}
+	if floor == 0 {
+		_, gates := s.repo.GetDimensions()
+		for gate := 1; gate <= gates; gate++ {
+			floorMap.Gates = append(floorMap.Gates, gate)
+		}
+	}
+
+	for row := range spots {
+		floorMap.Cells[row] = make([]MapCell, len(spots[row]))
+		for col, spot := range spots[row] {
+			if !spot.Exists {
+				continue
+			}
+			floorMap.Cells[row][col] = MapCell{
+				SpotID:      s.repo.FormatSpotID(floor, row, col),
+				Exists:      true,
+				IsActive:    spot.InService(),
+				VehicleType: spot.VehicleType,
+				Parked:      len(spot.Vehicles),
+				Capacity:    spot.Capacity,
+			}
+		}
+	}
+
+	return floorMap, nil
+}
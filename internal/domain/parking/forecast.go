@@ -0,0 +1,73 @@
+package parking
+
+import (
+	"math"
+	"time"
+)
+
+// number of previous days sampled at the same time of day for a forecast
+const forecastSampleDays = 7
+
+// TypeForecast holds the predicted availability of a vehicle type
+type TypeForecast struct {
+	Capacity           int
+	PredictedOccupied  float64
+	PredictedAvailable float64
+}
+
+// Forecast holds the predicted availability per vehicle type at a point in time
+type Forecast struct {
+	At           time.Time
+	Samples      int                     // number of previous days with data the prediction is based on
+	VehicleTypes map[string]TypeForecast // vehicleType -> forecast
+}
+
+// GetForecast predicts the availability per vehicle type at the given time, using a
+// daily seasonal profile: the occupancy at the same time of day over the previous days is averaged.
+// Days without any session, e.g. before the lot opened, are left out of the average.
+func (s *ParkingService) GetForecast(at time.Time) Forecast {
+	now := time.Now()
+
+	// Step back whole days until the first sample lies in the past
+	sample := at
+	for sample.After(now) {
+		sample = sample.Add(-24 * time.Hour)
+	}
+
+	occupied := make(map[string]int)
+	samples := 0
+	for i := 0; i < forecastSampleDays; i++ {
+		local := sample.In(s.location)
+		dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.location)
+		sessions := s.repo.GetSessions(dayStart, dayStart.AddDate(0, 0, 1))
+		if len(sessions) > 0 {
+			for _, session := range sessions {
+				if !session.EntryTime.After(sample) && (session.IsActive() || session.ExitTime.After(sample)) {
+					occupied[session.VehicleType]++
+				}
+			}
+			samples++
+		}
+		sample = sample.Add(-24 * time.Hour)
+	}
+
+	forecast := Forecast{
+		At:           at,
+		Samples:      samples,
+		VehicleTypes: make(map[string]TypeForecast),
+	}
+
+	for vehicleType, count := range s.repo.GetOccupancyStats().VehicleTypes {
+		var predictedOccupied float64
+		if samples > 0 {
+			predictedOccupied = float64(occupied[vehicleType]) / float64(samples)
+		}
+		forecast.VehicleTypes[vehicleType] = TypeForecast{
+			Capacity:           count.Capacity,
+			PredictedOccupied:  predictedOccupied,
+			PredictedAvailable: math.Max(0, float64(count.Capacity)-predictedOccupied),
+		}
+	}
+
+	return forecast
+}
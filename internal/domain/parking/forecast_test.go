@@ -0,0 +1,26 @@
+package parking
+
+import (
+	"testing"
+	"time"
+)
+
+// A lot opened today forecasts from today alone rather than averaging in six empty days
+func TestForecastSkipsDaysWithoutData(t *testing.T) {
+	service := newTestService(t, 2)
+
+	if forecast := service.GetForecast(time.Now()); forecast.Samples != 0 || forecast.VehicleTypes[Automobile].PredictedOccupied != 0 {
+		t.Fatalf("got %+v, want no samples before the first session", forecast)
+	}
+
+	if _, err := service.Park(Automobile, "AB1"); err != nil {
+		t.Fatal(err)
+	}
+	forecast := service.GetForecast(time.Now())
+	if forecast.Samples != 1 {
+		t.Fatalf("got %d samples, want 1", forecast.Samples)
+	}
+	if got := forecast.VehicleTypes[Automobile]; got.PredictedOccupied != 1 || got.PredictedAvailable != 1 {
+		t.Fatalf("got %+v, want one spot occupied and one available", got)
+	}
+}
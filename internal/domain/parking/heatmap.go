@@ -0,0 +1,73 @@
+package parking
+
+import (
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// HeatmapCell holds the utilization of a single spot over the heatmap window
+type HeatmapCell struct {
+	Exists      bool
+	IsActive    bool
+	Utilization float64 // fraction of the window the spot's capacity was in use
+}
+
+// Heatmap holds the per-spot utilization grid of a floor
+type Heatmap struct {
+	Floor int
+	From  time.Time
+	To    time.Time
+	Cells [][]HeatmapCell // indexed by row and column
+}
+
+// GetHeatmap computes the fraction of time each spot on a floor was occupied within [from, to),
+// spots holding several vehicles are weighted by their capacity
+func (s *ParkingService) GetHeatmap(floor int, from, to time.Time) (Heatmap, error) {
+	if !from.Before(to) {
+		return Heatmap{}, pkgerrors.ErrInvalidTimeWindow
+	}
+
+	spots, err := s.repo.GetFloorSpots(floor)
+	if err != nil {
+		return Heatmap{}, err
+	}
+
+	heatmap := Heatmap{
+		Floor: floor,
+		From:  from,
+		To:    to,
+		Cells: make([][]HeatmapCell, len(spots)),
+	}
+	capacities := make([][]int, len(spots))
+	for row := range spots {
+		heatmap.Cells[row] = make([]HeatmapCell, len(spots[row]))
+		capacities[row] = make([]int, len(spots[row]))
+		for col, spot := range spots[row] {
+			heatmap.Cells[row][col].Exists = spot.Exists
+			heatmap.Cells[row][col].IsActive = spot.InService()
+			capacities[row][col] = spot.Capacity
+		}
+	}
+
+	window := to.Sub(from)
+	for _, session := range s.repo.GetSessions(from, to) {
+		sessionFloor, row, col, err := s.repo.ParseSpotID(session.SpotID)
+		if err != nil || sessionFloor != floor {
+			continue
+		}
+
+		// Clip the session to the window
+		start := session.EntryTime
+		if start.Before(from) {
+			start = from
+		}
+		end := to
+		if !session.IsActive() && session.ExitTime.Before(to) {
+			end = session.ExitTime
+		}
+
+		heatmap.Cells[row][col].Utilization += float64(end.Sub(start)) / float64(window) / float64(capacities[row][col])
+	}
+
+	return heatmap, nil
+}
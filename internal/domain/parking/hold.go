@@ -0,0 +1,279 @@
+package parking
+
+import (
+	"errors"
+	"fmt"
+	"parking-lot-system/internal/domain/plate"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultHoldTTL is how long a held spot stays reserved when the park is not confirmed
+const DefaultHoldTTL = 2 * time.Minute
+
+// Hold is a spot reserved for a vehicle at the entry gate, e.g. while its ticket is printed
+type Hold struct {
+	ID            int
+	SpotID        string
+	VehicleType   string
+	VehicleNumber string // empty when the vehicle number is only known on confirmation
+	Gate          int
+	Device        string // registered device the hold was requested from, empty when unknown
+	ExpiresAt     time.Time
+	Duplicate     bool // held for a duplicate park attempt, only an operator may confirm it
+	Contact       string
+
+	expiry *time.Timer
+}
+
+// holdRegistry keeps the holds that are neither confirmed nor expired
+type holdRegistry struct {
+	mutex  sync.Mutex
+	ttl    time.Duration
+	nextID int
+	holds  map[int]*Hold
+}
+
+// SetHoldTTL sets how long a held spot stays reserved when the park is not confirmed
+func (s *ParkingService) SetHoldTTL(ttl time.Duration) {
+	s.holds.mutex.Lock()
+	defer s.holds.mutex.Unlock()
+
+	s.holds.ttl = ttl
+}
+
+// HoldSpot reserves a spot for a vehicle until the park is confirmed with ConfirmHold. The spot
+// is found as for ParkWithOptions unless spotID is set, and released when the hold expires.
+func (s *ParkingService) HoldSpot(vehicleType, vehicleNumber, spotID string, opts ParkOptions) (Hold, error) {
+	vehicleNumber = plate.Normalize(vehicleNumber)
+
+	if err := s.checkIncident(); err != nil {
+		return Hold{}, err
+	}
+
+	registered, err := s.vehicleType(vehicleType)
+	if err != nil {
+		return Hold{}, err
+	}
+
+	// The vehicle number may be read at the gate later on
+	if vehicleNumber != "" {
+		if err := s.validateVehicleNumber(vehicleNumber); err != nil {
+			return Hold{}, err
+		}
+		isParked, currentSpotID, _ := s.repo.IsVehicleParked(vehicleNumber)
+		if isParked {
+			return Hold{}, fmt.Errorf("%w: %s at spot %s", pkgerrors.ErrVehicleAlreadyParked, vehicleNumber, currentSpotID)
+		}
+		if err := s.checkReentryCooldown(vehicleNumber); err != nil {
+			return Hold{}, err
+		}
+	}
+
+	device, err := s.resolveDevice(opts.Device)
+	if err != nil {
+		return Hold{}, err
+	}
+	if opts.Gate == 0 {
+		opts.Gate = device.Gate
+	}
+
+	if err := s.validateGate(opts.Gate); err != nil {
+		return Hold{}, err
+	}
+
+	if opts.Size == "" {
+		opts.Size = registered.Size
+	}
+	if err := s.validateSize(opts.Size); err != nil {
+		return Hold{}, err
+	}
+	if err := s.validateContact(opts.Contact); err != nil {
+		return Hold{}, err
+	}
+
+	spotID, err = s.reserveSpot(vehicleType, spotID, opts)
+	if err != nil {
+		return Hold{}, err
+	}
+
+	hold := s.addHold(Hold{
+		SpotID:        spotID,
+		VehicleType:   vehicleType,
+		VehicleNumber: vehicleNumber,
+		Gate:          opts.Gate,
+		Device:        opts.Device,
+		Contact:       opts.Contact,
+	})
+	s.notifyReservation(hold, opts.Contact)
+
+	return hold, nil
+}
+
+// reserveSpot holds a place at the requested spot, or at the spot found as for ParkWithOptions when spotID is empty
+func (s *ParkingService) reserveSpot(vehicleType, spotID string, opts ParkOptions) (string, error) {
+	if spotID != "" {
+		return spotID, s.holdRequestedSpot(vehicleType, spotID, opts)
+	}
+
+	// A concurrent request may take the found spot first
+	var err error
+	for attempt := 0; attempt < maxParkAttempts; attempt++ {
+		spotID, err = s.allocateSpot(vehicleType, opts)
+		if err != nil {
+			return "", err
+		}
+
+		err = s.repo.HoldSpot(spotID)
+		if !errors.Is(err, pkgerrors.ErrSpotFull) {
+			break
+		}
+	}
+	return spotID, err
+}
+
+// addHold registers the hold of a reserved spot, releasing the spot when the hold expires
+func (s *ParkingService) addHold(hold Hold) Hold {
+	s.holds.mutex.Lock()
+	defer s.holds.mutex.Unlock()
+
+	s.holds.nextID++
+	hold.ID = s.holds.nextID
+	hold.ExpiresAt = time.Now().Add(s.holds.ttl)
+	hold.expiry = time.AfterFunc(s.holds.ttl, func() { s.expireHold(hold.ID) })
+	s.holds.holds[hold.ID] = &hold
+
+	return hold
+}
+
+// holdRequestedSpot reserves a spot chosen by the caller, which must be one the vehicle may park in
+func (s *ParkingService) holdRequestedSpot(vehicleType, spotID string, opts ParkOptions) error {
+	floor, row, column, err := s.repo.ParseSpotID(spotID)
+	if err != nil {
+		return err
+	}
+
+	spot, err := s.repo.GetSpot(floor, row, column)
+	if err != nil {
+		return err
+	}
+
+	spotTypes := append([]string{vehicleType}, s.fallbacks[vehicleType]...)
+	if spot.IsActive && !slices.Contains(spotTypes, spot.VehicleType) {
+		return fmt.Errorf("%w: spot %s is for %s", pkgerrors.ErrInvalidSpotType, spotID, spot.VehicleType)
+	}
+	if !slices.Contains(s.usableSizes(opts.Size), spot.Size) {
+		return fmt.Errorf("%w: spot %s is %s", pkgerrors.ErrInvalidSize, spotID, spot.Size)
+	}
+	if spot.Blocked {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotBlocked, spotID)
+	}
+
+	return s.repo.HoldSpot(spotID)
+}
+
+// ConfirmHold parks the vehicle of a hold at its spot. The vehicle number is required
+// unless it was given when the spot was held, and replaces that one otherwise. A hold placed
+// from a registered device is only confirmed from that device.
+func (s *ParkingService) ConfirmHold(holdID int, vehicleNumber, device string) (Hold, error) {
+	hold, err := s.getHold(holdID)
+	if err != nil {
+		return Hold{}, err
+	}
+	if hold.Duplicate {
+		return Hold{}, fmt.Errorf("%w: hold %d", pkgerrors.ErrDuplicatePending, holdID)
+	}
+	if hold.Device != "" && device != hold.Device {
+		return Hold{}, fmt.Errorf("%w: hold %d was placed from device %s", pkgerrors.ErrForbidden, holdID, hold.Device)
+	}
+	if err := s.checkIncident(); err != nil {
+		return Hold{}, err
+	}
+
+	return s.confirmHold(hold, vehicleNumber)
+}
+
+// getHold returns a hold that is neither confirmed nor expired
+func (s *ParkingService) getHold(holdID int) (*Hold, error) {
+	s.holds.mutex.Lock()
+	defer s.holds.mutex.Unlock()
+
+	hold, exists := s.holds.holds[holdID]
+	if !exists {
+		return nil, fmt.Errorf("%w: %d", pkgerrors.ErrHoldNotFound, holdID)
+	}
+	return hold, nil
+}
+
+// confirmHold parks the vehicle of a hold at its spot
+func (s *ParkingService) confirmHold(hold *Hold, vehicleNumber string) (Hold, error) {
+	vehicleNumber = plate.Normalize(vehicleNumber)
+	if vehicleNumber == "" {
+		vehicleNumber = hold.VehicleNumber
+	}
+	if err := s.validateVehicleNumber(vehicleNumber); err != nil {
+		return Hold{}, err
+	}
+	isParked, currentSpotID, _ := s.repo.IsVehicleParked(vehicleNumber)
+	if isParked {
+		return Hold{}, fmt.Errorf("%w: %s at spot %s", pkgerrors.ErrVehicleAlreadyParked, vehicleNumber, currentSpotID)
+	}
+	if err := s.checkReentryCooldown(vehicleNumber); err != nil {
+		return Hold{}, err
+	}
+
+	// The hold may have expired in the meantime
+	if !s.takeHold(hold.ID) {
+		return Hold{}, fmt.Errorf("%w: %d", pkgerrors.ErrHoldNotFound, hold.ID)
+	}
+
+	// The vehicle may have been parked elsewhere concurrently, its hold is given up then
+	if err := s.repo.ConfirmHold(hold.SpotID, vehicleNumber, hold.VehicleType); err != nil {
+		_ = s.repo.ReleaseHold(hold.SpotID)
+		return Hold{}, err
+	}
+
+	confirmed := *hold
+	confirmed.VehicleNumber = vehicleNumber
+	s.rememberContact(vehicleNumber, hold.Contact)
+	s.publishParked(confirmed.SpotID, confirmed.VehicleNumber, confirmed.VehicleType, confirmed.Gate, confirmed.Device)
+
+	return confirmed, nil
+}
+
+// expireHold releases the spot of a hold that was not confirmed in time
+func (s *ParkingService) expireHold(holdID int) {
+	hold, err := s.getHold(holdID)
+	if err == nil && s.takeHold(holdID) {
+		// The spot is gone when the lot was re-initialized since
+		_ = s.repo.ReleaseHold(hold.SpotID)
+	}
+}
+
+// clearHolds drops every hold, e.g. when the lot is re-initialized and their spots are gone, so a
+// hold cannot be confirmed at a new spot with the same ID
+func (s *ParkingService) clearHolds() {
+	s.holds.mutex.Lock()
+	defer s.holds.mutex.Unlock()
+
+	for _, hold := range s.holds.holds {
+		hold.expiry.Stop()
+	}
+	s.holds.holds = make(map[int]*Hold)
+}
+
+// takeHold removes a hold from the registry, it reports false when the hold was already taken
+func (s *ParkingService) takeHold(holdID int) bool {
+	s.holds.mutex.Lock()
+	defer s.holds.mutex.Unlock()
+
+	hold, exists := s.holds.holds[holdID]
+	if !exists {
+		return false
+	}
+	hold.expiry.Stop()
+	delete(s.holds.holds, holdID)
+	return true
+}
@@ -0,0 +1,62 @@
+package parking
+
+import (
+	"errors"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"testing"
+)
+
+func TestConfirmHoldFromOtherDevice(t *testing.T) {
+	service := newTestService(t, 2)
+	for _, id := range []string{"kiosk-1", "kiosk-2"} {
+		if _, err := service.RegisterDevice("admin", repository.Device{ID: id, Type: "kiosk"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hold, err := service.HoldSpot(Automobile, "AB1", "", ParkOptions{Device: "kiosk-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, device := range []string{"kiosk-2", ""} {
+		if _, err := service.ConfirmHold(hold.ID, "", device); !errors.Is(err, pkgerrors.ErrForbidden) {
+			t.Fatalf("confirm from %q: got %v, want %v", device, err, pkgerrors.ErrForbidden)
+		}
+	}
+	if _, err := service.ConfirmHold(hold.ID, "", "kiosk-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Holds placed without a device are confirmed from anywhere
+	hold, err = service.HoldSpot(Automobile, "AB2", "", ParkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ConfirmHold(hold.ID, "", "kiosk-2"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReinitializeDropsHolds(t *testing.T) {
+	service := newTestService(t, 1)
+
+	hold, err := service.HoldSpot(Automobile, "AB1", "", ParkOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.InitializeParkingLot(1, 1, 1, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ConfigureSpot(0, 0, 0, "A-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new spot with the same ID takes the hold of no one
+	if _, err := service.ConfirmHold(hold.ID, "", ""); !errors.Is(err, pkgerrors.ErrHoldNotFound) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrHoldNotFound)
+	}
+	if _, err := service.Park(Automobile, "AB2"); err != nil {
+		t.Fatalf("spot of the dropped hold is not free: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package parking
+
+import (
+	"fmt"
+	"parking-lot-system/internal/alert"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sync"
+	"time"
+)
+
+// Incident is the emergency state of the lot. While it is active no vehicles are let in and
+// vehicles leave without being held back.
+type Incident struct {
+	Active    bool
+	Reason    string
+	StartedBy string
+	StartedAt time.Time
+}
+
+// incidentState keeps the current incident
+type incidentState struct {
+	mutex   sync.RWMutex
+	current Incident
+}
+
+// StartIncident puts the lot into emergency mode on behalf of an admin and announces it with an
+// incident.started event. Starting an incident while one is active returns the active one.
+func (s *ParkingService) StartIncident(admin, reason string) Incident {
+	s.incident.mutex.Lock()
+	if s.incident.current.Active {
+		defer s.incident.mutex.Unlock()
+		return s.incident.current
+	}
+	s.incident.current = Incident{Active: true, Reason: reason, StartedBy: admin, StartedAt: time.Now()}
+	incident := s.incident.current
+	s.incident.mutex.Unlock()
+
+	s.repo.AppendAudit(repository.AuditEntry{Actor: admin, Action: "incident.start", Reason: reason})
+	s.publish(Event{Type: EventIncidentStarted, Reason: reason})
+	s.raiseAlert(alert.TypeIncident, alert.SeverityCritical, "incident",
+		fmt.Sprintf("%s declared an emergency: %s", admin, reason))
+
+	return incident
+}
+
+// EndIncident returns the lot to normal operation on behalf of an admin and announces it with an
+// incident.ended event. Waiting check-ins get spots again.
+func (s *ParkingService) EndIncident(admin string) Incident {
+	s.incident.mutex.Lock()
+	if !s.incident.current.Active {
+		defer s.incident.mutex.Unlock()
+		return s.incident.current
+	}
+	reason := s.incident.current.Reason
+	s.incident.current = Incident{}
+	s.incident.mutex.Unlock()
+
+	s.repo.AppendAudit(repository.AuditEntry{Actor: admin, Action: "incident.end", Reason: reason})
+	s.publish(Event{Type: EventIncidentEnded, Reason: reason})
+
+	return Incident{}
+}
+
+// GetIncident returns the current incident, inactive when the lot operates normally
+func (s *ParkingService) GetIncident() Incident {
+	s.incident.mutex.RLock()
+	defer s.incident.mutex.RUnlock()
+
+	return s.incident.current
+}
+
+// checkIncident rejects vehicles entering the lot while an incident is active
+func (s *ParkingService) checkIncident() error {
+	if incident := s.GetIncident(); incident.Active {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrLotEmergency, incident.Reason)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package parking
+
+import (
+	"fmt"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+)
+
+// SpotSettings is the configuration a spot should have, empty fields stand for the defaults
+// of a fresh lot: an inactive standard spot without zone holding one vehicle
+type SpotSettings struct {
+	Type     string // spot code, e.g. "A-1"
+	Zone     string
+	Size     string
+	Capacity int
+}
+
+// ReconfigureSpot brings a spot to the given settings, changing only what differs from its current
+// configuration so parked vehicles are kept. The type of an occupied spot is not changed.
+// It reports whether anything was changed.
+func (s *ParkingService) ReconfigureSpot(floor, row, column int, settings SpotSettings) (bool, error) {
+	if settings.Type == "" {
+		settings.Type = InactiveSpotCode
+	}
+	if settings.Size == "" {
+		settings.Size = repository.DefaultSpotSize
+	}
+	if settings.Capacity == 0 {
+		settings.Capacity = 1
+	}
+
+	spotType, err := s.spotType(settings.Type)
+	if err != nil {
+		return false, err
+	}
+	if err := s.validateSize(settings.Size); err != nil {
+		return false, err
+	}
+
+	spot, err := s.repo.GetSpot(floor, row, column)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+	if spot.VehicleType != spotType.VehicleType || spot.Class != spotType.Class ||
+		spot.IsActive != spotType.IsActive || spot.Exists != spotType.Exists {
+		if spot.IsOccupied() {
+			return false, fmt.Errorf("%w: %s", pkgerrors.ErrSpotInUse, s.repo.FormatSpotID(floor, row, column))
+		}
+		if err := s.ConfigureSpot(floor, row, column, settings.Type); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	if spot.Zone != settings.Zone {
+		if err := s.ConfigureZone(floor, row, column, settings.Zone); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	if spot.Size != settings.Size {
+		if err := s.ConfigureSize(floor, row, column, settings.Size); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	if spot.Capacity != settings.Capacity {
+		if err := s.ConfigureCapacity(floor, row, column, settings.Capacity); err != nil {
+			return changed, err
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
+
+// FormatSpotID returns the ID of the spot at a location
+func (s *ParkingService) FormatSpotID(floor, row, column int) string {
+	return s.repo.FormatSpotID(floor, row, column)
+}
@@ -0,0 +1,69 @@
+package parking
+
+import (
+	"parking-lot-system/internal/repository"
+	"sort"
+)
+
+// Kinds of ledger transactions
+const (
+	LedgerPayment = "payment"
+	LedgerRefund  = "refund"
+)
+
+// Ledger accounts besides the cash drawers
+const (
+	AccountRevenue = "revenue" // parking fees earned
+	AccountRefunds = "refunds" // parking fees given back, offsetting the revenue
+)
+
+// CashAccount returns the ledger account of a cash drawer, e.g. "cash:booth-1"
+func CashAccount(drawerID string) string {
+	return "cash:" + drawerID
+}
+
+// AccountBalance holds the debit and credit totals of a ledger account
+type AccountBalance struct {
+	Account string
+	Debit   float64
+	Credit  float64
+}
+
+// Balance returns the debits minus the credits of the account
+func (b AccountBalance) Balance() float64 {
+	return b.Debit - b.Credit
+}
+
+// Ledger holds ledger transactions and the balances of the accounts they post to
+type Ledger struct {
+	Transactions []repository.LedgerTransaction
+	Balances     []AccountBalance // ordered by account
+}
+
+// GetLedger returns the ledger transactions of a session, or of all sessions when sessionID is 0,
+// restricted to those posting to an account unless account is empty, with the balances they add up to
+func (s *ParkingService) GetLedger(sessionID int, account string) Ledger {
+	ledger := Ledger{
+		Transactions: s.repo.GetLedger(repository.LedgerFilter{SessionID: sessionID, Account: account}),
+		Balances:     []AccountBalance{},
+	}
+
+	balances := make(map[string]*AccountBalance)
+	for _, transaction := range ledger.Transactions {
+		for _, line := range transaction.Lines {
+			balance, ok := balances[line.Account]
+			if !ok {
+				balance = &AccountBalance{Account: line.Account}
+				balances[line.Account] = balance
+			}
+			balance.Debit += line.Debit
+			balance.Credit += line.Credit
+		}
+	}
+	for _, balance := range balances {
+		ledger.Balances = append(ledger.Balances, *balance)
+	}
+	sort.Slice(ledger.Balances, func(i, j int) bool { return ledger.Balances[i].Account < ledger.Balances[j].Account })
+
+	return ledger
+}
@@ -0,0 +1,50 @@
+package parking
+
+import (
+	"parking-lot-system/internal/repository"
+	"testing"
+)
+
+func TestCashPaymentsPostToLedger(t *testing.T) {
+	service := newTestService(t, 2)
+	for _, vehicleNumber := range []string{"AB1", "AB2"} {
+		if _, err := service.Park(Automobile, vehicleNumber); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := service.RecordCashPayment("att", 1, 50000, 5000, "booth-1", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RecordCashPayment("att", 2, 20000, 0, "booth-2", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	ledger := service.GetLedger(0, "")
+	want := []AccountBalance{
+		{Account: CashAccount("booth-1"), Debit: 45000},
+		{Account: CashAccount("booth-2"), Debit: 20000},
+		{Account: AccountRevenue, Credit: 65000},
+	}
+	if len(ledger.Transactions) != 2 || len(ledger.Balances) != len(want) {
+		t.Fatalf("got %+v, want two payments posting to %+v", ledger, want)
+	}
+	for i, balance := range ledger.Balances {
+		if balance != want[i] {
+			t.Errorf("got balance %+v, want %+v", balance, want[i])
+		}
+	}
+
+	// A drawer's transactions add up to the cash in it
+	drawer := service.GetLedger(0, CashAccount("booth-1"))
+	if len(drawer.Transactions) != 1 || drawer.Transactions[0].PaymentID != 1 || drawer.Transactions[0].SessionID != 1 {
+		t.Fatalf("got %+v, want the payment of ticket 1", drawer.Transactions)
+	}
+
+	if _, err := service.repo.AddLedgerTransaction(repository.LedgerTransaction{
+		Kind:  LedgerPayment,
+		Lines: []repository.LedgerLine{{Account: AccountRevenue, Credit: 10}, {Account: CashAccount("booth-1"), Debit: 9}},
+	}); err == nil {
+		t.Fatal("unbalanced transaction was recorded")
+	}
+}
@@ -0,0 +1,35 @@
+package parking
+
+import (
+	"slices"
+	"testing"
+)
+
+// A vehicle parked at a fallback spot fills up the spots of that spot's type, not of its own
+func TestLotFullAtFallbackSpot(t *testing.T) {
+	service := newTestService(t, 2)
+	if err := service.ConfigureSpot(0, 0, 1, "M-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.SetFallbacks(map[string][]string{Motorcycle: {Automobile}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vehicleNumber := range []string{"AB1", "AB2"} {
+		if _, err := service.Park(Motorcycle, vehicleNumber); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := service.QueryEvents(EventQuery{Type: EventLotFull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var full []string
+	for _, event := range page.Events {
+		full = append(full, event.VehicleType)
+	}
+	if want := []string{Motorcycle, Automobile}; !slices.Equal(full, want) {
+		t.Fatalf("got lot.full for %v, want %v", full, want)
+	}
+}
@@ -0,0 +1,20 @@
+package parking
+
+import "parking-lot-system/internal/metrics"
+
+// AllocationStrategy names how spots are allocated, lower floors are filled first. It labels
+// the allocation latency metrics so they stay comparable when other strategies are added.
+const AllocationStrategy = "lowest-floor"
+
+var (
+	// time to find a spot for a vehicle, including fallback spot types and larger sizes
+	findSpotLatency = metrics.NewHistogram("parking_find_spot_duration_seconds",
+		"Time to find an available spot for a vehicle.", metrics.LatencyBuckets, "vehicle_type", "strategy")
+	// time to find a spot and park a vehicle at it, including retries when another request takes the spot first
+	allocateAndParkLatency = metrics.NewHistogram("parking_allocate_and_park_duration_seconds",
+		"Time to find an available spot and park a vehicle at it.", metrics.LatencyBuckets, "vehicle_type", "strategy")
+)
+
+func init() {
+	metrics.Register(findSpotLatency, allocateAndParkLatency)
+}
@@ -1,7 +1,7 @@
 package parking
 
 import (
-	"fmt"
+	"parking-lot-system/internal/domain/spotid"
 	"sync"
 )
 
@@ -11,6 +11,32 @@ const (
 	Automobile = "Automobile"
 )
 
+// Spot and vehicle size classes
+const (
+	SizeCompact  = "compact"
+	SizeStandard = "standard"
+	SizeLarge    = "large"
+)
+
+// SpotSizes lists the size classes from smallest to largest
+var SpotSizes = []string{SizeCompact, SizeStandard, SizeLarge}
+
+// isSpotSize reports whether size is one of the size classes
+func isSpotSize(size string) bool {
+	for _, spotSize := range SpotSizes {
+		if size == spotSize {
+			return true
+		}
+	}
+	return false
+}
+
+// Spot codes of cells that cannot be parked in
+const (
+	InactiveSpotCode = "X-0" // a spot that is out of service
+	NoSpotCode       = "N-0" // no spot exists at the cell, e.g. a pillar, ramp or stairwell
+)
+
 // ParkingSpotType represents the type of parking spot
 type ParkingSpotType struct {
 	VehicleType string
@@ -27,9 +53,9 @@ type ParkingSpot struct {
 	VehicleNumber string
 }
 
-// SpotID returns the ID of the parking spot in format "floor-row-column"
+// SpotID returns the ID of the parking spot in the default "floor-row-column" format
 func (p *ParkingSpot) SpotID() string {
-	return fmt.Sprintf("%d-%d-%d", p.Floor, p.Row, p.Column)
+	return spotid.Default.Format(p.Floor, p.Row, p.Column)
 }
 
 // ParkingLot represents the entire parking lot system
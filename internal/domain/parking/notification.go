@@ -0,0 +1,121 @@
+package parking
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"parking-lot-system/internal/notify"
+	"parking-lot-system/internal/templates"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sync"
+	"time"
+)
+
+// how long delivering a notification may take
+const notificationTimeout = 30 * time.Second
+
+// contactBook keeps where notifications about parked vehicles are sent, vehicleNumber -> contact
+type contactBook struct {
+	mutex    sync.Mutex
+	contacts map[string]string
+}
+
+// contactListener forgets the contact of a vehicle once it leaves
+type contactListener struct {
+	service *ParkingService
+}
+
+func (l contactListener) HandleEvent(event Event) {
+	if event.Type == EventVehicleUnparked {
+		l.service.contacts.mutex.Lock()
+		delete(l.service.contacts.contacts, event.VehicleNumber)
+		l.service.contacts.mutex.Unlock()
+	}
+}
+
+// SetNotifier sets how notifications to drivers are delivered, none are sent when nil
+func (s *ParkingService) SetNotifier(notifier notify.Notifier) {
+	s.notifier = notifier
+}
+
+// SetTemplates sets the templates the notifications are rendered with
+func (s *ParkingService) SetTemplates(set *templates.Set) {
+	s.templates = set
+}
+
+// validateContact checks that a contact is an email address or a phone number, it is optional
+func (s *ParkingService) validateContact(contact string) error {
+	if contact == "" || notify.IsEmail(contact) || notify.IsPhoneNumber(contact) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", pkgerrors.ErrInvalidContact, contact)
+}
+
+// contactOf returns the contact given for a parked vehicle, empty when none was given
+func (s *ParkingService) contactOf(vehicleNumber string) string {
+	s.contacts.mutex.Lock()
+	defer s.contacts.mutex.Unlock()
+
+	return s.contacts.contacts[vehicleNumber]
+}
+
+// rememberContact keeps the contact given for a parked vehicle until it leaves
+func (s *ParkingService) rememberContact(vehicleNumber, contact string) {
+	if contact == "" {
+		return
+	}
+
+	s.contacts.mutex.Lock()
+	defer s.contacts.mutex.Unlock()
+
+	s.contacts.contacts[vehicleNumber] = contact
+}
+
+// notifyReservation confirms a held spot to the driver who reserved it
+func (s *ParkingService) notifyReservation(hold Hold, contact string) {
+	if contact == "" {
+		return
+	}
+
+	hold.ExpiresAt = hold.ExpiresAt.In(s.location)
+	s.sendNotification(contact, templates.ReservationConfirmation, hold)
+}
+
+// notifyOverstay warns the driver of a vehicle flagged for overstaying, when a contact was given for it
+func (s *ParkingService) notifyOverstay(vehicleNumber, spotID string) {
+	contact := s.contactOf(vehicleNumber)
+	if contact == "" {
+		return
+	}
+
+	s.sendNotification(contact, templates.OverstayWarning, struct {
+		VehicleNumber string
+		SpotID        string
+	}{vehicleNumber, spotID})
+}
+
+// sendNotification renders a notification template and delivers the message in the background, failures are logged
+func (s *ParkingService) sendNotification(contact, template string, data any) {
+	if s.notifier == nil {
+		return
+	}
+
+	subject, body, err := s.templates.RenderMessage(template, data)
+	if err != nil {
+		log.Printf("Notification to %s failed: %v", contact, err)
+		return
+	}
+	s.deliver(notify.Message{To: contact, Subject: subject, Body: body})
+}
+
+// deliver sends a message in the background, failures are logged
+func (s *ParkingService) deliver(message notify.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+
+		if err := s.notifier.Send(ctx, message); err != nil {
+			log.Printf("Notification to %s failed: %v", message.To, err)
+		}
+	}()
+}
@@ -0,0 +1,140 @@
+package parking
+
+import (
+	"fmt"
+	"parking-lot-system/internal/domain/plate"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strings"
+)
+
+// Override actions
+const (
+	OverridePark   = "park"
+	OverrideUnpark = "unpark"
+)
+
+// ForcePark parks a vehicle at a specific spot on behalf of an attendant, regardless of
+// the spot's type and active status. A vehicle parked elsewhere is moved to the spot.
+func (s *ParkingService) ForcePark(attendant, spotID, vehicleNumber, reason string) error {
+	vehicleNumber = plate.Normalize(vehicleNumber)
+
+	if reason == "" {
+		return pkgerrors.ErrReasonRequired
+	}
+	if err := s.validateVehicleNumber(vehicleNumber); err != nil {
+		return err
+	}
+
+	floor, row, column, err := s.repo.ParseSpotID(spotID)
+	if err != nil {
+		return err
+	}
+
+	spot, err := s.repo.GetSpot(floor, row, column)
+	if err != nil {
+		return err
+	}
+
+	if spot.HasVehicle(vehicleNumber) {
+		return nil
+	}
+	if spot.IsFull() {
+		return fmt.Errorf("%w: %s at spot %s", pkgerrors.ErrSpotOccupied, strings.Join(spot.Vehicles, ", "), spotID)
+	}
+
+	// Move the vehicle if it is parked elsewhere
+	isParked, currentSpotID, _ := s.repo.IsVehicleParked(vehicleNumber)
+	if isParked {
+		currentFloor, currentRow, currentColumn, err := s.repo.ParseSpotID(currentSpotID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.UnparkVehicle(currentFloor, currentRow, currentColumn, vehicleNumber); err != nil {
+			return err
+		}
+		s.publish(Event{
+			Type:          EventVehicleUnparked,
+			SpotID:        currentSpotID,
+			VehicleNumber: vehicleNumber,
+		})
+	}
+
+	if err := s.repo.ParkVehicle(spotID, vehicleNumber, ""); err != nil {
+		return err
+	}
+
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:         attendant,
+		Action:        "override." + OverridePark,
+		SpotID:        spotID,
+		VehicleNumber: vehicleNumber,
+		Reason:        reason,
+	})
+
+	s.publish(Event{
+		Type:          EventVehicleParked,
+		SpotID:        spotID,
+		VehicleNumber: vehicleNumber,
+		VehicleType:   spot.VehicleType,
+	})
+
+	return nil
+}
+
+// ForceUnpark removes a vehicle from a spot on behalf of an attendant, e.g. after a tow-away.
+// The vehicle number may be left empty when a single vehicle occupies the spot.
+// It returns the number of the removed vehicle.
+func (s *ParkingService) ForceUnpark(attendant, spotID, vehicleNumber, reason string) (string, error) {
+	vehicleNumber = plate.Normalize(vehicleNumber)
+
+	if reason == "" {
+		return "", pkgerrors.ErrReasonRequired
+	}
+
+	floor, row, column, err := s.repo.ParseSpotID(spotID)
+	if err != nil {
+		return "", err
+	}
+
+	spot, err := s.repo.GetSpot(floor, row, column)
+	if err != nil {
+		return "", err
+	}
+
+	if !spot.IsOccupied() {
+		return "", fmt.Errorf("%w: %s", pkgerrors.ErrSpotNotOccupied, spotID)
+	}
+
+	if vehicleNumber == "" {
+		if len(spot.Vehicles) > 1 {
+			return "", fmt.Errorf("%w: %s", pkgerrors.ErrVehicleNumberNeeded, spotID)
+		}
+		vehicleNumber = spot.Vehicles[0]
+	}
+
+	if err := s.repo.UnparkVehicle(floor, row, column, vehicleNumber); err != nil {
+		return "", err
+	}
+
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:         attendant,
+		Action:        "override." + OverrideUnpark,
+		SpotID:        spotID,
+		VehicleNumber: vehicleNumber,
+		Reason:        reason,
+	})
+
+	s.publish(Event{
+		Type:          EventVehicleUnparked,
+		SpotID:        spotID,
+		VehicleNumber: vehicleNumber,
+	})
+
+	return vehicleNumber, nil
+}
+
+// GetAuditEntries returns all audited staff actions
+func (s *ParkingService) GetAuditEntries() []repository.AuditEntry {
+	return s.repo.GetAuditEntries()
+}
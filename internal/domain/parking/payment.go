@@ -0,0 +1,139 @@
+package parking
+
+import (
+	"fmt"
+	"log"
+	"parking-lot-system/internal/notify"
+	"parking-lot-system/internal/pdf"
+	"parking-lot-system/internal/repository"
+	"parking-lot-system/internal/templates"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strings"
+	"time"
+)
+
+// Payment methods
+const (
+	PaymentCash = "cash"
+)
+
+// Receipt is what a driver gets for a payment
+type Receipt struct {
+	Number        int // the payment ID
+	TicketID      int
+	VehicleNumber string
+	SpotID        string
+	EntryTime     time.Time
+	ExitTime      time.Time // zero while the vehicle is still parked
+	Method        string
+	Amount        float64
+	Tendered      float64
+	Change        float64
+	PaidAt        time.Time
+}
+
+// RecordCashPayment records cash an attendant took for a parking session, e.g. at a booth in a garage
+// without a payment provider. The amount kept is the amount tendered minus the change given, and is
+// posted to the ledger from revenue to the cash drawer.
+// The receipt is emailed to receiptTo, or to the email address given when the vehicle parked, if any.
+func (s *ParkingService) RecordCashPayment(attendant string, sessionID int, tendered, change float64, drawerID, receiptTo string) (repository.Payment, error) {
+	drawerID = strings.TrimSpace(drawerID)
+	if tendered <= 0 || change < 0 || change > tendered || drawerID == "" {
+		return repository.Payment{}, pkgerrors.ErrInvalidPayment
+	}
+	if receiptTo != "" && !notify.IsEmail(receiptTo) {
+		return repository.Payment{}, fmt.Errorf("%w: receipts are emailed, %q is not an email address", pkgerrors.ErrInvalidContact, receiptTo)
+	}
+
+	session, err := s.repo.GetSession(sessionID)
+	if err != nil {
+		return repository.Payment{}, err
+	}
+
+	payment, err := s.repo.AddPayment(repository.Payment{
+		SessionID: session.ID,
+		Method:    PaymentCash,
+		Amount:    tendered - change,
+		Tendered:  tendered,
+		Change:    change,
+		DrawerID:  drawerID,
+		TakenBy:   attendant,
+	})
+	if err != nil {
+		return repository.Payment{}, err
+	}
+
+	if _, err := s.repo.AddLedgerTransaction(repository.LedgerTransaction{
+		Kind:      LedgerPayment,
+		SessionID: session.ID,
+		PaymentID: payment.ID,
+		Lines: []repository.LedgerLine{
+			{Account: CashAccount(drawerID), Debit: payment.Amount},
+			{Account: AccountRevenue, Credit: payment.Amount},
+		},
+	}); err != nil {
+		return repository.Payment{}, err
+	}
+
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:         attendant,
+		Action:        "payment.cash",
+		SpotID:        session.SpotID,
+		VehicleNumber: session.VehicleNumber,
+		Reason:        fmt.Sprintf("%.2f into drawer %s", payment.Amount, drawerID),
+	})
+
+	if receiptTo == "" && notify.IsEmail(s.contactOf(session.VehicleNumber)) {
+		receiptTo = s.contactOf(session.VehicleNumber)
+	}
+	if receiptTo != "" {
+		s.emailReceipt(receiptTo, Receipt{
+			Number:        payment.ID,
+			TicketID:      session.ID,
+			VehicleNumber: session.VehicleNumber,
+			SpotID:        session.SpotID,
+			EntryTime:     session.EntryTime.In(s.location),
+			ExitTime:      session.ExitTime.In(s.location),
+			Method:        payment.Method,
+			Amount:        payment.Amount,
+			Tendered:      payment.Tendered,
+			Change:        payment.Change,
+			PaidAt:        payment.TakenAt.In(s.location),
+		})
+	}
+
+	return payment, nil
+}
+
+// emailReceipt emails a receipt with its text and HTML versions, and the text as a PDF attachment
+func (s *ParkingService) emailReceipt(to string, receipt Receipt) {
+	if s.notifier == nil {
+		return
+	}
+
+	subject, body, err := s.templates.RenderMessage(templates.PaymentReceipt, receipt)
+	if err == nil {
+		var html string
+		html, err = s.templates.Render(templates.PaymentReceipt+".html", receipt)
+		if err == nil {
+			s.deliver(notify.Message{
+				To:      to,
+				Subject: subject,
+				Body:    body,
+				HTML:    html,
+				Attachments: []notify.Attachment{{
+					Name:        fmt.Sprintf("receipt-%d.pdf", receipt.Number),
+					ContentType: "application/pdf",
+					Data:        pdf.Text(strings.Split(body, "\n")),
+				}},
+			})
+			return
+		}
+	}
+	log.Printf("Receipt to %s failed: %v", to, err)
+}
+
+// GetPayments returns the payments of a session, or of all sessions when sessionID is 0
+func (s *ParkingService) GetPayments(sessionID int) []repository.Payment {
+	return s.repo.GetPayments(sessionID)
+}
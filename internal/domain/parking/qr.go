@@ -0,0 +1,99 @@
+package parking
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"parking-lot-system/internal/domain/plate"
+	"parking-lot-system/internal/wallet"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strconv"
+)
+
+// Prefixes of the codes printed on tickets and spot signs, telling scanners what a code stands for
+const (
+	TicketCodePrefix = "ticket:"
+	SpotCodePrefix   = "spot:"
+)
+
+// TicketCode returns the scannable code of a parking ticket, a ticket being the parking session of a vehicle
+func (s *ParkingService) TicketCode(ticketID int) (string, error) {
+	session, err := s.repo.GetSession(ticketID)
+	if err != nil {
+		return "", err
+	}
+	return TicketCodePrefix + strconv.Itoa(session.ID), nil
+}
+
+// ActiveTicket returns the ID of the ticket of a parked vehicle, false when the vehicle is not parked
+func (s *ParkingService) ActiveTicket(vehicleNumber string) (int, bool) {
+	session, exists := s.repo.GetActiveSession(plate.Normalize(vehicleNumber))
+	return session.ID, exists
+}
+
+// TicketToken returns the token giving the driver of a ticket its wallet pass and QR code. Ticket IDs are
+// sequential, so the token keeps others from fetching the passes, and the plates on them, of every parked vehicle.
+func (s *ParkingService) TicketToken(ticketID int) string {
+	mac := hmac.New(sha256.New, s.walletKey)
+	mac.Write([]byte(TicketCodePrefix + strconv.Itoa(ticketID)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CheckTicketToken returns an error unless token is the token of a ticket
+func (s *ParkingService) CheckTicketToken(ticketID int, token string) error {
+	if !hmac.Equal([]byte(token), []byte(s.TicketToken(ticketID))) {
+		return fmt.Errorf("%w: invalid token for ticket %d", pkgerrors.ErrForbidden, ticketID)
+	}
+	return nil
+}
+
+// WalletTicket returns what the wallet pass of a parking ticket shows. Only tickets of parked vehicles get
+// passes. The lot has no maximum stay, so a pass expires when the session would count as stuck.
+func (s *ParkingService) WalletTicket(ticketID int) (wallet.Ticket, error) {
+	session, err := s.repo.GetSession(ticketID)
+	if err != nil {
+		return wallet.Ticket{}, err
+	}
+	if !session.IsActive() {
+		return wallet.Ticket{}, fmt.Errorf("%w: %d", pkgerrors.ErrTicketClosed, ticketID)
+	}
+
+	return wallet.Ticket{
+		ID:            session.ID,
+		VehicleNumber: session.VehicleNumber,
+		SpotID:        session.SpotID,
+		EntryTime:     session.EntryTime.In(s.location),
+		ExpiresAt:     session.EntryTime.Add(s.stuckAfter).In(s.location),
+		Code:          TicketCodePrefix + strconv.Itoa(session.ID),
+	}, nil
+}
+
+// newWalletKey returns a random key for the wallet pass tokens. Sessions are kept in memory, so tokens
+// do not need to outlive the process.
+func newWalletKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(err)
+	}
+	return key
+}
+
+// SpotCode returns the scannable code of a spot, e.g. for drivers to remember where they parked
+func (s *ParkingService) SpotCode(spotID string) (string, error) {
+	floor, row, column, err := s.repo.ParseSpotID(spotID)
+	if err != nil {
+		return "", err
+	}
+
+	spot, err := s.repo.GetSpot(floor, row, column)
+	if err != nil {
+		return "", err
+	}
+	if !spot.Exists {
+		return "", fmt.Errorf("%w: %s", pkgerrors.ErrSpotDoesNotExist, spotID)
+	}
+
+	return SpotCodePrefix + s.repo.FormatSpotID(floor, row, column), nil
+}
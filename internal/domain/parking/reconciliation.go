@@ -0,0 +1,127 @@
+package parking
+
+import (
+	"fmt"
+	"log"
+	"parking-lot-system/internal/alert"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Discrepancy kinds
+const (
+	// the sensor detects a vehicle but no vehicle is parked at the spot
+	DiscrepancyVehicleWithoutSession = "vehicle_without_session"
+	// a vehicle is parked at the spot but the sensor detects none
+	DiscrepancySessionWithoutVehicle = "session_without_vehicle"
+)
+
+// Discrepancy represents a spot where the sensor disagrees with the logical state
+type Discrepancy struct {
+	Kind             string
+	SpotID           string
+	VehicleNumber    string
+	SensorReportedAt time.Time
+}
+
+// DiscrepancyReport holds the result of a reconciliation run
+type DiscrepancyReport struct {
+	GeneratedAt   time.Time
+	Discrepancies []Discrepancy
+}
+
+// reconciler keeps the latest discrepancy report
+type reconciler struct {
+	mutex  sync.RWMutex
+	report DiscrepancyReport
+}
+
+// Reconcile compares the sensor readings with the logical occupancy of every active spot
+// and stores the resulting report. Readings taken before the spot last changed are ignored,
+// as the sensor has not caught up with the change yet.
+func (s *ParkingService) Reconcile() DiscrepancyReport {
+	report := DiscrepancyReport{
+		GeneratedAt:   time.Now(),
+		Discrepancies: []Discrepancy{},
+	}
+
+	floors, _ := s.repo.GetDimensions()
+	for floor := range floors {
+		spots, err := s.repo.GetFloorSpots(floor)
+		if err != nil {
+			continue
+		}
+
+		for _, row := range spots {
+			for _, spot := range row {
+				if !spot.InService() || spot.Sensor == nil || spot.Sensor.ReportedAt.Before(spot.ChangedAt) {
+					continue
+				}
+				if spot.Sensor.Occupied == spot.IsOccupied() {
+					continue
+				}
+
+				discrepancy := Discrepancy{
+					Kind:             DiscrepancyVehicleWithoutSession,
+					SpotID:           s.repo.FormatSpotID(spot.Floor, spot.Row, spot.Column),
+					SensorReportedAt: spot.Sensor.ReportedAt,
+				}
+				if spot.IsOccupied() {
+					discrepancy.Kind = DiscrepancySessionWithoutVehicle
+					discrepancy.VehicleNumber = strings.Join(spot.Vehicles, ", ")
+				}
+				report.Discrepancies = append(report.Discrepancies, discrepancy)
+			}
+		}
+	}
+
+	s.reconciler.mutex.Lock()
+	previous := make(map[Discrepancy]bool)
+	for _, discrepancy := range s.reconciler.report.Discrepancies {
+		previous[discrepancy] = true
+	}
+	s.reconciler.report = report
+	s.reconciler.mutex.Unlock()
+
+	// Alert once per discrepancy, not on every run it persists
+	for _, discrepancy := range report.Discrepancies {
+		if !previous[discrepancy] {
+			s.raiseDiscrepancyAlert(discrepancy)
+		}
+	}
+
+	return report
+}
+
+// raiseDiscrepancyAlert alerts the operators of a spot where the sensor disagrees with the logical state
+func (s *ParkingService) raiseDiscrepancyAlert(discrepancy Discrepancy) {
+	summary := fmt.Sprintf("The sensor of spot %s detects a vehicle but none is parked there", discrepancy.SpotID)
+	if discrepancy.Kind == DiscrepancySessionWithoutVehicle {
+		summary = fmt.Sprintf("%s is parked at spot %s but its sensor detects no vehicle", discrepancy.VehicleNumber, discrepancy.SpotID)
+	}
+	s.raiseAlert(alert.TypeDiscrepancy, alert.SeverityWarning, "discrepancy:"+discrepancy.SpotID, summary)
+}
+
+// GetDiscrepancyReport returns the report of the latest reconciliation run
+func (s *ParkingService) GetDiscrepancyReport() DiscrepancyReport {
+	s.reconciler.mutex.RLock()
+	defer s.reconciler.mutex.RUnlock()
+
+	return s.reconciler.report
+}
+
+// StartReconciliation runs Reconcile periodically in the background
+func (s *ParkingService) StartReconciliation(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			report := s.Reconcile()
+			if len(report.Discrepancies) > 0 {
+				log.Printf("Reconciliation found %d sensor discrepancies", len(report.Discrepancies))
+			}
+		}
+	}()
+}
@@ -0,0 +1,113 @@
+package parking
+
+import (
+	"fmt"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strings"
+	"sync"
+)
+
+// PaymentProvider pays money back for the payments taken with a payment method
+type PaymentProvider interface {
+	// Refund pays an amount of a payment back to the driver
+	Refund(payment repository.Payment, amount float64) error
+	// Account returns the ledger account the money of a payment went into
+	Account(payment repository.Payment) string
+}
+
+// cashDrawer refunds cash payments: the attendant hands the money out of the drawer it went into
+type cashDrawer struct{}
+
+func (cashDrawer) Refund(payment repository.Payment, amount float64) error {
+	return nil
+}
+
+func (cashDrawer) Account(payment repository.Payment) string {
+	return CashAccount(payment.DrawerID)
+}
+
+// refundDesk gives one refund at a time, so a provider never pays back more than is left of a payment
+type refundDesk struct {
+	mutex     sync.Mutex
+	providers map[string]PaymentProvider // payment method -> provider
+}
+
+// SetPaymentProvider sets the provider refunds of the payments taken with a method go through
+func (s *ParkingService) SetPaymentProvider(method string, provider PaymentProvider) {
+	s.refunds.mutex.Lock()
+	defer s.refunds.mutex.Unlock()
+
+	s.refunds.providers[method] = provider
+}
+
+// RefundPayment gives back an amount of a payment, all that is left of it when amount is 0, through
+// the provider of its payment method. The refund is posted to the ledger from the account the money
+// went into to refunds, and is written to the audit log with its reason.
+func (s *ParkingService) RefundPayment(actor string, paymentID int, amount float64, reason string) (repository.Refund, error) {
+	reason = strings.TrimSpace(reason)
+	if amount < 0 || reason == "" {
+		return repository.Refund{}, pkgerrors.ErrInvalidRefund
+	}
+
+	s.refunds.mutex.Lock()
+	defer s.refunds.mutex.Unlock()
+
+	payment, err := s.repo.GetPayment(paymentID)
+	if err != nil {
+		return repository.Refund{}, err
+	}
+	left := payment.Amount - payment.Refunded
+	if amount == 0 {
+		amount = left
+	}
+	if amount <= 0 || amount > left {
+		return repository.Refund{}, fmt.Errorf("%w: %.2f left of payment %d", pkgerrors.ErrInvalidRefund, left, payment.ID)
+	}
+
+	provider, ok := s.refunds.providers[payment.Method]
+	if !ok {
+		return repository.Refund{}, fmt.Errorf("%w: payments by %s cannot be refunded", pkgerrors.ErrInvalidRefund, payment.Method)
+	}
+	if err := provider.Refund(payment, amount); err != nil {
+		return repository.Refund{}, fmt.Errorf("refund of payment %d failed: %w", payment.ID, err)
+	}
+
+	refund, err := s.repo.AddRefund(repository.Refund{
+		PaymentID:  payment.ID,
+		Amount:     amount,
+		Reason:     reason,
+		RefundedBy: actor,
+	})
+	if err != nil {
+		return repository.Refund{}, err
+	}
+
+	if _, err := s.repo.AddLedgerTransaction(repository.LedgerTransaction{
+		Kind:      LedgerRefund,
+		SessionID: payment.SessionID,
+		PaymentID: payment.ID,
+		Lines: []repository.LedgerLine{
+			{Account: AccountRefunds, Debit: refund.Amount},
+			{Account: provider.Account(payment), Credit: refund.Amount},
+		},
+	}); err != nil {
+		return repository.Refund{}, err
+	}
+
+	session, _ := s.repo.GetSession(payment.SessionID)
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:         actor,
+		Action:        "payment.refund",
+		SpotID:        session.SpotID,
+		VehicleNumber: session.VehicleNumber,
+		Reason:        fmt.Sprintf("%.2f of payment %d: %s", refund.Amount, payment.ID, reason),
+	})
+
+	return refund, nil
+}
+
+// GetRefunds returns the refunds of a payment, or of all payments when paymentID is 0
+func (s *ParkingService) GetRefunds(paymentID int) []repository.Refund {
+	return s.repo.GetRefunds(paymentID)
+}
@@ -0,0 +1,68 @@
+package parking
+
+import (
+	"errors"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"testing"
+)
+
+func TestRefundPayment(t *testing.T) {
+	service := newTestService(t, 1)
+	if _, err := service.Park(Automobile, "AB1"); err != nil {
+		t.Fatal(err)
+	}
+	payment, err := service.RecordCashPayment("att", 1, 50000, 5000, "booth-1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.RefundPayment("root", payment.ID, 5000, ""); !errors.Is(err, pkgerrors.ErrInvalidRefund) {
+		t.Fatalf("refund without a reason: got %v, want %v", err, pkgerrors.ErrInvalidRefund)
+	}
+	if _, err := service.RefundPayment("root", payment.ID+1, 5000, "stuck barrier"); !errors.Is(err, pkgerrors.ErrPaymentNotFound) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrPaymentNotFound)
+	}
+
+	// A partial refund, then the rest of the payment
+	if _, err := service.RefundPayment("root", payment.ID, 5000, "stuck barrier"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RefundPayment("root", payment.ID, 40001, "charged twice"); !errors.Is(err, pkgerrors.ErrInvalidRefund) {
+		t.Fatalf("refund beyond the payment: got %v, want %v", err, pkgerrors.ErrInvalidRefund)
+	}
+	rest, err := service.RefundPayment("root", payment.ID, 0, "charged twice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rest.Amount != 40000 {
+		t.Fatalf("got a refund of %.2f, want the 40000 left", rest.Amount)
+	}
+	if _, err := service.RefundPayment("root", payment.ID, 0, "charged twice"); !errors.Is(err, pkgerrors.ErrInvalidRefund) {
+		t.Fatalf("refund of a refunded payment: got %v, want %v", err, pkgerrors.ErrInvalidRefund)
+	}
+
+	ledger := service.GetLedger(1, "")
+	want := []AccountBalance{
+		{Account: CashAccount("booth-1"), Debit: 45000, Credit: 45000},
+		{Account: AccountRefunds, Debit: 45000},
+		{Account: AccountRevenue, Credit: 45000},
+	}
+	if len(ledger.Transactions) != 3 || len(ledger.Balances) != len(want) {
+		t.Fatalf("got %+v, want a payment and two refunds posting to %+v", ledger, want)
+	}
+	for i, balance := range ledger.Balances {
+		if balance != want[i] {
+			t.Errorf("got balance %+v, want %+v", balance, want[i])
+		}
+	}
+
+	refunds := 0
+	for _, entry := range service.repo.GetAuditEntries() {
+		if entry.Action == "payment.refund" && entry.Actor == "root" {
+			refunds++
+		}
+	}
+	if refunds != 2 {
+		t.Fatalf("got %d refunds in the audit log, want 2", refunds)
+	}
+}
@@ -0,0 +1,79 @@
+package parking
+
+import (
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// RevenueTotals holds the parking fees taken and given back in a group of ledger transactions
+type RevenueTotals struct {
+	Revenue float64 // credited to the revenue account
+	Refunds float64 // debited to the refunds account
+}
+
+// Net returns the revenue less the refunds
+func (t RevenueTotals) Net() float64 {
+	return t.Revenue - t.Refunds
+}
+
+func (t *RevenueTotals) add(transaction repository.LedgerTransaction) {
+	for _, line := range transaction.Lines {
+		switch line.Account {
+		case AccountRevenue:
+			t.Revenue += line.Credit - line.Debit
+		case AccountRefunds:
+			t.Refunds += line.Debit - line.Credit
+		}
+	}
+}
+
+// RevenueReport adds up the ledger over a time window. A refund counts on the day it was given and under
+// the payment method and vehicle of the payment it gives back.
+type RevenueReport struct {
+	From         time.Time
+	To           time.Time
+	Total        RevenueTotals
+	Days         map[string]RevenueTotals // day in the lot's time zone, e.g. "2026-10-16" -> totals
+	VehicleTypes map[string]RevenueTotals // vehicle type of the ticket -> totals
+	Methods      map[string]RevenueTotals // payment method -> totals
+}
+
+// GetRevenueReport reports on the ledger transactions recorded in [from, to)
+func (s *ParkingService) GetRevenueReport(from, to time.Time) (RevenueReport, error) {
+	if !from.Before(to) {
+		return RevenueReport{}, pkgerrors.ErrInvalidTimeWindow
+	}
+
+	report := RevenueReport{
+		From:         from,
+		To:           to,
+		Days:         make(map[string]RevenueTotals),
+		VehicleTypes: make(map[string]RevenueTotals),
+		Methods:      make(map[string]RevenueTotals),
+	}
+
+	for _, transaction := range s.repo.GetLedger(repository.LedgerFilter{}) {
+		if transaction.Time.Before(from) || !transaction.Time.Before(to) {
+			continue
+		}
+
+		report.Total.add(transaction)
+		addRevenue(report.Days, transaction.Time.In(s.location).Format(time.DateOnly), transaction)
+		if session, err := s.repo.GetSession(transaction.SessionID); err == nil {
+			addRevenue(report.VehicleTypes, session.VehicleType, transaction)
+		}
+		if payment, err := s.repo.GetPayment(transaction.PaymentID); err == nil {
+			addRevenue(report.Methods, payment.Method, transaction)
+		}
+	}
+
+	return report, nil
+}
+
+// addRevenue adds a transaction to the totals of a key
+func addRevenue(totals map[string]RevenueTotals, key string, transaction repository.LedgerTransaction) {
+	total := totals[key]
+	total.add(transaction)
+	totals[key] = total
+}
@@ -0,0 +1,61 @@
+package parking
+
+import (
+	"errors"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestRevenueReport(t *testing.T) {
+	service := newTestService(t, 2)
+	for _, vehicleNumber := range []string{"AB1", "AB2"} {
+		if _, err := service.Park(Automobile, vehicleNumber); err != nil {
+			t.Fatal(err)
+		}
+	}
+	from := time.Now().Add(-time.Minute)
+
+	payment, err := service.RecordCashPayment("att", 1, 50000, 5000, "booth-1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RecordCashPayment("att", 2, 20000, 0, "booth-2", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.RefundPayment("root", payment.ID, 5000, "stuck barrier"); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := service.GetRevenueReport(from, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RevenueTotals{Revenue: 65000, Refunds: 5000}
+	if report.Total != want || report.Total.Net() != 60000 {
+		t.Fatalf("got total %+v, want %+v", report.Total, want)
+	}
+	if got := report.Methods[PaymentCash]; got != want || len(report.Methods) != 1 {
+		t.Errorf("got payment methods %+v, want cash %+v", report.Methods, want)
+	}
+	if got := report.VehicleTypes[Automobile]; got != want || len(report.VehicleTypes) != 1 {
+		t.Errorf("got vehicle types %+v, want %s %+v", report.VehicleTypes, Automobile, want)
+	}
+	today := time.Now().In(service.location).Format(time.DateOnly)
+	if got := report.Days[today]; got != want || len(report.Days) != 1 {
+		t.Errorf("got days %+v, want %s %+v", report.Days, today, want)
+	}
+
+	// Transactions outside the window are left out
+	report, err = service.GetRevenueReport(from.Add(-time.Hour), from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != (RevenueTotals{}) || len(report.Days) != 0 {
+		t.Fatalf("got %+v before the payments, want nothing", report)
+	}
+
+	if _, err := service.GetRevenueReport(from, from); !errors.Is(err, pkgerrors.ErrInvalidTimeWindow) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrInvalidTimeWindow)
+	}
+}
@@ -0,0 +1,193 @@
+package parking
+
+import (
+	"fmt"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReportSensor records the occupancy reported by a spot's sensor alongside its logical state,
+// together with the battery level and firmware version when the sensor reports them.
+// It returns whether the reading agrees with the logical occupancy of the spot.
+func (s *ParkingService) ReportSensor(spotID string, occupied bool, health repository.SensorHealth) (bool, error) {
+	floor, row, column, err := s.repo.ParseSpotID(spotID)
+	if err != nil {
+		return false, err
+	}
+	if health.Battery > 100 {
+		return false, fmt.Errorf("%w: got %d", pkgerrors.ErrInvalidBatteryLevel, health.Battery)
+	}
+	if health.Firmware != "" {
+		if _, err := parseFirmware(health.Firmware); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.repo.RecordSensorReading(floor, row, column, occupied, health); err != nil {
+		return false, err
+	}
+
+	isOccupied, err := s.repo.IsSpotOccupied(floor, row, column)
+	if err != nil {
+		return false, err
+	}
+
+	return isOccupied == occupied, nil
+}
+
+// DefaultLowBattery is the battery percentage below which a sensor needs maintenance by default
+const DefaultLowBattery = 20
+
+// Sensor maintenance reasons
+const (
+	MaintenanceLowBattery       = "low_battery"
+	MaintenanceOutdatedFirmware = "outdated_firmware"
+)
+
+// SensorPolicy decides which sensors need maintenance
+type SensorPolicy struct {
+	// sensors reporting a battery percentage below it need a battery swap
+	LowBattery int
+	// sensors reporting an older firmware version need an update, no version is required when empty
+	MinFirmware string
+}
+
+// SensorMaintenance is a sensor that needs a battery swap or a firmware update
+type SensorMaintenance struct {
+	SpotID     string
+	Battery    int // -1 when the sensor never reported it
+	Firmware   string
+	ReportedAt time.Time
+	Reasons    []string
+}
+
+// SensorMaintenanceReport lists the sensors needing maintenance under the policy that was applied
+type SensorMaintenanceReport struct {
+	Policy  SensorPolicy
+	Sensors []SensorMaintenance
+}
+
+// SetSensorPolicy sets the battery threshold and minimum firmware version the maintenance report
+// applies by default
+func (s *ParkingService) SetSensorPolicy(policy SensorPolicy) error {
+	if err := validateSensorPolicy(policy); err != nil {
+		return err
+	}
+
+	s.sensorPolicy = policy
+	return nil
+}
+
+// GetSensorMaintenance returns the sensors of active spots whose battery is below the threshold or
+// whose firmware is older than the minimum version, floor by floor. A threshold that is not positive
+// or an empty version falls back to the configured policy.
+func (s *ParkingService) GetSensorMaintenance(policy SensorPolicy) (SensorMaintenanceReport, error) {
+	if policy.LowBattery <= 0 {
+		policy.LowBattery = s.sensorPolicy.LowBattery
+	}
+	if policy.MinFirmware == "" {
+		policy.MinFirmware = s.sensorPolicy.MinFirmware
+	}
+	if err := validateSensorPolicy(policy); err != nil {
+		return SensorMaintenanceReport{}, err
+	}
+
+	var minFirmware []int
+	if policy.MinFirmware != "" {
+		minFirmware, _ = parseFirmware(policy.MinFirmware)
+	}
+
+	maintenance := []SensorMaintenance{}
+	floors, _ := s.repo.GetDimensions()
+	for floor := range floors {
+		spots, err := s.repo.GetFloorSpots(floor)
+		if err != nil {
+			continue
+		}
+
+		for _, row := range spots {
+			for _, spot := range row {
+				if !spot.InService() || spot.Sensor == nil {
+					continue
+				}
+
+				var reasons []string
+				if spot.Sensor.Battery >= 0 && spot.Sensor.Battery < policy.LowBattery {
+					reasons = append(reasons, MaintenanceLowBattery)
+				}
+				if minFirmware != nil && spot.Sensor.Firmware != "" {
+					// Reported versions were validated when recorded
+					firmware, _ := parseFirmware(spot.Sensor.Firmware)
+					if compareFirmware(firmware, minFirmware) < 0 {
+						reasons = append(reasons, MaintenanceOutdatedFirmware)
+					}
+				}
+				if len(reasons) == 0 {
+					continue
+				}
+
+				maintenance = append(maintenance, SensorMaintenance{
+					SpotID:     s.repo.FormatSpotID(spot.Floor, spot.Row, spot.Column),
+					Battery:    spot.Sensor.Battery,
+					Firmware:   spot.Sensor.Firmware,
+					ReportedAt: spot.Sensor.ReportedAt,
+					Reasons:    reasons,
+				})
+			}
+		}
+	}
+
+	return SensorMaintenanceReport{Policy: policy, Sensors: maintenance}, nil
+}
+
+func validateSensorPolicy(policy SensorPolicy) error {
+	if policy.LowBattery < 0 || policy.LowBattery > 100 {
+		return fmt.Errorf("%w: got %d", pkgerrors.ErrInvalidBatteryLevel, policy.LowBattery)
+	}
+	if policy.MinFirmware != "" {
+		if _, err := parseFirmware(policy.MinFirmware); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseFirmware splits a dotted firmware version like 1.4.2 or v1.4.2 into its numbers
+func parseFirmware(version string) ([]int, error) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("%w: got %q", pkgerrors.ErrInvalidFirmwareVersion, version)
+		}
+		numbers = append(numbers, number)
+	}
+
+	return numbers, nil
+}
+
+// compareFirmware orders two parsed firmware versions, missing trailing numbers count as 0
+func compareFirmware(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -3,44 +3,217 @@ package parking
 import (
 	"errors"
 	"fmt"
+	"parking-lot-system/internal/alert"
+	"parking-lot-system/internal/domain/plate"
+	"parking-lot-system/internal/notify"
 	"parking-lot-system/internal/repository"
+	"parking-lot-system/internal/templates"
 	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+	"unicode/utf8"
 )
 
+// number of times a park request looks for another spot when the found one was taken concurrently
+const maxParkAttempts = 3
+
+// shortest plate suffix that can be searched, single characters match too many vehicles
+const minPlateSuffix = 2
+
 type ParkingService struct {
-	repo repository.ParkingRepository
+	repo       repository.ParkingRepository
+	events     eventBus
+	reconciler reconciler
+
+	// whether vehicles may use spots larger than their size class, they never use smaller ones
+	allowLargerSpots bool
+	// how long an unparked vehicle must stay away before it may park again, 0 when it may return right away
+	reentryCooldown time.Duration
+	// how park attempts for a vehicle number that is already parked are handled
+	duplicatePolicy string
+	// how far back the unused spot report looks by default
+	unusedSpotWindow time.Duration
+	// vehicleType -> spot types to fall back to, in order, when its own spots are full
+	fallbacks map[string][]string
+	// vehicle numbers accepted by the lot
+	plates *plate.Scheme
+	// vehicle types accepted by the lot
+	vehicleTypes *vehicleTypeRegistry
+	// spot code -> spot type, and the spot types registered on top of the built-in and vehicle type ones
+	spotTypes       map[string]SpotType
+	customSpotTypes []SpotType
+	// spots reserved at the entry gate until the park is confirmed
+	holds holdRegistry
+	// vehicles waiting in the buffer lane for a spot
+	checkIns checkInQueue
+	// duplicate park attempts retried in the background
+	retries parkRetries
+	// occupancy samples for the time series
+	occupancy occupancyRecorder
+	// where notifications to drivers are delivered, nil when none are sent
+	notifier notify.Notifier
+	// contacts of the parked vehicles whose drivers asked to be notified
+	contacts contactBook
+	// operators notified when few spots are left for a vehicle type
+	availability availabilityAlerts
+	// templates the notifications are rendered with
+	templates *templates.Set
+	// registered devices that went silent
+	deviceHealth deviceHealth
+	// which spot sensors need a battery swap or a firmware update
+	sensorPolicy SensorPolicy
+	// where alerts for operators are sent, nil when none are sent
+	alerts *alert.Dispatcher
+	// the emergency state of the lot
+	incident incidentState
+	// spots reserved for scheduled events
+	blocks blockSchedule
+	// how long a vehicle may stay before the daily report lists its session as stuck
+	stuckAfter time.Duration
+	// signs the tokens giving drivers the wallet pass of their ticket
+	walletKey []byte
+	// time zone of the lot, for the hours and days of reports and the times shown to drivers
+	location *time.Location
+	// bounds the event log the lot state is rebuilt from
+	eventLog eventLog
+	// pay refunds back per payment method
+	refunds refundDesk
 }
 
 func NewParkingService(repo repository.ParkingRepository) *ParkingService {
-	return &ParkingService{
-		repo: repo,
+	// the default vehicle and spot types are valid
+	vehicleTypes, _ := newVehicleTypeRegistry(DefaultVehicleTypes)
+	spotTypes, _ := buildSpotTypes(vehicleTypes, nil)
+
+	service := &ParkingService{
+		repo:             repo,
+		allowLargerSpots: true,
+		duplicatePolicy:  DuplicateReject,
+		unusedSpotWindow: DefaultUnusedSpotWindow,
+		plates:           plate.Generic,
+		vehicleTypes:     vehicleTypes,
+		spotTypes:        spotTypes,
+		holds:            holdRegistry{ttl: DefaultHoldTTL, holds: make(map[int]*Hold)},
+		blocks:           blockSchedule{blocks: make(map[int]*SpotBlock), blocked: make(map[string]bool)},
+		stuckAfter:       DefaultStuckAfter,
+		checkIns:         checkInQueue{checkIns: make(map[int]*CheckIn), wake: make(chan struct{}, 1)},
+		retries:          parkRetries{retries: make(map[int]*ParkRetry), timeout: duplicateRetryTimeout, interval: duplicateRetryInterval},
+		contacts:         contactBook{contacts: make(map[string]string)},
+		templates:        templates.Default,
+		deviceHealth:     deviceHealth{staleAfter: DefaultDeviceStaleAfter, stale: make(map[string]bool)},
+		sensorPolicy:     SensorPolicy{LowBattery: DefaultLowBattery},
+		walletKey:        newWalletKey(),
+		location:         time.Local,
+		eventLog:         eventLog{limit: DefaultEventLogLimit, parked: make(map[string]Event)},
+		refunds:          refundDesk{providers: map[string]PaymentProvider{PaymentCash: cashDrawer{}}},
+	}
+
+	// Waiting check-ins get the spots that are vacated
+	service.AddEventListener(checkInListener{service: service})
+	service.AddEventListener(contactListener{service: service})
+	service.AddEventListener(availabilityListener{service: service})
+
+	return service
+}
+
+// SetLocation sets the time zone of the lot
+func (s *ParkingService) SetLocation(location *time.Location) {
+	s.location = location
+}
+
+// SetPlateScheme sets the vehicle number format accepted by the lot
+func (s *ParkingService) SetPlateScheme(scheme *plate.Scheme) {
+	s.plates = scheme
+}
+
+// SetFallbacks sets the spot types each vehicle type may fall back to, in order of preference,
+// when all spots of its own type are full, e.g. Motorcycle -> [Automobile]
+func (s *ParkingService) SetFallbacks(fallbacks map[string][]string) error {
+	for vehicleType, spotTypes := range fallbacks {
+		if err := s.validateVehicleType(vehicleType); err != nil {
+			return err
+		}
+		for _, spotType := range spotTypes {
+			if err := s.validateVehicleType(spotType); err != nil {
+				return err
+			}
+		}
 	}
+
+	s.fallbacks = fallbacks
+	return nil
+}
+
+// SetAllowLargerSpots sets whether vehicles may park in spots larger than their size class
+// when no spot of their own size is available
+func (s *ParkingService) SetAllowLargerSpots(allow bool) {
+	s.allowLargerSpots = allow
 }
 
-// InitializeParkingLot creates a new parking lot with the specified dimensions
-func (s *ParkingService) InitializeParkingLot(floors, rows, columns, gates int) error {
+// SetReentryCooldown sets how long an unparked vehicle must stay away before it may park again,
+// so the free grace period cannot be extended by leaving and re-entering. 0 disables the rule.
+func (s *ParkingService) SetReentryCooldown(cooldown time.Duration) {
+	s.reentryCooldown = cooldown
+}
+
+// InitializeParkingLot creates a new parking lot with the specified dimensions. Re-initializing a lot
+// fails while vehicles are parked unless force is set, which unparks them.
+func (s *ParkingService) InitializeParkingLot(floors, rows, columns, gates int, force bool) error {
 	// Validate inputs
 	if floors < 1 || floors > 8 {
-		return errors.New("floors must be between 1 and 8")
+		return fmt.Errorf("%w: floors must be between 1 and 8", pkgerrors.ErrInvalidDimensions)
 	}
 	if rows < 1 || rows > 1000 {
-		return errors.New("rows must be between 1 and 1000")
+		return fmt.Errorf("%w: rows must be between 1 and 1000", pkgerrors.ErrInvalidDimensions)
 	}
 	if columns < 1 || columns > 1000 {
-		return errors.New("columns must be between 1 and 1000")
+		return fmt.Errorf("%w: columns must be between 1 and 1000", pkgerrors.ErrInvalidDimensions)
 	}
 	if gates < 1 {
-		return errors.New("gates must be at least 1")
+		return fmt.Errorf("%w: gates must be at least 1", pkgerrors.ErrInvalidDimensions)
 	}
 
-	return s.repo.InitializeParkingLot(floors, rows, columns, gates)
+	if err := s.repo.InitializeParkingLot(floors, rows, columns, gates, force); err != nil {
+		return err
+	}
+	s.clearHolds()
+	s.clearSpotBlocks()
+	s.publish(Event{Type: EventLotInitialized})
+	return nil
 }
 
-// ConfigureSpot sets the type and active status of a specific parking spot
+// InitializeFloors creates a new parking lot where every floor declares its own rows and columns.
+// Re-initializing a lot fails while vehicles are parked unless force is set, which unparks them.
+func (s *ParkingService) InitializeFloors(floors []repository.FloorDimensions, gates int, force bool) error {
+	// Validate inputs
+	if len(floors) < 1 || len(floors) > 8 {
+		return fmt.Errorf("%w: floors must be between 1 and 8", pkgerrors.ErrInvalidDimensions)
+	}
+	for f, dimensions := range floors {
+		if dimensions.Rows < 1 || dimensions.Rows > 1000 {
+			return fmt.Errorf("%w: floor %d: rows must be between 1 and 1000", pkgerrors.ErrInvalidDimensions, f)
+		}
+		if dimensions.Columns < 1 || dimensions.Columns > 1000 {
+			return fmt.Errorf("%w: floor %d: columns must be between 1 and 1000", pkgerrors.ErrInvalidDimensions, f)
+		}
+	}
+	if gates < 1 {
+		return fmt.Errorf("%w: gates must be at least 1", pkgerrors.ErrInvalidDimensions)
+	}
+
+	if err := s.repo.InitializeFloors(floors, gates, force); err != nil {
+		return err
+	}
+	s.clearHolds()
+	s.clearSpotBlocks()
+	s.publish(Event{Type: EventLotInitialized})
+	return nil
+}
+
+// ConfigureSpot sets the type and active status of a specific parking spot from its spot code, e.g. "A-1"
 func (s *ParkingService) ConfigureSpot(floor, row, column int, spotType string) error {
 	// Validate location indices
 	if !s.repo.IsValidLocation(floor, row, column) {
-		return errors.New(pkgerrors.ErrInvalidLocation)
+		return pkgerrors.ErrInvalidLocation
 	}
 
 	// Check if spot is occupied
@@ -50,37 +223,70 @@ func (s *ParkingService) ConfigureSpot(floor, row, column int, spotType string)
 	}
 
 	if isOccupied {
-		return errors.New("cannot reconfigure an occupied parking spot")
+		return pkgerrors.ErrSpotInUse
 	}
 
 	// Validate and set spot type
-	var vehicleType string
-	var isActive bool
+	registered, err := s.spotType(spotType)
+	if err != nil {
+		return err
+	}
 
-	switch spotType {
-	case "B-1":
-		vehicleType = Bicycle
-		isActive = true
-	case "M-1":
-		vehicleType = Motorcycle
-		isActive = true
-	case "A-1":
-		vehicleType = Automobile
-		isActive = true
-	case "X-0":
-		vehicleType = ""
-		isActive = false
-	default:
-		return errors.New(pkgerrors.ErrInvalidSpotType)
+	err = s.repo.ConfigureSpot(floor, row, column, registered.VehicleType, registered.Class, registered.IsActive, registered.Exists)
+	if err != nil {
+		return err
 	}
 
-	return s.repo.ConfigureSpot(floor, row, column, vehicleType, isActive)
+	s.publish(Event{
+		Type:        EventSpotReconfigured,
+		SpotID:      s.repo.FormatSpotID(floor, row, column),
+		VehicleType: registered.VehicleType,
+	})
+
+	return nil
+}
+
+// ParkOptions holds the optional parameters of a park request
+type ParkOptions struct {
+	Gate int    // entry gate the vehicle passes through, 0 when unknown
+	Zone string // preferred zone, other zones are used when it is full
+	Size string // size class of the vehicle, standard when empty
+	// email address or phone number the driver is notified at, e.g. when overstaying, none when empty
+	Contact string
+	// registered device the request comes from, e.g. an entrance kiosk, none when empty.
+	// The vehicle passes through the gate of the device unless Gate is set.
+	Device string
+}
+
+// UnparkOptions holds the optional parameters of an unpark request
+type UnparkOptions struct {
+	Gate   int    // exit gate the vehicle passes through, 0 when unknown
+	Device string // registered device the request comes from, see ParkOptions
 }
 
 // Park assigns a parking spot to a vehicle
 func (s *ParkingService) Park(vehicleType, vehicleNumber string) (string, error) {
+	return s.ParkWithOptions(vehicleType, vehicleNumber, ParkOptions{})
+}
+
+// ParkWithOptions assigns a parking spot to a vehicle. Attempts for a vehicle that is already parked
+// are handled per the duplicate policy.
+func (s *ParkingService) ParkWithOptions(vehicleType, vehicleNumber string, opts ParkOptions) (string, error) {
+	return s.parkWithOptions(vehicleType, vehicleNumber, opts, s.duplicatePolicy)
+}
+
+// parkWithOptions assigns a parking spot to a vehicle, handling attempts for a vehicle that is
+// already parked per duplicatePolicy
+func (s *ParkingService) parkWithOptions(vehicleType, vehicleNumber string, opts ParkOptions, duplicatePolicy string) (string, error) {
+	vehicleNumber = plate.Normalize(vehicleNumber)
+
+	if err := s.checkIncident(); err != nil {
+		return "", err
+	}
+
 	// Validate inputs
-	if err := s.validateVehicleType(vehicleType); err != nil {
+	registered, err := s.vehicleType(vehicleType)
+	if err != nil {
 		return "", err
 	}
 
@@ -88,72 +294,350 @@ func (s *ParkingService) Park(vehicleType, vehicleNumber string) (string, error)
 		return "", err
 	}
 
+	device, err := s.resolveDevice(opts.Device)
+	if err != nil {
+		return "", err
+	}
+	if opts.Gate == 0 {
+		opts.Gate = device.Gate
+	}
+
+	if err := s.validateGate(opts.Gate); err != nil {
+		return "", err
+	}
+
+	if opts.Size == "" {
+		opts.Size = registered.Size
+	}
+	if err := s.validateSize(opts.Size); err != nil {
+		return "", err
+	}
+
+	if err := s.checkReentryCooldown(vehicleNumber); err != nil {
+		return "", err
+	}
+
+	if err := s.validateContact(opts.Contact); err != nil {
+		return "", err
+	}
+
+	// Park the vehicle, attempts for a vehicle that is already parked are handled per the duplicate policy
+	spotID, err := s.park(vehicleType, vehicleNumber, opts)
+	if errors.Is(err, pkgerrors.ErrVehicleAlreadyParked) {
+		spotID, err = s.resolveDuplicate(duplicatePolicy, vehicleType, vehicleNumber, opts, err)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	s.rememberContact(vehicleNumber, opts.Contact)
+	s.publishParked(spotID, vehicleNumber, vehicleType, opts.Gate, opts.Device)
+	return spotID, nil
+}
+
+// park parks a vehicle at an allocated spot unless it is already parked
+func (s *ParkingService) park(vehicleType, vehicleNumber string, opts ParkOptions) (string, error) {
 	// Check if vehicle is already parked
 	isParked, currentSpotID, _ := s.repo.IsVehicleParked(vehicleNumber)
 	if isParked {
-		return "", fmt.Errorf("%s: %s at spot %s", pkgerrors.ErrVehicleAlreadyParked, vehicleNumber, currentSpotID)
+		return "", fmt.Errorf("%w: %s at spot %s", pkgerrors.ErrVehicleAlreadyParked, vehicleNumber, currentSpotID)
 	}
 
-	// Find an available spot
-	spotID, err := s.repo.FindAvailableSpot(vehicleType)
-	if err != nil {
-		return "", errors.New(pkgerrors.ErrNoAvailableSpot)
+	defer allocateAndParkLatency.ObserveSince(time.Now(), vehicleType, AllocationStrategy)
+
+	// A concurrent request may take the found spot first
+	var err error
+	for attempt := 0; attempt < maxParkAttempts; attempt++ {
+		var spotID string
+		spotID, err = s.allocateSpot(vehicleType, opts)
+		if err != nil {
+			return "", err
+		}
+
+		err = s.repo.ParkVehicle(spotID, vehicleNumber, vehicleType)
+		if err == nil {
+			return spotID, nil
+		}
+		if !errors.Is(err, pkgerrors.ErrSpotFull) {
+			break
+		}
 	}
+	return "", err
+}
 
-	// Park the vehicle
-	err = s.repo.ParkVehicle(spotID, vehicleNumber)
+// RecommendSpot returns the spot ParkWithOptions would assign to a vehicle right now without claiming it,
+// e.g. for a preview screen at the entrance. Another vehicle may take the spot before this one parks.
+func (s *ParkingService) RecommendSpot(vehicleType string, opts ParkOptions) (string, error) {
+	// Validate inputs
+	registered, err := s.vehicleType(vehicleType)
 	if err != nil {
 		return "", err
 	}
 
-	return spotID, nil
+	if err := s.validateGate(opts.Gate); err != nil {
+		return "", err
+	}
+
+	if opts.Size == "" {
+		opts.Size = registered.Size
+	}
+	if err := s.validateSize(opts.Size); err != nil {
+		return "", err
+	}
+
+	return s.allocateSpot(vehicleType, opts)
+}
+
+// publishParked announces a parked vehicle, and a full lot when it took the last spot for its type
+func (s *ParkingService) publishParked(spotID, vehicleNumber, vehicleType string, gate int, device string) {
+	s.publish(Event{
+		Type:          EventVehicleParked,
+		SpotID:        spotID,
+		VehicleNumber: vehicleNumber,
+		VehicleType:   vehicleType,
+		Gate:          gate,
+		Device:        device,
+	})
+
+	// Notify when the last spot of the type the vehicle took is gone, which differs from the vehicle
+	// type when it parked at a fallback spot
+	spotType, err := s.spotVehicleType(spotID)
+	if err != nil {
+		return
+	}
+	count := s.repo.GetOccupancyStats().VehicleTypes[spotType]
+	if count.Occupied >= count.Capacity {
+		s.publish(Event{
+			Type:        EventLotFull,
+			VehicleType: spotType,
+		})
+		s.raiseAlert(alert.TypeCapacity, alert.SeverityCritical, "capacity:"+spotType,
+			fmt.Sprintf("No %s spots are left", spotType))
+	}
+}
+
+// spotVehicleType returns the vehicle type a spot is for
+func (s *ParkingService) spotVehicleType(spotID string) (string, error) {
+	floor, row, column, err := s.repo.ParseSpotID(spotID)
+	if err != nil {
+		return "", err
+	}
+	spot, err := s.repo.GetSpot(floor, row, column)
+	if err != nil {
+		return "", err
+	}
+	return spot.VehicleType, nil
 }
 
 // Unpark removes a vehicle from its parking spot
 func (s *ParkingService) Unpark(spotID, vehicleNumber string) error {
+	_, err := s.UnparkWithOptions(spotID, vehicleNumber, UnparkOptions{})
+	return err
+}
+
+// UnparkByPlate removes a vehicle from whichever spot it is parked at and returns that spot's ID
+func (s *ParkingService) UnparkByPlate(vehicleNumber string) (string, error) {
+	return s.UnparkWithOptions("", vehicleNumber, UnparkOptions{})
+}
+
+// UnparkWithOptions removes a vehicle from its parking spot and returns the spot's ID.
+// When spotID is empty the spot is resolved from the vehicle number.
+func (s *ParkingService) UnparkWithOptions(spotID, vehicleNumber string, opts UnparkOptions) (string, error) {
+	vehicleNumber = plate.Normalize(vehicleNumber)
+
 	// Validate inputs
 	if err := s.validateVehicleNumber(vehicleNumber); err != nil {
-		return err
+		return "", err
+	}
+
+	device, err := s.resolveDevice(opts.Device)
+	if err != nil {
+		return "", err
+	}
+	if opts.Gate == 0 {
+		opts.Gate = device.Gate
+	}
+
+	if err := s.validateGate(opts.Gate); err != nil {
+		return "", err
 	}
 
 	// Check if the vehicle is currently parked
 	isParked, currentSpotID, err := s.repo.IsVehicleParked(vehicleNumber)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	if !isParked {
-		return fmt.Errorf("%s: %s", pkgerrors.ErrVehicleNotParked, vehicleNumber)
+		return "", fmt.Errorf("%w: %s", pkgerrors.ErrVehicleNotParked, vehicleNumber)
+	}
+
+	// Flagged vehicles stay until the violation is resolved or fined, unless the lot is being evacuated
+	if !s.GetIncident().Active {
+		if err := s.checkViolations(vehicleNumber); err != nil {
+			return "", err
+		}
 	}
 
 	// Check if the vehicle is at the specified spot
-	if currentSpotID != spotID {
-		return fmt.Errorf("%s: %s (expected: %s, actual: %s)",
+	if spotID == "" {
+		spotID = currentSpotID
+	} else if currentSpotID != spotID {
+		return "", fmt.Errorf("%w: %s (expected: %s, actual: %s)",
 			pkgerrors.ErrVehicleNotAtSpot, vehicleNumber, spotID, currentSpotID)
 	}
 
 	// Parse and validate spotID
 	floor, row, column, err := s.repo.ParseSpotID(spotID)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Unpark the vehicle
-	return s.repo.UnparkVehicle(floor, row, column, vehicleNumber)
+	err = s.repo.UnparkVehicle(floor, row, column, vehicleNumber)
+	if err != nil {
+		return "", err
+	}
+
+	s.publish(Event{
+		Type:          EventVehicleUnparked,
+		SpotID:        spotID,
+		VehicleNumber: vehicleNumber,
+		Gate:          opts.Gate,
+		Device:        opts.Device,
+	})
+
+	return spotID, nil
+}
+
+// allocateSpot finds an available spot of the vehicle's own type, then of its fallback types
+func (s *ParkingService) allocateSpot(vehicleType string, opts ParkOptions) (string, error) {
+	defer findSpotLatency.ObserveSince(time.Now(), vehicleType, AllocationStrategy)
+
+	for _, spotType := range append([]string{vehicleType}, s.fallbacks[vehicleType]...) {
+		// Prefer the requested zone
+		spotID, err := s.findSpot(spotType, opts.Zone, opts.Size)
+		if errors.Is(err, pkgerrors.ErrNoAvailableSpot) && opts.Zone != "" {
+			spotID, err = s.findSpot(spotType, "", opts.Size)
+		}
+		if err == nil {
+			return spotID, nil
+		}
+		// A store that cannot be searched is not a full lot
+		if !errors.Is(err, pkgerrors.ErrNoAvailableSpot) {
+			return "", err
+		}
+	}
+
+	return "", pkgerrors.ErrNoAvailableSpot
 }
 
-// GetAvailableSpots returns the list of available spots for a vehicle type
-func (s *ParkingService) GetAvailableSpots(vehicleType string) ([]string, error) {
+// findSpot finds an available spot a vehicle of the given size may use, preferring the smallest fitting size
+func (s *ParkingService) findSpot(vehicleType, zone, size string) (string, error) {
+	var err error
+	for _, spotSize := range s.usableSizes(size) {
+		var spotID string
+		spotID, err = s.repo.FindAvailableSpot(vehicleType, zone, spotSize)
+		if !errors.Is(err, pkgerrors.ErrNoAvailableSpot) {
+			return spotID, err
+		}
+	}
+	return "", err
+}
+
+// usableSizes returns the spot sizes a vehicle of the given size may park in, smallest first
+func (s *ParkingService) usableSizes(size string) []string {
+	if !s.allowLargerSpots {
+		return []string{size}
+	}
+	for i, spotSize := range SpotSizes {
+		if spotSize == size {
+			return SpotSizes[i:]
+		}
+	}
+	return nil
+}
+
+// GetAvailableSpots returns the list of available spots for a vehicle type,
+// restricted to a zone unless zone is empty and to spots a vehicle of the given size may use unless size is empty
+func (s *ParkingService) GetAvailableSpots(vehicleType, zone, size string) ([]string, error) {
 	// Validate inputs
 	if err := s.validateVehicleType(vehicleType); err != nil {
 		return nil, err
 	}
 
-	return s.repo.GetAvailableSpots(vehicleType)
+	if size == "" {
+		return s.repo.GetAvailableSpots(vehicleType, zone, "")
+	}
+	if err := s.validateSize(size); err != nil {
+		return nil, err
+	}
+
+	availableSpots := []string{}
+	for _, spotSize := range s.usableSizes(size) {
+		spots, err := s.repo.GetAvailableSpots(vehicleType, zone, spotSize)
+		if err == nil {
+			availableSpots = append(availableSpots, spots...)
+		}
+	}
+
+	if len(availableSpots) == 0 {
+		return nil, fmt.Errorf("%w: %s", pkgerrors.ErrNoAvailableSpot, vehicleType)
+	}
+
+	return availableSpots, nil
+}
+
+// ConfigureZone assigns a parking spot to a zone, an empty zone removes it from its zone
+func (s *ParkingService) ConfigureZone(floor, row, column int, zone string) error {
+	if err := s.repo.SetSpotZone(floor, row, column, zone); err != nil {
+		return err
+	}
+
+	s.publish(Event{
+		Type:   EventSpotReconfigured,
+		SpotID: s.repo.FormatSpotID(floor, row, column),
+	})
+
+	return nil
+}
+
+// ConfigureCapacity sets how many vehicles a parking spot holds, e.g. 10 for a bicycle rack
+func (s *ParkingService) ConfigureCapacity(floor, row, column, capacity int) error {
+	if err := s.repo.SetSpotCapacity(floor, row, column, capacity); err != nil {
+		return err
+	}
+
+	s.publish(Event{
+		Type:   EventSpotReconfigured,
+		SpotID: s.repo.FormatSpotID(floor, row, column),
+	})
+
+	return nil
+}
+
+// ConfigureSize sets the size class of a parking spot
+func (s *ParkingService) ConfigureSize(floor, row, column int, size string) error {
+	if err := s.validateSize(size); err != nil {
+		return err
+	}
+
+	if err := s.repo.SetSpotSize(floor, row, column, size); err != nil {
+		return err
+	}
+
+	s.publish(Event{
+		Type:   EventSpotReconfigured,
+		SpotID: s.repo.FormatSpotID(floor, row, column),
+	})
+
+	return nil
 }
 
 // SearchVehicle returns the current or last known spot ID for a vehicle
 func (s *ParkingService) SearchVehicle(vehicleNumber string) (string, bool, error) {
+	vehicleNumber = plate.Normalize(vehicleNumber)
+
 	// Validate inputs
 	if err := s.validateVehicleNumber(vehicleNumber); err != nil {
 		return "", false, err
@@ -162,20 +646,62 @@ func (s *ParkingService) SearchVehicle(vehicleNumber string) (string, bool, erro
 	return s.repo.SearchVehicle(vehicleNumber)
 }
 
-// validateVehicleType checks if the vehicle type is valid
-func (s *ParkingService) validateVehicleType(vehicleType string) error {
-	switch vehicleType {
-	case Bicycle, Motorcycle, Automobile:
+// SearchBySuffix returns the parked vehicles whose number ends with suffix, e.g. the last
+// characters a driver remembers, as candidates for an attendant to check
+func (s *ParkingService) SearchBySuffix(suffix string) ([]repository.ParkedVehicle, error) {
+	suffix = plate.Normalize(suffix)
+	if utf8.RuneCountInString(suffix) < minPlateSuffix {
+		return nil, pkgerrors.ErrInvalidPlateSuffix
+	}
+
+	return s.repo.FindVehiclesBySuffix(suffix), nil
+}
+
+// checkReentryCooldown fails when a vehicle was unparked less than the re-entry cooldown ago
+func (s *ParkingService) checkReentryCooldown(vehicleNumber string) error {
+	if s.reentryCooldown <= 0 {
+		return nil
+	}
+
+	exitTime, exists := s.repo.GetLastExit(vehicleNumber)
+	if !exists {
 		return nil
-	default:
-		return errors.New(pkgerrors.ErrInvalidVehicleType)
 	}
+	if returnTime := exitTime.Add(s.reentryCooldown); time.Now().Before(returnTime) {
+		return fmt.Errorf("%w: %s may park again at %s", pkgerrors.ErrReentryCooldown, vehicleNumber, returnTime.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// validateVehicleType checks if the vehicle type is registered
+func (s *ParkingService) validateVehicleType(vehicleType string) error {
+	_, err := s.vehicleType(vehicleType)
+	return err
 }
 
-// validateVehicleNumber checks if the vehicle number is valid
+// validateVehicleNumber checks if the normalized vehicle number is valid under the lot's plate scheme
 func (s *ParkingService) validateVehicleNumber(vehicleNumber string) error {
-	if vehicleNumber == "" {
-		return errors.New("vehicle number cannot be empty")
+	return s.plates.Validate(vehicleNumber)
+}
+
+// validateSize checks if the size class is valid
+func (s *ParkingService) validateSize(size string) error {
+	if !isSpotSize(size) {
+		return pkgerrors.ErrInvalidSize
 	}
 	return nil
 }
+
+// validateGate checks if the gate exists, 0 stands for an unknown gate
+func (s *ParkingService) validateGate(gate int) error {
+	_, gates := s.repo.GetDimensions()
+	if gate < 0 || gate > gates {
+		return fmt.Errorf("%w: %d", pkgerrors.ErrInvalidGate, gate)
+	}
+	return nil
+}
+
+// ParseSpotID parses a spot ID into floor, row, column
+func (s *ParkingService) ParseSpotID(spotID string) (int, int, int, error) {
+	return s.repo.ParseSpotID(spotID)
+}
@@ -0,0 +1,110 @@
+package parking
+
+import (
+	"fmt"
+	"maps"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sort"
+	"time"
+)
+
+// GetSessions returns all sessions that were running at any point in [from, to)
+func (s *ParkingService) GetSessions(from, to time.Time) ([]repository.Session, error) {
+	if !from.Before(to) {
+		return nil, pkgerrors.ErrInvalidTimeWindow
+	}
+
+	return s.repo.GetSessions(from, to), nil
+}
+
+// ParkedVehicle is a vehicle parked at a spot at some moment, with the event it was parked by
+type ParkedVehicle struct {
+	Floor    int
+	Row      int
+	Column   int
+	Parked   Event
+	ExitTime time.Time // when the vehicle left after the moment, zero while it is still parked
+}
+
+// LotState lists the vehicles that were parked at a moment, ordered by floor, row and column
+type LotState struct {
+	At       time.Time
+	Vehicles []ParkedVehicle
+}
+
+// GetLotState reconstructs which vehicles were parked where at a moment by replaying the event log,
+// restricted to a floor unless floor is negative. Moves by attendant overrides unpark the vehicle and
+// park it again, so the vehicle is found at the spot it was at the moment. The moment must not lie
+// before the last event dropped from the log.
+func (s *ParkingService) GetLotState(at time.Time, floor int) (LotState, error) {
+	// The event log is not trimmed while it is replayed
+	s.eventLog.mutex.Lock()
+	defer s.eventLog.mutex.Unlock()
+
+	if at.Before(s.eventLog.droppedAt) {
+		return LotState{}, fmt.Errorf("%w: events up to %s were dropped", pkgerrors.ErrEventsExpired,
+			s.eventLog.droppedAt.In(s.location).Format(time.RFC3339))
+	}
+
+	parked := maps.Clone(s.eventLog.parked)
+	left := make(map[string]time.Time)
+	records, _ := s.repo.GetEvents(repository.EventFilter{After: s.eventLog.droppedLast})
+	for _, record := range records {
+		event := eventFromRecord(record)
+		if !event.Time.After(at) {
+			ReplayEvent(parked, event)
+			continue
+		}
+
+		// the first time the vehicles parked at the moment left afterwards
+		switch event.Type {
+		case EventVehicleUnparked:
+			if _, ok := parked[event.VehicleNumber]; ok && left[event.VehicleNumber].IsZero() {
+				left[event.VehicleNumber] = event.Time
+			}
+		case EventLotInitialized:
+			for vehicleNumber := range parked {
+				if left[vehicleNumber].IsZero() {
+					left[vehicleNumber] = event.Time
+				}
+			}
+		}
+	}
+
+	state := LotState{At: at, Vehicles: []ParkedVehicle{}}
+	for vehicleNumber, event := range parked {
+		spotFloor, row, column, err := s.repo.ParseSpotID(event.SpotID)
+		if err != nil {
+			// the spot no longer exists since the lot was initialized with other dimensions
+			spotFloor, row, column = -1, -1, -1
+		}
+		if floor >= 0 && spotFloor != floor {
+			continue
+		}
+
+		state.Vehicles = append(state.Vehicles, ParkedVehicle{
+			Floor:    spotFloor,
+			Row:      row,
+			Column:   column,
+			Parked:   event,
+			ExitTime: left[vehicleNumber],
+		})
+	}
+
+	sort.SliceStable(state.Vehicles, func(i, j int) bool {
+		a, b := state.Vehicles[i], state.Vehicles[j]
+		if a.Floor != b.Floor {
+			return a.Floor < b.Floor
+		}
+		if a.Row != b.Row {
+			return a.Row < b.Row
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Parked.ID < b.Parked.ID
+	})
+
+	return state, nil
+}
@@ -0,0 +1,242 @@
+package parking
+
+import (
+	"fmt"
+	"log"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpotBlock reserves spots for a scheduled event, e.g. the VIPs of a stadium match. While the block is
+// active its spots are out of general allocation: they are not found for park, hold and check-in requests
+// and do not count towards capacity. Attendants still park vehicles there with overrides.
+type SpotBlock struct {
+	ID        int
+	Name      string
+	SpotIDs   []string // blocked spots, besides those of the zone
+	Zone      string   // blocks every spot of the zone, empty for none
+	From      time.Time
+	Until     time.Time
+	CreatedBy string
+
+	start, end *time.Timer
+}
+
+// Active reports whether the block takes its spots out of allocation at a time
+func (b SpotBlock) Active(at time.Time) bool {
+	return !at.Before(b.From) && at.Before(b.Until)
+}
+
+// blockSchedule keeps the blocks that have not ended and the spots they block
+type blockSchedule struct {
+	mutex   sync.Mutex
+	nextID  int
+	blocks  map[int]*SpotBlock
+	blocked map[string]bool // spots currently blocked
+}
+
+// BlockSpots schedules a block on behalf of an attendant. It starts right away when From is zero or past,
+// and gives the spots back when Until is reached.
+func (s *ParkingService) BlockSpots(attendant string, block SpotBlock) (SpotBlock, error) {
+	block.Name = strings.TrimSpace(block.Name)
+	block.CreatedBy = attendant
+	if block.From.IsZero() {
+		block.From = time.Now()
+	}
+	if block.Name == "" || (len(block.SpotIDs) == 0 && block.Zone == "") || !block.Until.After(time.Now()) {
+		return SpotBlock{}, pkgerrors.ErrInvalidSpotBlock
+	}
+	if !block.From.Before(block.Until) {
+		return SpotBlock{}, pkgerrors.ErrInvalidTimeWindow
+	}
+
+	spotIDs := make([]string, 0, len(block.SpotIDs))
+	for _, spotID := range block.SpotIDs {
+		floor, row, column, err := s.repo.ParseSpotID(spotID)
+		if err != nil {
+			return SpotBlock{}, err
+		}
+		spot, err := s.repo.GetSpot(floor, row, column)
+		if err != nil {
+			return SpotBlock{}, err
+		}
+		if !spot.Exists {
+			return SpotBlock{}, fmt.Errorf("%w: %s", pkgerrors.ErrSpotDoesNotExist, spotID)
+		}
+		spotIDs = append(spotIDs, s.repo.FormatSpotID(floor, row, column))
+	}
+	block.SpotIDs = spotIDs
+	if block.Zone != "" && len(s.zoneSpots(block.Zone)) == 0 {
+		return SpotBlock{}, fmt.Errorf("%w: zone %s has no spots", pkgerrors.ErrInvalidSpotBlock, block.Zone)
+	}
+
+	s.blocks.mutex.Lock()
+	s.blocks.nextID++
+	block.ID = s.blocks.nextID
+	id := block.ID
+	block.start = time.AfterFunc(time.Until(block.From), s.applySpotBlocks)
+	block.end = time.AfterFunc(time.Until(block.Until), func() { s.endSpotBlock(id) })
+	s.blocks.blocks[id] = &block
+	s.blocks.mutex.Unlock()
+
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:  attendant,
+		Action: "spot.block",
+		Reason: fmt.Sprintf("%s, %s to %s", block.Name, block.From.Format(time.RFC3339), block.Until.Format(time.RFC3339)),
+	})
+
+	// Blocks starting now take their spots before the response
+	if block.Active(time.Now()) {
+		s.applySpotBlocks()
+	}
+
+	return block, nil
+}
+
+// CancelSpotBlock removes a block on behalf of an attendant, giving its spots back right away
+func (s *ParkingService) CancelSpotBlock(attendant string, id int) (SpotBlock, error) {
+	s.blocks.mutex.Lock()
+	block, exists := s.blocks.blocks[id]
+	if !exists {
+		s.blocks.mutex.Unlock()
+		return SpotBlock{}, fmt.Errorf("%w: %d", pkgerrors.ErrSpotBlockNotFound, id)
+	}
+	block.start.Stop()
+	block.end.Stop()
+	delete(s.blocks.blocks, id)
+	s.blocks.mutex.Unlock()
+
+	s.repo.AppendAudit(repository.AuditEntry{Actor: attendant, Action: "spot.unblock", Reason: block.Name})
+	s.applySpotBlocks()
+
+	return *block, nil
+}
+
+// GetSpotBlocks returns the blocks that have not ended, ordered by start
+func (s *ParkingService) GetSpotBlocks() []SpotBlock {
+	s.blocks.mutex.Lock()
+	defer s.blocks.mutex.Unlock()
+
+	blocks := make([]SpotBlock, 0, len(s.blocks.blocks))
+	for _, block := range s.blocks.blocks {
+		blocks = append(blocks, *block)
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		if !blocks[i].From.Equal(blocks[j].From) {
+			return blocks[i].From.Before(blocks[j].From)
+		}
+		return blocks[i].ID < blocks[j].ID
+	})
+
+	return blocks
+}
+
+// endSpotBlock removes a block whose window is over and gives its spots back
+func (s *ParkingService) endSpotBlock(id int) {
+	s.blocks.mutex.Lock()
+	block, exists := s.blocks.blocks[id]
+	delete(s.blocks.blocks, id)
+	s.blocks.mutex.Unlock()
+
+	if exists {
+		log.Printf("Spot block %d (%s) ended", id, block.Name)
+		s.applySpotBlocks()
+	}
+}
+
+// applySpotBlocks blocks the spots of the active blocks and gives back the spots no active block covers.
+// A spot in several blocks stays blocked until the last of them ends.
+func (s *ParkingService) applySpotBlocks() {
+	s.blocks.mutex.Lock()
+
+	now := time.Now()
+	wanted := make(map[string]bool)
+	for _, block := range s.blocks.blocks {
+		if !block.Active(now) {
+			continue
+		}
+		for _, spotID := range block.SpotIDs {
+			wanted[spotID] = true
+		}
+		if block.Zone != "" {
+			for _, spotID := range s.zoneSpots(block.Zone) {
+				wanted[spotID] = true
+			}
+		}
+	}
+
+	var changed []string
+	for spotID := range wanted {
+		if !s.blocks.blocked[spotID] {
+			changed = append(changed, spotID)
+		}
+	}
+	for spotID := range s.blocks.blocked {
+		if !wanted[spotID] {
+			changed = append(changed, spotID)
+		}
+	}
+
+	var events []Event
+	for _, spotID := range changed {
+		floor, row, column, err := s.repo.ParseSpotID(spotID)
+		if err == nil {
+			err = s.repo.SetSpotBlocked(floor, row, column, wanted[spotID])
+		}
+		if err != nil {
+			log.Printf("Error blocking spot %s: %v", spotID, err)
+			continue
+		}
+		if wanted[spotID] {
+			s.blocks.blocked[spotID] = true
+		} else {
+			delete(s.blocks.blocked, spotID)
+		}
+
+		spot, _ := s.repo.GetSpot(floor, row, column)
+		events = append(events, Event{Type: EventSpotReconfigured, SpotID: spotID, VehicleType: spot.VehicleType})
+	}
+	s.blocks.mutex.Unlock()
+
+	for _, event := range events {
+		s.publish(event)
+	}
+}
+
+// clearSpotBlocks drops every block, e.g. when the lot is re-initialized and their spots are gone, so a
+// block does not take a new spot with the same ID
+func (s *ParkingService) clearSpotBlocks() {
+	s.blocks.mutex.Lock()
+	defer s.blocks.mutex.Unlock()
+
+	for _, block := range s.blocks.blocks {
+		block.start.Stop()
+		block.end.Stop()
+	}
+	s.blocks.blocks = make(map[int]*SpotBlock)
+	s.blocks.blocked = make(map[string]bool)
+}
+
+// zoneSpots returns the IDs of the spots of a zone
+func (s *ParkingService) zoneSpots(zone string) []string {
+	var spotIDs []string
+	floors, _ := s.repo.GetDimensions()
+	for floor := range floors {
+		spots, err := s.repo.GetFloorSpots(floor)
+		if err != nil {
+			continue
+		}
+		for _, row := range spots {
+			for _, spot := range row {
+				if spot.Exists && spot.Zone == zone {
+					spotIDs = append(spotIDs, s.repo.FormatSpotID(spot.Floor, spot.Row, spot.Column))
+				}
+			}
+		}
+	}
+	return spotIDs
+}
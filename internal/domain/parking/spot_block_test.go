@@ -0,0 +1,48 @@
+package parking
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReinitializingDropsSpotBlocks(t *testing.T) {
+	service := newTestService(t, 4)
+	block := SpotBlock{Name: "VIP", SpotIDs: []string{"0-0-0", "0-0-1"}, Until: time.Now().Add(time.Hour)}
+	if _, err := service.BlockSpots("att", block); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := service.GetOccupancyStats()
+	if stats.Total.Capacity != 2 || stats.Total.Blocked != 2 {
+		t.Fatalf("got capacity %d and %d blocked, want 2 and 2", stats.Total.Capacity, stats.Total.Blocked)
+	}
+
+	// The new lot starts without blocks, whatever spot IDs it shares with the old one
+	if err := service.InitializeParkingLot(1, 1, 4, 1, true); err != nil {
+		t.Fatal(err)
+	}
+	for col := 0; col < 4; col++ {
+		if err := service.ConfigureSpot(0, 0, col, "A-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if blocks := service.GetSpotBlocks(); len(blocks) != 0 {
+		t.Fatalf("got blocks %+v after the lot was initialized, want none", blocks)
+	}
+	stats = service.GetOccupancyStats()
+	if stats.Total.Capacity != 4 || stats.Total.Blocked != 0 {
+		t.Fatalf("got capacity %d and %d blocked, want 4 and none", stats.Total.Capacity, stats.Total.Blocked)
+	}
+
+	// Blocking the same spots again takes them out of capacity again
+	if _, err := service.BlockSpots("att", block); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := service.ParkWithOptions(Automobile, "AB1", ParkOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	stats = service.GetOccupancyStats()
+	if stats.Total.Capacity != 2 || stats.Total.Occupied != 1 || stats.Total.Blocked != 2 {
+		t.Fatalf("got %+v, want capacity 2 with 1 occupied and 2 blocked", stats.Total)
+	}
+}
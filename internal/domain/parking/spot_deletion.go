@@ -0,0 +1,102 @@
+package parking
+
+import (
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+)
+
+// DeletedSpot describes a soft-deleted spot with the configuration it gets back when restored
+type DeletedSpot struct {
+	SpotID      string
+	VehicleType string
+	Class       string
+	IsActive    bool
+	Zone        string
+	Size        string
+	Capacity    int
+	TimesUsed   int
+}
+
+// DeleteSpot soft-deletes a spot on behalf of an attendant. The spot keeps its configuration and usage
+// history but takes no vehicles or holds and does not count towards capacity until it is restored.
+func (s *ParkingService) DeleteSpot(attendant, spotID, reason string) error {
+	return s.setSpotDeleted(attendant, spotID, reason, true)
+}
+
+// RestoreSpot brings a soft-deleted spot back into service with the configuration it had
+func (s *ParkingService) RestoreSpot(attendant, spotID, reason string) error {
+	return s.setSpotDeleted(attendant, spotID, reason, false)
+}
+
+func (s *ParkingService) setSpotDeleted(attendant, spotID, reason string, deleted bool) error {
+	if reason == "" {
+		return pkgerrors.ErrReasonRequired
+	}
+
+	floor, row, column, err := s.repo.ParseSpotID(spotID)
+	if err != nil {
+		return err
+	}
+
+	spot, err := s.repo.GetSpot(floor, row, column)
+	if err != nil {
+		return err
+	}
+	if spot.Deleted == deleted {
+		return nil
+	}
+
+	if err := s.repo.SetSpotDeleted(floor, row, column, deleted); err != nil {
+		return err
+	}
+
+	action := "spot.restore"
+	if deleted {
+		action = "spot.delete"
+	}
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:  attendant,
+		Action: action,
+		SpotID: s.repo.FormatSpotID(floor, row, column),
+		Reason: reason,
+	})
+
+	s.publish(Event{
+		Type:        EventSpotReconfigured,
+		SpotID:      s.repo.FormatSpotID(floor, row, column),
+		VehicleType: spot.VehicleType,
+	})
+
+	return nil
+}
+
+// GetDeletedSpots returns the soft-deleted spots ordered by floor, row and column
+func (s *ParkingService) GetDeletedSpots() []DeletedSpot {
+	deleted := []DeletedSpot{}
+	floors, _ := s.repo.GetDimensions()
+	for floor := range floors {
+		spots, err := s.repo.GetFloorSpots(floor)
+		if err != nil {
+			continue
+		}
+		for _, row := range spots {
+			for _, spot := range row {
+				if !spot.Deleted {
+					continue
+				}
+				deleted = append(deleted, DeletedSpot{
+					SpotID:      s.repo.FormatSpotID(spot.Floor, spot.Row, spot.Column),
+					VehicleType: spot.VehicleType,
+					Class:       spot.Class,
+					IsActive:    spot.IsActive,
+					Zone:        spot.Zone,
+					Size:        spot.Size,
+					Capacity:    spot.Capacity,
+					TimesUsed:   spot.TimesUsed,
+				})
+			}
+		}
+	}
+
+	return deleted
+}
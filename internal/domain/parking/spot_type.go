@@ -0,0 +1,100 @@
+package parking
+
+import (
+	"fmt"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sort"
+	"strings"
+)
+
+// SpotType describes the spot a spot code configures
+type SpotType struct {
+	Code        string // e.g. "A-1", "E-1"
+	VehicleType string // type of the vehicles parking at the spot, empty for inactive spots and cells without a spot
+	Class       string // e.g. "ev", "accessible", "vip", empty for regular spots
+	IsActive    bool
+	Exists      bool // false for grid cells without a spot (pillars, ramps, stairwells)
+}
+
+// spot types every lot has, whatever its vehicle types
+var builtinSpotTypes = []SpotType{
+	{Code: InactiveSpotCode, Exists: true},
+	{Code: NoSpotCode},
+}
+
+// buildSpotTypes returns the spot types by code: the built-in ones, a regular spot per vehicle type
+// and the custom ones, which must park registered vehicle types
+func buildSpotTypes(vehicleTypes *vehicleTypeRegistry, custom []SpotType) (map[string]SpotType, error) {
+	spotTypes := make(map[string]SpotType)
+
+	all := append([]SpotType(nil), builtinSpotTypes...)
+	for _, vehicleType := range vehicleTypes.types {
+		all = append(all, SpotType{
+			Code:        vehicleType.SpotCode,
+			VehicleType: vehicleType.Name,
+			IsActive:    true,
+			Exists:      true,
+		})
+	}
+	all = append(all, custom...)
+
+	for _, spotType := range all {
+		if spotType.Code == "" {
+			return nil, fmt.Errorf("%w: spot code is required", pkgerrors.ErrInvalidSpotTypeConfig)
+		}
+		if _, exists := spotTypes[spotType.Code]; exists {
+			return nil, fmt.Errorf("%w: spot code %s is registered twice", pkgerrors.ErrInvalidSpotTypeConfig, spotType.Code)
+		}
+		if spotType.IsActive && !spotType.Exists {
+			return nil, fmt.Errorf("%w: spot code %s is active but has no spot", pkgerrors.ErrInvalidSpotTypeConfig, spotType.Code)
+		}
+		if spotType.IsActive {
+			if _, ok := vehicleTypes.byName[spotType.VehicleType]; !ok {
+				return nil, fmt.Errorf("%w: spot code %s parks unknown vehicle type %q",
+					pkgerrors.ErrInvalidSpotTypeConfig, spotType.Code, spotType.VehicleType)
+			}
+		}
+		spotTypes[spotType.Code] = spotType
+	}
+
+	return spotTypes, nil
+}
+
+// RegisterSpotTypes adds spot types, e.g. an "E-1" EV charging spot for automobiles.
+// It must be called before the lot is configured.
+func (s *ParkingService) RegisterSpotTypes(types []SpotType) error {
+	custom := append(append([]SpotType(nil), s.customSpotTypes...), types...)
+	spotTypes, err := buildSpotTypes(s.vehicleTypes, custom)
+	if err != nil {
+		return err
+	}
+
+	s.spotTypes = spotTypes
+	s.customSpotTypes = custom
+	return nil
+}
+
+// GetSpotTypes returns the known spot types ordered by code
+func (s *ParkingService) GetSpotTypes() []SpotType {
+	spotTypes := make([]SpotType, 0, len(s.spotTypes))
+	for _, spotType := range s.spotTypes {
+		spotTypes = append(spotTypes, spotType)
+	}
+	sort.Slice(spotTypes, func(i, j int) bool {
+		return spotTypes[i].Code < spotTypes[j].Code
+	})
+	return spotTypes
+}
+
+// spotType returns the spot type with the given code
+func (s *ParkingService) spotType(code string) (SpotType, error) {
+	spotType, ok := s.spotTypes[code]
+	if !ok {
+		codes := []string{}
+		for _, known := range s.GetSpotTypes() {
+			codes = append(codes, known.Code)
+		}
+		return SpotType{}, fmt.Errorf("%w: %q, must be one of %s", pkgerrors.ErrInvalidSpotType, code, strings.Join(codes, ", "))
+	}
+	return spotType, nil
+}
@@ -0,0 +1,123 @@
+package parking
+
+import (
+	"sort"
+	"time"
+)
+
+// Spot usage orders
+const (
+	SpotUsageByID      = ""           // floor by floor, row by row
+	SpotUsageLeastUsed = "least-used" // least used first, e.g. to find dead spots
+	SpotUsageMostUsed  = "most-used"  // most used first, e.g. to rotate wear
+)
+
+// SpotUsageOrders lists the supported spot usage orders
+var SpotUsageOrders = []string{SpotUsageByID, SpotUsageLeastUsed, SpotUsageMostUsed}
+
+// SpotUsage holds the usage totals of a spot since the lot was initialized
+type SpotUsage struct {
+	SpotID       string
+	Floor        int
+	Row          int
+	Column       int
+	VehicleType  string
+	IsActive     bool
+	TimesUsed    int
+	OccupiedTime time.Duration // summed over the vehicles, including the ones still parked
+}
+
+// GetSpotUsage returns the usage totals of every spot in the given order, ties are ordered by spot.
+// Spots are used more when more vehicles parked at them, or when as many stayed longer.
+func (s *ParkingService) GetSpotUsage(order string) []SpotUsage {
+	now := time.Now()
+
+	// Sessions running now add the time their vehicle has been parked so far
+	parkedTime := make(map[string]time.Duration)
+	for _, session := range s.repo.GetSessions(now, now) {
+		parkedTime[session.SpotID] += session.Duration(now)
+	}
+
+	usages := []SpotUsage{}
+	floors, _ := s.repo.GetDimensions()
+	for floor := range floors {
+		spots, err := s.repo.GetFloorSpots(floor)
+		if err != nil {
+			continue
+		}
+		for _, row := range spots {
+			for _, spot := range row {
+				if !spot.Exists {
+					continue
+				}
+
+				spotID := s.repo.FormatSpotID(spot.Floor, spot.Row, spot.Column)
+				usages = append(usages, SpotUsage{
+					SpotID:       spotID,
+					Floor:        spot.Floor,
+					Row:          spot.Row,
+					Column:       spot.Column,
+					VehicleType:  spot.VehicleType,
+					IsActive:     spot.InService(),
+					TimesUsed:    spot.TimesUsed,
+					OccupiedTime: spot.OccupiedTime + parkedTime[spotID],
+				})
+			}
+		}
+	}
+
+	moreUsed := func(a, b SpotUsage) bool {
+		if a.TimesUsed != b.TimesUsed {
+			return a.TimesUsed > b.TimesUsed
+		}
+		return a.OccupiedTime > b.OccupiedTime
+	}
+	switch order {
+	case SpotUsageLeastUsed:
+		sort.SliceStable(usages, func(i, j int) bool { return moreUsed(usages[j], usages[i]) })
+	case SpotUsageMostUsed:
+		sort.SliceStable(usages, func(i, j int) bool { return moreUsed(usages[i], usages[j]) })
+	}
+
+	return usages
+}
+
+// DefaultUnusedSpotWindow is how far back the unused spot report looks by default
+const DefaultUnusedSpotWindow = 7 * 24 * time.Hour
+
+// UnusedSpots lists the active spots no vehicle was parked at within a window
+type UnusedSpots struct {
+	From  time.Time
+	To    time.Time
+	Spots []SpotUsage // the usage totals since the lot was initialized
+}
+
+// SetUnusedSpotWindow sets how far back the unused spot report looks by default
+func (s *ParkingService) SetUnusedSpotWindow(window time.Duration) {
+	s.unusedSpotWindow = window
+}
+
+// GetUnusedSpots returns the active spots no vehicle was parked at within the last window, or within
+// the configured default window when it is not positive. Such spots usually have a broken sensor or
+// missing signage. Spots held at the gate count as unused until the park is confirmed.
+func (s *ParkingService) GetUnusedSpots(window time.Duration) UnusedSpots {
+	if window <= 0 {
+		window = s.unusedSpotWindow
+	}
+	to := time.Now()
+	from := to.Add(-window)
+
+	used := make(map[string]bool)
+	for _, session := range s.repo.GetSessions(from, to) {
+		used[session.SpotID] = true
+	}
+
+	unused := UnusedSpots{From: from, To: to, Spots: []SpotUsage{}}
+	for _, usage := range s.GetSpotUsage(SpotUsageByID) {
+		if usage.IsActive && !used[usage.SpotID] {
+			unused.Spots = append(unused.Spots, usage)
+		}
+	}
+
+	return unused
+}
@@ -0,0 +1,20 @@
+package parking
+
+import "parking-lot-system/internal/repository"
+
+// GetOccupancyStats returns the current occupancy overall, per floor and per vehicle type
+func (s *ParkingService) GetOccupancyStats() repository.OccupancyStats {
+	return s.repo.GetOccupancyStats()
+}
+
+// GetAvailableCount returns the number of vehicles that can still park, restricted to
+// a vehicle type unless it is empty and to a floor unless floor is negative
+func (s *ParkingService) GetAvailableCount(vehicleType string, floor int) (int, error) {
+	if vehicleType != "" {
+		if err := s.validateVehicleType(vehicleType); err != nil {
+			return 0, err
+		}
+	}
+
+	return s.repo.GetAvailableCount(vehicleType, floor)
+}
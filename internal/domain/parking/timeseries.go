@@ -0,0 +1,171 @@
+package parking
+
+import (
+	"fmt"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sync"
+	"time"
+)
+
+// OccupancySample is the occupancy overall and per floor at a point in time
+type OccupancySample struct {
+	At     time.Time
+	Total  repository.OccupancyCount
+	Floors []repository.OccupancyCount
+}
+
+// OccupancyLevel is the occupancy of a group of spots averaged over a time series step
+type OccupancyLevel struct {
+	Capacity int     // at the end of the step
+	Occupied float64 // averaged over the samples taken within the step
+}
+
+// OccupancyPoint is the occupancy overall and per floor within a time series step
+type OccupancyPoint struct {
+	At     time.Time // start of the step
+	Total  OccupancyLevel
+	Floors []OccupancyLevel
+}
+
+// occupancyRecorder keeps the latest occupancy samples in a ring buffer, oldest first from next on
+type occupancyRecorder struct {
+	mutex    sync.RWMutex
+	interval time.Duration
+	samples  []OccupancySample
+	next     int
+	full     bool
+}
+
+// record stores a sample, overwriting the oldest one once the buffer is full
+func (r *occupancyRecorder) record(sample OccupancySample) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.samples[r.next] = sample
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// within returns the samples taken within [from, to), oldest first
+func (r *occupancyRecorder) within(from, to time.Time) []OccupancySample {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	ordered := r.samples[:r.next]
+	if r.full {
+		ordered = append(append([]OccupancySample(nil), r.samples[r.next:]...), r.samples[:r.next]...)
+	}
+
+	samples := []OccupancySample{}
+	for _, sample := range ordered {
+		if !sample.At.Before(from) && sample.At.Before(to) {
+			samples = append(samples, sample)
+		}
+	}
+	return samples
+}
+
+// StartOccupancySampling records the occupancy overall and per floor every interval in the background,
+// keeping the samples of the last retention period
+func (s *ParkingService) StartOccupancySampling(interval, retention time.Duration) {
+	size := int(retention / interval)
+	if size < 1 {
+		size = 1
+	}
+
+	s.occupancy.mutex.Lock()
+	s.occupancy.interval = interval
+	s.occupancy.samples = make([]OccupancySample, size)
+	s.occupancy.mutex.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		s.sampleOccupancy()
+		for range ticker.C {
+			s.sampleOccupancy()
+		}
+	}()
+}
+
+// sampleOccupancy records the current occupancy
+func (s *ParkingService) sampleOccupancy() {
+	stats := s.repo.GetOccupancyStats()
+	s.occupancy.record(OccupancySample{
+		At:     time.Now(),
+		Total:  stats.Total,
+		Floors: stats.Floors,
+	})
+}
+
+// GetOccupancyTimeSeries returns the recorded occupancy within [from, to) in steps of the given length,
+// every sample its own point when step is 0. Steps without samples, e.g. before sampling started
+// or beyond the retention period, are left out, so there are never more points than samples.
+func (s *ParkingService) GetOccupancyTimeSeries(from, to time.Time, step time.Duration) ([]OccupancyPoint, error) {
+	if !from.Before(to) {
+		return nil, pkgerrors.ErrInvalidTimeWindow
+	}
+
+	s.occupancy.mutex.RLock()
+	interval := s.occupancy.interval
+	s.occupancy.mutex.RUnlock()
+
+	if step == 0 {
+		step = interval
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("%w: %v", pkgerrors.ErrInvalidStep, step)
+	}
+
+	points := []OccupancyPoint{}
+	var samples []OccupancySample
+	flush := func() {
+		if len(samples) > 0 {
+			points = append(points, averageOccupancy(from.Add(samples[0].At.Sub(from)/step*step), samples))
+		}
+		samples = nil
+	}
+	for _, sample := range s.occupancy.within(from, to) {
+		if len(samples) > 0 && sample.At.Sub(from)/step != samples[0].At.Sub(from)/step {
+			flush()
+		}
+		samples = append(samples, sample)
+	}
+	flush()
+
+	return points, nil
+}
+
+// averageOccupancy averages the samples of a step, the floors are the ones of the latest sample
+// as floors may change when the lot is re-initialized
+func averageOccupancy(at time.Time, samples []OccupancySample) OccupancyPoint {
+	latest := samples[len(samples)-1]
+	point := OccupancyPoint{
+		At:     at,
+		Total:  OccupancyLevel{Capacity: latest.Total.Capacity},
+		Floors: make([]OccupancyLevel, len(latest.Floors)),
+	}
+
+	floorSamples := make([]int, len(latest.Floors))
+	for _, sample := range samples {
+		point.Total.Occupied += float64(sample.Total.Occupied)
+		for floor, count := range sample.Floors {
+			if floor < len(point.Floors) {
+				point.Floors[floor].Occupied += float64(count.Occupied)
+				floorSamples[floor]++
+			}
+		}
+	}
+
+	point.Total.Occupied /= float64(len(samples))
+	for floor := range point.Floors {
+		point.Floors[floor].Capacity = latest.Floors[floor].Capacity
+		point.Floors[floor].Occupied /= float64(floorSamples[floor])
+	}
+
+	return point
+}
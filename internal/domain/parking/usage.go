@@ -0,0 +1,62 @@
+package parking
+
+import (
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// StayStats holds the stay duration statistics of a vehicle type
+type StayStats struct {
+	CompletedSessions int
+	AverageStay       time.Duration
+}
+
+// UsageStats holds the usage analytics of the parking lot over a time window
+type UsageStats struct {
+	From           time.Time
+	To             time.Time
+	HourlyArrivals [24]int              // arrivals per hour of day
+	StayByType     map[string]StayStats // vehicleType -> stay statistics
+	SpotTurnover   map[string]int       // spotID -> sessions started in the window
+}
+
+// GetUsageStats aggregates the sessions that started within [from, to) into usage analytics
+func (s *ParkingService) GetUsageStats(from, to time.Time) (UsageStats, error) {
+	if !from.Before(to) {
+		return UsageStats{}, pkgerrors.ErrInvalidTimeWindow
+	}
+
+	stats := UsageStats{
+		From:         from,
+		To:           to,
+		StayByType:   make(map[string]StayStats),
+		SpotTurnover: make(map[string]int),
+	}
+	totalStay := make(map[string]time.Duration)
+
+	for _, session := range s.repo.GetSessions(from, to) {
+		// Only count arrivals within the window
+		if session.EntryTime.Before(from) {
+			continue
+		}
+
+		stats.HourlyArrivals[session.EntryTime.In(s.location).Hour()]++
+		stats.SpotTurnover[session.SpotID]++
+
+		if session.IsActive() {
+			continue
+		}
+
+		stay := stats.StayByType[session.VehicleType]
+		stay.CompletedSessions++
+		totalStay[session.VehicleType] += session.Duration(to)
+		stats.StayByType[session.VehicleType] = stay
+	}
+
+	for vehicleType, stay := range stats.StayByType {
+		stay.AverageStay = totalStay[vehicleType] / time.Duration(stay.CompletedSessions)
+		stats.StayByType[vehicleType] = stay
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,119 @@
+package parking
+
+import (
+	"fmt"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strings"
+)
+
+// VehicleType describes a kind of vehicle the lot accepts
+type VehicleType struct {
+	Name     string  // e.g. "Automobile", "Truck", "EV"
+	SpotCode string  // code of the spots it parks in, e.g. "A-1"
+	Size     string  // size class assumed when a park request does not give one
+	Rate     float64 // default hourly rate
+}
+
+// DefaultVehicleTypes are the vehicle types every lot accepts unless configured otherwise
+var DefaultVehicleTypes = []VehicleType{
+	{Name: Bicycle, SpotCode: "B-1", Size: SizeStandard, Rate: 0.5},
+	{Name: Motorcycle, SpotCode: "M-1", Size: SizeStandard, Rate: 1},
+	{Name: Automobile, SpotCode: "A-1", Size: SizeStandard, Rate: 2},
+}
+
+// vehicleTypeRegistry holds the accepted vehicle types in the order they were registered
+type vehicleTypeRegistry struct {
+	types  []VehicleType
+	byName map[string]VehicleType
+	byCode map[string]VehicleType // spot code -> vehicle type
+}
+
+func newVehicleTypeRegistry(types []VehicleType) (*vehicleTypeRegistry, error) {
+	registry := &vehicleTypeRegistry{
+		byName: make(map[string]VehicleType),
+		byCode: make(map[string]VehicleType),
+	}
+
+	for _, vehicleType := range types {
+		if vehicleType.Name == "" || vehicleType.SpotCode == "" {
+			return nil, fmt.Errorf("%w: name and spot code are required", pkgerrors.ErrInvalidVehicleTypeConfig)
+		}
+		if _, exists := registry.byName[vehicleType.Name]; exists {
+			return nil, fmt.Errorf("%w: %s is registered twice", pkgerrors.ErrInvalidVehicleTypeConfig, vehicleType.Name)
+		}
+		if other, exists := registry.byCode[vehicleType.SpotCode]; exists {
+			return nil, fmt.Errorf("%w: spot code %s is used by %s and %s",
+				pkgerrors.ErrInvalidVehicleTypeConfig, vehicleType.SpotCode, other.Name, vehicleType.Name)
+		}
+		if vehicleType.Size == "" {
+			vehicleType.Size = SizeStandard
+		}
+		if !isSpotSize(vehicleType.Size) {
+			return nil, fmt.Errorf("%w: %s of %s", pkgerrors.ErrInvalidSize, vehicleType.Size, vehicleType.Name)
+		}
+		if vehicleType.Rate < 0 {
+			return nil, fmt.Errorf("%w: negative rate of %s", pkgerrors.ErrInvalidVehicleTypeConfig, vehicleType.Name)
+		}
+
+		registry.types = append(registry.types, vehicleType)
+		registry.byName[vehicleType.Name] = vehicleType
+		registry.byCode[vehicleType.SpotCode] = vehicleType
+	}
+
+	return registry, nil
+}
+
+// names returns the names of the registered vehicle types, e.g. "Bicycle, Motorcycle, Automobile"
+func (r *vehicleTypeRegistry) names() string {
+	names := make([]string, len(r.types))
+	for i, vehicleType := range r.types {
+		names[i] = vehicleType.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// RegisterVehicleTypes adds vehicle types to the accepted ones, a type with the name of an accepted
+// type replaces it. It must be called before the lot is configured.
+func (s *ParkingService) RegisterVehicleTypes(types []VehicleType) error {
+	merged := append([]VehicleType(nil), s.vehicleTypes.types...)
+	for _, vehicleType := range types {
+		if _, exists := s.vehicleTypes.byName[vehicleType.Name]; !exists {
+			merged = append(merged, vehicleType)
+			continue
+		}
+		for i := range merged {
+			if merged[i].Name == vehicleType.Name {
+				merged[i] = vehicleType
+			}
+		}
+	}
+
+	registry, err := newVehicleTypeRegistry(merged)
+	if err != nil {
+		return err
+	}
+
+	// the spot codes of the vehicle types must not clash with the other spot types
+	spotTypes, err := buildSpotTypes(registry, s.customSpotTypes)
+	if err != nil {
+		return err
+	}
+
+	s.vehicleTypes = registry
+	s.spotTypes = spotTypes
+	return nil
+}
+
+// GetVehicleTypes returns the accepted vehicle types in the order they were registered
+func (s *ParkingService) GetVehicleTypes() []VehicleType {
+	return append([]VehicleType(nil), s.vehicleTypes.types...)
+}
+
+// vehicleType returns the registered vehicle type with the given name
+func (s *ParkingService) vehicleType(name string) (VehicleType, error) {
+	vehicleType, ok := s.vehicleTypes.byName[name]
+	if !ok {
+		return VehicleType{}, fmt.Errorf("%w: %q, must be one of %s", pkgerrors.ErrInvalidVehicleType, name, s.vehicleTypes.names())
+	}
+	return vehicleType, nil
+}
@@ -0,0 +1,131 @@
+package parking
+
+import (
+	"fmt"
+	"parking-lot-system/internal/alert"
+	"parking-lot-system/internal/domain/plate"
+	"parking-lot-system/internal/repository"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"strings"
+)
+
+// Violation kinds
+const (
+	ViolationWrongSpotType = "wrong_spot_type"
+	ViolationOverstay      = "overstay"
+	ViolationBlocking      = "blocking"
+)
+
+// ViolationKinds lists the kinds of violations enforcement staff can flag
+var ViolationKinds = []string{
+	ViolationWrongSpotType,
+	ViolationOverstay,
+	ViolationBlocking,
+}
+
+// FlagViolation records a violation of a parked vehicle on behalf of enforcement staff.
+// The vehicle cannot be unparked until the violation is resolved.
+func (s *ParkingService) FlagViolation(officer, vehicleNumber, kind, note string) (repository.Violation, error) {
+	vehicleNumber = plate.Normalize(vehicleNumber)
+
+	if err := s.validateVehicleNumber(vehicleNumber); err != nil {
+		return repository.Violation{}, err
+	}
+	if !isViolationKind(kind) {
+		return repository.Violation{}, fmt.Errorf("%w: %q", pkgerrors.ErrInvalidViolationKind, kind)
+	}
+
+	isParked, spotID, _ := s.repo.IsVehicleParked(vehicleNumber)
+	if !isParked {
+		return repository.Violation{}, fmt.Errorf("%w: %s", pkgerrors.ErrVehicleNotParked, vehicleNumber)
+	}
+
+	violation := s.repo.AddViolation(repository.Violation{
+		VehicleNumber: vehicleNumber,
+		SpotID:        spotID,
+		Kind:          kind,
+		Note:          note,
+		ReportedBy:    officer,
+	})
+
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:         officer,
+		Action:        "violation.flag",
+		SpotID:        spotID,
+		VehicleNumber: vehicleNumber,
+		Reason:        kind,
+	})
+
+	if kind == ViolationOverstay {
+		s.notifyOverstay(vehicleNumber, spotID)
+		s.raiseAlert(alert.TypeOverstay, alert.SeverityWarning, "overstay:"+vehicleNumber,
+			fmt.Sprintf("Vehicle %s overstayed at spot %s", vehicleNumber, spotID))
+	}
+
+	return violation, nil
+}
+
+// ResolveViolation closes a violation on behalf of enforcement staff, recording a fine unless it is 0
+func (s *ParkingService) ResolveViolation(officer string, id int, fine float64) (repository.Violation, error) {
+	if fine < 0 {
+		return repository.Violation{}, fmt.Errorf("%w: %v", pkgerrors.ErrInvalidFine, fine)
+	}
+
+	violation, err := s.repo.ResolveViolation(id, officer, fine)
+	if err != nil {
+		return repository.Violation{}, err
+	}
+
+	action := "violation.resolve"
+	if fine > 0 {
+		action = "violation.fine"
+	}
+	s.repo.AppendAudit(repository.AuditEntry{
+		Actor:         officer,
+		Action:        action,
+		SpotID:        violation.SpotID,
+		VehicleNumber: violation.VehicleNumber,
+		Reason:        violation.Kind,
+	})
+
+	return violation, nil
+}
+
+// GetViolations returns the violations of a vehicle, or of all vehicles when vehicleNumber is empty,
+// restricted to the open ones when openOnly is set
+func (s *ParkingService) GetViolations(vehicleNumber string, openOnly bool) []repository.Violation {
+	violations := s.repo.GetViolations(plate.Normalize(vehicleNumber))
+	if !openOnly {
+		return violations
+	}
+
+	open := []repository.Violation{}
+	for _, violation := range violations {
+		if violation.IsOpen() {
+			open = append(open, violation)
+		}
+	}
+	return open
+}
+
+// checkViolations fails when a vehicle has open violations
+func (s *ParkingService) checkViolations(vehicleNumber string) error {
+	kinds := []string{}
+	for _, violation := range s.GetViolations(vehicleNumber, true) {
+		kinds = append(kinds, violation.Kind)
+	}
+	if len(kinds) > 0 {
+		return fmt.Errorf("%w: %s (%s)", pkgerrors.ErrVehicleFlagged, vehicleNumber, strings.Join(kinds, ", "))
+	}
+	return nil
+}
+
+// isViolationKind checks if kind is one of ViolationKinds
+func isViolationKind(kind string) bool {
+	for _, known := range ViolationKinds {
+		if known == kind {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package plate
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize returns the canonical form of a vehicle number: upper case without any whitespace,
+// so "bc001", "BC001", " BC 001 " all refer to the same vehicle
+func Normalize(vehicleNumber string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, vehicleNumber)
+}
@@ -0,0 +1,82 @@
+package plate
+
+import (
+	"fmt"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"regexp"
+	"unicode/utf8"
+)
+
+// Scheme describes the vehicle numbers accepted in a region
+type Scheme struct {
+	Name      string
+	pattern   *regexp.Regexp
+	minLength int
+	maxLength int
+}
+
+// Generic accepts letters, digits, spaces and hyphens starting with a letter or digit, as used by most regions
+var Generic = mustScheme("generic", `^[A-Za-z0-9][A-Za-z0-9 -]*$`, 2, 15)
+
+// built-in schemes by name
+var schemes = map[string]*Scheme{
+	Generic.Name: Generic,
+	// Indonesian plates: area code, number and optional suffix, e.g. "B 1234 XYZ"
+	"id": mustScheme("id", `^(?i)[A-Z]{1,2} ?[0-9]{1,4} ?[A-Z]{0,3}$`, 2, 11),
+	// UK plates since 2001: area code, age identifier and random letters, e.g. "AB12 CDE"
+	"uk": mustScheme("uk", `^(?i)[A-Z]{2}[0-9]{2} ?[A-Z]{3}$`, 7, 8),
+}
+
+// Lookup returns the built-in scheme with the given name
+func Lookup(name string) (*Scheme, error) {
+	scheme, ok := schemes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown plate scheme %q", pkgerrors.ErrInvalidPlateScheme, name)
+	}
+	return scheme, nil
+}
+
+// NewScheme creates a scheme accepting vehicle numbers that match the regular expression pattern
+// and are between minLength and maxLength characters long
+func NewScheme(name, pattern string, minLength, maxLength int) (*Scheme, error) {
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", pkgerrors.ErrInvalidPlateScheme, err)
+	}
+	if minLength < 1 || maxLength < minLength {
+		return nil, fmt.Errorf("%w: length limits must satisfy 1 <= min <= max", pkgerrors.ErrInvalidPlateScheme)
+	}
+
+	return &Scheme{
+		Name:      name,
+		pattern:   compiled,
+		minLength: minLength,
+		maxLength: maxLength,
+	}, nil
+}
+
+func mustScheme(name, pattern string, minLength, maxLength int) *Scheme {
+	scheme, err := NewScheme(name, pattern, minLength, maxLength)
+	if err != nil {
+		panic(err)
+	}
+	return scheme
+}
+
+// Validate checks that a vehicle number is acceptable under the scheme
+func (s *Scheme) Validate(vehicleNumber string) error {
+	if vehicleNumber == "" {
+		return fmt.Errorf("%w: must not be empty", pkgerrors.ErrInvalidVehicleNumber)
+	}
+
+	length := utf8.RuneCountInString(vehicleNumber)
+	if length < s.minLength || length > s.maxLength {
+		return fmt.Errorf("%w: %q must be %d to %d characters long", pkgerrors.ErrInvalidVehicleNumber, vehicleNumber, s.minLength, s.maxLength)
+	}
+
+	if !s.pattern.MatchString(vehicleNumber) {
+		return fmt.Errorf("%w: %q does not match the %s plate format", pkgerrors.ErrInvalidVehicleNumber, vehicleNumber, s.Name)
+	}
+
+	return nil
+}
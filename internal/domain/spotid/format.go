@@ -0,0 +1,119 @@
+package spotid
+
+import (
+	"fmt"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Format converts between spot locations and the spot IDs shown to drivers and on signage
+type Format interface {
+	Format(floor, row, column int) string
+	Parse(spotID string) (int, int, int, error)
+}
+
+// Default is the "floor-row-column" format with zero-based indices, e.g. "0-2-1"
+var Default Format = defaultFormat{}
+
+type defaultFormat struct{}
+
+func (defaultFormat) Format(floor, row, column int) string {
+	return fmt.Sprintf("%d-%d-%d", floor, row, column)
+}
+
+func (defaultFormat) Parse(spotID string) (int, int, int, error) {
+	var floor, row, column int
+	_, err := fmt.Sscanf(spotID, "%d-%d-%d", &floor, &row, &column)
+	if err != nil {
+		return 0, 0, 0, pkgerrors.ErrInvalidSpotID
+	}
+	return floor, row, column, nil
+}
+
+// placeholder matches {floor}, {row} and {column}, optionally with a zero-padded width like {row:2}
+var placeholder = regexp.MustCompile(`\{(floor|row|column)(?::(\d+))?\}`)
+
+// Pattern formats spot IDs from a template such as "F{floor}R{row:2}C{column:2}".
+// Indices are shifted by Base, so a base of 1 numbers floors, rows and columns from 1.
+type Pattern struct {
+	template string
+	base     int
+	fields   []string // placeholder names in the order they appear
+	widths   map[string]int
+	matcher  *regexp.Regexp
+}
+
+// NewPattern compiles a spot ID template, every placeholder must appear exactly once
+func NewPattern(template string, base int) (*Pattern, error) {
+	p := &Pattern{
+		template: template,
+		base:     base,
+		widths:   make(map[string]int),
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	last := 0
+	for _, match := range placeholder.FindAllStringSubmatchIndex(template, -1) {
+		name := template[match[2]:match[3]]
+		if _, seen := p.widths[name]; seen {
+			return nil, fmt.Errorf("%w: {%s} appears more than once", pkgerrors.ErrInvalidSpotIDFormat, name)
+		}
+
+		width := 0
+		if match[4] >= 0 {
+			width, _ = strconv.Atoi(template[match[4]:match[5]])
+		}
+
+		p.fields = append(p.fields, name)
+		p.widths[name] = width
+
+		expr.WriteString(regexp.QuoteMeta(template[last:match[0]]))
+		if width > 0 {
+			expr.WriteString(fmt.Sprintf(`(\d{%d,})`, width))
+		} else {
+			expr.WriteString(`(\d+)`)
+		}
+		last = match[1]
+	}
+	expr.WriteString(regexp.QuoteMeta(template[last:]))
+	expr.WriteString("$")
+
+	if len(p.fields) != 3 {
+		return nil, fmt.Errorf("%w: %q must contain {floor}, {row} and {column}", pkgerrors.ErrInvalidSpotIDFormat, template)
+	}
+
+	p.matcher = regexp.MustCompile(expr.String())
+	return p, nil
+}
+
+// Format returns the spot ID of a location
+func (p *Pattern) Format(floor, row, column int) string {
+	values := map[string]int{"floor": floor, "row": row, "column": column}
+
+	return placeholder.ReplaceAllStringFunc(p.template, func(token string) string {
+		name := placeholder.FindStringSubmatch(token)[1]
+		return fmt.Sprintf("%0*d", p.widths[name], values[name]+p.base)
+	})
+}
+
+// Parse returns the location of a spot ID
+func (p *Pattern) Parse(spotID string) (int, int, int, error) {
+	match := p.matcher.FindStringSubmatch(spotID)
+	if match == nil {
+		return 0, 0, 0, pkgerrors.ErrInvalidSpotID
+	}
+
+	values := make(map[string]int)
+	for i, name := range p.fields {
+		value, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return 0, 0, 0, pkgerrors.ErrInvalidSpotID
+		}
+		values[name] = value - p.base
+	}
+
+	return values["floor"], values["row"], values["column"], nil
+}
@@ -0,0 +1,106 @@
+// Package eventlog persists the events of the parking service to a file of JSON lines, so the lot
+// state can be rebuilt from them after the server is gone, e.g. with cmd/replay.
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"parking-lot-system/internal/domain/parking"
+	"sync"
+	"time"
+)
+
+// Record is an event as written to the file, one JSON object per line
+type Record struct {
+	ID            int       `json:"id"`
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	SpotID        string    `json:"spotId,omitempty"`
+	VehicleNumber string    `json:"vehicleNumber,omitempty"`
+	VehicleType   string    `json:"vehicleType,omitempty"`
+	Gate          int       `json:"gate,omitempty"`
+	Device        string    `json:"device,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+}
+
+// FileWriter appends the events it receives to a file. Events are written as they are published,
+// so the file keeps their order; IDs start again at 1 when the server restarts.
+type FileWriter struct {
+	mutex   sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// OpenFile opens the file events are appended to, creating it if needed
+func OpenFile(path string) (*FileWriter, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("opening event log file: %w", err)
+	}
+	return &FileWriter{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+// HandleEvent appends an event to the file
+func (w *FileWriter) HandleEvent(event parking.Event) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err := w.encoder.Encode(Record{
+		ID:            event.ID,
+		Type:          event.Type,
+		Time:          event.Time,
+		SpotID:        event.SpotID,
+		VehicleNumber: event.VehicleNumber,
+		VehicleType:   event.VehicleType,
+		Gate:          event.Gate,
+		Device:        event.Device,
+		Reason:        event.Reason,
+	}); err != nil {
+		log.Printf("Error writing event %d to %s: %v", event.ID, w.file.Name(), err)
+	}
+}
+
+// Close closes the file
+func (w *FileWriter) Close() error {
+	return w.file.Close()
+}
+
+// ReadFile returns the events of a file written by a FileWriter, in the order they were written
+func ReadFile(path string) ([]parking.Event, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening event log file: %w", err)
+	}
+	defer file.Close()
+
+	var events []parking.Event
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		events = append(events, parking.Event{
+			ID:            record.ID,
+			Type:          record.Type,
+			Time:          record.Time,
+			SpotID:        record.SpotID,
+			VehicleNumber: record.VehicleNumber,
+			VehicleType:   record.VehicleType,
+			Gate:          record.Gate,
+			Device:        record.Device,
+			Reason:        record.Reason,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading event log file: %w", err)
+	}
+
+	return events, nil
+}
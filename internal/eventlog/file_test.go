@@ -0,0 +1,41 @@
+package eventlog
+
+import (
+	"parking-lot-system/internal/domain/parking"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	at := time.Date(2025, 5, 20, 14, 30, 0, 0, time.UTC)
+	events := []parking.Event{
+		{ID: 1, Type: parking.EventLotInitialized, Time: at},
+		{ID: 2, Type: parking.EventVehicleParked, Time: at.Add(time.Minute), SpotID: "0-0-1", VehicleNumber: "AB1", VehicleType: parking.Automobile, Gate: 1},
+		{ID: 3, Type: parking.EventVehicleUnparked, Time: at.Add(time.Hour), SpotID: "0-0-1", VehicleNumber: "AB1", Device: "exit-1"},
+	}
+
+	// The file is appended to across restarts of the server
+	for _, batch := range [][]parking.Event{events[:2], events[2:]} {
+		writer, err := OpenFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, event := range batch {
+			writer.HandleEvent(event)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, events) {
+		t.Fatalf("got %+v, want %+v", got, events)
+	}
+}
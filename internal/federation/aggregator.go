@@ -0,0 +1,138 @@
+package federation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"parking-lot-system/internal/api/dto"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	peerTimeout = 3 * time.Second
+
+	// how long an aggregated view is reused before the peers are polled again
+	cacheTTL = 10 * time.Second
+)
+
+// PeerAvailability holds the availability reported by a remote instance
+type PeerAvailability struct {
+	URL          string
+	Reachable    bool
+	Error        string
+	Overall      dto.PublicAvailability
+	VehicleTypes map[string]dto.PublicAvailability
+}
+
+// CombinedAvailability holds the availability summed over all reachable peers
+type CombinedAvailability struct {
+	FetchedAt    time.Time
+	Available    int
+	Capacity     int
+	VehicleTypes map[string]dto.PublicAvailability
+	Peers        []PeerAvailability
+}
+
+// Aggregator combines the public availability of several parking-lot-system instances
+type Aggregator struct {
+	peers  []string
+	client *http.Client
+
+	mutex  sync.Mutex
+	cached *CombinedAvailability
+}
+
+func NewAggregator(peers []string) *Aggregator {
+	trimmed := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		trimmed = append(trimmed, strings.TrimSuffix(peer, "/"))
+	}
+
+	return &Aggregator{
+		peers:  trimmed,
+		client: &http.Client{Timeout: peerTimeout},
+	}
+}
+
+// Availability polls every peer concurrently and combines their availability.
+// Results are cached briefly so a busy campus page does not fan out on every request.
+func (a *Aggregator) Availability(ctx context.Context) CombinedAvailability {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if a.cached != nil && time.Since(a.cached.FetchedAt) < cacheTTL {
+		return *a.cached
+	}
+
+	peers := make([]PeerAvailability, len(a.peers))
+	var wg sync.WaitGroup
+	for i, peer := range a.peers {
+		wg.Add(1)
+		go func(i int, peer string) {
+			defer wg.Done()
+			peers[i] = a.fetch(ctx, peer)
+		}(i, peer)
+	}
+	wg.Wait()
+
+	combined := CombinedAvailability{
+		FetchedAt:    time.Now(),
+		VehicleTypes: make(map[string]dto.PublicAvailability),
+		Peers:        peers,
+	}
+	for _, peer := range peers {
+		if !peer.Reachable {
+			continue
+		}
+
+		combined.Available += peer.Overall.Available
+		combined.Capacity += peer.Overall.Capacity
+		for vehicleType, availability := range peer.VehicleTypes {
+			total := combined.VehicleTypes[vehicleType]
+			total.Available += availability.Available
+			total.Capacity += availability.Capacity
+			combined.VehicleTypes[vehicleType] = total
+		}
+	}
+
+	a.cached = &combined
+	return combined
+}
+
+// fetch reads the public availability of a single peer
+func (a *Aggregator) fetch(ctx context.Context, peer string) PeerAvailability {
+	result := PeerAvailability{URL: peer}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, peer+"/public/availability", nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Sprintf("unexpected status code %d", resp.StatusCode)
+		return result
+	}
+
+	var body dto.PublicAvailabilityResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Reachable = true
+	result.Overall = body.Overall
+	result.VehicleTypes = body.VehicleTypes
+
+	return result
+}
@@ -0,0 +1,39 @@
+package gate
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Barrier states
+const (
+	BarrierOpen    = "open"
+	BarrierClosed  = "closed"
+	BarrierUnknown = "unknown"
+)
+
+// GateController drives the physical barrier of a gate
+type GateController interface {
+	OpenBarrier(ctx context.Context) error
+	CloseBarrier(ctx context.Context) error
+	Status(ctx context.Context) (string, error)
+}
+
+// NewController creates a controller for the driver matching the URL scheme:
+// http(s)://host/path for HTTP barrier controllers, tcp://host:port for TCP ones
+func NewController(address string) (GateController, error) {
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gate controller address %q: %v", address, err)
+	}
+
+	switch parsed.Scheme {
+	case "http", "https":
+		return NewHTTPController(address), nil
+	case "tcp":
+		return NewTCPController(parsed.Host), nil
+	default:
+		return nil, fmt.Errorf("unsupported gate controller scheme %q: must be http, https or tcp", parsed.Scheme)
+	}
+}
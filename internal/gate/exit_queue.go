@@ -0,0 +1,141 @@
+package gate
+
+import (
+	"sort"
+	"time"
+)
+
+// ExitTicket is the place of a leaving vehicle in the queue of a saturated exit gate
+type ExitTicket struct {
+	Gate          int
+	SpotID        string
+	VehicleNumber string
+	Position      int       // 1 for the next vehicle to pass
+	ReadyAt       time.Time // when the barrier opens for the vehicle
+}
+
+// Wait returns how long the vehicle still has to wait at the gate
+func (t ExitTicket) Wait(now time.Time) time.Duration {
+	if t.ReadyAt.Before(now) {
+		return 0
+	}
+	return t.ReadyAt.Sub(now)
+}
+
+// ExitQueue holds the vehicles waiting at an exit gate
+type ExitQueue struct {
+	Gate       int
+	Throughput int // vehicles per minute
+	Tickets    []ExitTicket
+}
+
+// exitQueue lets vehicles pass an exit gate one at a time, at most throughput vehicles per minute
+type exitQueue struct {
+	throughput int
+	nextSlot   time.Time // earliest time the next vehicle may pass
+	waiting    []ExitTicket
+}
+
+// interval returns the time one vehicle needs to pass the gate
+func (q *exitQueue) interval() time.Duration {
+	return time.Minute / time.Duration(q.throughput)
+}
+
+// prune drops the vehicles that passed the gate
+func (q *exitQueue) prune(now time.Time) {
+	passed := 0
+	for passed < len(q.waiting) && !q.waiting[passed].ReadyAt.After(now) {
+		passed++
+	}
+	q.waiting = q.waiting[passed:]
+	for i := range q.waiting {
+		q.waiting[i].Position = i + 1
+	}
+}
+
+// enqueue gives a leaving vehicle the next free slot, it only waits when the gate is saturated
+func (q *exitQueue) enqueue(ticket ExitTicket, now time.Time) ExitTicket {
+	q.prune(now)
+
+	ticket.ReadyAt = now
+	if q.nextSlot.After(now) {
+		ticket.ReadyAt = q.nextSlot
+		ticket.Position = len(q.waiting) + 1
+		q.waiting = append(q.waiting, ticket)
+	}
+	q.nextSlot = ticket.ReadyAt.Add(q.interval())
+
+	return ticket
+}
+
+// SetThroughput limits the number of vehicles per minute that leave through each gate,
+// gate -> vehicles per minute. Gates without a limit let vehicles leave right away.
+func (m *Manager) SetThroughput(throughput map[int]int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.queues = make(map[int]*exitQueue, len(throughput))
+	for gate, vehiclesPerMinute := range throughput {
+		if vehiclesPerMinute > 0 {
+			m.queues[gate] = &exitQueue{throughput: vehiclesPerMinute}
+		}
+	}
+}
+
+// enqueueExit queues a vehicle leaving through a gate, it returns when the barrier opens for it
+func (m *Manager) enqueueExit(gate int, spotID, vehicleNumber string) time.Time {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	queue, exists := m.queues[gate]
+	if !exists || m.freeFlow {
+		return now
+	}
+
+	ticket := queue.enqueue(ExitTicket{Gate: gate, SpotID: spotID, VehicleNumber: vehicleNumber}, now)
+	return ticket.ReadyAt
+}
+
+// ExitTicket returns the place in the exit queue of the vehicle that left a spot through a gate,
+// false when it does not have to wait
+func (m *Manager) ExitTicket(gate int, spotID string) (ExitTicket, bool) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	queue, exists := m.queues[gate]
+	if !exists {
+		return ExitTicket{}, false
+	}
+
+	queue.prune(time.Now())
+	for i := len(queue.waiting) - 1; i >= 0; i-- {
+		if queue.waiting[i].SpotID == spotID {
+			return queue.waiting[i], true
+		}
+	}
+	return ExitTicket{}, false
+}
+
+// ExitQueues returns the vehicles waiting at every gate with a throughput limit, ordered by gate
+func (m *Manager) ExitQueues() []ExitQueue {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	now := time.Now()
+	queues := make([]ExitQueue, 0, len(m.queues))
+	for gate, queue := range m.queues {
+		queue.prune(now)
+		queues = append(queues, ExitQueue{
+			Gate:       gate,
+			Throughput: queue.throughput,
+			Tickets:    append([]ExitTicket(nil), queue.waiting...),
+		})
+	}
+
+	sort.Slice(queues, func(i, j int) bool {
+		return queues[i].Gate < queues[j].Gate
+	})
+
+	return queues
+}
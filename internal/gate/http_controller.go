@@ -0,0 +1,95 @@
+package gate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPController drives a barrier exposing a small REST API:
+//
+//	POST {baseURL}/open
+//	POST {baseURL}/close
+//	GET  {baseURL}/status -> {"state": "open"|"closed"}
+type HTTPController struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPController(baseURL string) *HTTPController {
+	return &HTTPController{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// OpenBarrier opens the barrier
+func (c *HTTPController) OpenBarrier(ctx context.Context) error {
+	return c.post(ctx, "/open")
+}
+
+// CloseBarrier closes the barrier
+func (c *HTTPController) CloseBarrier(ctx context.Context) error {
+	return c.post(ctx, "/close")
+}
+
+// Status returns the state reported by the barrier
+func (c *HTTPController) Status(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/status", nil)
+	if err != nil {
+		return BarrierUnknown, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return BarrierUnknown, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return BarrierUnknown, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var body struct {
+		State string `json:"state"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return BarrierUnknown, err
+	}
+
+	return normalizeState(body.State), nil
+}
+
+func (c *HTTPController) post(ctx context.Context, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// normalizeState maps a state reported by a barrier onto the known barrier states
+func normalizeState(state string) string {
+	switch strings.ToLower(strings.TrimSpace(state)) {
+	case BarrierOpen:
+		return BarrierOpen
+	case BarrierClosed:
+		return BarrierClosed
+	default:
+		return BarrierUnknown
+	}
+}
@@ -0,0 +1,197 @@
+package gate
+
+import (
+	"context"
+	"log"
+	"parking-lot-system/internal/domain/parking"
+	"sort"
+	"sync"
+	"time"
+)
+
+const commandTimeout = 5 * time.Second
+
+// GateStatus holds the barrier state of a gate
+type GateStatus struct {
+	Gate  int
+	State string
+	Error string
+}
+
+// Command statuses of a gate
+const (
+	CommandAcknowledged = "acknowledged" // the controller performed the command
+	CommandFailed       = "failed"       // the controller could not be reached or refused the command
+)
+
+// CommandResult holds whether the controller of a gate performed a command
+type CommandResult struct {
+	Gate   int
+	Status string
+	Error  string
+}
+
+// Manager opens the barrier of a gate after a vehicle was parked or unparked through it,
+// closing it again once the vehicle had time to pass. Leaving vehicles queue at exit gates
+// with a throughput limit.
+type Manager struct {
+	controllers map[int]GateController // gate -> controller
+	holdTime    time.Duration
+
+	mutex  sync.Mutex
+	queues map[int]*exitQueue // gate -> vehicles waiting to leave
+	// whether vehicles leave without queueing, while the lot is in emergency mode
+	freeFlow bool
+	// whether all barriers are held open, e.g. to evacuate the lot
+	heldOpen bool
+}
+
+func NewManager(controllers map[int]GateController, holdTime time.Duration) *Manager {
+	return &Manager{
+		controllers: controllers,
+		holdTime:    holdTime,
+		queues:      make(map[int]*exitQueue),
+	}
+}
+
+// HandleEvent opens the barrier of the gate a vehicle passes through. During an incident
+// vehicles leave right away.
+func (m *Manager) HandleEvent(event parking.Event) {
+	if event.Type == parking.EventIncidentStarted || event.Type == parking.EventIncidentEnded {
+		m.mutex.Lock()
+		m.freeFlow = event.Type == parking.EventIncidentStarted
+		m.mutex.Unlock()
+		return
+	}
+	if event.Type != parking.EventVehicleParked && event.Type != parking.EventVehicleUnparked {
+		return
+	}
+
+	// Leaving vehicles wait for their turn when the gate is saturated
+	readyAt := time.Now()
+	if event.Type == parking.EventVehicleUnparked && event.Gate != 0 {
+		readyAt = m.enqueueExit(event.Gate, event.SpotID, event.VehicleNumber)
+	}
+
+	controller, exists := m.controllers[event.Gate]
+	if !exists {
+		return
+	}
+
+	go m.cycle(event.Gate, controller, readyAt)
+}
+
+// cycle opens the barrier at readyAt and closes it after the hold time
+func (m *Manager) cycle(gate int, controller GateController, readyAt time.Time) {
+	time.Sleep(time.Until(readyAt))
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	err := controller.OpenBarrier(ctx)
+	cancel()
+	if err != nil {
+		log.Printf("Error opening barrier of gate %d: %v", gate, err)
+		return
+	}
+
+	time.Sleep(m.holdTime)
+
+	// Barriers held open stay open
+	m.mutex.Lock()
+	heldOpen := m.heldOpen
+	m.mutex.Unlock()
+	if heldOpen {
+		return
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	if err := controller.CloseBarrier(ctx); err != nil {
+		log.Printf("Error closing barrier of gate %d: %v", gate, err)
+	}
+}
+
+// OpenAll opens the barrier of every controlled gate and keeps them open until ReleaseAll,
+// returning whether each controller performed the command
+func (m *Manager) OpenAll(ctx context.Context) []CommandResult {
+	m.mutex.Lock()
+	m.heldOpen = true
+	m.mutex.Unlock()
+
+	log.Printf("Holding the barriers of all gates open")
+	return m.commandAll(ctx, GateController.OpenBarrier)
+}
+
+// ReleaseAll closes the barrier of every controlled gate and returns them to opening for each vehicle,
+// returning whether each controller performed the command
+func (m *Manager) ReleaseAll(ctx context.Context) []CommandResult {
+	m.mutex.Lock()
+	m.heldOpen = false
+	m.mutex.Unlock()
+
+	log.Printf("Releasing the barriers of all gates")
+	return m.commandAll(ctx, GateController.CloseBarrier)
+}
+
+// HeldOpen reports whether all barriers are held open
+func (m *Manager) HeldOpen() bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.heldOpen
+}
+
+// commandAll sends a command to every controller at once, ordered by gate
+func (m *Manager) commandAll(ctx context.Context, command func(GateController, context.Context) error) []CommandResult {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	results := make([]CommandResult, 0, len(m.controllers))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	for gate, controller := range m.controllers {
+		wg.Add(1)
+		go func(gate int, controller GateController) {
+			defer wg.Done()
+
+			result := CommandResult{Gate: gate, Status: CommandAcknowledged}
+			if err := command(controller, ctx); err != nil {
+				log.Printf("Error commanding barrier of gate %d: %v", gate, err)
+				result.Status = CommandFailed
+				result.Error = err.Error()
+			}
+
+			mutex.Lock()
+			results = append(results, result)
+			mutex.Unlock()
+		}(gate, controller)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Gate < results[j].Gate
+	})
+
+	return results
+}
+
+// Statuses queries the barrier state of every controlled gate
+func (m *Manager) Statuses(ctx context.Context) []GateStatus {
+	statuses := make([]GateStatus, 0, len(m.controllers))
+	for gate, controller := range m.controllers {
+		status := GateStatus{Gate: gate}
+
+		state, err := controller.Status(ctx)
+		status.State = state
+		if err != nil {
+			status.Error = err.Error()
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Gate < statuses[j].Gate
+	})
+
+	return statuses
+}
@@ -0,0 +1,78 @@
+package gate
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// TCPController drives a barrier speaking a line based protocol: every command
+// (OPEN, CLOSE, STATUS) is answered with a single line, OK / ERR <reason> for
+// commands and OPEN / CLOSED for STATUS
+type TCPController struct {
+	address string
+	timeout time.Duration
+}
+
+func NewTCPController(address string) *TCPController {
+	return &TCPController{
+		address: address,
+		timeout: 5 * time.Second,
+	}
+}
+
+// OpenBarrier opens the barrier
+func (c *TCPController) OpenBarrier(ctx context.Context) error {
+	return c.command(ctx, "OPEN")
+}
+
+// CloseBarrier closes the barrier
+func (c *TCPController) CloseBarrier(ctx context.Context) error {
+	return c.command(ctx, "CLOSE")
+}
+
+// Status returns the state reported by the barrier
+func (c *TCPController) Status(ctx context.Context) (string, error) {
+	reply, err := c.send(ctx, "STATUS")
+	if err != nil {
+		return BarrierUnknown, err
+	}
+	return normalizeState(reply), nil
+}
+
+func (c *TCPController) command(ctx context.Context, command string) error {
+	reply, err := c.send(ctx, command)
+	if err != nil {
+		return err
+	}
+	if reply != "OK" {
+		return fmt.Errorf("barrier rejected %s: %s", command, reply)
+	}
+	return nil
+}
+
+// send writes a command on a fresh connection and reads the single line reply
+func (c *TCPController) send(ctx context.Context, command string) (string, error) {
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(c.timeout))
+
+	if _, err := fmt.Fprintf(conn, "%s\n", command); err != nil {
+		return "", err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(reply), nil
+}
@@ -0,0 +1,107 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog provides translated texts by language and key. Keys are error codes, e.g. "vehicle_not_found",
+// or the names of labels shown in responses, e.g. "display.full".
+type Catalog interface {
+	Translate(language, key string) (string, bool)
+	Languages() []string
+}
+
+// MapCatalog is a catalog held in memory, language -> key -> text
+type MapCatalog map[string]map[string]string
+
+func (c MapCatalog) Translate(language, key string) (string, bool) {
+	text, ok := c[language][key]
+	return text, ok
+}
+
+func (c MapCatalog) Languages() []string {
+	languages := make([]string, 0, len(c))
+	for language := range c {
+		languages = append(languages, language)
+	}
+	return languages
+}
+
+// LoadCatalog reads a YAML file mapping languages to keys and texts, e.g.
+//
+//	ms:
+//	  display.full: PENUH
+//	  vehicle_not_found: kenderaan tidak pernah diletakkan di sini
+func LoadCatalog(path string) (MapCatalog, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := MapCatalog{}
+	if err := yaml.Unmarshal(content, &catalog); err != nil {
+		return nil, fmt.Errorf("parsing message catalog %s: %v", path, err)
+	}
+	return catalog, nil
+}
+
+// catalogs added on top of the built-in texts, the last one added wins
+var (
+	catalogsMutex sync.RWMutex
+	catalogs      []Catalog
+)
+
+// AddCatalog adds translations, e.g. for another language or to reword the built-in texts
+func AddCatalog(catalog Catalog) {
+	catalogsMutex.Lock()
+	defer catalogsMutex.Unlock()
+
+	catalogs = append(catalogs, catalog)
+}
+
+// translate looks a key up in the added catalogs, then in the built-in texts
+func translate(language, key string) (string, bool) {
+	catalogsMutex.RLock()
+	defer catalogsMutex.RUnlock()
+
+	for i := len(catalogs) - 1; i >= 0; i-- {
+		if text, ok := catalogs[i].Translate(language, key); ok {
+			return text, true
+		}
+	}
+	return builtin.Translate(language, key)
+}
+
+// supports reports whether any catalog has texts in a language
+func supports(language string) bool {
+	catalogsMutex.RLock()
+	defer catalogsMutex.RUnlock()
+
+	for _, catalog := range append([]Catalog{builtin}, catalogs...) {
+		for _, supported := range catalog.Languages() {
+			if supported == language {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Text returns a label in the given language, falling back to English. The text is a format
+// string for the arguments, e.g. "Aisle %d".
+func Text(language, key string, args ...any) string {
+	text, ok := translate(language, key)
+	if !ok {
+		if text, ok = translate(English, key); !ok {
+			text = key
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
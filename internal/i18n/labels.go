@@ -0,0 +1,50 @@
+package i18n
+
+import pkgerrors "parking-lot-system/pkg/errors"
+
+// labels shown in responses per language and key
+var labels = map[string]map[string]string{
+	English: {
+		"display.full":      "FULL",
+		"display.aisle":     "Aisle %d",
+		"display.emergency": "EMERGENCY - NO ENTRY",
+
+		"map.floor":    "Floor %d",
+		"map.gates":    "Gates",
+		"map.occupied": "occupied",
+		"map.free":     "free",
+		"map.inactive": "inactive",
+	},
+	Indonesian: {
+		"display.full":      "PENUH",
+		"display.aisle":     "Lorong %d",
+		"display.emergency": "DARURAT - DILARANG MASUK",
+
+		"map.floor":    "Lantai %d",
+		"map.gates":    "Gerbang",
+		"map.occupied": "terisi",
+		"map.free":     "kosong",
+		"map.inactive": "tidak aktif",
+	},
+}
+
+// builtin holds the texts shipped with the server: the labels and the error messages
+var builtin builtinCatalog
+
+type builtinCatalog struct{}
+
+func (builtinCatalog) Translate(language, key string) (string, bool) {
+	if text, ok := labels[language][key]; ok {
+		return text, true
+	}
+	text, ok := messages[language][pkgerrors.Code(key)]
+	return text, ok
+}
+
+func (builtinCatalog) Languages() []string {
+	languages := make([]string, 0, len(messages))
+	for language := range messages {
+		languages = append(languages, language)
+	}
+	return languages
+}
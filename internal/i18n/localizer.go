@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	stderrors "errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	pkgerrors "parking-lot-system/pkg/errors"
+)
+
+// Supported languages
+const (
+	English    = "en"
+	Indonesian = "id"
+)
+
+// DefaultLanguage is used when the client accepts none of the supported languages
+const DefaultLanguage = English
+
+// Language picks the supported language the client prefers most from an Accept-Language header
+// such as "id-ID,id;q=0.9,en;q=0.8", falling back to DefaultLanguage
+func Language(acceptLanguage string) string {
+	type preference struct {
+		language string
+		quality  float64
+	}
+
+	preferences := []preference{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		// only the primary subtag matters, "id-ID" selects Indonesian
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		preferences = append(preferences, preference{language: primary, quality: quality})
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+
+	for _, pref := range preferences {
+		if pref.quality <= 0 {
+			continue
+		}
+		if supports(pref.language) {
+			return pref.language
+		}
+	}
+
+	return DefaultLanguage
+}
+
+// Message returns the message of err in the given language. The message of the domain error is
+// translated while the details wrapped around it, such as vehicle numbers and spot IDs, are kept;
+// errors without a translation keep their original message.
+func Message(err error, language string) string {
+	message := err.Error()
+
+	var domainErr *pkgerrors.Error
+	if !stderrors.As(err, &domainErr) {
+		return message
+	}
+
+	translated, ok := translate(language, string(domainErr.Code))
+	if !ok {
+		return message
+	}
+
+	// details are wrapped as "<message>: <details>"
+	if details, found := strings.CutPrefix(message, domainErr.Message); found {
+		return translated + details
+	}
+	return translated
+}
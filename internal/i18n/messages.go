@@ -0,0 +1,112 @@
+package i18n
+
+import pkgerrors "parking-lot-system/pkg/errors"
+
+// error messages per language and error code, English messages are the ones of the errors themselves
+var messages = map[string]map[pkgerrors.Code]string{
+	English: {},
+	Indonesian: {
+		pkgerrors.ErrInvalidLocation.Code:          "lokasi tempat parkir tidak valid: indeks di luar batas",
+		pkgerrors.ErrInvalidSpotID.Code:            "ID tempat parkir tidak valid: tidak sesuai format ID tempat parkir",
+		pkgerrors.ErrInvalidGate.Code:              "gerbang tidak valid: harus antara 1 dan jumlah gerbang",
+		pkgerrors.ErrInvalidDimensions.Code:        "dimensi area parkir tidak valid",
+		pkgerrors.ErrInvalidSpotType.Code:          "tipe tempat parkir tidak valid",
+		pkgerrors.ErrInvalidSpotIDFormat.Code:      "format ID tempat parkir tidak valid",
+		pkgerrors.ErrInvalidSize.Code:              "ukuran tidak valid: harus compact, standard, atau large",
+		pkgerrors.ErrInvalidCapacity.Code:          "kapasitas tidak valid: minimal 1 dan tidak kurang dari jumlah kendaraan yang terparkir",
+		pkgerrors.ErrLotInUse.Code:                 "area parkir dengan kendaraan terparkir tidak dapat diinisialisasi ulang",
+		pkgerrors.ErrSpotInUse.Code:                "tempat parkir yang terisi tidak dapat dikonfigurasi ulang",
+		pkgerrors.ErrInvalidPlateScheme.Code:       "skema pelat nomor tidak valid",
+		pkgerrors.ErrInvalidSpotTypeConfig.Code:    "konfigurasi tipe tempat parkir tidak valid",
+		pkgerrors.ErrInvalidVehicleTypeConfig.Code: "konfigurasi tipe kendaraan tidak valid",
+
+		pkgerrors.ErrInvalidVehicleType.Code:   "tipe kendaraan tidak valid",
+		pkgerrors.ErrInvalidVehicleNumber.Code: "nomor kendaraan tidak valid",
+		pkgerrors.ErrVehicleAlreadyParked.Code: "kendaraan sudah terparkir",
+		pkgerrors.ErrReentryCooldown.Code:      "kendaraan baru saja keluar dan belum dapat parkir kembali",
+		pkgerrors.ErrVehicleNotParked.Code:     "kendaraan sedang tidak terparkir",
+		pkgerrors.ErrVehicleNotAtSpot.Code:     "kendaraan tidak terparkir di tempat parkir yang ditentukan",
+		pkgerrors.ErrVehicleNotFound.Code:      "kendaraan belum pernah terparkir di area parkir ini",
+		pkgerrors.ErrSpotOccupied.Code:         "tempat parkir sudah ditempati kendaraan lain",
+		pkgerrors.ErrSpotNotOccupied.Code:      "tempat parkir tidak terisi",
+		pkgerrors.ErrSpotDoesNotExist.Code:     "tidak ada tempat parkir di lokasi ini",
+		pkgerrors.ErrSpotFull.Code:             "tempat parkir sudah penuh",
+		pkgerrors.ErrSpotInactive.Code:         "tempat parkir tidak aktif",
+		pkgerrors.ErrSpotDeleted.Code:          "tempat parkir sudah dihapus",
+		pkgerrors.ErrInvalidPlateSuffix.Code:   "akhiran pelat nomor minimal 2 karakter",
+		pkgerrors.ErrVehicleNumberNeeded.Code:  "nomor kendaraan wajib diisi jika beberapa kendaraan terparkir di tempat parkir ini",
+
+		pkgerrors.ErrNoAvailableSpot.Code: "tidak ada tempat parkir tersedia untuk tipe kendaraan tersebut",
+
+		pkgerrors.ErrInvalidSpotBlock.Code:  "blokir tempat parkir tidak valid: perlu nama, tempat parkir atau zona, dan rentang waktu yang berakhir di masa depan",
+		pkgerrors.ErrSpotBlockNotFound.Code: "blokir tempat parkir tidak ditemukan atau sudah berakhir",
+		pkgerrors.ErrSpotBlocked.Code:       "tempat parkir diblokir untuk sebuah acara",
+
+		pkgerrors.ErrHoldNotFound.Code:           "reservasi tempat parkir tidak ditemukan atau sudah kedaluwarsa",
+		pkgerrors.ErrDuplicatePending.Code:       "nomor kendaraan ganda menunggu konfirmasi operator",
+		pkgerrors.ErrDuplicateRetrying.Code:      "nomor kendaraan ganda dicoba ulang di latar belakang",
+		pkgerrors.ErrRetryNotFound.Code:          "percobaan ulang parkir ganda tidak ditemukan",
+		pkgerrors.ErrInvalidDuplicatePolicy.Code: "kebijakan duplikat tidak valid: harus reject, retry, atau confirm",
+		pkgerrors.ErrInvalidDuplicateAction.Code: "aksi duplikat tidak valid: harus confirm atau reject",
+
+		pkgerrors.ErrCheckInNotFound.Code:  "check-in tidak ditemukan",
+		pkgerrors.ErrVehicleCheckedIn.Code: "kendaraan sudah menunggu tempat parkir",
+
+		pkgerrors.ErrInvalidWebhookURL.Code:       "URL webhook tidak valid: harus URL http atau https absolut",
+		pkgerrors.ErrInvalidEventType.Code:        "tipe event tidak valid: harus vehicle.parked, vehicle.unparked, lot.full, spot.reconfigured, device.stale, device.recovered, incident.started, incident.ended, atau lot.initialized",
+		pkgerrors.ErrSubscriptionNotFound.Code:    "langganan webhook tidak ditemukan",
+		pkgerrors.ErrWebhookTargetNotAllowed.Code: "URL webhook mengarah ke alamat privat, loopback atau link-local yang tidak diizinkan",
+
+		pkgerrors.ErrInvalidDirection.Code: "arah tidak valid: harus entry atau exit",
+
+		pkgerrors.ErrUnauthorized.Code: "kredensial tidak ada atau tidak valid",
+		pkgerrors.ErrForbidden.Code:    "izin tidak cukup untuk operasi ini",
+
+		pkgerrors.ErrInvalidRole.Code:      "peran tidak valid: harus attendant, enforcement, atau admin",
+		pkgerrors.ErrInvalidExpiry.Code:    "masa berlaku tidak valid: harus di masa depan",
+		pkgerrors.ErrInvalidAllowlist.Code: "daftar IP tidak valid: entri harus alamat IP atau rentang CIDR",
+		pkgerrors.ErrTokenNotFound.Code:    "token API tidak ditemukan",
+		pkgerrors.ErrTokenRevoked.Code:     "token API sudah dicabut",
+
+		pkgerrors.ErrInvalidDevice.Code:         "perangkat tidak valid: perlu nama dan jenis kiosk atau pay_station",
+		pkgerrors.ErrInvalidEnrollmentCode.Code: "kode pendaftaran tidak dikenal, sudah dipakai, atau kedaluwarsa",
+		pkgerrors.ErrDeviceSessionNotFound.Code: "sesi perangkat tidak ditemukan",
+		pkgerrors.ErrInvalidDeviceID.Code:       "ID perangkat tidak valid: harus 1 sampai 64 huruf, angka, tanda hubung, titik, atau garis bawah",
+		pkgerrors.ErrInvalidDeviceType.Code:     "jenis perangkat tidak valid: harus gate, kiosk, pay_station, atau sensor",
+		pkgerrors.ErrDeviceNotFound.Code:        "perangkat tidak ditemukan",
+		pkgerrors.ErrDeviceExists.Code:          "perangkat sudah terdaftar",
+		pkgerrors.ErrInvalidDeviceStatus.Code:   "status perangkat tidak valid: harus online atau stale",
+		pkgerrors.ErrInvalidCommand.Code:        "perintah tidak valid: aksi harus open_barrier, show_message, atau reboot",
+		pkgerrors.ErrDeviceNotConnected.Code:    "perangkat tidak terhubung",
+
+		pkgerrors.ErrInvalidBatteryLevel.Code:    "level baterai tidak valid: harus antara 0 dan 100",
+		pkgerrors.ErrInvalidFirmwareVersion.Code: "versi firmware tidak valid: harus berupa angka bertitik seperti 1.4.2",
+
+		pkgerrors.ErrLotEmergency.Code: "area parkir dalam mode darurat dan tidak menerima kendaraan",
+
+		pkgerrors.ErrInvalidOverrideAction.Code: "aksi override tidak valid: harus park atau unpark",
+		pkgerrors.ErrReasonRequired.Code:        "alasan wajib diisi untuk override manual",
+
+		pkgerrors.ErrInvalidViolationKind.Code: "jenis pelanggaran tidak valid: harus wrong_spot_type, overstay, atau blocking",
+		pkgerrors.ErrInvalidFine.Code:          "denda tidak valid: tidak boleh negatif",
+		pkgerrors.ErrViolationNotFound.Code:    "pelanggaran tidak ditemukan",
+		pkgerrors.ErrViolationResolved.Code:    "pelanggaran sudah diselesaikan",
+		pkgerrors.ErrVehicleFlagged.Code:       "kendaraan memiliki pelanggaran yang belum diselesaikan",
+
+		pkgerrors.ErrInvalidTimeWindow.Code: "rentang waktu tidak valid: from harus sebelum to",
+		pkgerrors.ErrEventsExpired.Code:     "log event tidak lagi mencakup waktu ini",
+		pkgerrors.ErrInvalidStep.Code:       "langkah tidak valid: harus positif",
+		pkgerrors.ErrInvalidPageSize.Code:   "ukuran halaman tidak valid",
+
+		pkgerrors.ErrTicketNotFound.Code: "tiket parkir tidak ditemukan",
+		pkgerrors.ErrTicketClosed.Code:   "tiket parkir sudah ditutup, kendaraan sudah keluar",
+
+		pkgerrors.ErrInvalidPayment.Code:  "pembayaran tidak valid: uang diterima harus positif, kembalian antara 0 dan uang diterima, dan ID laci kas wajib diisi",
+		pkgerrors.ErrPaymentNotFound.Code: "pembayaran tidak ditemukan",
+		pkgerrors.ErrInvalidRefund.Code:   "pengembalian dana tidak valid: jumlah harus positif dan tidak melebihi sisa pembayaran, dan alasan wajib diisi",
+
+		pkgerrors.ErrInvalidContact.Code: "kontak tidak valid: harus alamat email atau nomor telepon seperti +6281234567890",
+
+		pkgerrors.ErrStoreTimeout.Code: "penyimpanan data parkir tidak merespons tepat waktu",
+	},
+}
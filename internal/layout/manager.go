@@ -0,0 +1,197 @@
+package layout
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"parking-lot-system/internal/config"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/repository"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Manager builds the lot from its layout file and applies later changes of the file to the running lot
+type Manager struct {
+	path    string
+	service *parking.ParkingService
+
+	// serializes reloads
+	mutex  sync.Mutex
+	floors []repository.FloorDimensions
+	gates  int
+	source []byte // the layout last applied, as written in its file
+}
+
+// describes what a load or reload changed
+type Report struct {
+	Changed []string      // IDs of the reconfigured spots
+	Skipped []SkippedSpot // spots that kept their configuration, e.g. because vehicles are parked there
+}
+
+// a spot whose new configuration could not be applied
+type SkippedSpot struct {
+	SpotID string
+	Err    error
+}
+
+func NewManager(path string, service *parking.ParkingService) *Manager {
+	return &Manager{path: path, service: service}
+}
+
+// Load creates the lot's floors and gates and configures its spots
+func (m *Manager) Load() error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return err
+	}
+	layout, err := config.ParseLayout(m.path, data)
+	if err != nil {
+		return err
+	}
+
+	floors, gates := dimensions(layout)
+	if err := m.service.InitializeFloors(floors, gates, false); err != nil {
+		return err
+	}
+	m.floors, m.gates, m.source = floors, gates, data
+
+	report := m.apply(layout)
+	if len(report.Skipped) > 0 {
+		skipped := report.Skipped[0]
+		return fmt.Errorf("spot %s: %w", skipped.SpotID, skipped.Err)
+	}
+
+	return nil
+}
+
+// Reload re-reads the layout file and applies the changed spot configuration without touching
+// parked vehicles. Occupied spots keep their type until they are vacated and the layout is reloaded.
+// The floors and gates cannot change while the lot is running.
+func (m *Manager) Reload() (Report, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return Report{}, err
+	}
+	layout, err := m.parse(m.path, data)
+	if err != nil {
+		return Report{}, err
+	}
+
+	m.source = data
+	return m.apply(layout), nil
+}
+
+// Import replaces the layout file with a new layout and applies it like Reload, e.g. for a layout
+// edited off the server. The file is only replaced when the layout is valid and keeps the floors and gates.
+func (m *Manager) Import(data []byte) (Report, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	layout, err := m.parse("imported layout", data)
+	if err != nil {
+		return Report{}, err
+	}
+
+	// Write a copy next to the file and move it over, so a failed write leaves the file as it was
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return Report{}, err
+	}
+	if err := os.Rename(tmp, m.path); err != nil {
+		os.Remove(tmp)
+		return Report{}, err
+	}
+
+	m.source = data
+	return m.apply(layout), nil
+}
+
+// Export returns the layout last applied, as written in its file
+func (m *Manager) Export() []byte {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return slices.Clone(m.source)
+}
+
+// parse parses and checks a layout for the running lot, whose floors and gates cannot change
+func (m *Manager) parse(source string, data []byte) (*config.Layout, error) {
+	layout, err := config.ParseLayout(source, data)
+	if err != nil {
+		return nil, err
+	}
+
+	floors, gates := dimensions(layout)
+	if !reflect.DeepEqual(floors, m.floors) || gates != m.gates {
+		return nil, fmt.Errorf("%s: floors and gates cannot change while the lot is running, restart to apply them", source)
+	}
+	return layout, nil
+}
+
+// ReloadOnSignal reloads the layout whenever the process receives one of the signals, e.g. SIGHUP
+func (m *Manager) ReloadOnSignal(signals ...os.Signal) {
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	go func() {
+		for sig := range received {
+			report, err := m.Reload()
+			if err != nil {
+				log.Printf("Error reloading lot layout on %s: %v", sig, err)
+				continue
+			}
+			log.Printf("Reloaded lot layout on %s: %d spots changed, %d skipped", sig, len(report.Changed), len(report.Skipped))
+			for _, skipped := range report.Skipped {
+				log.Printf("Skipped spot %s: %v", skipped.SpotID, skipped.Err)
+			}
+		}
+	}()
+}
+
+// brings every spot to the configuration of the layout, spots without rules to the defaults
+func (m *Manager) apply(layout *config.Layout) Report {
+	settings := make(map[[3]int]parking.SpotSettings)
+	for _, spot := range layout.SpotConfigs() {
+		settings[[3]int{spot.Floor, spot.Row, spot.Column}] = parking.SpotSettings{
+			Type:     spot.Type,
+			Zone:     spot.Zone,
+			Size:     spot.Size,
+			Capacity: spot.Capacity,
+		}
+	}
+
+	report := Report{Changed: []string{}, Skipped: []SkippedSpot{}}
+	for f, grid := range layout.FloorGrids() {
+		for row := 0; row < grid.Rows; row++ {
+			for column := 0; column < grid.Columns; column++ {
+				changed, err := m.service.ReconfigureSpot(f, row, column, settings[[3]int{f, row, column}])
+				spotID := m.service.FormatSpotID(f, row, column)
+				if err != nil {
+					report.Skipped = append(report.Skipped, SkippedSpot{SpotID: spotID, Err: err})
+				} else if changed {
+					report.Changed = append(report.Changed, spotID)
+				}
+			}
+		}
+	}
+
+	return report
+}
+
+// returns the floor dimensions and the number of gates of a layout
+func dimensions(layout *config.Layout) ([]repository.FloorDimensions, int) {
+	floors := []repository.FloorDimensions{}
+	for _, grid := range layout.FloorGrids() {
+		floors = append(floors, repository.FloorDimensions{Rows: grid.Rows, Columns: grid.Columns})
+	}
+	return floors, layout.Gates
+}
@@ -0,0 +1,57 @@
+package layout
+
+import (
+	"bytes"
+	"os"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/domain/spotid"
+	"parking-lot-system/internal/repository"
+	"path/filepath"
+	"testing"
+)
+
+const testLayout = `gates: 1
+floors:
+  - rows: 1
+    columns: 2
+spots:
+  - type: A-1
+`
+
+func TestImportReplacesTheLayoutFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "layout.yaml")
+	if err := os.WriteFile(path, []byte(testLayout), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	manager := NewManager(path, parking.NewParkingService(repository.NewParkingRepository(spotid.Default)))
+	if err := manager.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if exported := manager.Export(); string(exported) != testLayout {
+		t.Fatalf("export after load: got %q, want the layout file", exported)
+	}
+
+	// a layout changing the floors is rejected and leaves the file alone
+	grown := bytes.Replace([]byte(testLayout), []byte("columns: 2"), []byte("columns: 3"), 1)
+	if _, err := manager.Import(grown); err == nil {
+		t.Fatal("import with another grid: want an error")
+	}
+	if data, _ := os.ReadFile(path); string(data) != testLayout {
+		t.Fatalf("layout file after a rejected import: got %q", data)
+	}
+
+	changed := []byte(testLayout + "  - columns: 1\n    type: M-1\n")
+	report, err := manager.Import(changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changed) != 1 || len(report.Skipped) != 0 {
+		t.Fatalf("import: got report %+v, want one changed spot", report)
+	}
+	if data, _ := os.ReadFile(path); !bytes.Equal(data, changed) {
+		t.Fatalf("layout file after the import: got %q", data)
+	}
+	if exported := manager.Export(); !bytes.Equal(exported, changed) {
+		t.Fatalf("export after the import: got %q", exported)
+	}
+}
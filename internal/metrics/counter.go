@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Counter counts events, separately for every combination of label values
+type Counter struct {
+	name   string
+	help   string
+	labels []string
+
+	mutex  sync.Mutex
+	series map[string]*counterSeries // joined label values -> series
+}
+
+// counterSeries holds the count of one combination of label values
+type counterSeries struct {
+	labelValues []string
+	value       uint64
+}
+
+// NewCounter creates a counter
+func NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: make(map[string]*counterSeries),
+	}
+}
+
+// Inc adds one, the label values are given in the order of the counter's labels
+func (c *Counter) Inc(labelValues ...string) {
+	if len(labelValues) != len(c.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", c.name, len(c.labels), len(labelValues)))
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	series, exists := c.series[key]
+	if !exists {
+		series = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		c.series[key] = series
+	}
+	series.value++
+}
+
+// WriteTo writes the counter in the Prometheus text exposition format
+func (c *Counter) WriteTo(w io.Writer) (int64, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "# HELP %s %s\n", c.name, c.help)
+	fmt.Fprintf(&out, "# TYPE %s counter\n", c.name)
+	for _, key := range sortedKeys(c.series) {
+		series := c.series[key]
+		fmt.Fprintf(&out, "%s%s %d\n", c.name, labelSet(c.labels, series.labelValues), series.value)
+	}
+
+	n, err := io.WriteString(w, out.String())
+	return int64(n), err
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Gauge holds a value that goes up and down, separately for every combination of label values
+type Gauge struct {
+	name   string
+	help   string
+	labels []string
+
+	mutex  sync.Mutex
+	series map[string]*gaugeSeries // joined label values -> series
+}
+
+// gaugeSeries holds the value of one combination of label values
+type gaugeSeries struct {
+	labelValues []string
+	value       int64
+}
+
+// NewGauge creates a gauge
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{
+		name:   name,
+		help:   help,
+		labels: labels,
+		series: make(map[string]*gaugeSeries),
+	}
+}
+
+// Inc adds one, the label values are given in the order of the gauge's labels
+func (g *Gauge) Inc(labelValues ...string) {
+	g.add(1, labelValues)
+}
+
+// Dec subtracts one, the label values are given in the order of the gauge's labels
+func (g *Gauge) Dec(labelValues ...string) {
+	g.add(-1, labelValues)
+}
+
+func (g *Gauge) add(delta int64, labelValues []string) {
+	if len(labelValues) != len(g.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", g.name, len(g.labels), len(labelValues)))
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	series, exists := g.series[key]
+	if !exists {
+		series = &gaugeSeries{labelValues: append([]string(nil), labelValues...)}
+		g.series[key] = series
+	}
+	series.value += delta
+}
+
+// WriteTo writes the gauge in the Prometheus text exposition format
+func (g *Gauge) WriteTo(w io.Writer) (int64, error) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "# HELP %s %s\n", g.name, g.help)
+	fmt.Fprintf(&out, "# TYPE %s gauge\n", g.name)
+	for _, key := range sortedKeys(g.series) {
+		series := g.series[key]
+		fmt.Fprintf(&out, "%s%s %d\n", g.name, labelSet(g.labels, series.labelValues), series.value)
+	}
+
+	n, err := io.WriteString(w, out.String())
+	return int64(n), err
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds in seconds of the buckets for in-process operations, 10µs to 100ms
+var LatencyBuckets = []float64{0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1}
+
+// Histogram counts observations into buckets, separately for every combination of label values
+type Histogram struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64 // upper bounds, ascending
+
+	mutex  sync.Mutex
+	series map[string]*histogramSeries // joined label values -> series
+}
+
+// histogramSeries holds the observations of one combination of label values
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // per bucket, not cumulative
+	count       uint64
+	sum         float64
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds, which must be ascending
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return &Histogram{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*histogramSeries),
+	}
+}
+
+// Observe adds a value, the label values are given in the order of the histogram's labels
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	if len(labelValues) != len(h.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", h.name, len(h.labels), len(labelValues)))
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	key := strings.Join(labelValues, "\xff")
+	series, exists := h.series[key]
+	if !exists {
+		series = &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = series
+	}
+
+	for i, bound := range h.buckets {
+		if value <= bound {
+			series.counts[i]++
+			break
+		}
+	}
+	series.count++
+	series.sum += value
+}
+
+// ObserveSince adds the time elapsed since start in seconds
+func (h *Histogram) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+// WriteTo writes the histogram in the Prometheus text exposition format
+func (h *Histogram) WriteTo(w io.Writer) (int64, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "# HELP %s %s\n", h.name, h.help)
+	fmt.Fprintf(&out, "# TYPE %s histogram\n", h.name)
+
+	bucketLabels := append(append([]string(nil), h.labels...), "le")
+	for _, key := range sortedKeys(h.series) {
+		series := h.series[key]
+		bucketValues := append(append([]string(nil), series.labelValues...), "")
+
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += series.counts[i]
+			bucketValues[len(bucketValues)-1] = strconv.FormatFloat(bound, 'g', -1, 64)
+			fmt.Fprintf(&out, "%s_bucket%s %d\n", h.name, labelSet(bucketLabels, bucketValues), cumulative)
+		}
+		bucketValues[len(bucketValues)-1] = "+Inf"
+		fmt.Fprintf(&out, "%s_bucket%s %d\n", h.name, labelSet(bucketLabels, bucketValues), series.count)
+
+		labels := labelSet(h.labels, series.labelValues)
+		fmt.Fprintf(&out, "%s_sum%s %s\n", h.name, labels, strconv.FormatFloat(series.sum, 'g', -1, 64))
+		fmt.Fprintf(&out, "%s_count%s %d\n", h.name, labels, series.count)
+	}
+
+	n, err := io.WriteString(w, out.String())
+	return int64(n), err
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Collector writes its metrics in the Prometheus text exposition format
+type Collector interface {
+	WriteTo(w io.Writer) (int64, error)
+}
+
+// Registry holds the collectors exposed to Prometheus
+type Registry struct {
+	mutex      sync.Mutex
+	collectors []Collector
+}
+
+// Default is the registry served on /metrics
+var Default = &Registry{}
+
+// Register adds collectors to the default registry
+func Register(collectors ...Collector) {
+	Default.Register(collectors...)
+}
+
+// Register adds collectors to the registry
+func (r *Registry) Register(collectors ...Collector) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// WriteTo writes the metrics of every collector in the order they were registered
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mutex.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mutex.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	var written int64
+	for _, collector := range collectors {
+		n, err := collector.WriteTo(buffered)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, buffered.Flush()
+}
+
+// labelSet formats label names and values as {name="value",...}
+func labelSet(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escapeLabelValue(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabelValue escapes backslashes, double quotes and line feeds as the exposition format requires
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+// sortedKeys returns the keys of a series map in order, so the output is stable between scrapes
+func sortedKeys[T any](series map[string]T) []string {
+	keys := make([]string, 0, len(series))
+	for key := range series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
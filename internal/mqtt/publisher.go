@@ -0,0 +1,176 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"parking-lot-system/internal/domain/parking"
+	"strconv"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+)
+
+const (
+	publishQueueSize = 1000
+	connectTimeout   = 10 * time.Second
+)
+
+// SpotState is the payload published on spot state changes
+type SpotState struct {
+	SpotID        string    `json:"spotId"`
+	Occupied      bool      `json:"occupied"`
+	VehicleNumber string    `json:"vehicleNumber,omitempty"`
+	VehicleType   string    `json:"vehicleType,omitempty"`
+	Event         string    `json:"event"`
+	Time          time.Time `json:"time"`
+}
+
+// IncidentState is the payload published when an incident starts or ends
+type IncidentState struct {
+	Active    bool       `json:"active"`
+	Reason    string     `json:"reason,omitempty"`
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+}
+
+type message struct {
+	topic    string
+	payload  []byte
+	retained bool
+}
+
+// Publisher forwards parking events to MQTT topics:
+//
+//	{prefix}/{floor}/available       free spot count of the floor (retained)
+//	{prefix}/{floor}/spots/{spotId}  spot state changes (retained)
+//	{prefix}/incident                emergency state of the lot (retained)
+type Publisher struct {
+	client  paho.Client
+	service *parking.ParkingService
+	prefix  string
+	queue   chan message
+}
+
+// NewPublisher connects to the broker and starts forwarding queued messages
+func NewPublisher(brokerURL, clientID, prefix string, service *parking.ParkingService) (*Publisher, error) {
+	opts := paho.NewClientOptions().
+		AddBroker(brokerURL).
+		SetClientID(clientID).
+		SetAutoReconnect(true).
+		SetConnectRetry(true)
+
+	client := paho.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		return nil, fmt.Errorf("timed out connecting to MQTT broker %s", brokerURL)
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("connecting to MQTT broker %s: %v", brokerURL, err)
+	}
+
+	p := &Publisher{
+		client:  client,
+		service: service,
+		prefix:  prefix,
+		queue:   make(chan message, publishQueueSize),
+	}
+	go p.run()
+
+	// Publish the initial availability so displays don't wait for the first event
+	for floor := range service.GetOccupancyStats().Floors {
+		p.publishAvailability(floor)
+	}
+	// A retained incident of a previous run must not outlive it
+	p.publishIncident()
+
+	return p, nil
+}
+
+// HandleEvent publishes the spot state and floor availability affected by an event
+func (p *Publisher) HandleEvent(event parking.Event) {
+	if event.Type == parking.EventIncidentStarted || event.Type == parking.EventIncidentEnded {
+		p.publishIncident()
+		return
+	}
+	if event.SpotID == "" {
+		return
+	}
+
+	floor, _, _, err := p.service.ParseSpotID(event.SpotID)
+	if err != nil {
+		return
+	}
+
+	payload, err := json.Marshal(SpotState{
+		SpotID:        event.SpotID,
+		Occupied:      event.Type == parking.EventVehicleParked,
+		VehicleNumber: event.VehicleNumber,
+		VehicleType:   event.VehicleType,
+		Event:         event.Type,
+		Time:          event.Time,
+	})
+	if err != nil {
+		return
+	}
+
+	p.enqueue(message{
+		topic:    fmt.Sprintf("%s/%d/spots/%s", p.prefix, floor, event.SpotID),
+		payload:  payload,
+		retained: true,
+	})
+	p.publishAvailability(floor)
+}
+
+// publishIncident queues the current emergency state of the lot, so displays and gates can react
+func (p *Publisher) publishIncident() {
+	incident := p.service.GetIncident()
+	state := IncidentState{Active: incident.Active, Reason: incident.Reason}
+	if incident.Active {
+		state.StartedAt = &incident.StartedAt
+	}
+
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	p.enqueue(message{
+		topic:    p.prefix + "/incident",
+		payload:  payload,
+		retained: true,
+	})
+}
+
+// publishAvailability queues the current free spot count of a floor
+func (p *Publisher) publishAvailability(floor int) {
+	floors := p.service.GetOccupancyStats().Floors
+	if floor < 0 || floor >= len(floors) {
+		return
+	}
+
+	available := floors[floor].Capacity - floors[floor].Occupied
+	p.enqueue(message{
+		topic:    fmt.Sprintf("%s/%d/available", p.prefix, floor),
+		payload:  []byte(strconv.Itoa(available)),
+		retained: true,
+	})
+}
+
+// enqueue hands a message to the publishing goroutine, dropping it when the queue is full
+func (p *Publisher) enqueue(msg message) {
+	select {
+	case p.queue <- msg:
+	default:
+		log.Printf("MQTT publish queue is full, dropping message for %s", msg.topic)
+	}
+}
+
+// run publishes queued messages
+func (p *Publisher) run() {
+	for msg := range p.queue {
+		token := p.client.Publish(msg.topic, 1, msg.retained, msg.payload)
+		if token.WaitTimeout(connectTimeout) && token.Error() != nil {
+			log.Printf("MQTT publish to %s failed: %v", msg.topic, token.Error())
+		}
+	}
+}
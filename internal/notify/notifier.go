@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Message is a notification for a single recipient
+type Message struct {
+	To      string // email address or phone number
+	Subject string // only sent by email
+	Body    string
+	// only sent by email: an HTML version of the body, none when empty, and attached files
+	HTML        string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to an email, e.g. a PDF receipt
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Notifier delivers notifications through one channel, e.g. email or SMS
+type Notifier interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// IsEmail reports whether a recipient is an email address
+func IsEmail(to string) bool {
+	local, domain, found := strings.Cut(to, "@")
+	return found && local != "" && strings.Contains(domain, ".") && !strings.ContainsAny(to, " \r\n")
+}
+
+// IsPhoneNumber reports whether a recipient is a phone number in international format, e.g. +6281234567890
+func IsPhoneNumber(to string) bool {
+	digits, found := strings.CutPrefix(to, "+")
+	if !found || len(digits) < 6 || len(digits) > 15 {
+		return false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Router sends notifications to email addresses by email and to phone numbers by SMS.
+// Either notifier may be nil when the deployment has no such channel.
+type Router struct {
+	Email Notifier
+	SMS   Notifier
+}
+
+// Send delivers a message through the channel matching its recipient
+func (r Router) Send(ctx context.Context, message Message) error {
+	switch {
+	case IsEmail(message.To) && r.Email != nil:
+		return r.Email.Send(ctx, message)
+	case IsPhoneNumber(message.To) && r.SMS != nil:
+		return r.SMS.Send(ctx, message)
+	default:
+		return fmt.Errorf("no notification channel configured for %q", message.To)
+	}
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const smsRequestTimeout = 10 * time.Second
+
+// SMSGatewayNotifier sends notifications as text messages through an HTTP SMS gateway, posting
+// {"to": "+6281234567890", "message": "..."} to its URL
+type SMSGatewayNotifier struct {
+	url    string
+	token  string // sent as a bearer token when set
+	client *http.Client
+}
+
+// NewSMSGatewayNotifier creates a notifier for an SMS gateway URL
+func NewSMSGatewayNotifier(url, token string) *SMSGatewayNotifier {
+	return &SMSGatewayNotifier{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: smsRequestTimeout},
+	}
+}
+
+// smsRequest is the body posted to the gateway
+type smsRequest struct {
+	To      string `json:"to"`
+	Message string `json:"message"`
+}
+
+// Send texts the body of a message, the subject is left out
+func (n *SMSGatewayNotifier) Send(ctx context.Context, message Message) error {
+	body, err := json.Marshal(smsRequest{To: message.To, Message: message.Body})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("SMS gateway responded with HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+// Package pdf writes plain text documents, such as receipts, as PDF files without external dependencies
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A6 page in points, the size of a printed receipt
+const (
+	pageWidth    = 298
+	pageHeight   = 420
+	margin       = 24
+	fontSize     = 10
+	lineHeight   = 14
+	linesPerPage = (pageHeight - 2*margin) / lineHeight
+)
+
+// Text lays out lines of text in Helvetica on A6 pages, starting a new page when one is full.
+// Characters outside Latin-1 are written as question marks.
+func Text(lines []string) []byte {
+	if len(lines) == 0 {
+		lines = []string{""}
+	}
+	var pages [][]string
+	for start := 0; start < len(lines); start += linesPerPage {
+		pages = append(pages, lines[start:min(start+linesPerPage, len(lines))])
+	}
+
+	// Objects 1 to 3 are the catalog, the page tree and the font, then a page and its content per page
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+	kids := make([]string, len(pages))
+	for i, page := range pages {
+		pageObject := len(objects) + 1
+		kids[i] = fmt.Sprintf("%d 0 R", pageObject)
+
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", fontSize, lineHeight, margin, pageHeight-margin-fontSize)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) Tj T*\n", escape(line))
+		}
+		content.WriteString("ET")
+
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, pageObject+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+// escape writes a line as the content of a PDF string, in the single byte encoding of the font
+func escape(line string) string {
+	var out strings.Builder
+	for _, r := range line {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			out.WriteByte('\\')
+			out.WriteRune(r)
+		case r == '\t':
+			out.WriteString("    ")
+		case r < 0x20 || r > 0xff:
+			out.WriteByte('?')
+		default:
+			out.WriteByte(byte(r))
+		}
+	}
+	return out.String()
+}
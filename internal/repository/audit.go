@@ -0,0 +1,33 @@
+package repository
+
+import "time"
+
+// represents an audited action performed by staff
+type AuditEntry struct {
+	Time          time.Time
+	Actor         string
+	Action        string
+	SpotID        string
+	VehicleNumber string
+	Reason        string
+}
+
+// AppendAudit records an audit entry
+func (r *InMemoryParkingRepository) AppendAudit(entry AuditEntry) {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	entry.Time = time.Now()
+	r.auditLog = append(r.auditLog, entry)
+}
+
+// GetAuditEntries returns all audit entries in the order they were recorded
+func (r *InMemoryParkingRepository) GetAuditEntries() []AuditEntry {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	entries := make([]AuditEntry, len(r.auditLog))
+	copy(entries, r.auditLog)
+
+	return entries
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"fmt"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sort"
+	"time"
+)
+
+// represents a gate controller, kiosk, pay station or sensor installed in the lot
+type Device struct {
+	ID           string // e.g. "entrance-kiosk-2"
+	Type         string // e.g. "gate", "kiosk", "pay_station", "sensor"
+	Location     string // where the device is installed, e.g. "Level 1, north entrance"
+	Gate         int    // gate the device is assigned to, 0 when none
+	RegisteredAt time.Time
+	LastSeen     time.Time // last heartbeat of the device, zero until then
+}
+
+// AddDevice registers a device, recording its registration time
+func (r *InMemoryParkingRepository) AddDevice(device Device) (Device, error) {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	if _, exists := r.devices[device.ID]; exists {
+		return Device{}, fmt.Errorf("%w: %s", pkgerrors.ErrDeviceExists, device.ID)
+	}
+
+	device.RegisteredAt = time.Now()
+	device.LastSeen = time.Time{}
+	r.devices[device.ID] = &device
+
+	return device, nil
+}
+
+// UpdateDevice changes the type, location and gate of a registered device
+func (r *InMemoryParkingRepository) UpdateDevice(device Device) (Device, error) {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	registered, exists := r.devices[device.ID]
+	if !exists {
+		return Device{}, fmt.Errorf("%w: %s", pkgerrors.ErrDeviceNotFound, device.ID)
+	}
+
+	registered.Type = device.Type
+	registered.Location = device.Location
+	registered.Gate = device.Gate
+
+	return *registered, nil
+}
+
+// RemoveDevice unregisters a device and returns it
+func (r *InMemoryParkingRepository) RemoveDevice(id string) (Device, error) {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	device, exists := r.devices[id]
+	if !exists {
+		return Device{}, fmt.Errorf("%w: %s", pkgerrors.ErrDeviceNotFound, id)
+	}
+	delete(r.devices, id)
+
+	return *device, nil
+}
+
+// TouchDevice records that a registered device was seen at a moment and returns it
+func (r *InMemoryParkingRepository) TouchDevice(id string, at time.Time) (Device, error) {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	device, exists := r.devices[id]
+	if !exists {
+		return Device{}, fmt.Errorf("%w: %s", pkgerrors.ErrDeviceNotFound, id)
+	}
+	if at.After(device.LastSeen) {
+		device.LastSeen = at
+	}
+
+	return *device, nil
+}
+
+// GetDevice returns a registered device
+func (r *InMemoryParkingRepository) GetDevice(id string) (Device, error) {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	device, exists := r.devices[id]
+	if !exists {
+		return Device{}, fmt.Errorf("%w: %s", pkgerrors.ErrDeviceNotFound, id)
+	}
+
+	return *device, nil
+}
+
+// GetDevices returns all registered devices ordered by ID
+func (r *InMemoryParkingRepository) GetDevices() []Device {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	devices := make([]Device, 0, len(r.devices))
+	for _, device := range r.devices {
+		devices = append(devices, *device)
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].ID < devices[j].ID
+	})
+
+	return devices
+}
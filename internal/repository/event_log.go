@@ -0,0 +1,93 @@
+package repository
+
+import "time"
+
+// represents an event published by the parking service, as stored in the event log
+type EventRecord struct {
+	ID            int
+	Type          string
+	Time          time.Time
+	SpotID        string
+	VehicleNumber string
+	VehicleType   string
+	Gate          int
+	Device        string
+	Reason        string
+}
+
+// selects events from the event log, empty fields match all events
+type EventFilter struct {
+	Type          string
+	VehicleNumber string
+	SpotID        string
+	Device        string
+	From          time.Time // inclusive, zero for no lower bound
+	To            time.Time // exclusive, zero for no upper bound
+	After         int       // only events with a greater ID, for paging through the log
+	Limit         int       // maximum number of events returned, 0 for all
+}
+
+// matches reports whether an event is selected by the filter, ignoring After and Limit
+func (f EventFilter) matches(event *EventRecord) bool {
+	return (f.Type == "" || event.Type == f.Type) &&
+		(f.VehicleNumber == "" || event.VehicleNumber == f.VehicleNumber) &&
+		(f.SpotID == "" || event.SpotID == f.SpotID) &&
+		(f.Device == "" || event.Device == f.Device) &&
+		(f.From.IsZero() || !event.Time.Before(f.From)) &&
+		(f.To.IsZero() || event.Time.Before(f.To))
+}
+
+// AppendEvent records an event in the event log and returns it with its ID assigned
+func (r *InMemoryParkingRepository) AppendEvent(event EventRecord) EventRecord {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	event.ID = r.droppedEvents + len(r.events) + 1
+	r.events = append(r.events, &event)
+
+	return event
+}
+
+// GetEvents returns the events selected by a filter in the order they were recorded,
+// and whether more events after the last returned one match the filter
+func (r *InMemoryParkingRepository) GetEvents(filter EventFilter) ([]EventRecord, bool) {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	events := []EventRecord{}
+	// events are numbered in the order they were recorded
+	for _, event := range r.events[min(max(filter.After-r.droppedEvents, 0), len(r.events)):] {
+		if !filter.matches(event) {
+			continue
+		}
+		if filter.Limit > 0 && len(events) == filter.Limit {
+			return events, true
+		}
+		events = append(events, *event)
+	}
+
+	return events, false
+}
+
+// DropEvents drops the oldest events until at most keep are left and returns the dropped ones,
+// oldest first. The IDs of the events kept do not change.
+func (r *InMemoryParkingRepository) DropEvents(keep int) []EventRecord {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	n := len(r.events) - max(keep, 0)
+	if n <= 0 {
+		return nil
+	}
+
+	dropped := make([]EventRecord, n)
+	for i, event := range r.events[:n] {
+		dropped[i] = *event
+	}
+	// let the dropped events be collected before the slice grows again
+	clear(r.events[:n])
+	r.events = r.events[n:]
+	r.droppedEvents += n
+
+	return dropped
+}
@@ -0,0 +1,124 @@
+//go:build chaos
+
+package repository
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	pkgerrors "parking-lot-system/pkg/errors"
+)
+
+// FaultConfig describes the faults a FaultInjectingRepository injects
+type FaultConfig struct {
+	Latency      time.Duration // added to every call that may fail
+	TimeoutRate  float64       // fraction of those calls failing with pkgerrors.ErrStoreTimeout
+	ConflictRate float64       // fraction of spot claims failing as if a concurrent request took the spot first
+}
+
+// FaultInjectingRepository wraps a repository and makes the calls a remote store could fail
+// slow or fail, so that the degradation of the service and handlers can be checked.
+// Calls that only clean up, such as ReleaseHold, and the lot configuration are passed through.
+// It is only built with the chaos build tag.
+type FaultInjectingRepository struct {
+	ParkingRepository
+	faults FaultConfig
+
+	mutex  sync.Mutex
+	random *rand.Rand
+}
+
+func NewFaultInjectingRepository(repo ParkingRepository, faults FaultConfig, seed int64) *FaultInjectingRepository {
+	return &FaultInjectingRepository{
+		ParkingRepository: repo,
+		faults:            faults,
+		random:            rand.New(rand.NewSource(seed)),
+	}
+}
+
+// roll reports whether an event of the given rate happens
+func (r *FaultInjectingRepository) roll(rate float64) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.random.Float64() < rate
+}
+
+// fault delays a call and returns the error it fails with, nil when it goes through.
+// Claims of a spot may also fail with a conflict.
+func (r *FaultInjectingRepository) fault(call, spotID string) error {
+	time.Sleep(r.faults.Latency)
+
+	if r.roll(r.faults.TimeoutRate) {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrStoreTimeout, call)
+	}
+	if spotID != "" && r.roll(r.faults.ConflictRate) {
+		return fmt.Errorf("%w: %s (injected conflict)", pkgerrors.ErrSpotFull, spotID)
+	}
+	return nil
+}
+
+func (r *FaultInjectingRepository) FindAvailableSpot(vehicleType, zone, size string) (string, error) {
+	if err := r.fault("FindAvailableSpot", ""); err != nil {
+		return "", err
+	}
+	return r.ParkingRepository.FindAvailableSpot(vehicleType, zone, size)
+}
+
+func (r *FaultInjectingRepository) ParkVehicle(spotID, vehicleNumber, vehicleType string) error {
+	if err := r.fault("ParkVehicle", spotID); err != nil {
+		return err
+	}
+	return r.ParkingRepository.ParkVehicle(spotID, vehicleNumber, vehicleType)
+}
+
+func (r *FaultInjectingRepository) HoldSpot(spotID string) error {
+	if err := r.fault("HoldSpot", spotID); err != nil {
+		return err
+	}
+	return r.ParkingRepository.HoldSpot(spotID)
+}
+
+func (r *FaultInjectingRepository) ConfirmHold(spotID, vehicleNumber, vehicleType string) error {
+	if err := r.fault("ConfirmHold", spotID); err != nil {
+		return err
+	}
+	return r.ParkingRepository.ConfirmHold(spotID, vehicleNumber, vehicleType)
+}
+
+func (r *FaultInjectingRepository) UnparkVehicle(floor, row, column int, vehicleNumber string) error {
+	if err := r.fault("UnparkVehicle", ""); err != nil {
+		return err
+	}
+	return r.ParkingRepository.UnparkVehicle(floor, row, column, vehicleNumber)
+}
+
+func (r *FaultInjectingRepository) IsVehicleParked(vehicleNumber string) (bool, string, error) {
+	if err := r.fault("IsVehicleParked", ""); err != nil {
+		return false, "", err
+	}
+	return r.ParkingRepository.IsVehicleParked(vehicleNumber)
+}
+
+func (r *FaultInjectingRepository) SearchVehicle(vehicleNumber string) (string, bool, error) {
+	if err := r.fault("SearchVehicle", ""); err != nil {
+		return "", false, err
+	}
+	return r.ParkingRepository.SearchVehicle(vehicleNumber)
+}
+
+func (r *FaultInjectingRepository) GetAvailableSpots(vehicleType, zone, size string) ([]string, error) {
+	if err := r.fault("GetAvailableSpots", ""); err != nil {
+		return nil, err
+	}
+	return r.ParkingRepository.GetAvailableSpots(vehicleType, zone, size)
+}
+
+func (r *FaultInjectingRepository) GetAvailableCount(vehicleType string, floor int) (int, error) {
+	if err := r.fault("GetAvailableCount", ""); err != nil {
+		return 0, err
+	}
+	return r.ParkingRepository.GetAvailableCount(vehicleType, floor)
+}
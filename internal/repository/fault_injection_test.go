@@ -0,0 +1,58 @@
+//go:build chaos
+
+package repository
+
+import (
+	"errors"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestFaultInjectingRepositoryTimesOut(t *testing.T) {
+	repo := NewFaultInjectingRepository(newTestLot(t, 1, 1, 2), FaultConfig{TimeoutRate: 1}, 1)
+
+	if _, err := repo.FindAvailableSpot("Automobile", "", ""); !errors.Is(err, pkgerrors.ErrStoreTimeout) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrStoreTimeout)
+	}
+	if err := repo.ParkVehicle("0-0-0", "AB1", "Automobile"); !errors.Is(err, pkgerrors.ErrStoreTimeout) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrStoreTimeout)
+	}
+
+	// The lot is left as it was, and calls that are not faulted pass through
+	if stats := repo.GetOccupancyStats(); stats.Total.Occupied != 0 || stats.Total.Capacity != 2 {
+		t.Fatalf("got %+v after the timeouts, want an empty lot of 2", stats.Total)
+	}
+}
+
+func TestFaultInjectingRepositoryConflictsAndDelays(t *testing.T) {
+	const latency = 20 * time.Millisecond
+	repo := NewFaultInjectingRepository(newTestLot(t, 1, 1, 2), FaultConfig{Latency: latency, ConflictRate: 1}, 1)
+
+	start := time.Now()
+	if err := repo.ParkVehicle("0-0-0", "AB1", "Automobile"); !errors.Is(err, pkgerrors.ErrSpotFull) {
+		t.Fatalf("got %v, want %v", err, pkgerrors.ErrSpotFull)
+	}
+	if elapsed := time.Since(start); elapsed < latency {
+		t.Fatalf("the call took %v, want at least %v", elapsed, latency)
+	}
+
+	// Lookups are only delayed, conflicts are for claims of a spot
+	if _, err := repo.FindAvailableSpot("Automobile", "", ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFaultInjectingRepositoryIsReproducible(t *testing.T) {
+	faults := FaultConfig{TimeoutRate: 0.5}
+	first := NewFaultInjectingRepository(newTestLot(t, 1, 1, 1), faults, 42)
+	second := NewFaultInjectingRepository(newTestLot(t, 1, 1, 1), faults, 42)
+
+	for i := 0; i < 50; i++ {
+		_, err1 := first.FindAvailableSpot("Automobile", "", "")
+		_, err2 := second.FindAvailableSpot("Automobile", "", "")
+		if (err1 == nil) != (err2 == nil) {
+			t.Fatalf("call %d: got %v and %v with the same seed", i, err1, err2)
+		}
+	}
+}
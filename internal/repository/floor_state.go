@@ -0,0 +1,62 @@
+package repository
+
+import (
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sync"
+)
+
+// represents the state of a single floor. Each floor has its own lock so parking
+// on one floor does not wait for changes on another.
+type floorState struct {
+	mutex sync.RWMutex // guards the floor's spots and everything below
+
+	// Occupancy counters, kept in sync on every spot state change
+	count      OccupancyCount
+	typeCounts map[string]*OccupancyCount // vehicleType -> count
+	zoneCounts map[string]*OccupancyCount // zone -> count
+
+	// Spots with room left, listed by vehicle type, zone and size
+	freeSpots map[freeKey]*freeList
+}
+
+func newFloorState() *floorState {
+	return &floorState{
+		typeCounts: make(map[string]*OccupancyCount),
+		zoneCounts: make(map[string]*OccupancyCount),
+		freeSpots:  make(map[freeKey]*freeList),
+	}
+}
+
+// lockSpot write-locks the floor of a spot and returns the spot with the function releasing the locks
+func (r *InMemoryParkingRepository) lockSpot(floor, row, column int) (*ParkingSpot, func(), error) {
+	r.mutex.RLock()
+	if !r.isValidLocation(floor, row, column) {
+		r.mutex.RUnlock()
+		return nil, nil, pkgerrors.ErrInvalidLocation
+	}
+
+	state := r.floorStates[floor]
+	state.mutex.Lock()
+
+	return r.spot(floor, row, column), func() {
+		state.mutex.Unlock()
+		r.mutex.RUnlock()
+	}, nil
+}
+
+// rlockSpot read-locks the floor of a spot and returns the spot with the function releasing the locks
+func (r *InMemoryParkingRepository) rlockSpot(floor, row, column int) (*ParkingSpot, func(), error) {
+	r.mutex.RLock()
+	if !r.isValidLocation(floor, row, column) {
+		r.mutex.RUnlock()
+		return nil, nil, pkgerrors.ErrInvalidLocation
+	}
+
+	state := r.floorStates[floor]
+	state.mutex.RLock()
+
+	return r.spot(floor, row, column), func() {
+		state.mutex.RUnlock()
+		r.mutex.RUnlock()
+	}, nil
+}
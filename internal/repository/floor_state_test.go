@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sync"
+	"testing"
+)
+
+// TestConcurrentParkUnpark parks and unparks from many goroutines on every floor at once, with
+// readers of the counters alongside, then checks that the counters, the spots, the vehicle records
+// and the free lists agree. Run with -race.
+func TestConcurrentParkUnpark(t *testing.T) {
+	const (
+		floors  = 4
+		rows    = 5
+		columns = 10
+		workers = 16
+		rounds  = 300
+	)
+
+	repo := newTestLot(t, floors, rows, columns)
+	// the first row of every floor takes two vehicles per spot
+	for f := 0; f < floors; f++ {
+		for col := 0; col < columns; col++ {
+			if err := repo.SetSpotCapacity(f, 0, col, 2); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	parked := make([][]string, workers) // vehicles each worker left parked
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			random := rand.New(rand.NewSource(int64(w)))
+			var mine []string
+
+			for i := 0; i < rounds; i++ {
+				vehicleNumber := fmt.Sprintf("W%d-%d", w, i)
+
+				// Half the workers let the lot allocate, which crowds the lower floors, the other
+				// half pick spots on their own floor
+				var spotID string
+				if w%2 == 0 {
+					var err error
+					if spotID, err = repo.FindAvailableSpot("Automobile", "", ""); err != nil {
+						spotID = ""
+					}
+				} else {
+					spotID = repo.FormatSpotID(w/2%floors, random.Intn(rows), random.Intn(columns))
+				}
+
+				if spotID != "" {
+					err := repo.ParkVehicle(spotID, vehicleNumber, "")
+					switch {
+					case err == nil:
+						mine = append(mine, vehicleNumber)
+					case !errors.Is(err, pkgerrors.ErrSpotFull):
+						t.Errorf("park %s at %s: %v", vehicleNumber, spotID, err)
+						return
+					}
+				}
+
+				// Unpark the oldest vehicle every other round, so the lot keeps filling and emptying
+				if i%2 == 1 && len(mine) > 0 {
+					_, spotID, _ := repo.IsVehicleParked(mine[0])
+					floor, row, col, _ := repo.ParseSpotID(spotID)
+					if err := repo.UnparkVehicle(floor, row, col, mine[0]); err != nil {
+						t.Errorf("unpark %s from %s: %v", mine[0], spotID, err)
+						return
+					}
+					mine = mine[1:]
+				}
+			}
+			parked[w] = mine
+		}(w)
+	}
+
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				repo.GetOccupancyStats()
+				repo.GetAvailableCount("Automobile", -1)
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+	readers.Wait()
+	if t.Failed() {
+		return
+	}
+
+	var vehicles []string
+	for _, mine := range parked {
+		vehicles = append(vehicles, mine...)
+	}
+	checkLotState(t, repo, vehicles)
+
+	// Emptying the lot brings every counter back to zero and every spot back on the free lists
+	for _, vehicleNumber := range vehicles {
+		_, spotID, _ := repo.IsVehicleParked(vehicleNumber)
+		floor, row, col, _ := repo.ParseSpotID(spotID)
+		if err := repo.UnparkVehicle(floor, row, col, vehicleNumber); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkLotState(t, repo, nil)
+}
+
+// checkLotState checks that exactly the given vehicles are parked and that the counters and free
+// lists of every floor match its spots
+func checkLotState(t *testing.T, repo *InMemoryParkingRepository, vehicles []string) {
+	t.Helper()
+
+	stats := repo.GetOccupancyStats()
+	if stats.Total.Occupied != len(vehicles) {
+		t.Errorf("total occupied %d, want %d parked vehicles", stats.Total.Occupied, len(vehicles))
+	}
+	for _, vehicleNumber := range vehicles {
+		isParked, spotID, _ := repo.IsVehicleParked(vehicleNumber)
+		if !isParked {
+			t.Errorf("vehicle %s is not parked", vehicleNumber)
+			continue
+		}
+		floor, row, col, _ := repo.ParseSpotID(spotID)
+		if spot, _ := repo.GetSpot(floor, row, col); !spot.HasVehicle(vehicleNumber) {
+			t.Errorf("vehicle %s is not at its spot %s", vehicleNumber, spotID)
+		}
+	}
+
+	for f, state := range repo.floorStates {
+		spots, _ := repo.GetFloorSpots(f)
+		var want OccupancyCount
+		free := make(map[*ParkingSpot]bool)
+		for _, row := range spots {
+			for _, spot := range row {
+				if spot.Blocked {
+					want.Blocked += spot.Capacity
+					want.BlockedOccupied += len(spot.Vehicles) + spot.Held
+				} else {
+					want.Capacity += spot.Capacity
+					want.Occupied += len(spot.Vehicles) + spot.Held
+				}
+				if !spot.IsFull() {
+					free[repo.spot(spot.Floor, spot.Row, spot.Column)] = true
+				}
+			}
+		}
+		if stats.Floors[f] != want {
+			t.Errorf("floor %d counters %+v, want %+v", f, stats.Floors[f], want)
+		}
+
+		listed := 0
+		if list := state.freeSpots[lookupKey("Automobile", "", "")]; list != nil {
+			for spot := list.head; spot != nil; spot = spot.free.links[freeListsPerSpot-1].next {
+				if !free[spot] {
+					t.Errorf("floor %d lists full spot %d-%d-%d as free", f, spot.Floor, spot.Row, spot.Column)
+				}
+				listed++
+			}
+		}
+		if listed != len(free) {
+			t.Errorf("floor %d lists %d free spots, want %d", f, listed, len(free))
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+// identifies a free list of a floor. Every free spot is on four lists: the one of its exact zone and
+// size, and the ones matching any zone, any size, or both, so a lookup is a single map access whatever
+// the request leaves open.
+type freeKey struct {
+	vehicleType string
+	zone        string
+	size        string
+	anyZone     bool
+	anySize     bool
+}
+
+// number of free lists a spot is on, see freeKey
+const freeListsPerSpot = 4
+
+// lists returns the keys of the free lists a spot of a group is on, in the order of freeSlot.lists
+func (k freeKey) lists() [freeListsPerSpot]freeKey {
+	return [freeListsPerSpot]freeKey{
+		{vehicleType: k.vehicleType, zone: k.zone, size: k.size},
+		{vehicleType: k.vehicleType, size: k.size, anyZone: true},
+		{vehicleType: k.vehicleType, zone: k.zone, anySize: true},
+		{vehicleType: k.vehicleType, anyZone: true, anySize: true},
+	}
+}
+
+// lookupKey returns the key of the free list answering a request, an empty zone or size matches any
+func lookupKey(vehicleType, zone, size string) freeKey {
+	return freeKey{vehicleType: vehicleType, zone: zone, size: size, anyZone: zone == "", anySize: size == ""}
+}
+
+// links of a spot in one free list
+type freeLink struct {
+	prev, next *ParkingSpot
+}
+
+// position of a spot in the free lists of its floor
+type freeSlot struct {
+	lists [freeListsPerSpot]*freeList // lists the spot is on, nil when it is not free
+	links [freeListsPerSpot]freeLink
+}
+
+// doubly linked list of free spots, linked through the spots themselves. Spots are handed out from the
+// head and join at the tail, so a newly configured floor fills in row and column order and vacated
+// spots are reused after the ones that have been free longer.
+type freeList struct {
+	head, tail *ParkingSpot
+}
+
+// push adds a spot at the tail, using its links at position i
+func (l *freeList) push(spot *ParkingSpot, i int) {
+	spot.free.links[i] = freeLink{prev: l.tail}
+	if l.tail != nil {
+		l.tail.free.links[i].next = spot
+	} else {
+		l.head = spot
+	}
+	l.tail = spot
+}
+
+// remove takes a spot out of the list, using its links at position i
+func (l *freeList) remove(spot *ParkingSpot, i int) {
+	link := spot.free.links[i]
+	if link.prev != nil {
+		link.prev.free.links[i].next = link.next
+	} else {
+		l.head = link.next
+	}
+	if link.next != nil {
+		link.next.free.links[i].prev = link.prev
+	} else {
+		l.tail = link.prev
+	}
+	spot.free.links[i] = freeLink{}
+}
+
+// updateFreeIndex moves a spot in or out of the free lists of its floor after its state changed,
+// in constant time. Must be called with the floor's write lock held.
+func (r *InMemoryParkingRepository) updateFreeIndex(spot *ParkingSpot) {
+	state := r.floorStates[spot.Floor]
+	key := freeKey{vehicleType: spot.VehicleType, zone: spot.Zone, size: spot.Size}
+	available := spot.Allocatable() && !spot.IsFull()
+
+	if spot.free.lists[0] != nil {
+		// the first list is the one of the spot's exact group, its zone and size may have changed since
+		if available && spot.free.lists[0] == state.freeSpots[key] {
+			return
+		}
+		for i, free := range spot.free.lists {
+			free.remove(spot, i)
+		}
+		spot.free.lists = [freeListsPerSpot]*freeList{}
+	}
+
+	if !available {
+		return
+	}
+
+	for i, listKey := range key.lists() {
+		free, exists := state.freeSpots[listKey]
+		if !exists {
+			free = &freeList{}
+			state.freeSpots[listKey] = free
+		}
+		free.push(spot, i)
+		spot.free.lists[i] = free
+	}
+}
+
+// firstFreeSpot returns a free spot of the floor for a vehicle type, restricted to a zone and a
+// spot size unless they are empty, or nil when there is none. It takes the head of a single list,
+// so its cost does not depend on the size of the floor. Must be called with the floor's lock held.
+func (s *floorState) firstFreeSpot(vehicleType, zone, size string) *ParkingSpot {
+	if free, exists := s.freeSpots[lookupKey(vehicleType, zone, size)]; exists {
+		return free.head
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"fmt"
+	"parking-lot-system/internal/domain/spotid"
+	"testing"
+)
+
+// newTestLot returns a lot of one vehicle type where every spot is active
+func newTestLot(tb testing.TB, floors, rows, columns int) *InMemoryParkingRepository {
+	tb.Helper()
+
+	repo := NewParkingRepository(spotid.Default).(*InMemoryParkingRepository)
+	if err := repo.InitializeParkingLot(floors, rows, columns, 1, false); err != nil {
+		tb.Fatal(err)
+	}
+	for f := 0; f < floors; f++ {
+		for row := 0; row < rows; row++ {
+			for col := 0; col < columns; col++ {
+				if err := repo.ConfigureSpot(f, row, col, "Automobile", "", true, true); err != nil {
+					tb.Fatal(err)
+				}
+			}
+		}
+	}
+	return repo
+}
+
+func TestFindAvailableSpotFillsInOrder(t *testing.T) {
+	repo := newTestLot(t, 2, 2, 2)
+
+	want := []string{"0-0-0", "0-0-1", "0-1-0", "0-1-1", "1-0-0"}
+	for i, spotID := range want {
+		got, err := repo.FindAvailableSpot("Automobile", "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != spotID {
+			t.Fatalf("park %d: got spot %s, want %s", i, got, spotID)
+		}
+		if err := repo.ParkVehicle(got, fmt.Sprintf("B%d", i), ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A vacated spot on a lower floor is found before the free spots above it
+	if err := repo.UnparkVehicle(0, 1, 0, "B2"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := repo.FindAvailableSpot("Automobile", "", ""); got != "0-1-0" {
+		t.Fatalf("got spot %s, want the vacated spot 0-1-0", got)
+	}
+}
+
+func TestFindAvailableSpotMatchesZoneAndSize(t *testing.T) {
+	repo := newTestLot(t, 1, 1, 3)
+	repo.SetSpotZone(0, 0, 1, "A")
+	repo.SetSpotSize(0, 0, 2, "large")
+	repo.SetSpotZone(0, 0, 2, "A")
+
+	tests := []struct {
+		zone, size string
+		want       string
+	}{
+		{"", "", "0-0-0"},
+		{"A", "", "0-0-1"},
+		{"", "large", "0-0-2"},
+		{"A", "large", "0-0-2"},
+		{"B", "", ""},
+	}
+	for _, tt := range tests {
+		got, err := repo.FindAvailableSpot("Automobile", tt.zone, tt.size)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("zone %q size %q: got spot %s, want no spot", tt.zone, tt.size, got)
+			}
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("zone %q size %q: got spot %s (%v), want %s", tt.zone, tt.size, got, err, tt.want)
+		}
+	}
+
+	// Spots leave every list they are on once full, and come back when vacated
+	repo.ParkVehicle("0-0-2", "L1", "")
+	if got, err := repo.FindAvailableSpot("Automobile", "", "large"); err == nil {
+		t.Fatalf("got full spot %s", got)
+	}
+	repo.UnparkVehicle(0, 0, 2, "L1")
+	if got, _ := repo.FindAvailableSpot("Automobile", "A", "large"); got != "0-0-2" {
+		t.Fatalf("got spot %s, want the vacated spot 0-0-2", got)
+	}
+}
+
+// BenchmarkFindAvailableSpot finds the only active spot of a lot, the last one, which a scan of the
+// lot reaches last. The time per allocation stays the same from ten thousand to a million spots.
+func BenchmarkFindAvailableSpot(b *testing.B) {
+	for _, side := range []int{100, 316, 1000} {
+		b.Run(fmt.Sprintf("spots=%d", side*side), func(b *testing.B) {
+			repo := newTestLot(b, 1, side, side)
+			for row := 0; row < side; row++ {
+				for col := 0; col < side; col++ {
+					if row != side-1 || col != side-1 {
+						repo.ConfigureSpot(0, row, col, "Automobile", "", false, true)
+					}
+				}
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.FindAvailableSpot("Automobile", "", ""); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkParkUnpark allocates, parks and vacates a spot of a half full million-spot lot
+func BenchmarkParkUnpark(b *testing.B) {
+	repo := newTestLot(b, 1, 1000, 1000)
+	for i := 0; i < 500_000; i++ {
+		repo.ParkVehicle(repo.FormatSpotID(0, i/1000, i%1000), fmt.Sprintf("P%d", i), "")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		spotID, err := repo.FindAvailableSpot("Automobile", "", "")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := repo.ParkVehicle(spotID, "BENCH", ""); err != nil {
+			b.Fatal(err)
+		}
+		floor, row, col, _ := repo.ParseSpotID(spotID)
+		if err := repo.UnparkVehicle(floor, row, col, "BENCH"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkInitializeParkingLot measures laying out a floor of a million spots, which takes a single
+// allocation for the spots
+func BenchmarkInitializeParkingLot(b *testing.B) {
+	repo := NewParkingRepository(spotid.Default).(*InMemoryParkingRepository)
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if err := repo.InitializeParkingLot(1, 1000, 1000, 1, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
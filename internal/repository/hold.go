@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"fmt"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// HoldSpot reserves a place at the specified spot for a vehicle that is about to park.
+// A held place counts as occupied until the hold is confirmed or released.
+func (r *InMemoryParkingRepository) HoldSpot(spotID string) error {
+	floor, row, col, err := r.ids.Parse(spotID)
+	if err != nil {
+		return err
+	}
+
+	spot, unlock, err := r.lockSpot(floor, row, col)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !spot.Exists {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotDoesNotExist, spotID)
+	}
+	if spot.Deleted {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotDeleted, spotID)
+	}
+	if !spot.IsActive {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotInactive, spotID)
+	}
+	if spot.IsFull() {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotFull, spotID)
+	}
+
+	r.updateCounters(spot, -1)
+	spot.Held++
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
+
+	return nil
+}
+
+// ReleaseHold gives a held place at the specified spot back
+func (r *InMemoryParkingRepository) ReleaseHold(spotID string) error {
+	floor, row, col, err := r.ids.Parse(spotID)
+	if err != nil {
+		return err
+	}
+
+	spot, unlock, err := r.lockSpot(floor, row, col)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if spot.Held == 0 {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrHoldNotFound, spotID)
+	}
+
+	r.updateCounters(spot, -1)
+	spot.Held--
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
+
+	return nil
+}
+
+// ConfirmHold parks a vehicle in a held place at the specified spot. An empty vehicle type
+// stands for the type the spot is configured for.
+func (r *InMemoryParkingRepository) ConfirmHold(spotID, vehicleNumber, vehicleType string) error {
+	floor, row, col, err := r.ids.Parse(spotID)
+	if err != nil {
+		return err
+	}
+
+	spot, unlock, err := r.lockSpot(floor, row, col)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if spot.Held == 0 {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrHoldNotFound, spotID)
+	}
+
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	if currentSpotID, exists := r.vehicleMap[vehicleNumber]; exists {
+		return fmt.Errorf("%w: %s at spot %s", pkgerrors.ErrVehicleAlreadyParked, vehicleNumber, currentSpotID)
+	}
+
+	r.updateCounters(spot, -1)
+	spot.Held--
+	spot.Vehicles = append(spot.Vehicles, vehicleNumber)
+	spot.ChangedAt = time.Now()
+	spot.TimesUsed++
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
+
+	if vehicleType == "" {
+		vehicleType = spot.VehicleType
+	}
+
+	r.vehicleMap[vehicleNumber] = spotID
+	r.suffixes.add(vehicleNumber)
+	r.startSession(vehicleNumber, vehicleType, spotID, spot.VehicleType)
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// dimensions of the lot the large lot benchmarks run on, 8 million spots
+const (
+	largeFloors  = 8
+	largeRows    = 1000
+	largeColumns = 1000
+)
+
+var (
+	largeLotOnce sync.Once
+	largeLot     *InMemoryParkingRepository
+)
+
+// sharedLargeLot returns an empty 8x1000x1000 lot, built once for all benchmarks as it takes a few
+// seconds and gigabytes. Benchmarks must leave it empty.
+func sharedLargeLot(b *testing.B) *InMemoryParkingRepository {
+	b.Helper()
+
+	largeLotOnce.Do(func() {
+		largeLot = newTestLot(b, largeFloors, largeRows, largeColumns)
+	})
+	if largeLot == nil {
+		b.Fatal("building the large lot failed")
+	}
+	return largeLot
+}
+
+// parkAll parks n vehicles and returns their spots
+func parkAll(b *testing.B, repo *InMemoryParkingRepository, n int) []string {
+	b.Helper()
+
+	spotIDs := make([]string, n)
+	for i := range spotIDs {
+		spotID, err := repo.FindAvailableSpot("Automobile", "", "")
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err := repo.ParkVehicle(spotID, fmt.Sprintf("V%d", i), ""); err != nil {
+			b.Fatal(err)
+		}
+		spotIDs[i] = spotID
+	}
+	return spotIDs
+}
+
+// unparkAll vacates the spots parkAll returned
+func unparkAll(b *testing.B, repo *InMemoryParkingRepository, spotIDs []string) {
+	b.Helper()
+
+	for i, spotID := range spotIDs {
+		floor, row, col, _ := repo.ParseSpotID(spotID)
+		if err := repo.UnparkVehicle(floor, row, col, fmt.Sprintf("V%d", i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLargeLotPark(b *testing.B) {
+	repo := sharedLargeLot(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	spotIDs := parkAll(b, repo, b.N)
+
+	b.StopTimer()
+	unparkAll(b, repo, spotIDs)
+}
+
+func BenchmarkLargeLotUnpark(b *testing.B) {
+	repo := sharedLargeLot(b)
+	spotIDs := parkAll(b, repo, b.N)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	unparkAll(b, repo, spotIDs)
+}
+
+func BenchmarkLargeLotAvailableCount(b *testing.B) {
+	repo := sharedLargeLot(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetAvailableCount("Automobile", -1); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkLargeLotOccupancyStats(b *testing.B) {
+	repo := sharedLargeLot(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		repo.GetOccupancyStats()
+	}
+}
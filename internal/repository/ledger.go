@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// represents a line of a ledger transaction, debiting or crediting one account
+type LedgerLine struct {
+	Account string
+	Debit   float64
+	Credit  float64
+}
+
+// represents a monetary event, e.g. a payment, as ledger lines whose debits and credits balance
+type LedgerTransaction struct {
+	ID        int
+	Kind      string // e.g. "payment"
+	SessionID int
+	PaymentID int // payment the transaction records, 0 when none
+	Time      time.Time
+	Lines     []LedgerLine
+}
+
+// selects ledger transactions, empty fields match all transactions
+type LedgerFilter struct {
+	SessionID int
+	Account   string
+}
+
+// AddLedgerTransaction records a transaction in the ledger, assigning its ID and time.
+// It fails when the debits and credits of its lines do not balance.
+func (r *InMemoryParkingRepository) AddLedgerTransaction(transaction LedgerTransaction) (LedgerTransaction, error) {
+	var debits, credits float64
+	for _, line := range transaction.Lines {
+		debits += line.Debit
+		credits += line.Credit
+	}
+	if len(transaction.Lines) < 2 || math.Abs(debits-credits) > 1e-9 {
+		return LedgerTransaction{}, fmt.Errorf("unbalanced %s transaction: debits %g, credits %g", transaction.Kind, debits, credits)
+	}
+
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	transaction.ID = len(r.ledger) + 1
+	transaction.Time = time.Now()
+	transaction.Lines = slices.Clone(transaction.Lines)
+	r.ledger = append(r.ledger, &transaction)
+
+	return transaction, nil
+}
+
+// GetLedger returns the transactions selected by a filter in the order they were recorded
+func (r *InMemoryParkingRepository) GetLedger(filter LedgerFilter) []LedgerTransaction {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	transactions := []LedgerTransaction{}
+	for _, transaction := range r.ledger {
+		if filter.SessionID != 0 && transaction.SessionID != filter.SessionID {
+			continue
+		}
+		if filter.Account != "" && !slices.ContainsFunc(transaction.Lines, func(line LedgerLine) bool { return line.Account == filter.Account }) {
+			continue
+		}
+		transactions = append(transactions, *transaction)
+	}
+
+	return transactions
+}
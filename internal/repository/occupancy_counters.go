@@ -0,0 +1,129 @@
+package repository
+
+import (
+	pkgerrors "parking-lot-system/pkg/errors"
+)
+
+// represents the number of vehicles a group of spots holds and the number parked in it. Spots blocked
+// for an event are counted apart, so Capacity only counts spots open to allocation.
+type OccupancyCount struct {
+	Capacity        int
+	Occupied        int
+	Blocked         int // number of vehicles the blocked spots hold
+	BlockedOccupied int // number of vehicles parked at blocked spots, e.g. with attendant overrides
+}
+
+// add adds another count to the count
+func (c *OccupancyCount) add(other OccupancyCount) {
+	c.Capacity += other.Capacity
+	c.Occupied += other.Occupied
+	c.Blocked += other.Blocked
+	c.BlockedOccupied += other.BlockedOccupied
+}
+
+// represents a snapshot of the occupancy counters
+type OccupancyStats struct {
+	Total        OccupancyCount
+	Floors       []OccupancyCount
+	VehicleTypes map[string]OccupancyCount
+	Zones        map[string]OccupancyCount
+}
+
+// updateCounters adds (delta = 1) or removes (delta = -1) the contribution
+// of a spot to the counters of its floor. Inactive and deleted spots do not count, blocked spots count
+// as blocked. Must be called with the floor's write lock held.
+func (r *InMemoryParkingRepository) updateCounters(spot *ParkingSpot, delta int) {
+	if !spot.InService() {
+		return
+	}
+
+	count := OccupancyCount{
+		Capacity: spot.Capacity * delta,
+		Occupied: (len(spot.Vehicles) + spot.Held) * delta,
+	}
+	if spot.Blocked {
+		count = OccupancyCount{Blocked: count.Capacity, BlockedOccupied: count.Occupied}
+	}
+
+	state := r.floorStates[spot.Floor]
+	state.count.add(count)
+
+	addCount(state.typeCounts, spot.VehicleType, count)
+	if spot.Zone != "" {
+		addCount(state.zoneCounts, spot.Zone, count)
+	}
+}
+
+// addCount adds to the count of a key, creating it when missing
+func addCount(counts map[string]*OccupancyCount, key string, delta OccupancyCount) {
+	count, exists := counts[key]
+	if !exists {
+		count = &OccupancyCount{}
+		counts[key] = count
+	}
+	count.add(delta)
+}
+
+// GetOccupancyStats returns a snapshot of the occupancy counters. Floors are read one
+// after the other, so the totals may mix states of concurrent changes on different floors.
+func (r *InMemoryParkingRepository) GetOccupancyStats() OccupancyStats {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	stats := OccupancyStats{
+		Floors:       make([]OccupancyCount, len(r.floorStates)),
+		VehicleTypes: make(map[string]OccupancyCount),
+		Zones:        make(map[string]OccupancyCount),
+	}
+
+	for f, state := range r.floorStates {
+		state.mutex.RLock()
+		stats.Floors[f] = state.count
+		stats.Total.add(state.count)
+		for vehicleType, count := range state.typeCounts {
+			total := stats.VehicleTypes[vehicleType]
+			total.add(*count)
+			stats.VehicleTypes[vehicleType] = total
+		}
+		for zone, count := range state.zoneCounts {
+			total := stats.Zones[zone]
+			total.add(*count)
+			stats.Zones[zone] = total
+		}
+		state.mutex.RUnlock()
+	}
+
+	return stats
+}
+
+// GetAvailableCount returns the number of vehicles that can still park, restricted to
+// a vehicle type unless it is empty and to a floor unless floor is negative
+func (r *InMemoryParkingRepository) GetAvailableCount(vehicleType string, floor int) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if floor >= len(r.floors) {
+		return 0, pkgerrors.ErrInvalidLocation
+	}
+
+	available := 0
+	for f, state := range r.floorStates {
+		if floor >= 0 && f != floor {
+			continue
+		}
+
+		state.mutex.RLock()
+		count := state.count
+		if vehicleType != "" {
+			count = OccupancyCount{}
+			if typeCount, exists := state.typeCounts[vehicleType]; exists {
+				count = *typeCount
+			}
+		}
+		state.mutex.RUnlock()
+
+		available += count.Capacity - count.Occupied
+	}
+
+	return available, nil
+}
@@ -1,80 +1,268 @@
 package repository
 
 import (
-	"errors"
 	"fmt"
+	"parking-lot-system/internal/domain/spotid"
 	pkgerrors "parking-lot-system/pkg/errors"
 	"sync"
+	"time"
 )
 
+// DefaultSpotSize is the size of a spot until it is configured otherwise
+const DefaultSpotSize = "standard"
+
 // represents a single parking spot in the repository
 type ParkingSpot struct {
-	Floor         int
-	Row           int
-	Column        int
-	VehicleType   string
-	IsActive      bool
-	IsOccupied    bool
-	VehicleNumber string
+	Floor       int
+	Row         int
+	Column      int
+	VehicleType string
+	IsActive    bool
+	Exists      bool           // false for grid cells without a spot (pillars, ramps, stairwells)
+	Deleted     bool           // soft-deleted, the spot keeps its configuration and history until restored
+	Blocked     bool           // reserved for a scheduled event, out of general allocation
+	Class       string         // e.g. "ev", "accessible", "vip", empty for regular spots
+	Zone        string         // e.g. "A", "B", "Rooftop", empty when unzoned
+	Size        string         // e.g. "compact", "standard", "large"
+	Capacity    int            // number of vehicles the spot holds, e.g. 10 for a bicycle rack
+	Vehicles    []string       // numbers of the vehicles parked at the spot
+	Held        int            // places reserved by holds awaiting confirmation
+	Sensor      *SensorReading // nil until the spot's sensor reports
+	ChangedAt   time.Time      // last time the spot was parked in or vacated
+
+	// usage totals since the lot was initialized
+	TimesUsed    int           // number of vehicles that parked at the spot
+	OccupiedTime time.Duration // time the unparked vehicles stayed, summed over the vehicles
+
+	free freeSlot // position in the free spot index
+}
+
+// InService reports whether the spot is active and not deleted, i.e. takes vehicles and counts towards capacity
+func (s ParkingSpot) InService() bool {
+	return s.IsActive && !s.Deleted
+}
+
+// Allocatable reports whether the spot is in service and not blocked, i.e. is given to vehicles looking for a spot
+func (s ParkingSpot) Allocatable() bool {
+	return s.InService() && !s.Blocked
+}
+
+// IsOccupied reports whether at least one vehicle is parked at the spot
+func (s ParkingSpot) IsOccupied() bool {
+	return len(s.Vehicles) > 0
+}
+
+// IsFull reports whether the spot has no room left for another vehicle
+func (s ParkingSpot) IsFull() bool {
+	return len(s.Vehicles)+s.Held >= s.Capacity
+}
+
+// Free returns the number of vehicles that can still park at the spot
+func (s ParkingSpot) Free() int {
+	if s.IsFull() {
+		return 0
+	}
+	return s.Capacity - len(s.Vehicles) - s.Held
+}
+
+// HasVehicle reports whether a vehicle is parked at the spot
+func (s ParkingSpot) HasVehicle(vehicleNumber string) bool {
+	for _, parked := range s.Vehicles {
+		if parked == vehicleNumber {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshot returns a copy of the spot that does not share its vehicle list
+func (s *ParkingSpot) snapshot() ParkingSpot {
+	spot := *s
+	spot.Vehicles = append([]string(nil), s.Vehicles...)
+	return spot
+}
+
+// represents the grid size of a single floor
+type FloorDimensions struct {
+	Rows    int
+	Columns int
 }
 
 type ParkingRepository interface {
-	InitializeParkingLot(floors, rows, columns, gates int) error
-	ConfigureSpot(floor, row, column int, vehicleType string, isActive bool) error
+	InitializeParkingLot(floors, rows, columns, gates int, force bool) error
+	InitializeFloors(floors []FloorDimensions, gates int, force bool) error
+	ConfigureSpot(floor, row, column int, vehicleType, class string, isActive, exists bool) error
+	SetSpotZone(floor, row, column int, zone string) error
+	SetSpotCapacity(floor, row, column, capacity int) error
+	SetSpotSize(floor, row, column int, size string) error
+	SetSpotDeleted(floor, row, column int, deleted bool) error
+	SetSpotBlocked(floor, row, column int, blocked bool) error
 	IsValidLocation(floor, row, column int) bool
 	IsSpotOccupied(floor, row, column int) (bool, error)
-	FindAvailableSpot(vehicleType string) (string, error)
-	ParkVehicle(spotID string, vehicleNumber string) error
+	FindAvailableSpot(vehicleType, zone, size string) (string, error)
+	ParkVehicle(spotID, vehicleNumber, vehicleType string) error
+	HoldSpot(spotID string) error
+	ReleaseHold(spotID string) error
+	ConfirmHold(spotID, vehicleNumber, vehicleType string) error
 	UnparkVehicle(floor, row, column int, vehicleNumber string) error
 	IsVehicleParked(vehicleNumber string) (bool, string, error)
-	GetAvailableSpots(vehicleType string) ([]string, error)
+	GetAvailableSpots(vehicleType, zone, size string) ([]string, error)
 	SearchVehicle(vehicleNumber string) (string, bool, error)
+	FindVehiclesBySuffix(suffix string) []ParkedVehicle
 	ParseSpotID(spotID string) (int, int, int, error)
+	FormatSpotID(floor, row, column int) string
+	GetOccupancyStats() OccupancyStats
+	GetAvailableCount(vehicleType string, floor int) (int, error)
+	GetSessions(from, to time.Time) []Session
+	GetSession(id int) (Session, error)
+	GetActiveSession(vehicleNumber string) (Session, bool)
+	GetLastExit(vehicleNumber string) (time.Time, bool)
+	GetFloorSpots(floor int) ([][]ParkingSpot, error)
+	RecordSensorReading(floor, row, column int, occupied bool, health SensorHealth) error
+	GetDimensions() ([]FloorDimensions, int)
+	GetSpot(floor, row, column int) (ParkingSpot, error)
+	AppendAudit(entry AuditEntry)
+	GetAuditEntries() []AuditEntry
+	AddViolation(violation Violation) Violation
+	ResolveViolation(id int, resolvedBy string, fine float64) (Violation, error)
+	GetViolations(vehicleNumber string) []Violation
+	AddPayment(payment Payment) (Payment, error)
+	GetPayments(sessionID int) []Payment
+	GetPayment(id int) (Payment, error)
+	AddRefund(refund Refund) (Refund, error)
+	GetRefunds(paymentID int) []Refund
+	AddLedgerTransaction(transaction LedgerTransaction) (LedgerTransaction, error)
+	GetLedger(filter LedgerFilter) []LedgerTransaction
+	AppendEvent(event EventRecord) EventRecord
+	GetEvents(filter EventFilter) ([]EventRecord, bool)
+	DropEvents(keep int) []EventRecord
+	AddDevice(device Device) (Device, error)
+	UpdateDevice(device Device) (Device, error)
+	RemoveDevice(id string) (Device, error)
+	TouchDevice(id string, at time.Time) (Device, error)
+	GetDevice(id string) (Device, error)
+	GetDevices() []Device
 }
 
+// Locks are taken in the order mutex, floor mutex, recordMutex.
 type InMemoryParkingRepository struct {
-	floors         int
-	rows           int
-	columns        int
-	spots          [][][]*ParkingSpot
-	gates          int
-	mutex          sync.RWMutex
-	vehicleMap     map[string]string // vehicleNumber -> current spotID
-	vehicleHistory map[string]string // vehicleNumber -> last spotID
+	// guards the layout of the lot, only written when the lot is initialized
+	mutex        sync.RWMutex
+	floors       []FloorDimensions
+	spots        []ParkingSpot // all spots, floor by floor, row by row
+	floorOffsets []int         // index in spots of the first spot of each floor
+	floorStates  []*floorState // per-floor locks, counters and free spot indexes
+	gates        int
+	ids          spotid.Format
+
+	// guards the vehicle records, sessions, audit log, violations, payments, event log and devices below
+	recordMutex    sync.RWMutex
+	vehicleMap     map[string]string    // vehicleNumber -> current spotID
+	vehicleHistory map[string]string    // vehicleNumber -> last spotID
+	exitTimes      map[string]time.Time // vehicleNumber -> time of the last unpark
+	suffixes       suffixIndex          // plate suffix -> numbers of the parked vehicles
+
+	// Parking sessions, in the order they were started
+	sessions       []*Session
+	activeSessions map[string]*Session // vehicleNumber -> active session
+
+	auditLog []AuditEntry
+
+	// Violations flagged by enforcement staff, in the order they were reported
+	violations []*Violation
+
+	// Payments taken for sessions, in the order they were taken
+	payments []*Payment
+	// Refunds of payments, in the order they were given
+	refunds []*Refund
+
+	// Balanced ledger transactions of the monetary events, in the order they were recorded
+	ledger []*LedgerTransaction
+
+	// Events published by the parking service, in the order they were published, and how many of
+	// the oldest ones were dropped before them
+	events        []*EventRecord
+	droppedEvents int
+
+	// Devices installed in the lot, device ID -> device
+	devices map[string]*Device
 }
 
-func NewParkingRepository() ParkingRepository {
+// NewParkingRepository creates an empty repository that names spots using ids
+func NewParkingRepository(ids spotid.Format) ParkingRepository {
 	return &InMemoryParkingRepository{
+		ids:            ids,
 		vehicleMap:     make(map[string]string),
 		vehicleHistory: make(map[string]string),
+		exitTimes:      make(map[string]time.Time),
+		suffixes:       make(suffixIndex),
+		activeSessions: make(map[string]*Session),
+		devices:        make(map[string]*Device),
 	}
 }
 
-// InitializeParkingLot creates a new parking lot with the specified dimensions
-func (r *InMemoryParkingRepository) InitializeParkingLot(floors, rows, columns, gates int) error {
+// InitializeParkingLot creates a new parking lot with the specified dimensions, see InitializeFloors
+func (r *InMemoryParkingRepository) InitializeParkingLot(floors, rows, columns, gates int, force bool) error {
+	dimensions := make([]FloorDimensions, floors)
+	for f := range dimensions {
+		dimensions[f] = FloorDimensions{Rows: rows, Columns: columns}
+	}
+
+	return r.InitializeFloors(dimensions, gates, force)
+}
+
+// InitializeFloors creates a new parking lot where every floor has its own dimensions.
+// It fails while vehicles are parked unless force is set, which unparks them. The vehicle
+// history, sessions and audit log are kept.
+func (r *InMemoryParkingRepository) InitializeFloors(floors []FloorDimensions, gates int, force bool) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	r.floors = floors
-	r.rows = rows
-	r.columns = columns
+	r.recordMutex.Lock()
+	if len(r.vehicleMap) > 0 && !force {
+		parked := len(r.vehicleMap)
+		r.recordMutex.Unlock()
+		return fmt.Errorf("%w: %d vehicles parked", pkgerrors.ErrLotInUse, parked)
+	}
+	for vehicleNumber, spotID := range r.vehicleMap {
+		r.vehicleHistory[vehicleNumber] = spotID
+		r.endSession(vehicleNumber)
+		r.suffixes.remove(vehicleNumber)
+	}
+	r.vehicleMap = make(map[string]string)
+	r.recordMutex.Unlock()
+
+	r.floors = make([]FloorDimensions, len(floors))
+	copy(r.floors, floors)
 	r.gates = gates
 
-	// Initialize parking spots
-	r.spots = make([][][]*ParkingSpot, floors)
-	for f := 0; f < floors; f++ {
-		r.spots[f] = make([][]*ParkingSpot, rows)
-		for row := 0; row < rows; row++ {
-			r.spots[f][row] = make([]*ParkingSpot, columns)
-			for col := 0; col < columns; col++ {
-				r.spots[f][row][col] = &ParkingSpot{
-					Floor:         f,
-					Row:           row,
-					Column:        col,
-					IsOccupied:    false,
-					VehicleType:   "",
-					IsActive:      false,
-					VehicleNumber: "",
+	// Reset occupancy counters and free spot indexes
+	r.floorStates = make([]*floorState, len(floors))
+	for f := range r.floorStates {
+		r.floorStates[f] = newFloorState()
+	}
+
+	// Initialize parking spots in a single allocation
+	r.floorOffsets = make([]int, len(floors))
+	total := 0
+	for f, dimensions := range floors {
+		r.floorOffsets[f] = total
+		total += dimensions.Rows * dimensions.Columns
+	}
+
+	r.spots = make([]ParkingSpot, total)
+	for f, dimensions := range floors {
+		for row := 0; row < dimensions.Rows; row++ {
+			for col := 0; col < dimensions.Columns; col++ {
+				*r.spot(f, row, col) = ParkingSpot{
+					Floor:       f,
+					Row:         row,
+					Column:      col,
+					VehicleType: "",
+					IsActive:    false,
+					Exists:      true,
+					Size:        DefaultSpotSize,
+					Capacity:    1,
 				}
 			}
 		}
@@ -83,18 +271,125 @@ func (r *InMemoryParkingRepository) InitializeParkingLot(floors, rows, columns,
 	return nil
 }
 
-// ConfigureSpot sets the type and active status of a specific parking spot
-func (r *InMemoryParkingRepository) ConfigureSpot(floor, row, column int, vehicleType string, isActive bool) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
-	if !r.isValidLocation(floor, row, column) {
-		return errors.New(pkgerrors.ErrInvalidLocation)
+// ConfigureSpot sets the type, active status and existence of a specific parking spot
+func (r *InMemoryParkingRepository) ConfigureSpot(floor, row, column int, vehicleType, class string, isActive, exists bool) error {
+	spot, unlock, err := r.lockSpot(floor, row, column)
+	if err != nil {
+		return err
 	}
+	defer unlock()
 
-	spot := r.spots[floor][row][column]
+	r.updateCounters(spot, -1)
 	spot.VehicleType = vehicleType
-	spot.IsActive = isActive
+	spot.Class = class
+	spot.IsActive = isActive && exists
+	spot.Exists = exists
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
+
+	return nil
+}
+
+// GetDimensions returns the dimensions of every floor and the number of gates of the parking lot
+func (r *InMemoryParkingRepository) GetDimensions() ([]FloorDimensions, int) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	floors := make([]FloorDimensions, len(r.floors))
+	copy(floors, r.floors)
+
+	return floors, r.gates
+}
+
+// SetSpotZone assigns a spot to a zone, an empty zone removes it from its zone
+func (r *InMemoryParkingRepository) SetSpotZone(floor, row, column int, zone string) error {
+	spot, unlock, err := r.lockSpot(floor, row, column)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	r.updateCounters(spot, -1)
+	spot.Zone = zone
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
+
+	return nil
+}
+
+// SetSpotCapacity sets the number of vehicles a spot holds
+func (r *InMemoryParkingRepository) SetSpotCapacity(floor, row, column, capacity int) error {
+	spot, unlock, err := r.lockSpot(floor, row, column)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if capacity < 1 || capacity < len(spot.Vehicles)+spot.Held {
+		return fmt.Errorf("%w: %d (%d vehicles parked, %d held)", pkgerrors.ErrInvalidCapacity, capacity, len(spot.Vehicles), spot.Held)
+	}
+
+	r.updateCounters(spot, -1)
+	spot.Capacity = capacity
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
+
+	return nil
+}
+
+// SetSpotSize sets the size class of a spot
+func (r *InMemoryParkingRepository) SetSpotSize(floor, row, column int, size string) error {
+	spot, unlock, err := r.lockSpot(floor, row, column)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	spot.Size = size
+	r.updateFreeIndex(spot)
+
+	return nil
+}
+
+// SetSpotDeleted soft-deletes or restores a spot. A spot with parked vehicles or holds cannot be deleted.
+func (r *InMemoryParkingRepository) SetSpotDeleted(floor, row, column int, deleted bool) error {
+	spot, unlock, err := r.lockSpot(floor, row, column)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !spot.Exists {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotDoesNotExist, r.ids.Format(floor, row, column))
+	}
+	if deleted && (spot.IsOccupied() || spot.Held > 0) {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotInUse, r.ids.Format(floor, row, column))
+	}
+
+	r.updateCounters(spot, -1)
+	spot.Deleted = deleted
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
+
+	return nil
+}
+
+// SetSpotBlocked takes a spot out of general allocation or gives it back. Vehicles parked at the spot stay.
+func (r *InMemoryParkingRepository) SetSpotBlocked(floor, row, column int, blocked bool) error {
+	spot, unlock, err := r.lockSpot(floor, row, column)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !spot.Exists {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotDoesNotExist, r.ids.Format(floor, row, column))
+	}
+
+	r.updateCounters(spot, -1)
+	spot.Blocked = blocked
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
 
 	return nil
 }
@@ -107,123 +402,184 @@ func (r *InMemoryParkingRepository) IsValidLocation(floor, row, column int) bool
 	return r.isValidLocation(floor, row, column)
 }
 
+// spot returns the spot at a location, which must be valid
+func (r *InMemoryParkingRepository) spot(floor, row, column int) *ParkingSpot {
+	return &r.spots[r.floorOffsets[floor]+row*r.floors[floor].Columns+column]
+}
+
 // isValidLocation is a helper function to check location validity
 func (r *InMemoryParkingRepository) isValidLocation(floor, row, column int) bool {
-	return floor >= 0 && floor < r.floors &&
-		row >= 0 && row < r.rows &&
-		column >= 0 && column < r.columns
+	return floor >= 0 && floor < len(r.floors) &&
+		row >= 0 && row < r.floors[floor].Rows &&
+		column >= 0 && column < r.floors[floor].Columns
 }
 
-// IsSpotOccupied checks if a spot is occupied
-func (r *InMemoryParkingRepository) IsSpotOccupied(floor, row, column int) (bool, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
+// GetSpot returns a snapshot of a single spot
+func (r *InMemoryParkingRepository) GetSpot(floor, row, column int) (ParkingSpot, error) {
+	spot, unlock, err := r.rlockSpot(floor, row, column)
+	if err != nil {
+		return ParkingSpot{}, err
+	}
+	defer unlock()
 
-	if !r.isValidLocation(floor, row, column) {
-		return false, errors.New(pkgerrors.ErrInvalidLocation)
+	return spot.snapshot(), nil
+}
+
+// IsSpotOccupied checks if at least one vehicle is parked at a spot or holds a place in it
+func (r *InMemoryParkingRepository) IsSpotOccupied(floor, row, column int) (bool, error) {
+	spot, unlock, err := r.rlockSpot(floor, row, column)
+	if err != nil {
+		return false, err
 	}
+	defer unlock()
 
-	return r.spots[floor][row][column].IsOccupied, nil
+	return spot.IsOccupied() || spot.Held > 0, nil
 }
 
-// FindAvailableSpot finds an available spot for the specified vehicle type
-func (r *InMemoryParkingRepository) FindAvailableSpot(vehicleType string) (string, error) {
+// FindAvailableSpot finds an available spot for the specified vehicle type,
+// restricted to a zone and a spot size unless they are empty. Lower floors are filled first.
+func (r *InMemoryParkingRepository) FindAvailableSpot(vehicleType, zone, size string) (string, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
-	for f := 0; f < r.floors; f++ {
-		for row := 0; row < r.rows; row++ {
-			for col := 0; col < r.columns; col++ {
-				spot := r.spots[f][row][col]
+	for _, state := range r.floorStates {
+		state.mutex.RLock()
+		spot := state.firstFreeSpot(vehicleType, zone, size)
+		state.mutex.RUnlock()
 
-				if spot.IsActive && spot.VehicleType == vehicleType && !spot.IsOccupied {
-					// Found an available spot
-					return fmt.Sprintf("%d-%d-%d", f, row, col), nil
-				}
-			}
+		if spot != nil {
+			return r.ids.Format(spot.Floor, spot.Row, spot.Column), nil
 		}
 	}
 
-	return "", errors.New(pkgerrors.ErrNoAvailableSpot)
+	return "", pkgerrors.ErrNoAvailableSpot
 }
 
-// ParkVehicle parks a vehicle at the specified spot
-func (r *InMemoryParkingRepository) ParkVehicle(spotID string, vehicleNumber string) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
+// ParkVehicle parks a vehicle at the specified spot. An empty vehicle type
+// stands for the type the spot is configured for.
+func (r *InMemoryParkingRepository) ParkVehicle(spotID, vehicleNumber, vehicleType string) error {
+	floor, row, col, err := r.ids.Parse(spotID)
+	if err != nil {
+		return err
+	}
 
-	floor, row, col, err := r.parseSpotID(spotID)
+	spot, unlock, err := r.lockSpot(floor, row, col)
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	if !spot.Exists {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotDoesNotExist, spotID)
+	}
+	if spot.Deleted {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotDeleted, spotID)
+	}
+	if spot.IsFull() {
+		return fmt.Errorf("%w: %s", pkgerrors.ErrSpotFull, spotID)
+	}
+
+	// Two gates may park the same vehicle number at once, only one of them succeeds
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	if currentSpotID, exists := r.vehicleMap[vehicleNumber]; exists {
+		return fmt.Errorf("%w: %s at spot %s", pkgerrors.ErrVehicleAlreadyParked, vehicleNumber, currentSpotID)
+	}
+
+	r.updateCounters(spot, -1)
+	spot.Vehicles = append(spot.Vehicles, vehicleNumber)
+	spot.ChangedAt = time.Now()
+	spot.TimesUsed++
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
+
+	if vehicleType == "" {
+		vehicleType = spot.VehicleType
+	}
 
-	spot := r.spots[floor][row][col]
-	spot.IsOccupied = true
-	spot.VehicleNumber = vehicleNumber
 	r.vehicleMap[vehicleNumber] = spotID
+	r.suffixes.add(vehicleNumber)
+	r.startSession(vehicleNumber, vehicleType, spotID, spot.VehicleType)
 
 	return nil
 }
 
 // UnparkVehicle removes a vehicle from the specified spot
 func (r *InMemoryParkingRepository) UnparkVehicle(floor, row, column int, vehicleNumber string) error {
-	r.mutex.Lock()
-	defer r.mutex.Unlock()
-
-	if !r.isValidLocation(floor, row, column) {
-		return errors.New(pkgerrors.ErrInvalidLocation)
+	spot, unlock, err := r.lockSpot(floor, row, column)
+	if err != nil {
+		return err
 	}
-
-	spot := r.spots[floor][row][column]
+	defer unlock()
 
 	// Check if the spot is occupied by the specified vehicle
-	if !spot.IsOccupied || spot.VehicleNumber != vehicleNumber {
-		return fmt.Errorf("%s: %s at spot %d-%d-%d",
-			pkgerrors.ErrVehicleNotAtSpot, vehicleNumber, floor, row, column)
+	if !spot.HasVehicle(vehicleNumber) {
+		return fmt.Errorf("%w: %s at spot %s",
+			pkgerrors.ErrVehicleNotAtSpot, vehicleNumber, r.ids.Format(floor, row, column))
 	}
 
 	// Unpark the vehicle
-	spot.IsOccupied = false
-	spot.VehicleNumber = ""
+	r.updateCounters(spot, -1)
+	vehicles := make([]string, 0, len(spot.Vehicles)-1)
+	for _, parked := range spot.Vehicles {
+		if parked != vehicleNumber {
+			vehicles = append(vehicles, parked)
+		}
+	}
+	spot.Vehicles = vehicles
+	spot.ChangedAt = time.Now()
+	r.updateCounters(spot, 1)
+	r.updateFreeIndex(spot)
 
 	// Update the vehicle history and remove from current map
-	spotID := fmt.Sprintf("%d-%d-%d", floor, row, column)
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	spotID := r.ids.Format(floor, row, column)
 	r.vehicleHistory[vehicleNumber] = spotID
+	r.exitTimes[vehicleNumber] = spot.ChangedAt
+	if session, exists := r.activeSessions[vehicleNumber]; exists {
+		spot.OccupiedTime += spot.ChangedAt.Sub(session.EntryTime)
+	}
 	delete(r.vehicleMap, vehicleNumber)
+	r.suffixes.remove(vehicleNumber)
+	r.endSession(vehicleNumber)
 
 	return nil
 }
 
 // IsVehicleParked checks if a vehicle is currently parked
 func (r *InMemoryParkingRepository) IsVehicleParked(vehicleNumber string) (bool, string, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
 
 	spotID, exists := r.vehicleMap[vehicleNumber]
 	return exists, spotID, nil
 }
 
-// GetAvailableSpots returns the list of available spots for a vehicle type
-func (r *InMemoryParkingRepository) GetAvailableSpots(vehicleType string) ([]string, error) {
+// GetAvailableSpots returns the list of available spots for a vehicle type,
+// restricted to a zone and a spot size unless they are empty
+func (r *InMemoryParkingRepository) GetAvailableSpots(vehicleType, zone, size string) ([]string, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 
 	availableSpots := []string{}
 
-	for f := 0; f < r.floors; f++ {
-		for row := 0; row < r.rows; row++ {
-			for col := 0; col < r.columns; col++ {
-				spot := r.spots[f][row][col]
-
-				if spot.IsActive && spot.VehicleType == vehicleType && !spot.IsOccupied {
-					availableSpots = append(availableSpots, fmt.Sprintf("%d-%d-%d", f, row, col))
-				}
+	for f, state := range r.floorStates {
+		state.mutex.RLock()
+		dimensions := r.floors[f]
+		for _, spot := range r.spots[r.floorOffsets[f] : r.floorOffsets[f]+dimensions.Rows*dimensions.Columns] {
+			if spot.Allocatable() && spot.VehicleType == vehicleType && !spot.IsFull() &&
+				(zone == "" || spot.Zone == zone) && (size == "" || spot.Size == size) {
+				availableSpots = append(availableSpots, r.ids.Format(spot.Floor, spot.Row, spot.Column))
 			}
 		}
+		state.mutex.RUnlock()
 	}
 
 	if len(availableSpots) == 0 {
-		return nil, fmt.Errorf("%s: %s", pkgerrors.ErrNoAvailableSpot, vehicleType)
+		return nil, fmt.Errorf("%w: %s", pkgerrors.ErrNoAvailableSpot, vehicleType)
 	}
 
 	return availableSpots, nil
@@ -231,8 +587,8 @@ func (r *InMemoryParkingRepository) GetAvailableSpots(vehicleType string) ([]str
 
 // SearchVehicle returns the current or last known spot ID for a vehicle
 func (r *InMemoryParkingRepository) SearchVehicle(vehicleNumber string) (string, bool, error) {
-	r.mutex.RLock()
-	defer r.mutex.RUnlock()
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
 
 	// Check if the vehicle is currently parked
 	if spotID, exists := r.vehicleMap[vehicleNumber]; exists {
@@ -244,7 +600,7 @@ func (r *InMemoryParkingRepository) SearchVehicle(vehicleNumber string) (string,
 		return lastSpotID, false, nil
 	}
 
-	return "", false, fmt.Errorf("vehicle %s has never been parked in this parking lot", vehicleNumber)
+	return "", false, fmt.Errorf("%w: %s", pkgerrors.ErrVehicleNotFound, vehicleNumber)
 }
 
 // ParseSpotID parses a spot ID string into floor, row, column
@@ -255,18 +611,47 @@ func (r *InMemoryParkingRepository) ParseSpotID(spotID string) (int, int, int, e
 	return r.parseSpotID(spotID)
 }
 
+// FormatSpotID returns the spot ID of a location
+func (r *InMemoryParkingRepository) FormatSpotID(floor, row, column int) string {
+	return r.ids.Format(floor, row, column)
+}
+
 // parseSpotID is a helper function to parse a spot ID
 func (r *InMemoryParkingRepository) parseSpotID(spotID string) (int, int, int, error) {
-	var floor, row, column int
-	_, err := fmt.Sscanf(spotID, "%d-%d-%d", &floor, &row, &column)
+	floor, row, column, err := r.ids.Parse(spotID)
 	if err != nil {
-		return 0, 0, 0, errors.New(pkgerrors.ErrInvalidSpotID)
+		return 0, 0, 0, err
 	}
 
 	// Check if the indices are within bounds
 	if !r.isValidLocation(floor, row, column) {
-		return 0, 0, 0, errors.New(pkgerrors.ErrInvalidLocation)
+		return 0, 0, 0, pkgerrors.ErrInvalidLocation
 	}
 
 	return floor, row, column, nil
 }
+
+// GetFloorSpots returns a snapshot of all spots on a floor, indexed by row and column
+func (r *InMemoryParkingRepository) GetFloorSpots(floor int) ([][]ParkingSpot, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if floor < 0 || floor >= len(r.floors) {
+		return nil, pkgerrors.ErrInvalidLocation
+	}
+
+	state := r.floorStates[floor]
+	state.mutex.RLock()
+	defer state.mutex.RUnlock()
+
+	dimensions := r.floors[floor]
+	spots := make([][]ParkingSpot, dimensions.Rows)
+	for row := range spots {
+		spots[row] = make([]ParkingSpot, dimensions.Columns)
+		for col := range spots[row] {
+			spots[row][col] = r.spot(floor, row, col).snapshot()
+		}
+	}
+
+	return spots, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"fmt"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// represents money taken for a parking session
+type Payment struct {
+	ID        int
+	SessionID int
+	Method    string  // e.g. "cash"
+	Amount    float64 // amount kept, the amount tendered minus the change given
+	Tendered  float64
+	Change    float64
+	DrawerID  string // cash drawer the money went into
+	TakenBy   string
+	TakenAt   time.Time
+	Refunded  float64 // part of the amount refunded since
+}
+
+// represents money given back for a payment
+type Refund struct {
+	ID         int
+	PaymentID  int
+	Amount     float64
+	Reason     string
+	RefundedBy string
+	RefundedAt time.Time
+}
+
+// AddPayment records a payment against an existing session, assigning its ID and time
+func (r *InMemoryParkingRepository) AddPayment(payment Payment) (Payment, error) {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	if payment.SessionID < 1 || payment.SessionID > len(r.sessions) {
+		return Payment{}, fmt.Errorf("%w: %d", pkgerrors.ErrTicketNotFound, payment.SessionID)
+	}
+
+	payment.ID = len(r.payments) + 1
+	payment.TakenAt = time.Now()
+	r.payments = append(r.payments, &payment)
+
+	return payment, nil
+}
+
+// GetPayments returns the payments of a session, or of all sessions when sessionID is 0,
+// in the order they were taken
+func (r *InMemoryParkingRepository) GetPayments(sessionID int) []Payment {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	payments := []Payment{}
+	for _, payment := range r.payments {
+		if sessionID == 0 || payment.SessionID == sessionID {
+			payments = append(payments, *payment)
+		}
+	}
+
+	return payments
+}
+
+// GetPayment returns a payment by its ID
+func (r *InMemoryParkingRepository) GetPayment(id int) (Payment, error) {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	if id < 1 || id > len(r.payments) {
+		return Payment{}, fmt.Errorf("%w: %d", pkgerrors.ErrPaymentNotFound, id)
+	}
+	return *r.payments[id-1], nil
+}
+
+// AddRefund records a refund of a payment, assigning its ID and time. The refunds of a payment
+// never add up to more than its amount.
+func (r *InMemoryParkingRepository) AddRefund(refund Refund) (Refund, error) {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	if refund.PaymentID < 1 || refund.PaymentID > len(r.payments) {
+		return Refund{}, fmt.Errorf("%w: %d", pkgerrors.ErrPaymentNotFound, refund.PaymentID)
+	}
+	payment := r.payments[refund.PaymentID-1]
+	if left := payment.Amount - payment.Refunded; refund.Amount <= 0 || refund.Amount > left {
+		return Refund{}, fmt.Errorf("%w: %.2f left of payment %d", pkgerrors.ErrInvalidRefund, left, payment.ID)
+	}
+
+	refund.ID = len(r.refunds) + 1
+	refund.RefundedAt = time.Now()
+	r.refunds = append(r.refunds, &refund)
+	payment.Refunded += refund.Amount
+
+	return refund, nil
+}
+
+// GetRefunds returns the refunds of a payment, or of all payments when paymentID is 0,
+// in the order they were given
+func (r *InMemoryParkingRepository) GetRefunds(paymentID int) []Refund {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	refunds := []Refund{}
+	for _, refund := range r.refunds {
+		if paymentID == 0 || refund.PaymentID == paymentID {
+			refunds = append(refunds, *refund)
+		}
+	}
+
+	return refunds
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+)
+
+// longest plate suffix kept in the index, longer suffixes are matched among the vehicles found for their last characters
+const maxIndexedSuffix = 4
+
+// represents a parked vehicle and the spot it occupies
+type ParkedVehicle struct {
+	VehicleNumber string
+	SpotID        string
+}
+
+// suffixIndex maps the last 1 to maxIndexedSuffix characters of the parked vehicles' numbers to those numbers
+type suffixIndex map[string]map[string]struct{}
+
+// suffixes returns the indexed suffixes of a vehicle number, shortest first
+func suffixes(vehicleNumber string) []string {
+	runes := []rune(vehicleNumber)
+	result := []string{}
+	for length := 1; length <= maxIndexedSuffix && length <= len(runes); length++ {
+		result = append(result, string(runes[len(runes)-length:]))
+	}
+	return result
+}
+
+func (idx suffixIndex) add(vehicleNumber string) {
+	for _, suffix := range suffixes(vehicleNumber) {
+		if idx[suffix] == nil {
+			idx[suffix] = make(map[string]struct{})
+		}
+		idx[suffix][vehicleNumber] = struct{}{}
+	}
+}
+
+func (idx suffixIndex) remove(vehicleNumber string) {
+	for _, suffix := range suffixes(vehicleNumber) {
+		delete(idx[suffix], vehicleNumber)
+		if len(idx[suffix]) == 0 {
+			delete(idx, suffix)
+		}
+	}
+}
+
+// FindVehiclesBySuffix returns the parked vehicles whose number ends with suffix, ordered by vehicle number
+func (r *InMemoryParkingRepository) FindVehiclesBySuffix(suffix string) []ParkedVehicle {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	indexed := suffix
+	if runes := []rune(suffix); len(runes) > maxIndexedSuffix {
+		indexed = string(runes[len(runes)-maxIndexedSuffix:])
+	}
+
+	vehicles := []ParkedVehicle{}
+	for vehicleNumber := range r.suffixes[indexed] {
+		if strings.HasSuffix(vehicleNumber, suffix) {
+			vehicles = append(vehicles, ParkedVehicle{
+				VehicleNumber: vehicleNumber,
+				SpotID:        r.vehicleMap[vehicleNumber],
+			})
+		}
+	}
+
+	sort.Slice(vehicles, func(i, j int) bool {
+		return vehicles[i].VehicleNumber < vehicles[j].VehicleNumber
+	})
+
+	return vehicles
+}
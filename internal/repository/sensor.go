@@ -0,0 +1,55 @@
+package repository
+
+import (
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// represents the last occupancy reported by the in-ground sensor of a spot
+type SensorReading struct {
+	Occupied   bool
+	ReportedAt time.Time
+	Battery    int    // percent, -1 until the sensor reports it
+	Firmware   string // empty until the sensor reports it
+}
+
+// SensorHealth holds the battery level and firmware version a sensor may report along with its
+// occupancy. A negative battery or empty firmware means the report did not include them.
+type SensorHealth struct {
+	Battery  int
+	Firmware string
+}
+
+// RecordSensorReading stores the occupancy reported by the sensor of a spot,
+// independently of the spot's logical occupancy. The battery level and firmware
+// version of the previous reading are kept when the report omits them.
+func (r *InMemoryParkingRepository) RecordSensorReading(floor, row, column int, occupied bool, health SensorHealth) error {
+	spot, unlock, err := r.lockSpot(floor, row, column)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !spot.Exists {
+		return pkgerrors.ErrSpotDoesNotExist
+	}
+
+	reading := &SensorReading{
+		Occupied:   occupied,
+		ReportedAt: time.Now(),
+		Battery:    -1,
+	}
+	if spot.Sensor != nil {
+		reading.Battery = spot.Sensor.Battery
+		reading.Firmware = spot.Sensor.Firmware
+	}
+	if health.Battery >= 0 {
+		reading.Battery = health.Battery
+	}
+	if health.Firmware != "" {
+		reading.Firmware = health.Firmware
+	}
+	spot.Sensor = reading
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"fmt"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// represents a single parking session, from park to unpark
+type Session struct {
+	ID            int
+	VehicleNumber string
+	VehicleType   string
+	SpotID        string
+	SpotType      string // vehicle type the spot is configured for
+	Fallback      bool   // the vehicle was parked in a spot of another type because its own type was full
+	EntryTime     time.Time
+	ExitTime      time.Time // zero while the vehicle is still parked
+}
+
+// IsActive reports whether the vehicle of the session is still parked
+func (s Session) IsActive() bool {
+	return s.ExitTime.IsZero()
+}
+
+// Duration returns the length of the session, up to now for active sessions
+func (s Session) Duration(now time.Time) time.Duration {
+	if s.IsActive() {
+		return now.Sub(s.EntryTime)
+	}
+	return s.ExitTime.Sub(s.EntryTime)
+}
+
+// overlaps reports whether the session was running at any point in [from, to)
+func (s Session) overlaps(from, to time.Time) bool {
+	if !s.EntryTime.Before(to) {
+		return false
+	}
+	return s.IsActive() || s.ExitTime.After(from)
+}
+
+// startSession opens a new session for a vehicle that was just parked.
+// Must be called with the record lock held.
+func (r *InMemoryParkingRepository) startSession(vehicleNumber, vehicleType, spotID, spotType string) {
+	session := &Session{
+		ID:            len(r.sessions) + 1,
+		VehicleNumber: vehicleNumber,
+		VehicleType:   vehicleType,
+		SpotID:        spotID,
+		SpotType:      spotType,
+		Fallback:      vehicleType != spotType,
+		EntryTime:     time.Now(),
+	}
+	r.sessions = append(r.sessions, session)
+	r.activeSessions[vehicleNumber] = session
+}
+
+// endSession closes the active session of a vehicle that was just unparked.
+// Must be called with the record lock held.
+func (r *InMemoryParkingRepository) endSession(vehicleNumber string) {
+	if session, exists := r.activeSessions[vehicleNumber]; exists {
+		session.ExitTime = time.Now()
+		delete(r.activeSessions, vehicleNumber)
+	}
+}
+
+// GetSessions returns all sessions that were running at any point in [from, to),
+// ordered by entry time
+func (r *InMemoryParkingRepository) GetSessions(from, to time.Time) []Session {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	sessions := []Session{}
+	for _, session := range r.sessions {
+		if session.overlaps(from, to) {
+			sessions = append(sessions, *session)
+		}
+	}
+
+	return sessions
+}
+
+// GetSession returns a session by its ID
+func (r *InMemoryParkingRepository) GetSession(id int) (Session, error) {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	// Sessions are numbered in the order they started
+	if id < 1 || id > len(r.sessions) {
+		return Session{}, fmt.Errorf("%w: %d", pkgerrors.ErrTicketNotFound, id)
+	}
+	return *r.sessions[id-1], nil
+}
+
+// GetActiveSession returns the session of a parked vehicle, false when the vehicle is not parked
+func (r *InMemoryParkingRepository) GetActiveSession(vehicleNumber string) (Session, bool) {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	session, exists := r.activeSessions[vehicleNumber]
+	if !exists {
+		return Session{}, false
+	}
+	return *session, true
+}
+
+// GetLastExit returns when a vehicle was last unparked, false when it never was
+func (r *InMemoryParkingRepository) GetLastExit(vehicleNumber string) (time.Time, bool) {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	exitTime, exists := r.exitTimes[vehicleNumber]
+	return exitTime, exists
+}
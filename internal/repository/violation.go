@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"fmt"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"time"
+)
+
+// represents a violation of a parked vehicle flagged by enforcement staff
+type Violation struct {
+	ID            int
+	VehicleNumber string
+	SpotID        string
+	Kind          string // e.g. "wrong_spot_type", "overstay", "blocking"
+	Note          string
+	ReportedBy    string
+	ReportedAt    time.Time
+	ResolvedBy    string
+	ResolvedAt    time.Time // zero while the violation is open
+	Fine          float64   // fine recorded on resolution, 0 when dismissed
+}
+
+// IsOpen reports whether the violation is neither resolved nor fined
+func (v Violation) IsOpen() bool {
+	return v.ResolvedAt.IsZero()
+}
+
+// AddViolation records a violation, assigning its ID and report time
+func (r *InMemoryParkingRepository) AddViolation(violation Violation) Violation {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	violation.ID = len(r.violations) + 1
+	violation.ReportedAt = time.Now()
+	r.violations = append(r.violations, &violation)
+
+	return violation
+}
+
+// ResolveViolation closes an open violation, recording the fine when it is not 0
+func (r *InMemoryParkingRepository) ResolveViolation(id int, resolvedBy string, fine float64) (Violation, error) {
+	r.recordMutex.Lock()
+	defer r.recordMutex.Unlock()
+
+	if id < 1 || id > len(r.violations) {
+		return Violation{}, fmt.Errorf("%w: %d", pkgerrors.ErrViolationNotFound, id)
+	}
+
+	violation := r.violations[id-1]
+	if !violation.IsOpen() {
+		return Violation{}, fmt.Errorf("%w: %d", pkgerrors.ErrViolationResolved, id)
+	}
+
+	violation.ResolvedBy = resolvedBy
+	violation.ResolvedAt = time.Now()
+	violation.Fine = fine
+
+	return *violation, nil
+}
+
+// GetViolations returns the violations of a vehicle, or of all vehicles when vehicleNumber is empty,
+// in the order they were reported
+func (r *InMemoryParkingRepository) GetViolations(vehicleNumber string) []Violation {
+	r.recordMutex.RLock()
+	defer r.recordMutex.RUnlock()
+
+	violations := []Violation{}
+	for _, violation := range r.violations {
+		if vehicleNumber == "" || violation.VehicleNumber == vehicleNumber {
+			violations = append(violations, *violation)
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,98 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// Template names, notifications have a subject and a body template each
+const (
+	ReservationConfirmation = "reservation_confirmation"
+	OverstayWarning         = "overstay_warning"
+	AvailabilityAlert       = "availability_alert"
+	DailyReport             = "daily_report"
+	PaymentReceipt          = "payment_receipt" // has an HTML version, payment_receipt.html
+	WebhookBody             = "webhook.body"
+)
+
+// the built-in templates, which the files of the template directory override by name
+//
+//go:embed defaults/*.tmpl
+var defaults embed.FS
+
+// functions available to the templates
+var funcs = template.FuncMap{
+	// json encodes a value, e.g. {{json .VehicleNumber}} for a quoted string in a webhook body
+	"json": func(value any) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+}
+
+// Set holds the templates of the outgoing notifications and webhook bodies
+type Set struct {
+	templates *template.Template
+}
+
+// Default holds the built-in templates only
+var Default = mustLoadDefaults()
+
+func mustLoadDefaults() *Set {
+	set, err := Load("")
+	if err != nil {
+		panic(err)
+	}
+	return set
+}
+
+// Load reads the built-in templates and overrides them with the {{define}} blocks of the *.tmpl files in
+// dir. A missing directory leaves the built-in templates in place.
+func Load(dir string) (*Set, error) {
+	tmpl, err := template.New("").Funcs(funcs).ParseFS(defaults, "defaults/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.tmpl"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := tmpl.New(filepath.Base(file)).Parse(string(content)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &Set{templates: tmpl}, nil
+}
+
+// Render executes a template with the given data
+func (s *Set) Render(name string, data any) (string, error) {
+	var out bytes.Buffer
+	if err := s.templates.ExecuteTemplate(&out, name, data); err != nil {
+		return "", fmt.Errorf("rendering template %s: %v", name, err)
+	}
+	return out.String(), nil
+}
+
+// RenderMessage executes the subject and body templates of a notification
+func (s *Set) RenderMessage(name string, data any) (subject, body string, err error) {
+	if subject, err = s.Render(name+".subject", data); err != nil {
+		return "", "", err
+	}
+	if body, err = s.Render(name+".body", data); err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
@@ -0,0 +1,182 @@
+// Package wallet builds the payloads of Apple Wallet and Google Wallet passes for parking tickets
+package wallet
+
+import (
+	"fmt"
+	"time"
+)
+
+// Issuer identifies the lot to the wallet providers
+type Issuer struct {
+	Organization    string // shown on the pass, e.g. the name of the garage
+	ApplePassTypeID string // pass type identifier registered with Apple, e.g. pass.com.example.parking
+	AppleTeamID     string
+	GoogleIssuerID  string // issuer ID of the Google Wallet console
+	GoogleClass     string // class of the ticket objects, created once in the Google Wallet console
+}
+
+// Ticket is what a pass shows: the ticket ID, the spot, the expiry and the scannable code
+type Ticket struct {
+	ID            int
+	VehicleNumber string
+	SpotID        string
+	EntryTime     time.Time
+	ExpiresAt     time.Time
+	Code          string // encoded as the QR code
+}
+
+// Payload holds a pass for each wallet, for an app or a signing service to turn into the pass the
+// driver adds to their phone: a signed .pkpass file for Apple, a signed save link for Google
+type Payload struct {
+	Apple  ApplePass    `json:"apple"`
+	Google GoogleObject `json:"google"`
+}
+
+// New builds the passes of a ticket
+func New(issuer Issuer, ticket Ticket) Payload {
+	return Payload{Apple: applePass(issuer, ticket), Google: googleObject(issuer, ticket)}
+}
+
+// ApplePass is the pass.json of an Apple Wallet pass
+type ApplePass struct {
+	FormatVersion      int            `json:"formatVersion"`
+	PassTypeIdentifier string         `json:"passTypeIdentifier"`
+	TeamIdentifier     string         `json:"teamIdentifier"`
+	SerialNumber       string         `json:"serialNumber"`
+	OrganizationName   string         `json:"organizationName"`
+	Description        string         `json:"description"`
+	RelevantDate       string         `json:"relevantDate"`
+	ExpirationDate     string         `json:"expirationDate"`
+	Barcodes           []AppleBarcode `json:"barcodes"`
+	Generic            AppleFields    `json:"generic"`
+}
+
+type AppleBarcode struct {
+	Format          string `json:"format"`
+	Message         string `json:"message"`
+	MessageEncoding string `json:"messageEncoding"`
+	AltText         string `json:"altText,omitempty"`
+}
+
+type AppleFields struct {
+	PrimaryFields   []AppleField `json:"primaryFields"`
+	SecondaryFields []AppleField `json:"secondaryFields"`
+	AuxiliaryFields []AppleField `json:"auxiliaryFields"`
+}
+
+type AppleField struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Value string `json:"value"`
+	// shows dates in the time zone of the phone, empty for plain text
+	DateStyle string `json:"dateStyle,omitempty"`
+	TimeStyle string `json:"timeStyle,omitempty"`
+}
+
+func applePass(issuer Issuer, ticket Ticket) ApplePass {
+	return ApplePass{
+		FormatVersion:      1,
+		PassTypeIdentifier: issuer.ApplePassTypeID,
+		TeamIdentifier:     issuer.AppleTeamID,
+		SerialNumber:       serialNumber(ticket),
+		OrganizationName:   issuer.Organization,
+		Description:        "Parking ticket",
+		RelevantDate:       ticket.EntryTime.Format(time.RFC3339),
+		ExpirationDate:     ticket.ExpiresAt.Format(time.RFC3339),
+		Barcodes: []AppleBarcode{{
+			Format:          "PKBarcodeFormatQR",
+			Message:         ticket.Code,
+			MessageEncoding: "iso-8859-1",
+			AltText:         fmt.Sprintf("Ticket %d", ticket.ID),
+		}},
+		Generic: AppleFields{
+			PrimaryFields: []AppleField{{Key: "spot", Label: "SPOT", Value: ticket.SpotID}},
+			SecondaryFields: []AppleField{
+				{Key: "ticket", Label: "TICKET", Value: fmt.Sprint(ticket.ID)},
+				{Key: "vehicle", Label: "VEHICLE", Value: ticket.VehicleNumber},
+			},
+			AuxiliaryFields: []AppleField{
+				{Key: "entry", Label: "ENTRY", Value: ticket.EntryTime.Format(time.RFC3339), DateStyle: "PKDateStyleShort", TimeStyle: "PKDateStyleShort"},
+				{Key: "expires", Label: "EXPIRES", Value: ticket.ExpiresAt.Format(time.RFC3339), DateStyle: "PKDateStyleShort", TimeStyle: "PKDateStyleShort"},
+			},
+		},
+	}
+}
+
+// GoogleObject is the generic object of a Google Wallet pass
+type GoogleObject struct {
+	ID                string             `json:"id"`
+	ClassID           string             `json:"classId"`
+	State             string             `json:"state"`
+	CardTitle         GoogleString       `json:"cardTitle"`
+	Header            GoogleString       `json:"header"`
+	Subheader         GoogleString       `json:"subheader"`
+	Barcode           GoogleBarcode      `json:"barcode"`
+	ValidTimeInterval GoogleTimeInterval `json:"validTimeInterval"`
+	TextModulesData   []GoogleTextModule `json:"textModulesData"`
+}
+
+type GoogleString struct {
+	DefaultValue GoogleTranslatedString `json:"defaultValue"`
+}
+
+type GoogleTranslatedString struct {
+	Language string `json:"language"`
+	Value    string `json:"value"`
+}
+
+type GoogleBarcode struct {
+	Type          string `json:"type"`
+	Value         string `json:"value"`
+	AlternateText string `json:"alternateText,omitempty"`
+}
+
+type GoogleTimeInterval struct {
+	Start GoogleDateTime `json:"start"`
+	End   GoogleDateTime `json:"end"`
+}
+
+type GoogleDateTime struct {
+	Date string `json:"date"`
+}
+
+type GoogleTextModule struct {
+	ID     string `json:"id"`
+	Header string `json:"header"`
+	Body   string `json:"body"`
+}
+
+func googleObject(issuer Issuer, ticket Ticket) GoogleObject {
+	text := func(value string) GoogleString {
+		return GoogleString{DefaultValue: GoogleTranslatedString{Language: "en", Value: value}}
+	}
+
+	return GoogleObject{
+		ID:        issuer.GoogleIssuerID + "." + serialNumber(ticket),
+		ClassID:   issuer.GoogleIssuerID + "." + issuer.GoogleClass,
+		State:     "ACTIVE",
+		CardTitle: text(issuer.Organization),
+		Header:    text("Spot " + ticket.SpotID),
+		Subheader: text(fmt.Sprintf("Ticket %d", ticket.ID)),
+		Barcode: GoogleBarcode{
+			Type:          "QR_CODE",
+			Value:         ticket.Code,
+			AlternateText: fmt.Sprintf("Ticket %d", ticket.ID),
+		},
+		ValidTimeInterval: GoogleTimeInterval{
+			Start: GoogleDateTime{Date: ticket.EntryTime.Format(time.RFC3339)},
+			End:   GoogleDateTime{Date: ticket.ExpiresAt.Format(time.RFC3339)},
+		},
+		TextModulesData: []GoogleTextModule{
+			{ID: "vehicle", Header: "Vehicle", Body: ticket.VehicleNumber},
+			{ID: "entry", Header: "Entry", Body: ticket.EntryTime.Format("2006-01-02 15:04")},
+			{ID: "expires", Header: "Expires", Body: ticket.ExpiresAt.Format("2006-01-02 15:04")},
+		},
+	}
+}
+
+// serialNumber identifies the pass of a ticket; it stays the same when the pass is fetched again, so the
+// wallet replaces the pass instead of adding a second one
+func serialNumber(ticket Ticket) string {
+	return fmt.Sprintf("ticket-%d", ticket.ID)
+}
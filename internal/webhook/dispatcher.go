@@ -0,0 +1,385 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"parking-lot-system/internal/domain/parking"
+	"parking-lot-system/internal/templates"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"sync"
+	"time"
+)
+
+// Delivery statuses
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+	DeliveryRetrying  = "retrying"
+	DeliveryDead      = "dead"
+)
+
+// SignatureHeader carries the hex encoded HMAC-SHA256 of the payload, keyed with the subscription secret
+const SignatureHeader = "X-Parking-Signature"
+
+const (
+	deliveryWorkers   = 4
+	deliveryQueueSize = 1000
+	deliveryTimeout   = 5 * time.Second
+
+	// number of deliveries kept per subscription
+	deliveryHistorySize = 100
+
+	// retry policy for failed deliveries: the delay doubles after every attempt
+	maxDeliveryAttempts = 5
+	initialRetryDelay   = time.Second
+
+	// number of dead-lettered deliveries kept for inspection
+	deadLetterSize = 1000
+)
+
+// Subscription represents a callback URL subscribed to a set of event types
+type Subscription struct {
+	ID         int
+	URL        string
+	EventTypes []string
+	Secret     string
+	CreatedAt  time.Time
+}
+
+// Delivery represents a single attempt to deliver an event to a subscription
+type Delivery struct {
+	ID             int
+	SubscriptionID int
+	EventType      string
+	Status         string
+	Attempts       int
+	StatusCode     int
+	Error          string
+	CreatedAt      time.Time
+	NextAttemptAt  time.Time
+	DeliveredAt    time.Time
+}
+
+// Payload is the JSON body posted to subscribers
+type Payload struct {
+	DeliveryID    int       `json:"deliveryId"`
+	Type          string    `json:"type"`
+	Time          time.Time `json:"time"`
+	SpotID        string    `json:"spotId,omitempty"`
+	VehicleNumber string    `json:"vehicleNumber,omitempty"`
+	VehicleType   string    `json:"vehicleType,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+}
+
+type job struct {
+	delivery *Delivery
+	url      string
+	secret   string
+	body     []byte
+}
+
+// Dispatcher keeps webhook subscriptions and delivers parking events to them asynchronously
+type Dispatcher struct {
+	client        *http.Client
+	templates     *templates.Set
+	queue         chan job
+	mutex         sync.RWMutex
+	subscriptions []*Subscription
+	deliveries    map[int][]*Delivery // subscriptionID -> most recent deliveries
+	deadLetters   []*Delivery
+	nextID        int
+	// private networks callbacks may reach, see SetAllowedNetworks
+	allowedNetworks []netip.Prefix
+}
+
+func NewDispatcher() *Dispatcher {
+	d := &Dispatcher{
+		templates:  templates.Default,
+		queue:      make(chan job, deliveryQueueSize),
+		deliveries: make(map[int][]*Delivery),
+	}
+	d.client = d.newClient()
+
+	for i := 0; i < deliveryWorkers; i++ {
+		go d.worker()
+	}
+
+	return d
+}
+
+// SetTemplates sets the templates the payloads are rendered with, e.g. to match what a receiver expects
+func (d *Dispatcher) SetTemplates(set *templates.Set) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.templates = set
+}
+
+// Subscribe registers a callback URL for the given event types. When secret is empty
+// a random one is generated; it is used to sign every payload sent to the subscription.
+// URLs of private, loopback or link-local addresses are refused unless their network is allowed.
+func (d *Dispatcher) Subscribe(ctx context.Context, callbackURL string, eventTypes []string, secret string) (Subscription, error) {
+	parsed, err := url.Parse(callbackURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return Subscription{}, pkgerrors.ErrInvalidWebhookURL
+	}
+	if err := d.checkTarget(ctx, parsed.Hostname()); err != nil {
+		return Subscription{}, err
+	}
+
+	if len(eventTypes) == 0 {
+		return Subscription{}, pkgerrors.ErrInvalidEventType
+	}
+	for _, eventType := range eventTypes {
+		if !parking.IsValidEventType(eventType) {
+			return Subscription{}, fmt.Errorf("%w: %s", pkgerrors.ErrInvalidEventType, eventType)
+		}
+	}
+
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			return Subscription{}, err
+		}
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.nextID++
+	subscription := &Subscription{
+		ID:         d.nextID,
+		URL:        callbackURL,
+		EventTypes: eventTypes,
+		Secret:     secret,
+		CreatedAt:  time.Now(),
+	}
+	d.subscriptions = append(d.subscriptions, subscription)
+
+	return *subscription, nil
+}
+
+// Subscriptions returns all registered subscriptions
+func (d *Dispatcher) Subscriptions() []Subscription {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	subscriptions := make([]Subscription, 0, len(d.subscriptions))
+	for _, subscription := range d.subscriptions {
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	return subscriptions
+}
+
+// Deliveries returns the most recent deliveries of a subscription
+func (d *Dispatcher) Deliveries(subscriptionID int) ([]Delivery, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.findSubscription(subscriptionID) == nil {
+		return nil, fmt.Errorf("%w: %d", pkgerrors.ErrSubscriptionNotFound, subscriptionID)
+	}
+
+	deliveries := make([]Delivery, 0, len(d.deliveries[subscriptionID]))
+	for _, delivery := range d.deliveries[subscriptionID] {
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, nil
+}
+
+// HandleEvent queues the event for delivery to every subscription interested in it
+func (d *Dispatcher) HandleEvent(event parking.Event) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, subscription := range d.subscriptions {
+		if !subscription.wants(event.Type) {
+			continue
+		}
+
+		d.nextID++
+		delivery := &Delivery{
+			ID:             d.nextID,
+			SubscriptionID: subscription.ID,
+			EventType:      event.Type,
+			Status:         DeliveryPending,
+			CreatedAt:      time.Now(),
+		}
+		d.addDelivery(delivery)
+
+		body, err := d.templates.Render(templates.WebhookBody, Payload{
+			DeliveryID:    delivery.ID,
+			Type:          event.Type,
+			Time:          event.Time,
+			SpotID:        event.SpotID,
+			VehicleNumber: event.VehicleNumber,
+			VehicleType:   event.VehicleType,
+			Reason:        event.Reason,
+		})
+		if err != nil {
+			delivery.Status = DeliveryFailed
+			delivery.Error = err.Error()
+			continue
+		}
+
+		d.enqueue(job{
+			delivery: delivery,
+			url:      subscription.URL,
+			secret:   subscription.Secret,
+			body:     []byte(body),
+		})
+	}
+}
+
+// DeadLetters returns the deliveries that failed after all retry attempts
+func (d *Dispatcher) DeadLetters() []Delivery {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	deadLetters := make([]Delivery, 0, len(d.deadLetters))
+	for _, delivery := range d.deadLetters {
+		deadLetters = append(deadLetters, *delivery)
+	}
+
+	return deadLetters
+}
+
+// enqueue hands a job to the workers without ever blocking the caller,
+// the caller must hold the mutex
+func (d *Dispatcher) enqueue(j job) {
+	select {
+	case d.queue <- j:
+	default:
+		d.deadLetter(j.delivery, "delivery queue is full")
+	}
+}
+
+// deadLetter gives up on a delivery, the caller must hold the mutex
+func (d *Dispatcher) deadLetter(delivery *Delivery, reason string) {
+	delivery.Status = DeliveryDead
+	delivery.Error = reason
+	delivery.NextAttemptAt = time.Time{}
+
+	d.deadLetters = append(d.deadLetters, delivery)
+	if len(d.deadLetters) > deadLetterSize {
+		d.deadLetters = d.deadLetters[len(d.deadLetters)-deadLetterSize:]
+	}
+}
+
+// wants checks if the subscription is interested in the event type
+func (s *Subscription) wants(eventType string) bool {
+	for _, subscribed := range s.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// findSubscription looks up a subscription by ID, the caller must hold the mutex
+func (d *Dispatcher) findSubscription(id int) *Subscription {
+	for _, subscription := range d.subscriptions {
+		if subscription.ID == id {
+			return subscription
+		}
+	}
+	return nil
+}
+
+// addDelivery records a delivery, dropping the oldest ones beyond the history size
+func (d *Dispatcher) addDelivery(delivery *Delivery) {
+	deliveries := append(d.deliveries[delivery.SubscriptionID], delivery)
+	if len(deliveries) > deliveryHistorySize {
+		deliveries = deliveries[len(deliveries)-deliveryHistorySize:]
+	}
+	d.deliveries[delivery.SubscriptionID] = deliveries
+}
+
+// worker delivers queued payloads, scheduling a retry with exponential backoff on failure
+func (d *Dispatcher) worker() {
+	for j := range d.queue {
+		statusCode, err := d.post(j)
+
+		d.mutex.Lock()
+		j.delivery.Attempts++
+		j.delivery.StatusCode = statusCode
+
+		switch {
+		case err == nil:
+			j.delivery.Status = DeliveryDelivered
+			j.delivery.Error = ""
+			j.delivery.NextAttemptAt = time.Time{}
+			j.delivery.DeliveredAt = time.Now()
+		case j.delivery.Attempts >= maxDeliveryAttempts:
+			d.deadLetter(j.delivery, err.Error())
+		default:
+			delay := initialRetryDelay << (j.delivery.Attempts - 1)
+			j.delivery.Status = DeliveryRetrying
+			j.delivery.Error = err.Error()
+			j.delivery.NextAttemptAt = time.Now().Add(delay)
+
+			retry := j
+			time.AfterFunc(delay, func() {
+				d.mutex.Lock()
+				defer d.mutex.Unlock()
+				d.enqueue(retry)
+			})
+		}
+		d.mutex.Unlock()
+
+		if err != nil {
+			log.Printf("Webhook delivery %d to %s failed (attempt %d): %v", j.delivery.ID, j.url, j.delivery.Attempts, err)
+		}
+	}
+}
+
+// post sends the signed payload to the callback URL, treating non-2xx responses as failures
+func (d *Dispatcher) post(j job) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, j.url, bytes.NewReader(j.body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, "sha256="+Sign(j.secret, j.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the body keyed with the secret,
+// subscribers recompute it to verify a payload
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateSecret returns a random hex encoded signing secret
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
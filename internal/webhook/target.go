@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	pkgerrors "parking-lot-system/pkg/errors"
+	"slices"
+	"syscall"
+)
+
+// SetAllowedNetworks lets subscriptions call the given private networks, e.g. a receiver on the lot's LAN.
+// Without it webhooks only reach public addresses, so a subscriber cannot make the server call internal
+// services.
+func (d *Dispatcher) SetAllowedNetworks(networks []netip.Prefix) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.allowedNetworks = networks
+}
+
+// allows reports whether webhooks may be delivered to an address
+func (d *Dispatcher) allows(address netip.Addr) bool {
+	address = address.Unmap()
+	if address.IsGlobalUnicast() && !address.IsPrivate() {
+		return true
+	}
+
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	return slices.ContainsFunc(d.allowedNetworks, func(network netip.Prefix) bool {
+		return network.Contains(address)
+	})
+}
+
+// checkTarget rejects callback hosts resolving to private, loopback or link-local addresses that are not allowed
+func (d *Dispatcher) checkTarget(ctx context.Context, host string) error {
+	addresses, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: %v", pkgerrors.ErrInvalidWebhookURL, err)
+	}
+	for _, address := range addresses {
+		if !d.allows(address) {
+			return fmt.Errorf("%w: %s resolves to %s", pkgerrors.ErrWebhookTargetNotAllowed, host, address.Unmap())
+		}
+	}
+	return nil
+}
+
+// newClient returns the HTTP client deliveries are posted with. It checks every address it connects to,
+// redirects included, so a host resolving to another address after subscribing is still refused.
+func (d *Dispatcher) newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: deliveryTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !d.allows(addrPort.Addr()) {
+				return fmt.Errorf("%w: %s", pkgerrors.ErrWebhookTargetNotAllowed, addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be the only address checked, not the receiver behind it
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Timeout: deliveryTimeout, Transport: transport}
+}
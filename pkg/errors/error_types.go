@@ -1,20 +1,163 @@
 package errors
 
-// Error constants used throughout the parking lot system
-const (
+import stderrors "errors"
+
+// Code is a stable, machine-readable error code that API clients can match on
+type Code string
+
+// Error is a domain error carrying a machine-readable code. Details are added by
+// wrapping it, e.g. fmt.Errorf("%w: %s", ErrVehicleNotParked, vehicleNumber),
+// so callers match it with errors.Is and read its code with errors.As or CodeOf.
+type Error struct {
+	Code    Code
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// New creates a domain error
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// CodeInternal is the code of errors that are not domain errors
+const CodeInternal Code = "internal"
+
+// CodeOf returns the code of the first domain error in err's chain, CodeInternal when there is none
+func CodeOf(err error) Code {
+	var domainErr *Error
+	if stderrors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return CodeInternal
+}
+
+// Errors used throughout the parking lot system
+var (
 	// Location related errors
-	ErrInvalidLocation = "invalid parking spot location: index out of bounds"
-	ErrInvalidSpotID   = "invalid spot ID format: must be floor-row-column"
+	ErrInvalidLocation = New("invalid_location", "invalid parking spot location: index out of bounds")
+	ErrInvalidSpotID   = New("invalid_spot_id", "invalid spot ID: does not match the spot ID format")
+	ErrInvalidGate     = New("invalid_gate", "invalid gate: must be between 1 and the number of gates")
 
 	// Configuration related errors
-	ErrInvalidSpotType = "invalid spot type: must be B-1, M-1, A-1, or X-0"
+	ErrInvalidDimensions        = New("invalid_dimensions", "invalid parking lot dimensions")
+	ErrInvalidSpotType          = New("invalid_spot_type", "invalid spot type")
+	ErrInvalidSpotIDFormat      = New("invalid_spot_id_format", "invalid spot ID format")
+	ErrInvalidSize              = New("invalid_size", "invalid size: must be compact, standard, or large")
+	ErrInvalidCapacity          = New("invalid_capacity", "invalid capacity: must be at least 1 and not below the number of parked vehicles")
+	ErrLotInUse                 = New("lot_in_use", "cannot re-initialize a parking lot with parked vehicles")
+	ErrSpotInUse                = New("spot_in_use", "cannot reconfigure an occupied parking spot")
+	ErrInvalidPlateScheme       = New("invalid_plate_scheme", "invalid plate scheme")
+	ErrInvalidSpotTypeConfig    = New("invalid_spot_type_config", "invalid spot type configuration")
+	ErrInvalidVehicleTypeConfig = New("invalid_vehicle_type_config", "invalid vehicle type configuration")
 
 	// Vehicle related errors
-	ErrInvalidVehicleType   = "invalid vehicle type: must be Bicycle, Motorcycle, or Automobile"
-	ErrVehicleAlreadyParked = "vehicle is already parked"
-	ErrVehicleNotParked     = "vehicle is not currently parked"
-	ErrVehicleNotAtSpot     = "vehicle is not parked at the specified spot"
+	ErrInvalidVehicleType   = New("invalid_vehicle_type", "invalid vehicle type")
+	ErrInvalidVehicleNumber = New("invalid_vehicle_number", "invalid vehicle number")
+	ErrVehicleAlreadyParked = New("vehicle_already_parked", "vehicle is already parked")
+	ErrReentryCooldown      = New("reentry_cooldown", "vehicle left too recently to park again")
+	ErrVehicleNotParked     = New("vehicle_not_parked", "vehicle is not currently parked")
+	ErrVehicleNotAtSpot     = New("vehicle_not_at_spot", "vehicle is not parked at the specified spot")
+	ErrVehicleNotFound      = New("vehicle_not_found", "vehicle has never been parked in this parking lot")
+	ErrSpotOccupied         = New("spot_occupied", "parking spot is occupied by another vehicle")
+	ErrSpotNotOccupied      = New("spot_not_occupied", "parking spot is not occupied")
+	ErrSpotDoesNotExist     = New("spot_does_not_exist", "no parking spot exists at this location")
+	ErrSpotFull             = New("spot_full", "parking spot has no room left")
+	ErrSpotInactive         = New("spot_inactive", "parking spot is not active")
+	ErrSpotDeleted          = New("spot_deleted", "parking spot is deleted")
+	ErrInvalidPlateSuffix   = New("invalid_plate_suffix", "plate suffix must be at least 2 characters")
+	ErrVehicleNumberNeeded  = New("vehicle_number_needed", "vehicle number is required when several vehicles are parked at the spot")
 
 	// Availability related errors
-	ErrNoAvailableSpot = "no available parking spot for the specified vehicle type"
+	ErrNoAvailableSpot = New("no_available_spot", "no available parking spot for the specified vehicle type")
+
+	// Spot block related errors
+	ErrInvalidSpotBlock  = New("invalid_spot_block", "invalid spot block: needs a name, spots or a zone, and a window ending in the future")
+	ErrSpotBlockNotFound = New("spot_block_not_found", "spot block not found or ended")
+	ErrSpotBlocked       = New("spot_blocked", "parking spot is blocked for an event")
+
+	// Hold related errors
+	ErrHoldNotFound           = New("hold_not_found", "spot hold not found or expired")
+	ErrDuplicatePending       = New("duplicate_pending", "duplicate vehicle number awaits operator confirmation")
+	ErrDuplicateRetrying      = New("duplicate_retrying", "duplicate vehicle number is retried in the background")
+	ErrRetryNotFound          = New("retry_not_found", "duplicate park retry not found")
+	ErrInvalidDuplicatePolicy = New("invalid_duplicate_policy", "invalid duplicate policy: must be reject, retry, or confirm")
+	ErrInvalidDuplicateAction = New("invalid_duplicate_action", "invalid duplicate action: must be confirm or reject")
+
+	// Check-in related errors
+	ErrCheckInNotFound  = New("checkin_not_found", "check-in not found")
+	ErrVehicleCheckedIn = New("vehicle_checked_in", "vehicle is already waiting for a spot")
+
+	// Webhook related errors
+	ErrInvalidWebhookURL       = New("invalid_webhook_url", "invalid webhook URL: must be an absolute http or https URL")
+	ErrInvalidEventType        = New("invalid_event_type", "invalid event type: must be vehicle.parked, vehicle.unparked, lot.full, spot.reconfigured, device.stale, device.recovered, incident.started, incident.ended, or lot.initialized")
+	ErrSubscriptionNotFound    = New("subscription_not_found", "webhook subscription not found")
+	ErrWebhookTargetNotAllowed = New("webhook_target_not_allowed", "webhook URL points to a private, loopback or link-local address that is not allowed")
+
+	// Plate recognition related errors
+	ErrInvalidDirection = New("invalid_direction", "invalid direction: must be entry or exit")
+
+	// Authentication related errors
+	ErrUnauthorized = New("unauthorized", "missing or invalid credentials")
+	ErrForbidden    = New("forbidden", "insufficient permissions for this operation")
+
+	// API token related errors
+	ErrInvalidRole      = New("invalid_role", "invalid role: must be attendant, enforcement, or admin")
+	ErrInvalidExpiry    = New("invalid_expiry", "invalid expiry: must be in the future")
+	ErrInvalidAllowlist = New("invalid_allowlist", "invalid IP allowlist: entries must be IP addresses or CIDR ranges")
+	ErrTokenNotFound    = New("token_not_found", "API token not found")
+	ErrTokenRevoked     = New("token_revoked", "API token is revoked")
+
+	// Device related errors
+	ErrInvalidDevice         = New("invalid_device", "invalid device: needs a name and a kind of kiosk or pay_station")
+	ErrInvalidEnrollmentCode = New("invalid_enrollment_code", "enrollment code is unknown, used or expired")
+	ErrDeviceSessionNotFound = New("device_session_not_found", "device session not found")
+	ErrInvalidDeviceID       = New("invalid_device_id", "invalid device ID: must be 1 to 64 letters, digits, dashes, dots or underscores")
+	ErrInvalidDeviceType     = New("invalid_device_type", "invalid device type: must be gate, kiosk, pay_station, or sensor")
+	ErrDeviceNotFound        = New("device_not_found", "device not found")
+	ErrDeviceExists          = New("device_exists", "device is already registered")
+	ErrInvalidDeviceStatus   = New("invalid_device_status", "invalid device status: must be online or stale")
+	ErrInvalidCommand        = New("invalid_command", "invalid command: action must be open_barrier, show_message, or reboot")
+	ErrDeviceNotConnected    = New("device_not_connected", "device is not connected")
+
+	// Sensor related errors
+	ErrInvalidBatteryLevel    = New("invalid_battery_level", "invalid battery level: must be between 0 and 100")
+	ErrInvalidFirmwareVersion = New("invalid_firmware_version", "invalid firmware version: must be dotted numbers like 1.4.2")
+
+	// Incident related errors
+	ErrLotEmergency = New("lot_in_emergency", "the lot is in emergency mode and lets no vehicles in")
+
+	// Override related errors
+	ErrInvalidOverrideAction = New("invalid_override_action", "invalid override action: must be park or unpark")
+	ErrReasonRequired        = New("reason_required", "a reason is required for manual overrides")
+
+	// Violation related errors
+	ErrInvalidViolationKind = New("invalid_violation_kind", "invalid violation kind: must be wrong_spot_type, overstay, or blocking")
+	ErrInvalidFine          = New("invalid_fine", "invalid fine: must not be negative")
+	ErrViolationNotFound    = New("violation_not_found", "violation not found")
+	ErrViolationResolved    = New("violation_resolved", "violation is already resolved")
+	ErrVehicleFlagged       = New("vehicle_flagged", "vehicle has an unresolved violation")
+
+	// Reporting related errors
+	ErrInvalidTimeWindow = New("invalid_time_window", "invalid time window: from must be before to")
+	ErrInvalidStep       = New("invalid_step", "invalid step: must be positive")
+	ErrInvalidPageSize   = New("invalid_page_size", "invalid page size")
+	ErrEventsExpired     = New("events_expired", "the event log no longer goes back to this moment")
+
+	// Ticket related errors
+	ErrTicketNotFound = New("ticket_not_found", "parking ticket not found")
+	ErrTicketClosed   = New("ticket_closed", "parking ticket is closed, the vehicle has left")
+
+	// Payment related errors
+	ErrInvalidPayment  = New("invalid_payment", "invalid payment: tendered must be positive, change between 0 and the amount tendered, and a drawer ID is required")
+	ErrPaymentNotFound = New("payment_not_found", "payment not found")
+	ErrInvalidRefund   = New("invalid_refund", "invalid refund: the amount must be positive and at most what is left of the payment, and a reason is required")
+
+	// Notification related errors
+	ErrInvalidContact = New("invalid_contact", "invalid contact: must be an email address or a phone number like +6281234567890")
+
+	// Storage related errors
+	ErrStoreTimeout = New("store_timeout", "the parking store did not answer in time")
 )